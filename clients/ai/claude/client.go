@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -23,6 +24,8 @@ type ClaudeClient struct {
 	config        types.ClaudeConfig
 	memoryID      string // For conversation tracking
 	conversations map[string]ConversationContext
+	// httpClient calls the Messages API directly for streaming
+	httpClient *http.Client
 }
 
 // ConversationContext tracks the state of a conversation
@@ -30,7 +33,12 @@ type ConversationContext struct {
 	ContentType        string
 	UserPreferences    map[string]interface{}
 	SystemInstructions string
-	History            []ChatMessage
+	// Summary stands in for the turns compacted out of History
+	Summary string
+	History []ChatMessage
+	// ToolExchange holds the messages of a turn that is still calling tools,
+	// only the user's message and the final reply are added to History
+	ToolExchange []anthropicMessage
 }
 
 // ChatMessage represents a message in the conversation
@@ -68,6 +76,7 @@ func NewClaudeClient(ctx context.Context, clientID uint64, cfg types.ClaudeConfi
 		llm:           llm,
 		config:        cfg,
 		conversations: make(map[string]ConversationContext),
+		httpClient:    &http.Client{Timeout: 5 * time.Minute},
 	}, nil
 }
 
@@ -118,6 +127,7 @@ func (c *ClaudeClient) GenerateText(ctx context.Context, promptText string, opti
 		log.Error().Err(err).Msg("Failed to generate text with Claude")
 		return "", fmt.Errorf("claude text generation failed: %w", err)
 	}
+	c.reportEstimatedUsage(ctx, promptText, response)
 
 	return response, nil
 }
@@ -223,6 +233,7 @@ func (c *ClaudeClient) SendMessage(ctx context.Context, conversationID string, m
 		log.Error().Err(err).Msg("Failed to send message to Claude")
 		return "", fmt.Errorf("claude message send failed: %w", err)
 	}
+	c.reportEstimatedUsage(ctx, message, response)
 
 	return response, nil
 }
@@ -238,12 +249,18 @@ func (c *ClaudeClient) GetSupportedModels() []string {
 	}
 }
 
+// CreateEmbeddings is not supported, Anthropic has no embeddings API
+func (c *ClaudeClient) CreateEmbeddings(ctx context.Context, texts []string, model string) (*aitypes.EmbeddingResponse, error) {
+	return nil, ai.ErrFeatureNotSupported
+}
+
 // GetCapabilities returns the capabilities of this Claude client
 func (c *ClaudeClient) GetCapabilities() *aitypes.AICapabilities {
 	return &aitypes.AICapabilities{
 		SupportsStructuredOutput: true,
 		SupportsConversation:     true,
-		SupportsStreaming:        false, // Implement if needed
+		SupportsStreaming:        true,
+		SupportsToolCalling:      true,
 		MaxContextTokens:         c.config.AIClientConfig.GetMaxContextTokens(),
 		DefaultMaxTokens:         c.config.AIClientConfig.GetMaxTokens(),
 	}
@@ -483,6 +500,10 @@ func (c *ClaudeClient) ContinueRecommendationConversation(ctx context.Context, c
 	// Add system instructions
 	promptBuilder.WriteString(conversation.SystemInstructions)
 	promptBuilder.WriteString("\n\n")
+	if conversation.Summary != "" {
+		promptBuilder.WriteString(conversation.Summary)
+		promptBuilder.WriteString("\n\n")
+	}
 
 	// Add user preferences
 	if len(conversation.UserPreferences) > 0 {
@@ -692,6 +713,16 @@ func (c *ClaudeClient) GenerateContent(ctx context.Context, systemPrompt string,
 			TotalTokens:      promptTokens + completionTokens,
 		},
 	}
+	aitypes.ReportUsage(ctx, c.config.AIClientConfig.GetModel(), response.TokenUsage)
 
 	return response, nil
 }
+
+// reportEstimatedUsage reports the usage of a request made through gollm, which does not return token counts.
+// Like GenerateContent it estimates four characters per token.
+func (c *ClaudeClient) reportEstimatedUsage(ctx context.Context, prompt string, reply string) {
+	aitypes.ReportUsage(ctx, c.config.AIClientConfig.GetModel(), aitypes.TokenUsage{
+		PromptTokens:     len(prompt) / 4,
+		CompletionTokens: len(reply) / 4,
+	})
+}
@@ -0,0 +1,26 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+
+	aitypes "suasor/clients/ai/types"
+)
+
+// CompactConversation replaces the history of a conversation with a summary of its earlier turns and the
+// messages since. The summary is added to the system prompt.
+func (c *ClaudeClient) CompactConversation(ctx context.Context, conversationID string, summary string, history []aitypes.ChatMessage) error {
+	conversation, exists := c.conversations[conversationID]
+	if !exists {
+		return fmt.Errorf("conversation not found: %s", conversationID)
+	}
+
+	conversation.Summary = summary
+	conversation.History = make([]ChatMessage, 0, len(history))
+	for _, msg := range history {
+		conversation.History = append(conversation.History, ChatMessage{Role: msg.Role, Content: msg.Content})
+	}
+	c.conversations[conversationID] = conversation
+
+	return nil
+}
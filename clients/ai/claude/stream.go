@@ -0,0 +1,252 @@
+package claude
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	aitypes "suasor/clients/ai/types"
+	"suasor/utils/logger"
+	"suasor/utils/sse"
+)
+
+const (
+	defaultAnthropicURL = "https://api.anthropic.com"
+	anthropicVersion    = "2023-06-01"
+	// defaultMaxTokens is used when the config does not set max tokens
+	defaultMaxTokens = 1024
+)
+
+// messagesRequest is the body of POST /v1/messages
+type messagesRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Temperature float64            `json:"temperature,omitempty"`
+	Stream      bool               `json:"stream"`
+	Tools       []toolDefinition   `json:"tools,omitempty"`
+}
+
+// anthropicMessage is a message of the Messages API, Content is a string or a list of content blocks
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// streamEvent is the data of a server-sent event of a streamed message
+type streamEvent struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Delta *struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage *anthropicUsage `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// StreamRecommendationConversation continues an existing conversation, streaming the reply to onDelta
+func (c *ClaudeClient) StreamRecommendationConversation(ctx context.Context, conversationID string, message string, context map[string]any, onDelta aitypes.StreamHandler) (string, []map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("conversationID", conversationID).
+		Msg("Streaming recommendation conversation with Claude")
+
+	conversation, exists := c.conversations[conversationID]
+	if !exists {
+		return "", nil, fmt.Errorf("conversation not found: %s", conversationID)
+	}
+
+	shouldExtractRecommendations, _ := context["extractRecommendations"].(bool)
+
+	messages := historyMessages(conversation)
+	messages = append(messages, anthropicMessage{Role: "user", Content: message})
+
+	aiResponse, usage, err := c.streamMessage(ctx, &messagesRequest{
+		Model:       c.config.AIClientConfig.GetModel(),
+		MaxTokens:   c.messagesMaxTokens(),
+		System:      systemPrompt(conversation, shouldExtractRecommendations),
+		Messages:    messages,
+		Temperature: 0.7,
+	}, onDelta)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to stream conversation with Claude")
+		return "", nil, err
+	}
+
+	log.Debug().
+		Int("promptTokens", usage.PromptTokens).
+		Int("completionTokens", usage.CompletionTokens).
+		Msg("Claude stream finished")
+
+	conversation.History = append(conversation.History,
+		ChatMessage{Role: "user", Content: message},
+		ChatMessage{Role: "assistant", Content: aiResponse},
+	)
+	c.conversations[conversationID] = conversation
+
+	var recommendations []map[string]any
+	if shouldExtractRecommendations {
+		recommendations = c.extractRecommendationsFromText(ctx, aiResponse, conversation.ContentType)
+	}
+
+	return aiResponse, recommendations, nil
+}
+
+// systemPrompt adds the summary of compacted turns and the user's preferences to the conversation's system instructions
+func systemPrompt(conversation ConversationContext, extractRecommendations bool) string {
+	var system strings.Builder
+	system.WriteString(conversation.SystemInstructions)
+	if conversation.Summary != "" {
+		system.WriteString("\n\n")
+		system.WriteString(conversation.Summary)
+	}
+	if len(conversation.UserPreferences) > 0 {
+		system.WriteString("\n\nUser preferences:\n")
+		for k, v := range conversation.UserPreferences {
+			system.WriteString(fmt.Sprintf("- %s: %v\n", k, v))
+		}
+	}
+	if extractRecommendations {
+		system.WriteString("\n\nPlease include specific recommendations in your response. Format each recommendation as a clear item with relevant details.")
+	}
+	return system.String()
+}
+
+// historyMessages converts the conversation history to Messages API messages.
+// The Messages API requires the first message to come from the user, so the welcome message is left out.
+func historyMessages(conversation ConversationContext) []anthropicMessage {
+	messages := make([]anthropicMessage, 0, len(conversation.History)+1)
+	for _, msg := range conversation.History {
+		if len(messages) == 0 && msg.Role != "user" {
+			continue
+		}
+		messages = append(messages, anthropicMessage{Role: msg.Role, Content: msg.Content})
+	}
+	return messages
+}
+
+// messagesMaxTokens is the configured max tokens, the Messages API requires it
+func (c *ClaudeClient) messagesMaxTokens() int {
+	if maxTokens := c.config.AIClientConfig.GetMaxTokens(); maxTokens > 0 {
+		return maxTokens
+	}
+	return defaultMaxTokens
+}
+
+// streamMessage calls POST /v1/messages with streaming, passing each text delta to onDelta.
+// It returns the whole reply and the token usage.
+func (c *ClaudeClient) streamMessage(ctx context.Context, request *messagesRequest, onDelta aitypes.StreamHandler) (string, aitypes.TokenUsage, error) {
+	request.Stream = true
+
+	resp, err := c.postMessages(ctx, request, "text/event-stream")
+	if err != nil {
+		return "", aitypes.TokenUsage{}, err
+	}
+	defer resp.Body.Close()
+
+	var text strings.Builder
+	var usage anthropicUsage
+
+	reader := sse.NewReader(resp.Body)
+	for {
+		sseEvent, err := reader.Next()
+		if err == io.EOF {
+			return "", aitypes.TokenUsage{}, fmt.Errorf("claude stream ended unexpectedly")
+		}
+		if err != nil {
+			return "", aitypes.TokenUsage{}, fmt.Errorf("failed to read claude stream: %w", err)
+		}
+
+		var event streamEvent
+		if err := json.Unmarshal([]byte(sseEvent.Data), &event); err != nil {
+			return "", aitypes.TokenUsage{}, fmt.Errorf("failed to decode claude stream event: %w", err)
+		}
+
+		switch event.Type {
+		case "message_start":
+			if event.Message != nil {
+				usage.InputTokens = event.Message.Usage.InputTokens
+			}
+		case "content_block_delta":
+			if event.Delta == nil || event.Delta.Type != "text_delta" || event.Delta.Text == "" {
+				continue
+			}
+			text.WriteString(event.Delta.Text)
+			if err := onDelta(event.Delta.Text); err != nil {
+				return "", aitypes.TokenUsage{}, err
+			}
+		case "message_delta":
+			if event.Usage != nil {
+				usage.OutputTokens = event.Usage.OutputTokens
+			}
+		case "message_stop":
+			tokenUsage := aitypes.TokenUsage{
+				PromptTokens:     usage.InputTokens,
+				CompletionTokens: usage.OutputTokens,
+				TotalTokens:      usage.InputTokens + usage.OutputTokens,
+			}
+			aitypes.ReportUsage(ctx, request.Model, tokenUsage)
+			return text.String(), tokenUsage, nil
+		case "error":
+			if event.Error != nil {
+				return "", aitypes.TokenUsage{}, fmt.Errorf("claude stream error: %s", event.Error.Message)
+			}
+			return "", aitypes.TokenUsage{}, fmt.Errorf("claude stream error")
+		}
+	}
+}
+
+// postMessages calls POST /v1/messages, non-2xx responses are returned as errors
+func (c *ClaudeClient) postMessages(ctx context.Context, request *messagesRequest, accept string) (*http.Response, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode claude request: %w", err)
+	}
+
+	baseURL := strings.TrimSuffix(strings.TrimRight(c.config.AIClientConfig.GetBaseURL(), "/"), "/v1")
+	if baseURL == "" {
+		baseURL = defaultAnthropicURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/messages", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create claude request: %w", err)
+	}
+	req.Header.Set("x-api-key", c.config.AIClientConfig.GetAPIKey())
+	req.Header.Set("anthropic-version", anthropicVersion)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("claude request failed: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var event streamEvent
+		if data, _ := io.ReadAll(resp.Body); json.Unmarshal(data, &event) == nil && event.Error != nil {
+			return nil, fmt.Errorf("claude returned status %d: %s", resp.StatusCode, event.Error.Message)
+		}
+		return nil, fmt.Errorf("claude returned status %d", resp.StatusCode)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,161 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	aitypes "suasor/clients/ai/types"
+	"suasor/utils/logger"
+)
+
+// toolDefinition describes a tool the model may use
+type toolDefinition struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// contentBlock is a block of a message: text, a tool call or the result of one
+type contentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+// messagesResponse is the response of POST /v1/messages without streaming
+type messagesResponse struct {
+	ID         string         `json:"id"`
+	Model      string         `json:"model"`
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      anthropicUsage `json:"usage"`
+}
+
+// ContinueConversationWithTools sends the message, or the results of the previous turn's tool calls,
+// offering the model the given tools. The conversation history only keeps the user's message and the
+// final reply, the tool calls in between are kept until the turn ends.
+func (c *ClaudeClient) ContinueConversationWithTools(ctx context.Context, conversationID string, message string, results []aitypes.ToolResult, tools []aitypes.Tool) (*aitypes.ToolTurn, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("conversationID", conversationID).
+		Int("tools", len(tools)).
+		Int("toolResults", len(results)).
+		Msg("Continuing conversation with tools on Claude")
+
+	conversation, exists := c.conversations[conversationID]
+	if !exists {
+		return nil, fmt.Errorf("conversation not found: %s", conversationID)
+	}
+
+	exchange := conversation.ToolExchange
+	if len(results) > 0 {
+		if len(exchange) == 0 {
+			return nil, fmt.Errorf("no tool calls pending in conversation: %s", conversationID)
+		}
+		blocks := make([]contentBlock, len(results))
+		for i, result := range results {
+			blocks[i] = contentBlock{
+				Type:      "tool_result",
+				ToolUseID: result.CallID,
+				Content:   result.Content,
+				IsError:   result.IsError,
+			}
+		}
+		exchange = append(exchange, anthropicMessage{Role: "user", Content: blocks})
+	} else {
+		exchange = []anthropicMessage{{Role: "user", Content: message}}
+	}
+
+	definitions := make([]toolDefinition, len(tools))
+	for i, tool := range tools {
+		definitions[i] = toolDefinition{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: tool.Parameters,
+		}
+	}
+
+	response, err := c.createMessage(ctx, &messagesRequest{
+		Model:       c.config.AIClientConfig.GetModel(),
+		MaxTokens:   c.messagesMaxTokens(),
+		System:      systemPrompt(conversation, false),
+		Messages:    append(historyMessages(conversation), exchange...),
+		Temperature: 0.7,
+		Tools:       definitions,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to continue conversation with tools on Claude")
+		return nil, err
+	}
+
+	turn := &aitypes.ToolTurn{
+		TokenUsage: aitypes.TokenUsage{
+			PromptTokens:     response.Usage.InputTokens,
+			CompletionTokens: response.Usage.OutputTokens,
+			TotalTokens:      response.Usage.InputTokens + response.Usage.OutputTokens,
+		},
+	}
+	var text strings.Builder
+	for _, block := range response.Content {
+		switch block.Type {
+		case "text":
+			text.WriteString(block.Text)
+		case "tool_use":
+			turn.ToolCalls = append(turn.ToolCalls, aitypes.ToolCall{
+				ID:        block.ID,
+				Name:      block.Name,
+				Arguments: block.Input,
+			})
+		}
+	}
+	turn.Text = text.String()
+
+	if len(turn.ToolCalls) > 0 {
+		conversation.ToolExchange = append(exchange, anthropicMessage{Role: "assistant", Content: response.Content})
+	} else {
+		// The turn is over, the user's message is the first message of the exchange
+		conversation.History = append(conversation.History,
+			ChatMessage{Role: "user", Content: exchange[0].Content.(string)},
+			ChatMessage{Role: "assistant", Content: turn.Text},
+		)
+		conversation.ToolExchange = nil
+	}
+	c.conversations[conversationID] = conversation
+
+	return turn, nil
+}
+
+// createMessage calls POST /v1/messages without streaming
+func (c *ClaudeClient) createMessage(ctx context.Context, request *messagesRequest) (*messagesResponse, error) {
+	resp, err := c.postMessages(ctx, request, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response messagesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode claude response: %w", err)
+	}
+
+	log := logger.LoggerFromContext(ctx)
+	log.Debug().
+		Str("model", response.Model).
+		Int("promptTokens", response.Usage.InputTokens).
+		Int("completionTokens", response.Usage.OutputTokens).
+		Str("stopReason", response.StopReason).
+		Msg("Claude message finished")
+
+	aitypes.ReportUsage(ctx, response.Model, aitypes.TokenUsage{
+		PromptTokens:     response.Usage.InputTokens,
+		CompletionTokens: response.Usage.OutputTokens,
+	})
+	return &response, nil
+}
@@ -32,6 +32,17 @@ type ClientAI interface {
 
 	StartRecommendationConversation(ctx context.Context, contentType string, preferences map[string]any, systemInstructions string) (string, string, error)
 	ContinueRecommendationConversation(ctx context.Context, conversationID string, message string, context map[string]any) (string, []map[string]any, error)
+	// StreamRecommendationConversation is ContinueRecommendationConversation with the reply passed to onDelta as it is generated.
+	// It returns the full reply and the extracted recommendations once the stream ends.
+	StreamRecommendationConversation(ctx context.Context, conversationID string, message string, context map[string]any, onDelta aitypes.StreamHandler) (string, []map[string]any, error)
+	// ContinueConversationWithTools sends the message, or the results of the previous turn's tool calls when
+	// results is set, offering the model the given tools. The conversation keeps the tool calls in its history.
+	ContinueConversationWithTools(ctx context.Context, conversationID string, message string, results []aitypes.ToolResult, tools []aitypes.Tool) (*aitypes.ToolTurn, error)
+	// CompactConversation replaces the history of a conversation with a summary of its earlier turns and the
+	// messages since. The summary is sent with the system instructions from then on, an empty summary removes it.
+	CompactConversation(ctx context.Context, conversationID string, summary string, history []aitypes.ChatMessage) error
+	// CreateEmbeddings returns a vector for each text, using the client's default embedding model when model is empty
+	CreateEmbeddings(ctx context.Context, texts []string, model string) (*aitypes.EmbeddingResponse, error)
 
 	// Information methods
 	GetSupportedModels() []string
@@ -114,3 +125,15 @@ func (b *clientAI) StartRecommendationConversation(ctx context.Context, contentT
 func (b *clientAI) ContinueRecommendationConversation(ctx context.Context, conversationID string, message string, context map[string]any) (string, []map[string]any, error) {
 	return "", nil, ErrFeatureNotSupported
 }
+func (b *clientAI) StreamRecommendationConversation(ctx context.Context, conversationID string, message string, context map[string]any, onDelta aitypes.StreamHandler) (string, []map[string]any, error) {
+	return "", nil, ErrFeatureNotSupported
+}
+func (b *clientAI) ContinueConversationWithTools(ctx context.Context, conversationID string, message string, results []aitypes.ToolResult, tools []aitypes.Tool) (*aitypes.ToolTurn, error) {
+	return nil, ErrFeatureNotSupported
+}
+func (b *clientAI) CompactConversation(ctx context.Context, conversationID string, summary string, history []aitypes.ChatMessage) error {
+	return ErrFeatureNotSupported
+}
+func (b *clientAI) CreateEmbeddings(ctx context.Context, texts []string, model string) (*aitypes.EmbeddingResponse, error) {
+	return nil, ErrFeatureNotSupported
+}
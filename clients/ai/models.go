@@ -0,0 +1,18 @@
+package ai
+
+import "strings"
+
+// SupportedModel returns model when the client supports it, or "" so the client uses its default model.
+// A model preference names the model of one provider, which the other providers of a fallback chain do not have.
+func SupportedModel(client ClientAI, model string) string {
+	if model == "" {
+		return ""
+	}
+	for _, supported := range client.GetSupportedModels() {
+		// Ollama lists its models with a tag, "llama3" is "llama3:latest"
+		if supported == model || strings.HasPrefix(supported, model+":") {
+			return model
+		}
+	}
+	return ""
+}
@@ -0,0 +1,25 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type modelsClient struct {
+	ClientAI
+	models []string
+}
+
+func (c modelsClient) GetSupportedModels() []string {
+	return c.models
+}
+
+func TestSupportedModel(t *testing.T) {
+	client := modelsClient{models: []string{"gpt-4o", "llama3.2:latest"}}
+
+	assert.Equal(t, "gpt-4o", SupportedModel(client, "gpt-4o"))
+	assert.Equal(t, "llama3.2", SupportedModel(client, "llama3.2"))
+	assert.Equal(t, "", SupportedModel(client, "claude-3-opus-20240229"))
+	assert.Equal(t, "", SupportedModel(client, ""))
+}
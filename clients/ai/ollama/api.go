@@ -0,0 +1,128 @@
+package ollama
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// chatMessage is a message in the /api/chat format
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+	// ToolCalls is set on assistant messages that call tools
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+	// ToolName is set on "tool" messages, Ollama has no call IDs
+	ToolName string `json:"tool_name,omitempty"`
+}
+
+// toolCall is a function call requested by the model, unlike OpenAI the arguments are an object
+type toolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// toolDefinition describes a function the model may call
+type toolDefinition struct {
+	Type     string       `json:"type"`
+	Function toolFunction `json:"function"`
+}
+
+type toolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+// modelOptions are the model parameters Ollama accepts per request
+type modelOptions struct {
+	Temperature float64  `json:"temperature,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+	NumCtx      int      `json:"num_ctx,omitempty"`
+	TopP        float64  `json:"top_p,omitempty"`
+	TopK        int      `json:"top_k,omitempty"`
+	Seed        int      `json:"seed,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+}
+
+// chatRequest is the body of POST /api/chat
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+	// Format is "json" for JSON mode
+	Format  string        `json:"format,omitempty"`
+	Options *modelOptions `json:"options,omitempty"`
+	// Tools the model may call, only models trained for tool use accept them
+	Tools []toolDefinition `json:"tools,omitempty"`
+}
+
+// chatResponse is the response of POST /api/chat without streaming
+type chatResponse struct {
+	Model           string      `json:"model"`
+	CreatedAt       time.Time   `json:"created_at"`
+	Message         chatMessage `json:"message"`
+	Done            bool        `json:"done"`
+	DoneReason      string      `json:"done_reason"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	EvalCount       int         `json:"eval_count"`
+}
+
+// generateRequest is the body of POST /api/generate
+type generateRequest struct {
+	Model   string        `json:"model"`
+	Prompt  string        `json:"prompt"`
+	System  string        `json:"system,omitempty"`
+	Stream  bool          `json:"stream"`
+	Format  string        `json:"format,omitempty"`
+	Options *modelOptions `json:"options,omitempty"`
+}
+
+// generateResponse is the response of POST /api/generate without streaming
+type generateResponse struct {
+	Model           string    `json:"model"`
+	CreatedAt       time.Time `json:"created_at"`
+	Response        string    `json:"response"`
+	Done            bool      `json:"done"`
+	DoneReason      string    `json:"done_reason"`
+	PromptEvalCount int       `json:"prompt_eval_count"`
+	EvalCount       int       `json:"eval_count"`
+}
+
+// embedRequest is the body of POST /api/embed
+type embedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embedResponse is the response of POST /api/embed
+type embedResponse struct {
+	Model           string      `json:"model"`
+	Embeddings      [][]float32 `json:"embeddings"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+}
+
+// tagsResponse is the response of GET /api/tags, the models pulled on the server
+type tagsResponse struct {
+	Models []struct {
+		Name       string    `json:"name"`
+		Model      string    `json:"model"`
+		Size       int64     `json:"size"`
+		ModifiedAt time.Time `json:"modified_at"`
+	} `json:"models"`
+}
+
+// APIError is an error returned by the Ollama server
+type APIError struct {
+	StatusCode int
+	Message    string `json:"error"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ollama returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("ollama returned status %d: %s", e.StatusCode, e.Message)
+}
@@ -0,0 +1,865 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"suasor/clients"
+	"suasor/clients/ai"
+	aitypes "suasor/clients/ai/types"
+	"suasor/clients/types"
+	"suasor/utils"
+	"suasor/utils/logger"
+)
+
+const (
+	defaultBaseURL = "http://localhost:11434"
+	defaultModel   = "llama3.2"
+	// defaultEmbeddingModel is used by CreateEmbeddings when no model is given
+	defaultEmbeddingModel = "nomic-embed-text"
+	// Local models can be slow, especially on the first request when the model is loaded
+	requestTimeout = 5 * time.Minute
+	// modelListTimeout bounds the /api/tags call of GetSupportedModels, which has no context
+	modelListTimeout = 5 * time.Second
+	// modelListTTL is how long GetSupportedModels reuses the model list before asking the server again
+	modelListTTL = 5 * time.Minute
+)
+
+// OllamaClient implements the AI client interface on top of a local Ollama server
+type OllamaClient struct {
+	clients.Client
+	config     types.OllamaConfig
+	baseURL    string
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	conversations map[string]*ConversationContext
+
+	// models is the model list last fetched from the server, guarded by modelsMu
+	modelsMu        sync.Mutex
+	models          []string
+	modelsFetchedAt time.Time
+}
+
+// ConversationContext tracks the state of a conversation.
+// Messages are sent in full on every turn, /api/chat itself is stateless.
+type ConversationContext struct {
+	ContentType     string
+	UserPreferences map[string]any
+	Messages        []chatMessage
+}
+
+// NewOllamaClient creates a new Ollama client instance
+func NewOllamaClient(ctx context.Context, clientID uint64, cfg types.OllamaConfig) (ai.ClientAI, error) {
+	if cfg.AIClientConfig == nil {
+		return nil, fmt.Errorf("ollama client %d has no configuration", clientID)
+	}
+
+	c := &OllamaClient{
+		Client:        clients.NewClient(clientID, types.ClientCategoryAI, &cfg),
+		config:        cfg,
+		baseURL:       strings.TrimRight(cfg.AIClientConfig.GetBaseURL(), "/"),
+		httpClient:    &http.Client{Timeout: requestTimeout},
+		conversations: make(map[string]*ConversationContext),
+	}
+	if c.baseURL == "" {
+		c.baseURL = defaultBaseURL
+	}
+
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Uint64("clientID", clientID).
+		Str("model", c.model()).
+		Str("baseURL", c.baseURL).
+		Msg("Creating new Ollama client")
+
+	return c, nil
+}
+
+// TestConnection checks the server is reachable and warns when the configured model is not pulled
+func (c *OllamaClient) TestConnection(ctx context.Context) (bool, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().Msg("Testing Ollama connection")
+
+	models, err := c.listModels(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Ollama connection test failed")
+		return false, err
+	}
+
+	if !containsModel(models, c.model()) {
+		log.Warn().
+			Str("model", c.model()).
+			Strs("available", models).
+			Msg("Configured model is not pulled on the Ollama server")
+	}
+
+	log.Info().Int("models", len(models)).Msg("Ollama connection test successful")
+	return true, nil
+}
+
+// GenerateText sends a prompt to Ollama and returns the response
+func (c *OllamaClient) GenerateText(ctx context.Context, promptText string, options *aitypes.GenerationOptions) (string, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	request := &generateRequest{
+		Model:   c.model(),
+		Prompt:  promptText,
+		Options: c.modelOptions(options),
+	}
+	if options != nil {
+		request.System = options.SystemInstructions
+		if options.ResponseFormat == "json" {
+			request.Format = "json"
+		}
+	}
+
+	response, err := c.generate(ctx, request)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate text with Ollama")
+		return "", fmt.Errorf("ollama text generation failed: %w", err)
+	}
+
+	return response.Response, nil
+}
+
+// GenerateStructured generates JSON output and decodes it into outputSchema.
+// The schema is described in the prompt and the reply is constrained with JSON mode.
+func (c *OllamaClient) GenerateStructured(ctx context.Context, promptText string, outputSchema any, options *aitypes.GenerationOptions) error {
+	_, err := c.generateStructured(ctx, promptText, outputSchema, options)
+	return err
+}
+
+func (c *OllamaClient) generateStructured(ctx context.Context, promptText string, outputSchema any, options *aitypes.GenerationOptions) (aitypes.TokenUsage, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	jsonOptions := &aitypes.GenerationOptions{
+		Temperature: 0.2, // Lower temperature for more predictable output
+	}
+	if options != nil {
+		if options.MaxTokens > 0 {
+			jsonOptions.MaxTokens = options.MaxTokens
+		}
+		if options.Temperature > 0 {
+			jsonOptions.Temperature = options.Temperature
+		}
+		jsonOptions.SystemInstructions = options.SystemInstructions
+	}
+	if jsonOptions.SystemInstructions == "" {
+		jsonOptions.SystemInstructions = "You are a helpful assistant that responds only with valid JSON. Do not include any explanations, markdown formatting, or text outside of the JSON structure."
+	}
+
+	schema, wrapped := ai.StructuredSchema(outputSchema)
+	if schema != nil {
+		schemaJSON, err := json.Marshal(schema)
+		if err != nil {
+			return aitypes.TokenUsage{}, fmt.Errorf("failed to encode output schema: %w", err)
+		}
+		promptText += "\n\nRespond with a JSON object matching this JSON schema:\n" + string(schemaJSON)
+	} else {
+		promptText += "\n\nRespond with a JSON object."
+	}
+
+	response, err := c.generate(ctx, &generateRequest{
+		Model:   c.model(),
+		Prompt:  promptText,
+		System:  jsonOptions.SystemInstructions,
+		Format:  "json",
+		Options: c.modelOptions(jsonOptions),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate structured output with Ollama")
+		return aitypes.TokenUsage{}, fmt.Errorf("ollama structured generation failed: %w", err)
+	}
+	usage := tokenUsage(response.PromptEvalCount, response.EvalCount)
+
+	if err := ai.DecodeStructured([]byte(response.Response), wrapped, outputSchema); err != nil {
+		log.Error().Err(err).Str("response", response.Response).Msg("Failed to parse Ollama response as JSON")
+		return usage, fmt.Errorf("failed to parse Ollama JSON response: %w", err)
+	}
+
+	return usage, nil
+}
+
+// StartConversation begins a new conversation
+func (c *OllamaClient) StartConversation(ctx context.Context, systemInstructions string) (string, error) {
+	conversationID := fmt.Sprintf("conv-%d-%s", c.GetClientID(), utils.GenerateRandomID(12))
+
+	conversation := &ConversationContext{}
+	if systemInstructions != "" {
+		conversation.Messages = append(conversation.Messages, chatMessage{Role: "system", Content: systemInstructions})
+	}
+
+	c.mu.Lock()
+	c.conversations[conversationID] = conversation
+	c.mu.Unlock()
+
+	return conversationID, nil
+}
+
+// SendMessage sends a message in an existing conversation
+func (c *OllamaClient) SendMessage(ctx context.Context, conversationID string, message string) (string, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	reply, _, err := c.sendConversationMessage(ctx, conversationID, message, nil, nil)
+	if err != nil {
+		log.Error().Err(err).Str("conversationID", conversationID).Msg("Failed to send message to Ollama")
+		return "", fmt.Errorf("ollama message send failed: %w", err)
+	}
+
+	return reply, nil
+}
+
+// sendConversationMessage adds the message to the conversation, sends the whole history and records the reply.
+// The reply is streamed to onDelta when it is set. The conversation is only updated when the request succeeds.
+func (c *OllamaClient) sendConversationMessage(ctx context.Context, conversationID string, message string, options *aitypes.GenerationOptions, onDelta aitypes.StreamHandler) (string, aitypes.TokenUsage, error) {
+	c.mu.Lock()
+	conversation, exists := c.conversations[conversationID]
+	if !exists {
+		c.mu.Unlock()
+		return "", aitypes.TokenUsage{}, fmt.Errorf("conversation not found: %s", conversationID)
+	}
+	messages := make([]chatMessage, len(conversation.Messages), len(conversation.Messages)+1)
+	copy(messages, conversation.Messages)
+	c.mu.Unlock()
+
+	userMessage := chatMessage{Role: "user", Content: message}
+	request := &chatRequest{
+		Model:    c.model(),
+		Messages: append(messages, userMessage),
+		Options:  c.modelOptions(options),
+	}
+
+	var response *chatResponse
+	var err error
+	if onDelta != nil {
+		response, err = c.streamChat(ctx, request, onDelta)
+	} else {
+		response, err = c.chat(ctx, request)
+	}
+	if err != nil {
+		return "", aitypes.TokenUsage{}, err
+	}
+	reply := response.Message.Content
+
+	c.mu.Lock()
+	conversation.Messages = append(conversation.Messages, userMessage, chatMessage{Role: "assistant", Content: reply})
+	c.mu.Unlock()
+
+	return reply, tokenUsage(response.PromptEvalCount, response.EvalCount), nil
+}
+
+// GetSupportedModels returns the models pulled on the Ollama server, the list is fetched again after modelListTTL.
+// The last list, or just the configured model, is returned when the server cannot be reached.
+func (c *OllamaClient) GetSupportedModels() []string {
+	c.modelsMu.Lock()
+	fresh := len(c.models) > 0 && time.Since(c.modelsFetchedAt) < modelListTTL
+	c.modelsMu.Unlock()
+	if fresh {
+		return c.knownModels()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), modelListTimeout)
+	defer cancel()
+
+	models, err := c.listModels(ctx)
+	if err == nil && len(models) > 0 {
+		c.modelsMu.Lock()
+		c.models = models
+		c.modelsFetchedAt = time.Now()
+		c.modelsMu.Unlock()
+	}
+	return c.knownModels()
+}
+
+// knownModels returns the model list last fetched by GetSupportedModels, or the configured model before the first fetch
+func (c *OllamaClient) knownModels() []string {
+	c.modelsMu.Lock()
+	defer c.modelsMu.Unlock()
+	if len(c.models) == 0 {
+		return []string{c.model()}
+	}
+	return append([]string(nil), c.models...)
+}
+
+// GetCapabilities returns the capabilities of this Ollama client.
+// It doesn't call the server, AvailableModels is the list last fetched by GetSupportedModels.
+func (c *OllamaClient) GetCapabilities() *aitypes.AICapabilities {
+	return &aitypes.AICapabilities{
+		SupportsStructuredOutput: true,
+		SupportsConversation:     true,
+		SupportsStreaming:        true,
+		SupportsToolCalling:      true,
+		SupportsEmbeddings:       true,
+		EmbeddingModel:           defaultEmbeddingModel,
+		MaxContextTokens:         c.config.AIClientConfig.GetMaxContextTokens(),
+		DefaultMaxTokens:         c.config.AIClientConfig.GetMaxTokens(),
+		AvailableModels:          c.knownModels(),
+	}
+}
+
+// recommendationOutput is the structured output requested for recommendations
+type recommendationOutput struct {
+	Items       []aitypes.RecommendationItem `json:"items"`
+	Explanation string                       `json:"explanation"`
+}
+
+// GetRecommendations implements the AIClient interface to get content recommendations
+func (c *OllamaClient) GetRecommendations(ctx context.Context, request *aitypes.RecommendationRequest) (*aitypes.RecommendationResponse, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("mediaType", request.MediaType).
+		Int("count", request.Count).
+		Msg("Getting recommendations from Ollama")
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Please recommend %d %s items.\n", request.Count, request.MediaType)
+
+	if len(request.UserPreferences) > 0 {
+		prompt.WriteString("\nConsider these preferences:\n")
+		for k, v := range request.UserPreferences {
+			fmt.Fprintf(&prompt, "- %s: %v\n", k, v)
+		}
+	}
+	if len(request.IncludeSimilarTo) > 0 {
+		prompt.WriteString("\nRecommend items similar to:\n")
+		for _, id := range request.IncludeSimilarTo {
+			fmt.Fprintf(&prompt, "- %s\n", id)
+		}
+	}
+	if len(request.ExcludeIDs) > 0 {
+		prompt.WriteString("\nPlease exclude the following items:\n")
+		for _, id := range request.ExcludeIDs {
+			fmt.Fprintf(&prompt, "- %s\n", id)
+		}
+	}
+	if request.AdditionalContext != "" {
+		prompt.WriteString("\n" + request.AdditionalContext + "\n")
+	}
+	prompt.WriteString("\nFor each item give the title, release year, genres, a brief reason why it is recommended, " +
+		"the average rating (0-10 scale), a popularity rank (1-100, where 1 is most popular) and a brief description. " +
+		"Add a one sentence explanation of the overall selection.")
+
+	genOptions := &aitypes.GenerationOptions{
+		Temperature:        0.4,
+		MaxTokens:          2000,
+		SystemInstructions: fmt.Sprintf("You are a helpful recommendation system specialized in %s. Provide detailed and personalized recommendations based on the user's preferences. Respond only with JSON.", request.MediaType),
+	}
+	if request.GenerationOptions != nil {
+		if request.GenerationOptions.Temperature > 0 {
+			genOptions.Temperature = request.GenerationOptions.Temperature
+		}
+		if request.GenerationOptions.MaxTokens > 0 {
+			genOptions.MaxTokens = request.GenerationOptions.MaxTokens
+		}
+		if request.GenerationOptions.SystemInstructions != "" {
+			genOptions.SystemInstructions = request.GenerationOptions.SystemInstructions
+		}
+	}
+
+	var output recommendationOutput
+	usage, err := c.generateStructured(ctx, prompt.String(), &output, genOptions)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get recommendations from Ollama")
+		return nil, err
+	}
+
+	if request.Count > 0 && len(output.Items) > request.Count {
+		output.Items = output.Items[:request.Count]
+	}
+
+	explanation := output.Explanation
+	if explanation == "" {
+		explanation = "Recommendations generated based on user preferences using Ollama"
+	}
+
+	return &aitypes.RecommendationResponse{
+		Items:       output.Items,
+		Explanation: explanation,
+		TokenUsage:  usage,
+	}, nil
+}
+
+// AnalyzeContent implements the AIClient interface to analyze content
+func (c *OllamaClient) AnalyzeContent(ctx context.Context, contentType string, content string, options map[string]any) (map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("contentType", contentType).
+		Int("contentLength", len(content)).
+		Interface("options", options).
+		Msg("Analyzing content with Ollama")
+
+	prompt := fmt.Sprintf("Please analyze the following %s content:\n\n%s\n\n", contentType, content)
+	if include, ok := options["includeThemes"].(bool); ok && include {
+		prompt += "Include main themes and motifs. "
+	}
+	if include, ok := options["includeSentiment"].(bool); ok && include {
+		prompt += "Analyze the sentiment. "
+	}
+	if include, ok := options["includeStyleAnalysis"].(bool); ok && include {
+		prompt += "Analyze the stylistic elements. "
+	}
+	prompt += "\nReturn the analysis with appropriate fields for the requested analysis."
+
+	var analysis map[string]any
+	_, err := c.generateStructured(ctx, prompt, &analysis, &aitypes.GenerationOptions{
+		Temperature:        0.3,
+		MaxTokens:          2000,
+		SystemInstructions: fmt.Sprintf("You are an expert at analyzing %s content. Provide detailed, insightful analysis as JSON.", contentType),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to analyze content with Ollama")
+		return nil, err
+	}
+
+	return analysis, nil
+}
+
+// StartRecommendationConversation starts a conversational recommendation session
+func (c *OllamaClient) StartRecommendationConversation(ctx context.Context, contentType string, preferences map[string]any, systemInstructions string) (string, string, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("contentType", contentType).
+		Interface("preferences", preferences).
+		Msg("Starting recommendation conversation with Ollama")
+
+	conversationID := fmt.Sprintf("rec-%d-%s", c.GetClientID(), utils.GenerateRandomID(12))
+
+	if systemInstructions == "" {
+		systemInstructions = fmt.Sprintf(
+			"You are an expert %s recommendation assistant. Your goal is to help the user discover %s they'll love based on their preferences and interests. "+
+				"Maintain a friendly, conversational tone. Ask questions to understand their preferences better. "+
+				"When recommending items, provide a brief explanation of why you're recommending them based on the user's preferences.",
+			contentType, contentType)
+	}
+	if len(preferences) > 0 {
+		var b strings.Builder
+		b.WriteString(systemInstructions)
+		b.WriteString("\n\nUser preferences:\n")
+		for k, v := range preferences {
+			fmt.Fprintf(&b, "- %s: %v\n", k, v)
+		}
+		systemInstructions = b.String()
+	}
+
+	welcomeMessage := welcomeMessage(contentType, preferences)
+
+	c.mu.Lock()
+	c.conversations[conversationID] = &ConversationContext{
+		ContentType:     contentType,
+		UserPreferences: preferences,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemInstructions},
+			{Role: "assistant", Content: welcomeMessage},
+		},
+	}
+	c.mu.Unlock()
+
+	return conversationID, welcomeMessage, nil
+}
+
+// welcomeMessage greets the user, mentioning their favourite genres and recent items when known
+func welcomeMessage(contentType string, preferences map[string]any) string {
+	message := fmt.Sprintf("Hi there! I'm your %s recommendation assistant. ", contentType)
+
+	if genres, ok := preferences["favoriteGenres"].([]any); ok && len(genres) > 0 {
+		message += fmt.Sprintf("I see you enjoy %s like %s. ", contentType, joinList(genres))
+	}
+	if recent, ok := preferences["recentlyWatched"].([]any); ok && len(recent) > 0 {
+		message += fmt.Sprintf("You've recently enjoyed %s. ", joinList(recent))
+	}
+
+	return message + fmt.Sprintf("What kind of %s are you in the mood for today?", contentType)
+}
+
+// joinList joins values as "a, b and c"
+func joinList(values []any) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprintf("%v", value)
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// ContinueRecommendationConversation continues an existing conversation with a new message
+func (c *OllamaClient) ContinueRecommendationConversation(ctx context.Context, conversationID string, message string, context map[string]any) (string, []map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("conversationID", conversationID).
+		Msg("Continuing recommendation conversation with Ollama")
+
+	return c.continueRecommendationConversation(ctx, conversationID, message, context, nil)
+}
+
+// StreamRecommendationConversation continues an existing conversation, streaming the reply to onDelta
+func (c *OllamaClient) StreamRecommendationConversation(ctx context.Context, conversationID string, message string, context map[string]any, onDelta aitypes.StreamHandler) (string, []map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("conversationID", conversationID).
+		Msg("Streaming recommendation conversation with Ollama")
+
+	return c.continueRecommendationConversation(ctx, conversationID, message, context, onDelta)
+}
+
+func (c *OllamaClient) continueRecommendationConversation(ctx context.Context, conversationID string, message string, context map[string]any, onDelta aitypes.StreamHandler) (string, []map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	shouldExtractRecommendations, _ := context["extractRecommendations"].(bool)
+
+	prompt := message
+	if shouldExtractRecommendations {
+		prompt += "\n\nPlease include specific recommendations in your response. Format each recommendation as a clear item with relevant details."
+	}
+
+	aiResponse, _, err := c.sendConversationMessage(ctx, conversationID, prompt, &aitypes.GenerationOptions{
+		Temperature: 0.7,
+		MaxTokens:   1000,
+	}, onDelta)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to continue conversation with Ollama")
+		return "", nil, err
+	}
+
+	var recommendations []map[string]any
+	if shouldExtractRecommendations {
+		c.mu.Lock()
+		contentType := c.conversations[conversationID].ContentType
+		c.mu.Unlock()
+		recommendations = c.extractRecommendationsFromText(ctx, aiResponse, contentType)
+	}
+
+	return aiResponse, recommendations, nil
+}
+
+// extractRecommendationsFromText parses the AI response to extract structured recommendations
+func (c *OllamaClient) extractRecommendationsFromText(ctx context.Context, text string, contentType string) []map[string]any {
+	log := logger.LoggerFromContext(ctx)
+
+	extractPrompt := fmt.Sprintf(
+		"From the following assistant response, extract the %s recommendations as structured data:\n\n%s\n\n"+
+			"Return a \"recommendations\" array. Each object should have appropriate fields for %s items.",
+		contentType, text, contentType)
+
+	var output struct {
+		Recommendations []map[string]any `json:"recommendations"`
+	}
+	_, err := c.generateStructured(ctx, extractPrompt, &output, &aitypes.GenerationOptions{
+		Temperature:        0.1, // Low temperature for deterministic extraction
+		MaxTokens:          1000,
+		SystemInstructions: "You are a helpful data extraction assistant. Your job is to extract structured recommendations from text.",
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to extract recommendations from text")
+		return []map[string]any{}
+	}
+
+	return output.Recommendations
+}
+
+// CreateMessage implements the AIClient interface for Ollama
+func (c *OllamaClient) CreateMessage(ctx context.Context, messageRequest aitypes.MessageRequest) (*aitypes.MessageResponse, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("model", messageRequest.Model).
+		Int("messageCount", len(messageRequest.Messages)).
+		Msg("Creating message with Ollama")
+
+	request := &chatRequest{
+		Model: c.model(),
+		Options: c.modelOptions(&aitypes.GenerationOptions{
+			Temperature: messageRequest.Temperature,
+			MaxTokens:   messageRequest.MaxTokens,
+		}),
+	}
+	if messageRequest.Model != "" {
+		request.Model = messageRequest.Model
+	}
+	request.Options.TopP = messageRequest.TopP
+	request.Options.TopK = messageRequest.TopK
+	request.Options.Seed = messageRequest.Seed
+	request.Options.Stop = messageRequest.Stop
+	if messageRequest.ResponseFormat.Type == "json" || messageRequest.ResponseFormat.Type == "json_object" {
+		request.Format = "json"
+	}
+
+	if messageRequest.System != "" {
+		request.Messages = append(request.Messages, chatMessage{Role: "system", Content: messageRequest.System})
+	}
+	for _, msg := range messageRequest.Messages {
+		var text strings.Builder
+		for _, content := range msg.Content {
+			if content.Type == "text" {
+				if text.Len() > 0 {
+					text.WriteString("\n\n")
+				}
+				text.WriteString(content.Text)
+			}
+		}
+		request.Messages = append(request.Messages, chatMessage{Role: msg.Role, Content: text.String()})
+	}
+
+	response, err := c.chat(ctx, request)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create message with Ollama")
+		return nil, err
+	}
+
+	return &aitypes.MessageResponse{
+		ID:         utils.GenerateRandomID(16),
+		Object:     "message",
+		Created:    response.CreatedAt.Unix(),
+		Model:      response.Model,
+		Content:    []aitypes.MessageContent{{Type: "text", Text: response.Message.Content}},
+		Role:       "assistant",
+		TokenUsage: tokenUsage(response.PromptEvalCount, response.EvalCount),
+	}, nil
+}
+
+// GenerateContent implements the AIClient interface for generating content with Ollama
+func (c *OllamaClient) GenerateContent(ctx context.Context, systemPrompt string, userPrompt string, model string, options map[string]any) (*aitypes.ContentResponse, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("model", model).
+		Int("userPromptLength", len(userPrompt)).
+		Int("systemPromptLength", len(systemPrompt)).
+		Msg("Generating content with Ollama")
+
+	genOptions := &aitypes.GenerationOptions{}
+	if temp, ok := options["temperature"].(float64); ok {
+		genOptions.Temperature = temp
+	}
+	if maxTokens, ok := options["max_tokens"].(int); ok {
+		genOptions.MaxTokens = maxTokens
+	}
+
+	request := &generateRequest{
+		Model:   c.model(),
+		Prompt:  userPrompt,
+		System:  systemPrompt,
+		Options: c.modelOptions(genOptions),
+	}
+	if model != "" {
+		request.Model = model
+	}
+
+	response, err := c.generate(ctx, request)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate content with Ollama")
+		return nil, fmt.Errorf("ollama content generation failed: %w", err)
+	}
+
+	return &aitypes.ContentResponse{
+		Text:       response.Response,
+		TokenUsage: tokenUsage(response.PromptEvalCount, response.EvalCount),
+	}, nil
+}
+
+// modelOptions uses the configured temperature, max tokens and context size unless the options set them
+func (c *OllamaClient) modelOptions(options *aitypes.GenerationOptions) *modelOptions {
+	modelOptions := &modelOptions{
+		Temperature: c.temperature(),
+		NumPredict:  c.config.AIClientConfig.GetMaxTokens(),
+		NumCtx:      c.config.AIClientConfig.GetMaxContextTokens(),
+	}
+	if options != nil {
+		if options.Temperature > 0 {
+			modelOptions.Temperature = options.Temperature
+		}
+		if options.MaxTokens > 0 {
+			modelOptions.NumPredict = options.MaxTokens
+		}
+	}
+	return modelOptions
+}
+
+// chat calls POST /api/chat
+func (c *OllamaClient) chat(ctx context.Context, request *chatRequest) (*chatResponse, error) {
+	var response chatResponse
+	if err := c.doRequest(ctx, http.MethodPost, "/api/chat", request, &response); err != nil {
+		return nil, err
+	}
+
+	log := logger.LoggerFromContext(ctx)
+	log.Debug().
+		Str("model", response.Model).
+		Int("promptTokens", response.PromptEvalCount).
+		Int("completionTokens", response.EvalCount).
+		Str("doneReason", response.DoneReason).
+		Msg("Ollama chat finished")
+
+	aitypes.ReportUsage(ctx, response.Model, tokenUsage(response.PromptEvalCount, response.EvalCount))
+	return &response, nil
+}
+
+// streamChat calls POST /api/chat with streaming, passing each content delta to onDelta.
+// Ollama streams one JSON object per line, the last one has done set and carries the token counts.
+func (c *OllamaClient) streamChat(ctx context.Context, request *chatRequest, onDelta aitypes.StreamHandler) (*chatResponse, error) {
+	request.Stream = true
+
+	resp, err := c.send(ctx, http.MethodPost, "/api/chat", request, "application/x-ndjson")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var content strings.Builder
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var chunk chatResponse
+		if err := decoder.Decode(&chunk); err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("ollama stream ended unexpectedly")
+			}
+			return nil, fmt.Errorf("failed to decode ollama stream chunk: %w", err)
+		}
+
+		if chunk.Message.Content != "" {
+			content.WriteString(chunk.Message.Content)
+			if err := onDelta(chunk.Message.Content); err != nil {
+				return nil, err
+			}
+		}
+
+		if chunk.Done {
+			chunk.Message = chatMessage{Role: "assistant", Content: content.String()}
+			aitypes.ReportUsage(ctx, chunk.Model, tokenUsage(chunk.PromptEvalCount, chunk.EvalCount))
+			return &chunk, nil
+		}
+	}
+}
+
+// generate calls POST /api/generate
+func (c *OllamaClient) generate(ctx context.Context, request *generateRequest) (*generateResponse, error) {
+	var response generateResponse
+	if err := c.doRequest(ctx, http.MethodPost, "/api/generate", request, &response); err != nil {
+		return nil, err
+	}
+
+	log := logger.LoggerFromContext(ctx)
+	log.Debug().
+		Str("model", response.Model).
+		Int("promptTokens", response.PromptEvalCount).
+		Int("completionTokens", response.EvalCount).
+		Str("doneReason", response.DoneReason).
+		Msg("Ollama generation finished")
+
+	aitypes.ReportUsage(ctx, response.Model, tokenUsage(response.PromptEvalCount, response.EvalCount))
+	return &response, nil
+}
+
+// listModels calls GET /api/tags and returns the names of the pulled models
+func (c *OllamaClient) listModels(ctx context.Context) ([]string, error) {
+	var tags tagsResponse
+	if err := c.doRequest(ctx, http.MethodGet, "/api/tags", nil, &tags); err != nil {
+		return nil, err
+	}
+
+	models := make([]string, 0, len(tags.Models))
+	for _, model := range tags.Models {
+		models = append(models, model.Name)
+	}
+	return models, nil
+}
+
+// doRequest sends a JSON request to the server and decodes the JSON response into out
+func (c *OllamaClient) doRequest(ctx context.Context, method string, path string, body any, out any) error {
+	resp, err := c.send(ctx, method, path, body, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode ollama response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// send sends a JSON request to the server and returns the response when its status is successful
+func (c *OllamaClient) send(ctx context.Context, method string, path string, body any, accept string) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode ollama request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ollama request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// Ollama has no authentication itself, but it is often put behind a proxy that does
+	if apiKey := c.config.AIClientConfig.GetAPIKey(); apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama request to %s failed: %w", path, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		apiErr := &APIError{}
+		if data, _ := io.ReadAll(resp.Body); json.Unmarshal(data, apiErr) != nil {
+			apiErr.Message = ""
+		}
+		apiErr.StatusCode = resp.StatusCode
+		return nil, apiErr
+	}
+
+	return resp, nil
+}
+
+func tokenUsage(promptTokens int, completionTokens int) aitypes.TokenUsage {
+	return aitypes.TokenUsage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+	}
+}
+
+// containsModel reports whether model is in models, "llama3" matches "llama3:latest"
+func containsModel(models []string, model string) bool {
+	for _, name := range models {
+		if name == model || strings.TrimSuffix(name, ":latest") == model {
+			return true
+		}
+	}
+	return false
+}
+
+// The Ollama config has its own model and temperature, the generic AI config is the fallback
+
+func (c *OllamaClient) model() string {
+	if c.config.Model != "" {
+		return c.config.Model
+	}
+	if model := c.config.AIClientConfig.GetModel(); model != "" {
+		return model
+	}
+	return defaultModel
+}
+
+func (c *OllamaClient) temperature() float64 {
+	if c.config.Temperature > 0 {
+		return c.config.Temperature
+	}
+	return c.config.AIClientConfig.GetTemperature()
+}
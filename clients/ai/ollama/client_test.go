@@ -0,0 +1,250 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	aitypes "suasor/clients/ai/types"
+	"suasor/clients/types"
+)
+
+// fakeOllama records the requests it receives and answers with the queued replies
+type fakeOllama struct {
+	chatRequests     []chatRequest
+	generateRequests []generateRequest
+	tagsRequests     int
+	replies          []string
+}
+
+func (f *fakeOllama) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/tags":
+		f.tagsRequests++
+		_, _ = w.Write([]byte(`{"models":[{"name":"llama3.2:latest"},{"name":"mistral:7b"}]}`))
+	case "/api/chat":
+		var request chatRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		f.chatRequests = append(f.chatRequests, request)
+		if request.Stream {
+			encoder := json.NewEncoder(w)
+			for _, word := range strings.SplitAfter(f.nextReply(), " ") {
+				_ = encoder.Encode(chatResponse{Model: request.Model, Message: chatMessage{Role: "assistant", Content: word}})
+			}
+			_ = encoder.Encode(chatResponse{Model: request.Model, Done: true, PromptEvalCount: 20, EvalCount: 8})
+			return
+		}
+		message := chatMessage{Role: "assistant", Content: f.nextReply()}
+		// Replies of the form "call:<name>:<arguments>" become a tool call
+		if content, isCall := strings.CutPrefix(message.Content, "call:"); isCall {
+			name, arguments, _ := strings.Cut(content, ":")
+			var call toolCall
+			call.Function.Name = name
+			call.Function.Arguments = json.RawMessage(arguments)
+			message = chatMessage{Role: "assistant", ToolCalls: []toolCall{call}}
+		}
+		_ = json.NewEncoder(w).Encode(chatResponse{
+			Model:           request.Model,
+			Message:         message,
+			Done:            true,
+			PromptEvalCount: 20,
+			EvalCount:       8,
+		})
+	case "/api/generate":
+		var request generateRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if request.Model == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"model \"missing\" not found, try pulling it first"}`))
+			return
+		}
+		f.generateRequests = append(f.generateRequests, request)
+		_ = json.NewEncoder(w).Encode(generateResponse{
+			Model:           request.Model,
+			Response:        f.nextReply(),
+			Done:            true,
+			PromptEvalCount: 20,
+			EvalCount:       8,
+		})
+	case "/api/embed":
+		var request embedRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		response := embedResponse{Model: request.Model, PromptEvalCount: 4}
+		for _, input := range request.Input {
+			response.Embeddings = append(response.Embeddings, []float32{float32(len(input)), 1})
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeOllama) nextReply() string {
+	reply := f.replies[0]
+	f.replies = f.replies[1:]
+	return reply
+}
+
+func newTestClient(t *testing.T, replies ...string) (*OllamaClient, *fakeOllama) {
+	fake := &fakeOllama{replies: replies}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := types.NewOllamaConfig(server.URL, "llama3.2", 0.7, true, false)
+	client, err := NewOllamaClient(context.Background(), 1, config)
+	require.NoError(t, err)
+
+	return client.(*OllamaClient), fake
+}
+
+func TestOllamaClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("GetSupportedModels", func(t *testing.T) {
+		client, _ := newTestClient(t)
+		assert.Equal(t, []string{"llama3.2:latest", "mistral:7b"}, client.GetSupportedModels())
+
+		ok, err := client.TestConnection(ctx)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("GetCapabilities", func(t *testing.T) {
+		client, fake := newTestClient(t)
+		capabilities := client.GetCapabilities()
+		assert.Equal(t, []string{client.model()}, capabilities.AvailableModels, "the configured model until the list is fetched")
+		assert.Zero(t, fake.tagsRequests, "capability checks don't call the server")
+
+		client.GetSupportedModels()
+		client.GetSupportedModels()
+		assert.Equal(t, 1, fake.tagsRequests, "the model list is reused")
+		assert.Equal(t, []string{"llama3.2:latest", "mistral:7b"}, client.GetCapabilities().AvailableModels)
+	})
+
+	t.Run("GenerateContent", func(t *testing.T) {
+		client, fake := newTestClient(t, "4")
+		response, err := client.GenerateContent(ctx, "Answer briefly", "What is 2+2?", "", map[string]any{"max_tokens": 10})
+		require.NoError(t, err)
+
+		assert.Equal(t, "4", response.Text)
+		assert.Equal(t, aitypes.TokenUsage{PromptTokens: 20, CompletionTokens: 8, TotalTokens: 28}, response.TokenUsage)
+
+		request := fake.generateRequests[0]
+		assert.Equal(t, "llama3.2", request.Model)
+		assert.Equal(t, "Answer briefly", request.System)
+		assert.False(t, request.Stream)
+		assert.Equal(t, 10, request.Options.NumPredict)
+	})
+
+	t.Run("ModelNotFound", func(t *testing.T) {
+		client, _ := newTestClient(t)
+		_, err := client.GenerateContent(ctx, "", "Hello", "missing", nil)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Contains(t, apiErr.Message, "try pulling it first")
+	})
+
+	t.Run("GetRecommendations", func(t *testing.T) {
+		client, fake := newTestClient(t, `{"items":[{"title":"Arrival","year":2016}],"explanation":"Thoughtful science fiction"}`)
+		response, err := client.GetRecommendations(ctx, &aitypes.RecommendationRequest{MediaType: "movie", Count: 5})
+		require.NoError(t, err)
+
+		require.Len(t, response.Items, 1)
+		assert.Equal(t, "Arrival", response.Items[0].Title)
+		assert.Equal(t, 28, response.TokenUsage.TotalTokens)
+
+		request := fake.generateRequests[0]
+		assert.Equal(t, "json", request.Format)
+		assert.True(t, strings.Contains(request.Prompt, `"explanation"`), "the prompt should describe the schema")
+	})
+
+	t.Run("Conversation", func(t *testing.T) {
+		client, fake := newTestClient(t, "Hi!", "You said hello.")
+		conversationID, err := client.StartConversation(ctx, "Be brief")
+		require.NoError(t, err)
+
+		_, err = client.SendMessage(ctx, conversationID, "Hello")
+		require.NoError(t, err)
+		reply, err := client.SendMessage(ctx, conversationID, "What did I say?")
+		require.NoError(t, err)
+
+		assert.Equal(t, "You said hello.", reply)
+		assert.Equal(t, []chatMessage{
+			{Role: "system", Content: "Be brief"},
+			{Role: "user", Content: "Hello"},
+			{Role: "assistant", Content: "Hi!"},
+			{Role: "user", Content: "What did I say?"},
+		}, fake.chatRequests[1].Messages)
+	})
+
+	t.Run("StreamRecommendationConversation", func(t *testing.T) {
+		client, fake := newTestClient(t, "Try Arrival or Contact.")
+		conversationID, _, err := client.StartRecommendationConversation(ctx, "movie", nil, "")
+		require.NoError(t, err)
+
+		var deltas []string
+		reply, _, err := client.StreamRecommendationConversation(ctx, conversationID, "Something cerebral", nil, func(delta string) error {
+			deltas = append(deltas, delta)
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Try Arrival or Contact.", reply)
+		assert.Equal(t, []string{"Try ", "Arrival ", "or ", "Contact."}, deltas)
+		assert.True(t, fake.chatRequests[0].Stream)
+	})
+	t.Run("ContinueConversationWithTools", func(t *testing.T) {
+		client, fake := newTestClient(t, `call:get_favorites:{"media_type":"movie"}`, "Your favourite is Arrival.")
+		conversationID, err := client.StartConversation(ctx, "")
+		require.NoError(t, err)
+
+		tools := []aitypes.Tool{{Name: "get_favorites", Description: "Get favourites", Parameters: map[string]any{"type": "object"}}}
+		turn, err := client.ContinueConversationWithTools(ctx, conversationID, "What is my favourite movie?", nil, tools)
+		require.NoError(t, err)
+		require.Len(t, turn.ToolCalls, 1)
+		assert.Equal(t, "get_favorites", turn.ToolCalls[0].Name)
+		assert.Equal(t, `{"media_type":"movie"}`, string(turn.ToolCalls[0].Arguments))
+		require.Len(t, fake.chatRequests[0].Tools, 1)
+		assert.Equal(t, "get_favorites", fake.chatRequests[0].Tools[0].Function.Name)
+
+		results := []aitypes.ToolResult{{CallID: turn.ToolCalls[0].ID, Name: "get_favorites", Content: `[{"title":"Arrival"}]`}}
+		turn, err = client.ContinueConversationWithTools(ctx, conversationID, "", results, tools)
+		require.NoError(t, err)
+		assert.Equal(t, "Your favourite is Arrival.", turn.Text)
+
+		messages := fake.chatRequests[1].Messages
+		require.Len(t, messages, 3)
+		assert.Equal(t, chatMessage{Role: "tool", ToolName: "get_favorites", Content: `[{"title":"Arrival"}]`}, messages[2])
+	})
+
+	t.Run("CreateEmbeddings", func(t *testing.T) {
+		client, _ := newTestClient(t)
+		response, err := client.CreateEmbeddings(ctx, []string{"Alien", "Solaris"}, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "nomic-embed-text", response.Model)
+		assert.Equal(t, [][]float32{{5, 1}, {7, 1}}, response.Embeddings)
+		assert.Equal(t, 4, response.TokenUsage.PromptTokens)
+	})
+
+	t.Run("ReportUsage", func(t *testing.T) {
+		client, _ := newTestClient(t, "Hello")
+		var reported []aitypes.TokenUsage
+		usageCtx := aitypes.WithUsageReporter(ctx, func(ctx context.Context, model string, usage aitypes.TokenUsage) {
+			assert.Equal(t, "llama3.2", model)
+			reported = append(reported, usage)
+		})
+
+		_, err := client.GenerateContent(usageCtx, "", "Hi", "", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []aitypes.TokenUsage{{PromptTokens: 20, CompletionTokens: 8, TotalTokens: 28}}, reported)
+	})
+}
@@ -0,0 +1,34 @@
+package ollama
+
+import (
+	"context"
+	"fmt"
+
+	aitypes "suasor/clients/ai/types"
+)
+
+// CompactConversation replaces the history of a conversation with a summary of its earlier turns and the
+// messages since. The summary is a second system message after the conversation's instructions.
+func (c *OllamaClient) CompactConversation(ctx context.Context, conversationID string, summary string, history []aitypes.ChatMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conversation, exists := c.conversations[conversationID]
+	if !exists {
+		return fmt.Errorf("conversation not found: %s", conversationID)
+	}
+
+	messages := make([]chatMessage, 0, len(history)+2)
+	if len(conversation.Messages) > 0 && conversation.Messages[0].Role == "system" {
+		messages = append(messages, conversation.Messages[0])
+	}
+	if summary != "" {
+		messages = append(messages, chatMessage{Role: "system", Content: summary})
+	}
+	for _, msg := range history {
+		messages = append(messages, chatMessage{Role: msg.Role, Content: msg.Content})
+	}
+	conversation.Messages = messages
+
+	return nil
+}
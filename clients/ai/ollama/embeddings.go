@@ -0,0 +1,45 @@
+package ollama
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	aitypes "suasor/clients/ai/types"
+	"suasor/utils/logger"
+)
+
+// CreateEmbeddings returns a vector for each text using /api/embed, the embedding model has to be pulled
+func (c *OllamaClient) CreateEmbeddings(ctx context.Context, texts []string, model string) (*aitypes.EmbeddingResponse, error) {
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+	log := logger.LoggerFromContext(ctx)
+	log.Debug().
+		Str("model", model).
+		Int("texts", len(texts)).
+		Msg("Creating embeddings with Ollama")
+
+	if len(texts) == 0 {
+		return &aitypes.EmbeddingResponse{Model: model}, nil
+	}
+
+	var response embedResponse
+	request := &embedRequest{Model: model, Input: texts}
+	if err := c.doRequest(ctx, http.MethodPost, "/api/embed", request, &response); err != nil {
+		log.Error().Err(err).Msg("Failed to create embeddings with Ollama")
+		return nil, err
+	}
+	if len(response.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d texts", len(response.Embeddings), len(texts))
+	}
+
+	usage := tokenUsage(response.PromptEvalCount, 0)
+	aitypes.ReportUsage(ctx, response.Model, usage)
+
+	return &aitypes.EmbeddingResponse{
+		Embeddings: response.Embeddings,
+		Model:      response.Model,
+		TokenUsage: usage,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	aitypes "suasor/clients/ai/types"
+	"suasor/utils/logger"
+)
+
+// ContinueConversationWithTools sends the message, or the results of the previous turn's tool calls,
+// offering the model the given tools. The calls and their results stay in the conversation history.
+func (c *OllamaClient) ContinueConversationWithTools(ctx context.Context, conversationID string, message string, results []aitypes.ToolResult, tools []aitypes.Tool) (*aitypes.ToolTurn, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("conversationID", conversationID).
+		Int("tools", len(tools)).
+		Int("toolResults", len(results)).
+		Msg("Continuing conversation with tools on Ollama")
+
+	c.mu.Lock()
+	conversation, exists := c.conversations[conversationID]
+	if !exists {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("conversation not found: %s", conversationID)
+	}
+	messages := make([]chatMessage, len(conversation.Messages), len(conversation.Messages)+len(results)+1)
+	copy(messages, conversation.Messages)
+	c.mu.Unlock()
+
+	var sent []chatMessage
+	if len(results) > 0 {
+		for _, result := range results {
+			sent = append(sent, chatMessage{Role: "tool", ToolName: result.Name, Content: result.Content})
+		}
+	} else {
+		sent = append(sent, chatMessage{Role: "user", Content: message})
+	}
+
+	request := &chatRequest{
+		Model:    c.model(),
+		Messages: append(messages, sent...),
+		Options:  c.modelOptions(nil),
+		Tools:    toolDefinitions(tools),
+	}
+
+	response, err := c.chat(ctx, request)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to continue conversation with tools on Ollama")
+		return nil, err
+	}
+	reply := response.Message
+
+	c.mu.Lock()
+	conversation.Messages = append(conversation.Messages, sent...)
+	conversation.Messages = append(conversation.Messages, chatMessage{
+		Role:      "assistant",
+		Content:   reply.Content,
+		ToolCalls: reply.ToolCalls,
+	})
+	c.mu.Unlock()
+
+	turn := &aitypes.ToolTurn{
+		Text:       reply.Content,
+		TokenUsage: tokenUsage(response.PromptEvalCount, response.EvalCount),
+	}
+	for i, call := range reply.ToolCalls {
+		arguments := call.Function.Arguments
+		if len(arguments) == 0 || string(arguments) == "null" {
+			arguments = json.RawMessage("{}")
+		}
+		// Ollama does not identify calls, results are matched by tool name
+		turn.ToolCalls = append(turn.ToolCalls, aitypes.ToolCall{
+			ID:        fmt.Sprintf("call_%d", i),
+			Name:      call.Function.Name,
+			Arguments: arguments,
+		})
+	}
+
+	return turn, nil
+}
+
+// toolDefinitions converts the tools to the function tools of /api/chat
+func toolDefinitions(tools []aitypes.Tool) []toolDefinition {
+	if len(tools) == 0 {
+		return nil
+	}
+	definitions := make([]toolDefinition, len(tools))
+	for i, tool := range tools {
+		definitions[i] = toolDefinition{
+			Type: "function",
+			Function: toolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		}
+	}
+	return definitions
+}
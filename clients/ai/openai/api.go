@@ -0,0 +1,152 @@
+package openai
+
+import "fmt"
+
+// chatMessage is a message in the Chat Completions format
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+	// ToolCalls is set on assistant messages that call tools
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+	// ToolCallID is set on "tool" messages, it links the result to its call
+	ToolCallID string `json:"tool_call_id,omitempty"`
+}
+
+// toolCall is a function call requested by the model, Arguments is a JSON encoded object
+type toolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// toolDefinition describes a function the model may call
+type toolDefinition struct {
+	Type     string       `json:"type"`
+	Function toolFunction `json:"function"`
+}
+
+type toolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+// responseFormat selects plain text, JSON mode or JSON-schema structured output
+type responseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *jsonSchema `json:"json_schema,omitempty"`
+}
+
+type jsonSchema struct {
+	Name   string         `json:"name"`
+	Schema map[string]any `json:"schema"`
+	Strict bool           `json:"strict"`
+}
+
+// chatRequest is the body of POST /chat/completions
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []chatMessage   `json:"messages"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	Temperature    float64         `json:"temperature,omitempty"`
+	TopP           float64         `json:"top_p,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+	Tools          any             `json:"tools,omitempty"`
+	ToolChoice     any             `json:"tool_choice,omitempty"`
+	Stop           []string        `json:"stop,omitempty"`
+	Seed           int             `json:"seed,omitempty"`
+	Stream         bool            `json:"stream,omitempty"`
+	StreamOptions  *streamOptions  `json:"stream_options,omitempty"`
+}
+
+type streamOptions struct {
+	// IncludeUsage adds a last chunk with the token usage of the whole request
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// chatResponse is the response of POST /chat/completions
+type chatResponse struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []chatChoice `json:"choices"`
+	Usage   chatUsage    `json:"usage"`
+}
+
+type chatChoice struct {
+	Index        int         `json:"index"`
+	Message      chatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+// chatChunk is one server-sent event of a streamed chat completion
+type chatChunk struct {
+	ID      string `json:"id"`
+	Created int64  `json:"created"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	// Usage is only set on the last chunk
+	Usage *chatUsage `json:"usage"`
+}
+
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// embeddingRequest is the body of POST /embeddings
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingResponse is the response of POST /embeddings
+type embeddingResponse struct {
+	Model string          `json:"model"`
+	Data  []embeddingData `json:"data"`
+	Usage chatUsage       `json:"usage"`
+}
+
+// embeddingData is the vector of one input, Index is its position in the input
+type embeddingData struct {
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding"`
+}
+
+// modelList is the response of GET /models
+type modelList struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// APIError is an error returned by the OpenAI API
+type APIError struct {
+	StatusCode int
+	Type       string `json:"type"`
+	Code       any    `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("openai API returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("openai API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// errorResponse wraps an APIError in the response body
+type errorResponse struct {
+	Error *APIError `json:"error"`
+}
@@ -1 +1,812 @@
 package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"suasor/clients"
+	"suasor/clients/ai"
+	aitypes "suasor/clients/ai/types"
+	"suasor/clients/types"
+	"suasor/utils"
+	"suasor/utils/logger"
+	"suasor/utils/sse"
+)
+
+const (
+	defaultBaseURL = "https://api.openai.com/v1"
+	defaultModel   = "gpt-4o-mini"
+	// defaultEmbeddingModel is used by CreateEmbeddings when no model is given
+	defaultEmbeddingModel = "text-embedding-3-small"
+	requestTimeout        = 2 * time.Minute
+)
+
+// OpenAIClient implements the AI client interface on top of the Chat Completions API
+type OpenAIClient struct {
+	clients.Client
+	config     types.OpenAIConfig
+	baseURL    string
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	conversations map[string]*ConversationContext
+}
+
+// ConversationContext tracks the state of a conversation.
+// Messages are sent in full on every turn, the API itself is stateless.
+type ConversationContext struct {
+	ContentType     string
+	UserPreferences map[string]any
+	Messages        []chatMessage
+}
+
+// NewOpenAIClient creates a new OpenAI client instance
+func NewOpenAIClient(ctx context.Context, clientID uint64, cfg types.OpenAIConfig) (ai.ClientAI, error) {
+	if cfg.AIClientConfig == nil {
+		return nil, fmt.Errorf("openai client %d has no configuration", clientID)
+	}
+
+	c := &OpenAIClient{
+		Client:        clients.NewClient(clientID, types.ClientCategoryAI, &cfg),
+		config:        cfg,
+		baseURL:       strings.TrimRight(cfg.AIClientConfig.GetBaseURL(), "/"),
+		httpClient:    &http.Client{Timeout: requestTimeout},
+		conversations: make(map[string]*ConversationContext),
+	}
+	if c.baseURL == "" {
+		c.baseURL = defaultBaseURL
+	}
+
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Uint64("clientID", clientID).
+		Str("model", c.model()).
+		Str("baseURL", c.baseURL).
+		Msg("Creating new OpenAI client")
+
+	return c, nil
+}
+
+// TestConnection checks the API key by listing the available models
+func (c *OpenAIClient) TestConnection(ctx context.Context) (bool, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().Msg("Testing OpenAI connection")
+
+	var models modelList
+	if err := c.doRequest(ctx, http.MethodGet, "/models", nil, &models); err != nil {
+		log.Error().Err(err).Msg("OpenAI connection test failed")
+		return false, err
+	}
+
+	log.Info().Int("models", len(models.Data)).Msg("OpenAI connection test successful")
+	return true, nil
+}
+
+// GenerateText sends a prompt to OpenAI and returns the response
+func (c *OpenAIClient) GenerateText(ctx context.Context, promptText string, options *aitypes.GenerationOptions) (string, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	request := c.newChatRequest(options)
+	request.Messages = promptMessages(options, promptText)
+	if options != nil && options.ResponseFormat == "json" {
+		request.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+
+	response, err := c.createChatCompletion(ctx, request)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate text with OpenAI")
+		return "", fmt.Errorf("openai text generation failed: %w", err)
+	}
+
+	return response.text(), nil
+}
+
+// GenerateStructured generates output matching the JSON schema of outputSchema and decodes it into outputSchema
+func (c *OpenAIClient) GenerateStructured(ctx context.Context, promptText string, outputSchema any, options *aitypes.GenerationOptions) error {
+	_, err := c.generateStructured(ctx, promptText, outputSchema, options)
+	return err
+}
+
+func (c *OpenAIClient) generateStructured(ctx context.Context, promptText string, outputSchema any, options *aitypes.GenerationOptions) (aitypes.TokenUsage, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	jsonOptions := &aitypes.GenerationOptions{
+		Temperature: 0.2, // Lower temperature for more predictable output
+	}
+	if options != nil {
+		if options.MaxTokens > 0 {
+			jsonOptions.MaxTokens = options.MaxTokens
+		}
+		if options.Temperature > 0 {
+			jsonOptions.Temperature = options.Temperature
+		}
+		jsonOptions.SystemInstructions = options.SystemInstructions
+	}
+	if jsonOptions.SystemInstructions == "" {
+		jsonOptions.SystemInstructions = "You are a helpful assistant that responds only with valid JSON."
+	}
+
+	request := c.newChatRequest(jsonOptions)
+	request.Messages = promptMessages(jsonOptions, promptText)
+
+	schema, wrapped := ai.StructuredSchema(outputSchema)
+	if schema != nil {
+		request.ResponseFormat = &responseFormat{
+			Type:       "json_schema",
+			JSONSchema: &jsonSchema{Name: "response", Schema: schema},
+		}
+	} else {
+		// JSON mode requires the word JSON to appear in the messages
+		request.Messages[len(request.Messages)-1].Content += "\n\nRespond with a JSON object."
+		request.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+
+	response, err := c.createChatCompletion(ctx, request)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate structured output with OpenAI")
+		return aitypes.TokenUsage{}, fmt.Errorf("openai structured generation failed: %w", err)
+	}
+
+	if err := ai.DecodeStructured([]byte(response.text()), wrapped, outputSchema); err != nil {
+		log.Error().Err(err).Str("response", response.text()).Msg("Failed to parse OpenAI response as JSON")
+		return response.tokenUsage(), fmt.Errorf("failed to parse OpenAI JSON response: %w", err)
+	}
+
+	return response.tokenUsage(), nil
+}
+
+// StartConversation begins a new conversation
+func (c *OpenAIClient) StartConversation(ctx context.Context, systemInstructions string) (string, error) {
+	conversationID := fmt.Sprintf("conv-%d-%s", c.GetClientID(), utils.GenerateRandomID(12))
+
+	conversation := &ConversationContext{}
+	if systemInstructions != "" {
+		conversation.Messages = append(conversation.Messages, chatMessage{Role: "system", Content: systemInstructions})
+	}
+
+	c.mu.Lock()
+	c.conversations[conversationID] = conversation
+	c.mu.Unlock()
+
+	return conversationID, nil
+}
+
+// SendMessage sends a message in an existing conversation
+func (c *OpenAIClient) SendMessage(ctx context.Context, conversationID string, message string) (string, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	reply, _, err := c.sendConversationMessage(ctx, conversationID, message, nil, nil)
+	if err != nil {
+		log.Error().Err(err).Str("conversationID", conversationID).Msg("Failed to send message to OpenAI")
+		return "", fmt.Errorf("openai message send failed: %w", err)
+	}
+
+	return reply, nil
+}
+
+// sendConversationMessage adds the message to the conversation, sends the whole history and records the reply.
+// The reply is streamed to onDelta when it is set. The conversation is only updated when the request succeeds.
+func (c *OpenAIClient) sendConversationMessage(ctx context.Context, conversationID string, message string, options *aitypes.GenerationOptions, onDelta aitypes.StreamHandler) (string, aitypes.TokenUsage, error) {
+	c.mu.Lock()
+	conversation, exists := c.conversations[conversationID]
+	if !exists {
+		c.mu.Unlock()
+		return "", aitypes.TokenUsage{}, fmt.Errorf("conversation not found: %s", conversationID)
+	}
+	messages := make([]chatMessage, len(conversation.Messages), len(conversation.Messages)+1)
+	copy(messages, conversation.Messages)
+	c.mu.Unlock()
+
+	userMessage := chatMessage{Role: "user", Content: message}
+	request := c.newChatRequest(options)
+	request.Messages = append(messages, userMessage)
+
+	var response *chatResponse
+	var err error
+	if onDelta != nil {
+		response, err = c.streamChatCompletion(ctx, request, onDelta)
+	} else {
+		response, err = c.createChatCompletion(ctx, request)
+	}
+	if err != nil {
+		return "", aitypes.TokenUsage{}, err
+	}
+	reply := response.text()
+
+	c.mu.Lock()
+	conversation.Messages = append(conversation.Messages, userMessage, chatMessage{Role: "assistant", Content: reply})
+	c.mu.Unlock()
+
+	return reply, response.tokenUsage(), nil
+}
+
+// GetSupportedModels returns a list of OpenAI chat models supported by this client
+func (c *OpenAIClient) GetSupportedModels() []string {
+	return []string{
+		"gpt-4o",
+		"gpt-4o-mini",
+		"gpt-4.1",
+		"gpt-4.1-mini",
+		"gpt-4-turbo",
+		"gpt-3.5-turbo",
+	}
+}
+
+// GetCapabilities returns the capabilities of this OpenAI client
+func (c *OpenAIClient) GetCapabilities() *aitypes.AICapabilities {
+	return &aitypes.AICapabilities{
+		SupportsStructuredOutput: true,
+		SupportsConversation:     true,
+		SupportsStreaming:        true,
+		SupportsToolCalling:      true,
+		SupportsEmbeddings:       true,
+		EmbeddingModel:           defaultEmbeddingModel,
+		MaxContextTokens:         c.maxContextTokens(),
+		DefaultMaxTokens:         c.maxTokens(),
+		AvailableModels:          c.GetSupportedModels(),
+	}
+}
+
+// recommendationOutput is the structured output requested for recommendations
+type recommendationOutput struct {
+	Items       []aitypes.RecommendationItem `json:"items"`
+	Explanation string                       `json:"explanation"`
+}
+
+// GetRecommendations implements the AIClient interface to get content recommendations
+func (c *OpenAIClient) GetRecommendations(ctx context.Context, request *aitypes.RecommendationRequest) (*aitypes.RecommendationResponse, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("mediaType", request.MediaType).
+		Int("count", request.Count).
+		Msg("Getting recommendations from OpenAI")
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Please recommend %d %s items.\n", request.Count, request.MediaType)
+
+	if len(request.UserPreferences) > 0 {
+		prompt.WriteString("\nConsider these preferences:\n")
+		for k, v := range request.UserPreferences {
+			fmt.Fprintf(&prompt, "- %s: %v\n", k, v)
+		}
+	}
+	if len(request.IncludeSimilarTo) > 0 {
+		prompt.WriteString("\nRecommend items similar to:\n")
+		for _, id := range request.IncludeSimilarTo {
+			fmt.Fprintf(&prompt, "- %s\n", id)
+		}
+	}
+	if len(request.ExcludeIDs) > 0 {
+		prompt.WriteString("\nPlease exclude the following items:\n")
+		for _, id := range request.ExcludeIDs {
+			fmt.Fprintf(&prompt, "- %s\n", id)
+		}
+	}
+	if request.AdditionalContext != "" {
+		prompt.WriteString("\n" + request.AdditionalContext + "\n")
+	}
+	prompt.WriteString("\nFor each item give the title, release year, genres, a brief reason why it is recommended, " +
+		"the average rating (0-10 scale), a popularity rank (1-100, where 1 is most popular) and a brief description. " +
+		"Add a one sentence explanation of the overall selection.")
+
+	genOptions := &aitypes.GenerationOptions{
+		Temperature:        0.4,
+		MaxTokens:          2000,
+		SystemInstructions: fmt.Sprintf("You are a helpful recommendation system specialized in %s. Provide detailed and personalized recommendations based on the user's preferences.", request.MediaType),
+	}
+	if request.GenerationOptions != nil {
+		if request.GenerationOptions.Temperature > 0 {
+			genOptions.Temperature = request.GenerationOptions.Temperature
+		}
+		if request.GenerationOptions.MaxTokens > 0 {
+			genOptions.MaxTokens = request.GenerationOptions.MaxTokens
+		}
+		if request.GenerationOptions.SystemInstructions != "" {
+			genOptions.SystemInstructions = request.GenerationOptions.SystemInstructions
+		}
+	}
+
+	var output recommendationOutput
+	usage, err := c.generateStructured(ctx, prompt.String(), &output, genOptions)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get recommendations from OpenAI")
+		return nil, err
+	}
+
+	if request.Count > 0 && len(output.Items) > request.Count {
+		output.Items = output.Items[:request.Count]
+	}
+
+	explanation := output.Explanation
+	if explanation == "" {
+		explanation = "Recommendations generated based on user preferences using OpenAI"
+	}
+
+	return &aitypes.RecommendationResponse{
+		Items:       output.Items,
+		Explanation: explanation,
+		TokenUsage:  usage,
+	}, nil
+}
+
+// AnalyzeContent implements the AIClient interface to analyze content
+func (c *OpenAIClient) AnalyzeContent(ctx context.Context, contentType string, content string, options map[string]any) (map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("contentType", contentType).
+		Int("contentLength", len(content)).
+		Interface("options", options).
+		Msg("Analyzing content with OpenAI")
+
+	prompt := fmt.Sprintf("Please analyze the following %s content:\n\n%s\n\n", contentType, content)
+	if include, ok := options["includeThemes"].(bool); ok && include {
+		prompt += "Include main themes and motifs. "
+	}
+	if include, ok := options["includeSentiment"].(bool); ok && include {
+		prompt += "Analyze the sentiment. "
+	}
+	if include, ok := options["includeStyleAnalysis"].(bool); ok && include {
+		prompt += "Analyze the stylistic elements. "
+	}
+	prompt += "\nReturn the analysis as a JSON object with appropriate fields for the requested analysis."
+
+	var analysis map[string]any
+	_, err := c.generateStructured(ctx, prompt, &analysis, &aitypes.GenerationOptions{
+		Temperature:        0.3,
+		MaxTokens:          2000,
+		SystemInstructions: fmt.Sprintf("You are an expert at analyzing %s content. Provide detailed, insightful analysis as JSON.", contentType),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to analyze content with OpenAI")
+		return nil, err
+	}
+
+	return analysis, nil
+}
+
+// StartRecommendationConversation starts a conversational recommendation session
+func (c *OpenAIClient) StartRecommendationConversation(ctx context.Context, contentType string, preferences map[string]any, systemInstructions string) (string, string, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("contentType", contentType).
+		Interface("preferences", preferences).
+		Msg("Starting recommendation conversation with OpenAI")
+
+	conversationID := fmt.Sprintf("rec-%d-%s", c.GetClientID(), utils.GenerateRandomID(12))
+
+	if systemInstructions == "" {
+		systemInstructions = fmt.Sprintf(
+			"You are an expert %s recommendation assistant. Your goal is to help the user discover %s they'll love based on their preferences and interests. "+
+				"Maintain a friendly, conversational tone. Ask questions to understand their preferences better. "+
+				"When recommending items, provide a brief explanation of why you're recommending them based on the user's preferences.",
+			contentType, contentType)
+	}
+	if len(preferences) > 0 {
+		var b strings.Builder
+		b.WriteString(systemInstructions)
+		b.WriteString("\n\nUser preferences:\n")
+		for k, v := range preferences {
+			fmt.Fprintf(&b, "- %s: %v\n", k, v)
+		}
+		systemInstructions = b.String()
+	}
+
+	welcomeMessage := welcomeMessage(contentType, preferences)
+
+	c.mu.Lock()
+	c.conversations[conversationID] = &ConversationContext{
+		ContentType:     contentType,
+		UserPreferences: preferences,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemInstructions},
+			{Role: "assistant", Content: welcomeMessage},
+		},
+	}
+	c.mu.Unlock()
+
+	return conversationID, welcomeMessage, nil
+}
+
+// welcomeMessage greets the user, mentioning their favourite genres and recent items when known
+func welcomeMessage(contentType string, preferences map[string]any) string {
+	message := fmt.Sprintf("Hi there! I'm your %s recommendation assistant. ", contentType)
+
+	if genres, ok := preferences["favoriteGenres"].([]any); ok && len(genres) > 0 {
+		message += fmt.Sprintf("I see you enjoy %s like %s. ", contentType, joinList(genres))
+	}
+	if recent, ok := preferences["recentlyWatched"].([]any); ok && len(recent) > 0 {
+		message += fmt.Sprintf("You've recently enjoyed %s. ", joinList(recent))
+	}
+
+	return message + fmt.Sprintf("What kind of %s are you in the mood for today?", contentType)
+}
+
+// joinList joins values as "a, b and c"
+func joinList(values []any) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprintf("%v", value)
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// ContinueRecommendationConversation continues an existing conversation with a new message
+func (c *OpenAIClient) ContinueRecommendationConversation(ctx context.Context, conversationID string, message string, context map[string]any) (string, []map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("conversationID", conversationID).
+		Msg("Continuing recommendation conversation with OpenAI")
+
+	return c.continueRecommendationConversation(ctx, conversationID, message, context, nil)
+}
+
+// StreamRecommendationConversation continues an existing conversation, streaming the reply to onDelta
+func (c *OpenAIClient) StreamRecommendationConversation(ctx context.Context, conversationID string, message string, context map[string]any, onDelta aitypes.StreamHandler) (string, []map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("conversationID", conversationID).
+		Msg("Streaming recommendation conversation with OpenAI")
+
+	return c.continueRecommendationConversation(ctx, conversationID, message, context, onDelta)
+}
+
+func (c *OpenAIClient) continueRecommendationConversation(ctx context.Context, conversationID string, message string, context map[string]any, onDelta aitypes.StreamHandler) (string, []map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	shouldExtractRecommendations, _ := context["extractRecommendations"].(bool)
+
+	prompt := message
+	if shouldExtractRecommendations {
+		prompt += "\n\nPlease include specific recommendations in your response. Format each recommendation as a clear item with relevant details."
+	}
+
+	aiResponse, _, err := c.sendConversationMessage(ctx, conversationID, prompt, &aitypes.GenerationOptions{
+		Temperature: 0.7,
+		MaxTokens:   1000,
+	}, onDelta)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to continue conversation with OpenAI")
+		return "", nil, err
+	}
+
+	var recommendations []map[string]any
+	if shouldExtractRecommendations {
+		c.mu.Lock()
+		contentType := c.conversations[conversationID].ContentType
+		c.mu.Unlock()
+		recommendations = c.extractRecommendationsFromText(ctx, aiResponse, contentType)
+	}
+
+	return aiResponse, recommendations, nil
+}
+
+// extractRecommendationsFromText parses the AI response to extract structured recommendations
+func (c *OpenAIClient) extractRecommendationsFromText(ctx context.Context, text string, contentType string) []map[string]any {
+	log := logger.LoggerFromContext(ctx)
+
+	extractPrompt := fmt.Sprintf(
+		"From the following assistant response, extract the %s recommendations as structured data:\n\n%s\n\n"+
+			"Return a JSON object with a \"recommendations\" array. Each object should have appropriate fields for %s items.",
+		contentType, text, contentType)
+
+	var output struct {
+		Recommendations []map[string]any `json:"recommendations"`
+	}
+	_, err := c.generateStructured(ctx, extractPrompt, &output, &aitypes.GenerationOptions{
+		Temperature:        0.1, // Low temperature for deterministic extraction
+		MaxTokens:          1000,
+		SystemInstructions: "You are a helpful data extraction assistant. Your job is to extract structured recommendations from text.",
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to extract recommendations from text")
+		return []map[string]any{}
+	}
+
+	return output.Recommendations
+}
+
+// CreateMessage implements the AIClient interface for OpenAI
+func (c *OpenAIClient) CreateMessage(ctx context.Context, messageRequest aitypes.MessageRequest) (*aitypes.MessageResponse, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("model", messageRequest.Model).
+		Int("messageCount", len(messageRequest.Messages)).
+		Msg("Creating message with OpenAI")
+
+	request := c.newChatRequest(&aitypes.GenerationOptions{
+		Temperature: messageRequest.Temperature,
+		MaxTokens:   messageRequest.MaxTokens,
+	})
+	if messageRequest.Model != "" {
+		request.Model = messageRequest.Model
+	}
+	request.TopP = messageRequest.TopP
+	request.Tools = messageRequest.Tools
+	request.ToolChoice = messageRequest.ToolChoice
+	request.Stop = messageRequest.Stop
+	request.Seed = messageRequest.Seed
+	if messageRequest.ResponseFormat.Type != "" {
+		request.ResponseFormat = &responseFormat{Type: messageRequest.ResponseFormat.Type}
+	}
+
+	if messageRequest.System != "" {
+		request.Messages = append(request.Messages, chatMessage{Role: "system", Content: messageRequest.System})
+	}
+	for _, msg := range messageRequest.Messages {
+		var text strings.Builder
+		for _, content := range msg.Content {
+			if content.Type == "text" {
+				if text.Len() > 0 {
+					text.WriteString("\n\n")
+				}
+				text.WriteString(content.Text)
+			}
+		}
+		request.Messages = append(request.Messages, chatMessage{Role: msg.Role, Content: text.String()})
+	}
+
+	response, err := c.createChatCompletion(ctx, request)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create message with OpenAI")
+		return nil, err
+	}
+
+	return &aitypes.MessageResponse{
+		ID:         response.ID,
+		Object:     "message",
+		Created:    response.Created,
+		Model:      response.Model,
+		Content:    []aitypes.MessageContent{{Type: "text", Text: response.text()}},
+		Role:       "assistant",
+		TokenUsage: response.tokenUsage(),
+	}, nil
+}
+
+// GenerateContent implements the AIClient interface for generating content with OpenAI
+func (c *OpenAIClient) GenerateContent(ctx context.Context, systemPrompt string, userPrompt string, model string, options map[string]any) (*aitypes.ContentResponse, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("model", model).
+		Int("userPromptLength", len(userPrompt)).
+		Int("systemPromptLength", len(systemPrompt)).
+		Msg("Generating content with OpenAI")
+
+	genOptions := &aitypes.GenerationOptions{SystemInstructions: systemPrompt}
+	if temp, ok := options["temperature"].(float64); ok {
+		genOptions.Temperature = temp
+	}
+	if maxTokens, ok := options["max_tokens"].(int); ok {
+		genOptions.MaxTokens = maxTokens
+	}
+
+	request := c.newChatRequest(genOptions)
+	if model != "" {
+		request.Model = model
+	}
+	request.Messages = promptMessages(genOptions, userPrompt)
+
+	response, err := c.createChatCompletion(ctx, request)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate content with OpenAI")
+		return nil, fmt.Errorf("openai content generation failed: %w", err)
+	}
+
+	return &aitypes.ContentResponse{
+		Text:       response.text(),
+		TokenUsage: response.tokenUsage(),
+	}, nil
+}
+
+// newChatRequest creates a request with the configured model, falling back to the configured
+// temperature and max tokens when the options leave them unset
+func (c *OpenAIClient) newChatRequest(options *aitypes.GenerationOptions) *chatRequest {
+	request := &chatRequest{
+		Model:       c.model(),
+		Temperature: c.temperature(),
+		MaxTokens:   c.maxTokens(),
+	}
+	if options != nil {
+		if options.Temperature > 0 {
+			request.Temperature = options.Temperature
+		}
+		if options.MaxTokens > 0 {
+			request.MaxTokens = options.MaxTokens
+		}
+	}
+	return request
+}
+
+// promptMessages builds the messages of a single prompt request
+func promptMessages(options *aitypes.GenerationOptions, prompt string) []chatMessage {
+	var messages []chatMessage
+	if options != nil && options.SystemInstructions != "" {
+		messages = append(messages, chatMessage{Role: "system", Content: options.SystemInstructions})
+	}
+	return append(messages, chatMessage{Role: "user", Content: prompt})
+}
+
+// createChatCompletion calls POST /chat/completions
+func (c *OpenAIClient) createChatCompletion(ctx context.Context, request *chatRequest) (*chatResponse, error) {
+	var response chatResponse
+	if err := c.doRequest(ctx, http.MethodPost, "/chat/completions", request, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("openai returned no choices")
+	}
+
+	log := logger.LoggerFromContext(ctx)
+	log.Debug().
+		Str("model", response.Model).
+		Int("promptTokens", response.Usage.PromptTokens).
+		Int("completionTokens", response.Usage.CompletionTokens).
+		Str("finishReason", response.Choices[0].FinishReason).
+		Msg("OpenAI chat completion finished")
+
+	aitypes.ReportUsage(ctx, response.Model, response.tokenUsage())
+	return &response, nil
+}
+
+// streamChatCompletion calls POST /chat/completions with streaming, passing each content delta to onDelta.
+// The returned response holds the whole reply and the token usage.
+func (c *OpenAIClient) streamChatCompletion(ctx context.Context, request *chatRequest, onDelta aitypes.StreamHandler) (*chatResponse, error) {
+	request.Stream = true
+	request.StreamOptions = &streamOptions{IncludeUsage: true}
+
+	resp, err := c.send(ctx, http.MethodPost, "/chat/completions", request, "text/event-stream")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	response := &chatResponse{Choices: []chatChoice{{Message: chatMessage{Role: "assistant"}}}}
+	var content strings.Builder
+
+	reader := sse.NewReader(resp.Body)
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("openai stream ended unexpectedly")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read openai stream: %w", err)
+		}
+		if event.Data == "[DONE]" {
+			break
+		}
+
+		var chunk chatChunk
+		if err := json.Unmarshal([]byte(event.Data), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode openai stream chunk: %w", err)
+		}
+		response.ID, response.Created, response.Model = chunk.ID, chunk.Created, chunk.Model
+		if chunk.Usage != nil {
+			response.Usage = *chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != "" {
+				response.Choices[0].FinishReason = choice.FinishReason
+			}
+			if choice.Delta.Content == "" {
+				continue
+			}
+			content.WriteString(choice.Delta.Content)
+			if err := onDelta(choice.Delta.Content); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	response.Choices[0].Message.Content = content.String()
+	aitypes.ReportUsage(ctx, response.Model, response.tokenUsage())
+	return response, nil
+}
+
+// doRequest sends a JSON request to the API and decodes the JSON response into out
+func (c *OpenAIClient) doRequest(ctx context.Context, method string, path string, body any, out any) error {
+	resp, err := c.send(ctx, method, path, body, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode openai response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// send sends a JSON request to the API and returns the response when its status is successful
+func (c *OpenAIClient) send(ctx context.Context, method string, path string, body any, accept string) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode openai request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create openai request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.config.AIClientConfig.GetAPIKey())
+	req.Header.Set("Accept", accept)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai request to %s failed: %w", path, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var errResp errorResponse
+		if data, _ := io.ReadAll(resp.Body); json.Unmarshal(data, &errResp) == nil && errResp.Error != nil {
+			apiErr = errResp.Error
+			apiErr.StatusCode = resp.StatusCode
+		}
+		return nil, apiErr
+	}
+
+	return resp, nil
+}
+
+func (r *chatResponse) text() string {
+	return r.Choices[0].Message.Content
+}
+
+func (r *chatResponse) tokenUsage() aitypes.TokenUsage {
+	return aitypes.TokenUsage{
+		PromptTokens:     r.Usage.PromptTokens,
+		CompletionTokens: r.Usage.CompletionTokens,
+		TotalTokens:      r.Usage.TotalTokens,
+	}
+}
+
+// The OpenAI config has its own model settings, the generic AI config is the fallback
+
+func (c *OpenAIClient) model() string {
+	if c.config.Model != "" {
+		return c.config.Model
+	}
+	if model := c.config.AIClientConfig.GetModel(); model != "" {
+		return model
+	}
+	return defaultModel
+}
+
+func (c *OpenAIClient) temperature() float64 {
+	if c.config.Temperature > 0 {
+		return c.config.Temperature
+	}
+	return c.config.AIClientConfig.GetTemperature()
+}
+
+func (c *OpenAIClient) maxTokens() int {
+	if c.config.MaxTokens > 0 {
+		return c.config.MaxTokens
+	}
+	return c.config.AIClientConfig.GetMaxTokens()
+}
+
+func (c *OpenAIClient) maxContextTokens() int {
+	if c.config.MaxContextTokens > 0 {
+		return c.config.MaxContextTokens
+	}
+	return c.config.AIClientConfig.GetMaxContextTokens()
+}
@@ -0,0 +1,276 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"suasor/clients/ai"
+	aitypes "suasor/clients/ai/types"
+	"suasor/clients/types"
+)
+
+// fakeOpenAI records the chat requests it receives and answers with the queued replies
+type fakeOpenAI struct {
+	requests []chatRequest
+	replies  []string
+}
+
+func (f *fakeOpenAI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer test-key" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"Incorrect API key provided","type":"invalid_request_error"}}`))
+		return
+	}
+
+	switch r.URL.Path {
+	case "/models":
+		_, _ = w.Write([]byte(`{"data":[{"id":"gpt-4o-mini"}]}`))
+	case "/chat/completions":
+		var request chatRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		f.requests = append(f.requests, request)
+
+		reply := f.replies[0]
+		f.replies = f.replies[1:]
+		if request.Stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, word := range strings.SplitAfter(reply, " ") {
+				fmt.Fprintf(w, "data: {\"model\":%q,\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", request.Model, word)
+			}
+			fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":12,\"completion_tokens\":5,\"total_tokens\":17}}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		message := chatMessage{Role: "assistant", Content: reply}
+		// Replies of the form "call:<name>:<arguments>" become a tool call
+		if content, isCall := strings.CutPrefix(reply, "call:"); isCall {
+			name, arguments, _ := strings.Cut(content, ":")
+			call := toolCall{ID: "call_1", Type: "function"}
+			call.Function.Name = name
+			call.Function.Arguments = arguments
+			message = chatMessage{Role: "assistant", ToolCalls: []toolCall{call}}
+		}
+		_ = json.NewEncoder(w).Encode(chatResponse{
+			ID:      "chatcmpl-1",
+			Model:   request.Model,
+			Choices: []chatChoice{{Message: message, FinishReason: "stop"}},
+			Usage:   chatUsage{PromptTokens: 12, CompletionTokens: 5, TotalTokens: 17},
+		})
+	case "/embeddings":
+		var request embeddingRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		// Answer in reverse order, the client has to sort by index
+		var response embeddingResponse
+		response.Model = request.Model
+		for i := len(request.Input) - 1; i >= 0; i-- {
+			response.Data = append(response.Data, embeddingData{Index: i, Embedding: []float32{float32(len(request.Input[i])), 1}})
+		}
+		response.Usage = chatUsage{PromptTokens: 6, TotalTokens: 6}
+		_ = json.NewEncoder(w).Encode(response)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestClient(t *testing.T, apiKey string, replies ...string) (*OpenAIClient, *fakeOpenAI) {
+	fake := &fakeOpenAI{replies: replies}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := types.NewOpenAIConfig(apiKey, server.URL, "gpt-4o-mini", 0.7, 500, 8192, true, false)
+	client, err := NewOpenAIClient(context.Background(), 1, config)
+	require.NoError(t, err)
+
+	return client.(*OpenAIClient), fake
+}
+
+func TestOpenAIClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("TestConnection", func(t *testing.T) {
+		client, _ := newTestClient(t, "test-key")
+		ok, err := client.TestConnection(ctx)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		client, _ := newTestClient(t, "wrong-key")
+		_, err := client.GenerateText(ctx, "Hello", nil)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+		assert.Equal(t, "Incorrect API key provided", apiErr.Message)
+	})
+
+	t.Run("GenerateContent", func(t *testing.T) {
+		client, fake := newTestClient(t, "test-key", "4")
+		response, err := client.GenerateContent(ctx, "Answer briefly", "What is 2+2?", "", map[string]any{"max_tokens": 10})
+		require.NoError(t, err)
+
+		assert.Equal(t, "4", response.Text)
+		assert.Equal(t, aitypes.TokenUsage{PromptTokens: 12, CompletionTokens: 5, TotalTokens: 17}, response.TokenUsage)
+
+		request := fake.requests[0]
+		assert.Equal(t, "gpt-4o-mini", request.Model)
+		assert.Equal(t, 10, request.MaxTokens)
+		assert.Equal(t, []chatMessage{{Role: "system", Content: "Answer briefly"}, {Role: "user", Content: "What is 2+2?"}}, request.Messages)
+	})
+
+	t.Run("GetRecommendations", func(t *testing.T) {
+		client, fake := newTestClient(t, "test-key",
+			`{"items":[{"title":"Arrival","year":2016},{"title":"Dune","year":2021}],"explanation":"Thoughtful science fiction"}`)
+		response, err := client.GetRecommendations(ctx, &aitypes.RecommendationRequest{MediaType: "movie", Count: 1})
+		require.NoError(t, err)
+
+		require.Len(t, response.Items, 1)
+		assert.Equal(t, "Arrival", response.Items[0].Title)
+		assert.Equal(t, "Thoughtful science fiction", response.Explanation)
+		assert.Equal(t, 17, response.TokenUsage.TotalTokens)
+
+		format := fake.requests[0].ResponseFormat
+		require.NotNil(t, format)
+		assert.Equal(t, "json_schema", format.Type)
+		assert.Equal(t, []any{"items", "explanation"}, format.JSONSchema.Schema["required"])
+	})
+
+	t.Run("GenerateStructuredArray", func(t *testing.T) {
+		client, fake := newTestClient(t, "test-key", `{"result":["Alien","Sunshine"]}`)
+		var titles []string
+		err := client.GenerateStructured(ctx, "List two space horror films", &titles, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Alien", "Sunshine"}, titles)
+		assert.Equal(t, []any{ai.StructuredResultKey}, fake.requests[0].ResponseFormat.JSONSchema.Schema["required"])
+	})
+
+	t.Run("Conversation", func(t *testing.T) {
+		client, fake := newTestClient(t, "test-key", "Hi!", "You said hello.")
+		conversationID, err := client.StartConversation(ctx, "Be brief")
+		require.NoError(t, err)
+
+		_, err = client.SendMessage(ctx, conversationID, "Hello")
+		require.NoError(t, err)
+		reply, err := client.SendMessage(ctx, conversationID, "What did I say?")
+		require.NoError(t, err)
+
+		assert.Equal(t, "You said hello.", reply)
+		assert.Equal(t, []chatMessage{
+			{Role: "system", Content: "Be brief"},
+			{Role: "user", Content: "Hello"},
+			{Role: "assistant", Content: "Hi!"},
+			{Role: "user", Content: "What did I say?"},
+		}, fake.requests[1].Messages)
+
+		_, err = client.SendMessage(ctx, "conv-unknown", "Hello")
+		assert.Error(t, err)
+	})
+
+	t.Run("StreamRecommendationConversation", func(t *testing.T) {
+		client, fake := newTestClient(t, "test-key", "Try Arrival or Contact.")
+		conversationID, _, err := client.StartRecommendationConversation(ctx, "movie", nil, "")
+		require.NoError(t, err)
+
+		var deltas []string
+		reply, _, err := client.StreamRecommendationConversation(ctx, conversationID, "Something cerebral", nil, func(delta string) error {
+			deltas = append(deltas, delta)
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Try Arrival or Contact.", reply)
+		assert.Equal(t, []string{"Try ", "Arrival ", "or ", "Contact."}, deltas)
+		assert.True(t, fake.requests[0].Stream)
+
+		// The streamed reply is part of the history of the next turn
+		fake.replies = append(fake.replies, "You're welcome.")
+		_, _, err = client.ContinueRecommendationConversation(ctx, conversationID, "Thanks", nil)
+		require.NoError(t, err)
+		history := fake.requests[1].Messages
+		assert.Equal(t, chatMessage{Role: "assistant", Content: "Try Arrival or Contact."}, history[len(history)-2])
+	})
+	t.Run("ContinueConversationWithTools", func(t *testing.T) {
+		client, fake := newTestClient(t, "test-key", `call:search_library:{"query":"dune"}`, "You have Dune (2021).")
+		conversationID, err := client.StartConversation(ctx, "")
+		require.NoError(t, err)
+
+		tools := []aitypes.Tool{{Name: "search_library", Description: "Search the library", Parameters: map[string]any{"type": "object"}}}
+		turn, err := client.ContinueConversationWithTools(ctx, conversationID, "Do I have Dune?", nil, tools)
+		require.NoError(t, err)
+		require.Len(t, turn.ToolCalls, 1)
+		assert.Equal(t, "search_library", turn.ToolCalls[0].Name)
+		assert.Equal(t, `{"query":"dune"}`, string(turn.ToolCalls[0].Arguments))
+		assert.NotNil(t, fake.requests[0].Tools)
+
+		results := []aitypes.ToolResult{{CallID: "call_1", Name: "search_library", Content: `[{"id":7,"title":"Dune"}]`}}
+		turn, err = client.ContinueConversationWithTools(ctx, conversationID, "", results, tools)
+		require.NoError(t, err)
+		assert.Equal(t, "You have Dune (2021).", turn.Text)
+		assert.Empty(t, turn.ToolCalls)
+
+		// The second request carries the call and its result
+		messages := fake.requests[1].Messages
+		require.Len(t, messages, 3)
+		assert.Equal(t, "call_1", messages[1].ToolCalls[0].ID)
+		assert.Equal(t, chatMessage{Role: "tool", ToolCallID: "call_1", Content: `[{"id":7,"title":"Dune"}]`}, messages[2])
+	})
+
+	t.Run("CompactConversation", func(t *testing.T) {
+		client, fake := newTestClient(t, "test-key", "Hi!", "Then try Contact.")
+		conversationID, err := client.StartConversation(ctx, "Be brief")
+		require.NoError(t, err)
+		_, err = client.SendMessage(ctx, conversationID, "Hello")
+		require.NoError(t, err)
+
+		summary := "Earlier the user said they loved Arrival."
+		history := []aitypes.ChatMessage{{Role: "user", Content: "More like that?"}, {Role: "assistant", Content: "Sure."}}
+		require.NoError(t, client.CompactConversation(ctx, conversationID, summary, history))
+
+		_, err = client.SendMessage(ctx, conversationID, "Something else")
+		require.NoError(t, err)
+		assert.Equal(t, []chatMessage{
+			{Role: "system", Content: "Be brief"},
+			{Role: "system", Content: summary},
+			{Role: "user", Content: "More like that?"},
+			{Role: "assistant", Content: "Sure."},
+			{Role: "user", Content: "Something else"},
+		}, fake.requests[1].Messages)
+
+		assert.Error(t, client.CompactConversation(ctx, "conv-unknown", summary, nil))
+	})
+
+	t.Run("CreateEmbeddings", func(t *testing.T) {
+		client, _ := newTestClient(t, "test-key")
+		response, err := client.CreateEmbeddings(ctx, []string{"Alien", "Solaris"}, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "text-embedding-3-small", response.Model)
+		assert.Equal(t, [][]float32{{5, 1}, {7, 1}}, response.Embeddings)
+		assert.Equal(t, 6, response.TokenUsage.TotalTokens)
+		assert.True(t, client.GetCapabilities().SupportsEmbeddings)
+	})
+
+	t.Run("ReportUsage", func(t *testing.T) {
+		client, _ := newTestClient(t, "test-key", "Hello", "Hello again")
+		var reported []aitypes.TokenUsage
+		usageCtx := aitypes.WithUsageReporter(ctx, func(ctx context.Context, model string, usage aitypes.TokenUsage) {
+			assert.Equal(t, "gpt-4o-mini", model)
+			reported = append(reported, usage)
+		})
+
+		_, err := client.GenerateText(usageCtx, "Hi", nil)
+		require.NoError(t, err)
+		_, err = client.GenerateText(ctx, "Hi again", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []aitypes.TokenUsage{{PromptTokens: 12, CompletionTokens: 5, TotalTokens: 17}}, reported)
+	})
+}
@@ -0,0 +1,57 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	aitypes "suasor/clients/ai/types"
+	"suasor/utils/logger"
+)
+
+// CreateEmbeddings returns a vector for each text using the embeddings endpoint
+func (c *OpenAIClient) CreateEmbeddings(ctx context.Context, texts []string, model string) (*aitypes.EmbeddingResponse, error) {
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+	log := logger.LoggerFromContext(ctx)
+	log.Debug().
+		Str("model", model).
+		Int("texts", len(texts)).
+		Msg("Creating embeddings with OpenAI")
+
+	if len(texts) == 0 {
+		return &aitypes.EmbeddingResponse{Model: model}, nil
+	}
+
+	var response embeddingResponse
+	request := &embeddingRequest{Model: model, Input: texts}
+	if err := c.doRequest(ctx, http.MethodPost, "/embeddings", request, &response); err != nil {
+		log.Error().Err(err).Msg("Failed to create embeddings with OpenAI")
+		return nil, err
+	}
+	if len(response.Data) != len(texts) {
+		return nil, fmt.Errorf("openai returned %d embeddings for %d texts", len(response.Data), len(texts))
+	}
+
+	// The data is not guaranteed to be in input order, each embedding carries its index
+	embeddings := make([][]float32, len(texts))
+	for _, data := range response.Data {
+		if data.Index < 0 || data.Index >= len(texts) {
+			return nil, fmt.Errorf("openai returned an embedding for unknown input %d", data.Index)
+		}
+		embeddings[data.Index] = data.Embedding
+	}
+
+	usage := aitypes.TokenUsage{
+		PromptTokens: response.Usage.PromptTokens,
+		TotalTokens:  response.Usage.TotalTokens,
+	}
+	aitypes.ReportUsage(ctx, response.Model, usage)
+
+	return &aitypes.EmbeddingResponse{
+		Embeddings: embeddings,
+		Model:      response.Model,
+		TokenUsage: usage,
+	}, nil
+}
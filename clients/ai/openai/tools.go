@@ -0,0 +1,96 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	aitypes "suasor/clients/ai/types"
+	"suasor/utils/logger"
+)
+
+// ContinueConversationWithTools sends the message, or the results of the previous turn's tool calls,
+// offering the model the given tools. The calls and their results stay in the conversation history.
+func (c *OpenAIClient) ContinueConversationWithTools(ctx context.Context, conversationID string, message string, results []aitypes.ToolResult, tools []aitypes.Tool) (*aitypes.ToolTurn, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("conversationID", conversationID).
+		Int("tools", len(tools)).
+		Int("toolResults", len(results)).
+		Msg("Continuing conversation with tools on OpenAI")
+
+	c.mu.Lock()
+	conversation, exists := c.conversations[conversationID]
+	if !exists {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("conversation not found: %s", conversationID)
+	}
+	messages := make([]chatMessage, len(conversation.Messages), len(conversation.Messages)+len(results)+1)
+	copy(messages, conversation.Messages)
+	c.mu.Unlock()
+
+	var sent []chatMessage
+	if len(results) > 0 {
+		for _, result := range results {
+			sent = append(sent, chatMessage{Role: "tool", ToolCallID: result.CallID, Content: result.Content})
+		}
+	} else {
+		sent = append(sent, chatMessage{Role: "user", Content: message})
+	}
+
+	request := c.newChatRequest(nil)
+	request.Messages = append(messages, sent...)
+	if len(tools) > 0 {
+		request.Tools = toolDefinitions(tools)
+	}
+
+	response, err := c.createChatCompletion(ctx, request)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to continue conversation with tools on OpenAI")
+		return nil, err
+	}
+	reply := response.Choices[0].Message
+
+	c.mu.Lock()
+	conversation.Messages = append(conversation.Messages, sent...)
+	conversation.Messages = append(conversation.Messages, chatMessage{
+		Role:      "assistant",
+		Content:   reply.Content,
+		ToolCalls: reply.ToolCalls,
+	})
+	c.mu.Unlock()
+
+	turn := &aitypes.ToolTurn{
+		Text:       reply.Content,
+		TokenUsage: response.tokenUsage(),
+	}
+	for _, call := range reply.ToolCalls {
+		arguments := json.RawMessage(call.Function.Arguments)
+		if len(arguments) == 0 {
+			arguments = json.RawMessage("{}")
+		}
+		turn.ToolCalls = append(turn.ToolCalls, aitypes.ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: arguments,
+		})
+	}
+
+	return turn, nil
+}
+
+// toolDefinitions converts the tools to the function tools of the Chat Completions API
+func toolDefinitions(tools []aitypes.Tool) []toolDefinition {
+	definitions := make([]toolDefinition, len(tools))
+	for i, tool := range tools {
+		definitions[i] = toolDefinition{
+			Type: "function",
+			Function: toolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		}
+	}
+	return definitions
+}
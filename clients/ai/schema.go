@@ -0,0 +1,150 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// StructuredResultKey wraps non-object outputs, providers only accept an object at the top level
+const StructuredResultKey = "result"
+
+// StructuredSchema builds a JSON schema for the value outputSchema points to, for providers
+// that support schema constrained output. Anything that is not a struct is wrapped in an object
+// under StructuredResultKey. It returns nil when the value has no fixed shape (maps, interfaces),
+// plain JSON mode should be used then.
+func StructuredSchema(outputSchema any) (schema map[string]any, wrapped bool) {
+	t := reflect.TypeOf(outputSchema)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil, false
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			break
+		}
+		return typeSchema(t, map[reflect.Type]bool{}), false
+	case reflect.Map, reflect.Interface:
+		return nil, false
+	}
+
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			StructuredResultKey: typeSchema(t, map[reflect.Type]bool{}),
+		},
+		"required": []string{StructuredResultKey},
+	}, true
+}
+
+// typeSchema describes a Go type the way encoding/json would encode it
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string"}
+		}
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), seen)}
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return map[string]any{"type": "string", "format": "date-time"}
+		}
+		// Recursive types are left open rather than expanded forever
+		if seen[t] {
+			return map[string]any{"type": "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		properties := map[string]any{}
+		required := []string{}
+		addStructFields(t, seen, properties, &required)
+		return map[string]any{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		}
+	}
+
+	// Interfaces and anything else accept any value
+	return map[string]any{}
+}
+
+// addStructFields adds the exported fields of a struct, flattening embedded structs like encoding/json
+func addStructFields(t reflect.Type, seen map[reflect.Type]bool, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitempty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && field.Tag.Get("json") == "" && fieldType.Kind() == reflect.Struct {
+			addStructFields(fieldType, seen, properties, required)
+			continue
+		}
+
+		properties[name] = typeSchema(field.Type, seen)
+		if !omitempty {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func jsonFieldName(field reflect.StructField) (name string, omitempty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(options, "omitempty"), false
+}
+
+// DecodeStructured decodes a structured response into outputSchema, unwrapping it when
+// StructuredSchema wrapped the schema
+func DecodeStructured(content []byte, wrapped bool, outputSchema any) error {
+	if wrapped {
+		var result map[string]json.RawMessage
+		if err := json.Unmarshal(content, &result); err != nil {
+			return err
+		}
+		value, ok := result[StructuredResultKey]
+		if !ok {
+			return fmt.Errorf("structured response has no %q field", StructuredResultKey)
+		}
+		content = value
+	}
+	return json.Unmarshal(content, outputSchema)
+}
@@ -1,5 +1,7 @@
 package types
 
+import "encoding/json"
+
 // GenerationOptions contains options for AI text generation
 type GenerationOptions struct {
 	Temperature        float64 // Controls randomness (0.0-1.0)
@@ -13,11 +15,61 @@ type AICapabilities struct {
 	SupportsStructuredOutput bool     // Whether the model can output structured data like JSON
 	SupportsConversation     bool     // Whether the model supports conversational mode
 	SupportsStreaming        bool     // Whether the model supports streaming responses
+	SupportsToolCalling      bool     // Whether the model can call tools during a conversation
+	SupportsEmbeddings       bool     // Whether the client can create text embeddings
+	EmbeddingModel           string   // Model used by CreateEmbeddings when none is given
 	MaxContextTokens         int      // Maximum context window size
 	DefaultMaxTokens         int      // Default maximum tokens for responses
 	AvailableModels          []string // List of available models
 }
 
+// StreamHandler receives the text of a streamed reply piece by piece as it is generated.
+// Returning an error stops the stream.
+type StreamHandler func(delta string) error
+
+// Tool describes a function the model may call during a conversation
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"` // JSON schema of the arguments object
+}
+
+// ToolCall is a call the model asked for, Arguments is a JSON object
+type ToolCall struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// ToolResult is the output of a tool call, sent back to the model on the next turn
+type ToolResult struct {
+	CallID  string `json:"callId"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	IsError bool   `json:"isError,omitempty"`
+}
+
+// ChatMessage is a text message of a conversation history, Role is "user" or "assistant"
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ToolTurn is the model's reply on a conversation with tools.
+// The turn is final when it has no tool calls, otherwise the caller runs them and sends the results.
+type ToolTurn struct {
+	Text       string     `json:"text"`
+	ToolCalls  []ToolCall `json:"toolCalls,omitempty"`
+	TokenUsage TokenUsage `json:"tokenUsage,omitempty"`
+}
+
+// EmbeddingResponse holds one vector per input text, in the order of the inputs
+type EmbeddingResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+	Model      string      `json:"model"`
+	TokenUsage TokenUsage  `json:"tokenUsage,omitempty"`
+}
+
 // AIResponse represents a structured response from an AI client
 type AIResponse struct {
 	Content    string            // The primary content returned
@@ -0,0 +1,25 @@
+package types
+
+import "context"
+
+// UsageReporter is called with the token usage of every request an AI client makes
+type UsageReporter func(ctx context.Context, model string, usage TokenUsage)
+
+type usageReporterKey struct{}
+
+// WithUsageReporter returns a context whose AI requests report their token usage to reporter
+func WithUsageReporter(ctx context.Context, reporter UsageReporter) context.Context {
+	return context.WithValue(ctx, usageReporterKey{}, reporter)
+}
+
+// ReportUsage passes the token usage of a request to the context's reporter, if it has one
+func ReportUsage(ctx context.Context, model string, usage TokenUsage) {
+	reporter, ok := ctx.Value(usageReporterKey{}).(UsageReporter)
+	if !ok || reporter == nil {
+		return
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	reporter(ctx, model, usage)
+}
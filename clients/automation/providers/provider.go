@@ -53,7 +53,7 @@ type TagProvider interface {
 
 // CalendarProvider defines methods for working with calendar events
 type CalendarProvider interface {
-	GetCalendar(ctx context.Context, start, end time.Time) ([]*models.AutomationCalendarItem[types.AutomationData], error)
+	GetCalendar(ctx context.Context, start, end time.Time) ([]models.AutomationMediaItem[types.AutomationData], error)
 }
 
 // CommandProvider defines methods for executing commands
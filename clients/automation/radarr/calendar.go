@@ -3,6 +3,7 @@ package radarr
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"suasor/clients/automation/types"
@@ -63,11 +64,35 @@ func (r *RadarrClient) GetCalendar(ctx context.Context, start, end time.Time) ([
 			Year:             item.GetYear(),
 			Monitored:        item.GetMonitored(),
 			DownloadedStatus: status,
+			ExternalIDs:      movieExternalIDs(item.GetTmdbId(), item.GetImdbId()),
 			Data: types.AutomationMovie{
-				ReleaseDate: item.GetPhysicalRelease(),
+				ReleaseDate: movieReleaseDate(item.GetPhysicalRelease(), item.GetDigitalRelease(), item.GetInCinemas()),
 			},
 		})
 	}
 
 	return result, nil
 }
+
+// movieExternalIDs returns the TMDB and IMDB IDs Radarr reports for a movie
+func movieExternalIDs(tmdbID int32, imdbID string) []types.ExternalID {
+	var ids []types.ExternalID
+	if tmdbID != 0 {
+		ids = append(ids, types.ExternalID{Source: "tmdb", Value: strconv.Itoa(int(tmdbID))})
+	}
+	if imdbID != "" {
+		ids = append(ids, types.ExternalID{Source: "imdb", Value: imdbID})
+	}
+	return ids
+}
+
+// movieReleaseDate prefers the home release and falls back to the cinema date
+func movieReleaseDate(physical, digital, cinemas time.Time) time.Time {
+	if !physical.IsZero() {
+		return physical
+	}
+	if !digital.IsZero() {
+		return digital
+	}
+	return cinemas
+}
@@ -3,6 +3,7 @@ package sonarr
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"suasor/utils/logger"
@@ -56,6 +57,7 @@ func (s *SonarrClient) GetCalendar(ctx context.Context, start, end time.Time) ([
 			Year:             *item.GetSeries().Year,
 			Monitored:        *item.GetSeries().Monitored,
 			DownloadedStatus: determineDownloadStatus(item.Series.GetStatistics()),
+			ExternalIDs:      seriesExternalIDs(item.Series.GetTvdbId(), item.Series.GetImdbId()),
 			Data: types.AutomationEpisode{
 				ReleaseDate: item.GetAirDateUtc(),
 			},
@@ -65,3 +67,15 @@ func (s *SonarrClient) GetCalendar(ctx context.Context, start, end time.Time) ([
 
 	return result, nil
 }
+
+// seriesExternalIDs returns the TVDB and IMDB IDs Sonarr reports for a series
+func seriesExternalIDs(tvdbID int32, imdbID string) []types.ExternalID {
+	var ids []types.ExternalID
+	if tvdbID != 0 {
+		ids = append(ids, types.ExternalID{Source: "tvdb", Value: strconv.Itoa(int(tvdbID))})
+	}
+	if imdbID != "" {
+		ids = append(ids, types.ExternalID{Source: "imdb", Value: imdbID})
+	}
+	return ids
+}
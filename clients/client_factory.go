@@ -30,13 +30,19 @@ var (
 	once     sync.Once
 )
 
+// NewClientProviderFactoryService creates a factory service with no providers registered.
+// The application uses the singleton, a separate instance keeps tests from sharing its providers.
+func NewClientProviderFactoryService() *ClientProviderFactoryService {
+	return &ClientProviderFactoryService{
+		factories: make(map[types.ClientType]ClientProviderFactory),
+		instances: make(map[ClientKey]Client),
+	}
+}
+
 // GetClientProviderFactoryService returns the singleton instance
 func GetClientProviderFactoryService() *ClientProviderFactoryService {
 	once.Do(func() {
-		instance = &ClientProviderFactoryService{
-			factories: make(map[types.ClientType]ClientProviderFactory),
-			instances: make(map[ClientKey]Client),
-		}
+		instance = NewClientProviderFactoryService()
 	})
 	return instance
 }
@@ -275,6 +281,42 @@ func (s *ClientProviderFactoryService) GetCollectionProvider(ctx context.Context
 	return collectionProvider, nil
 }
 
+func (s *ClientProviderFactoryService) GetPlayStateProvider(ctx context.Context, clientID uint64, config types.ClientConfig) (providers.PlayStateProvider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("cannot get play state provider: config is nil for clientID=%d", clientID)
+	}
+
+	client, err := s.GetClient(ctx, clientID, config)
+	if err != nil {
+		return nil, err
+	}
+
+	playStateProvider, ok := client.(providers.PlayStateProvider)
+	if !ok {
+		return nil, fmt.Errorf("client does not implement PlayStateProvider interface")
+	}
+
+	return playStateProvider, nil
+}
+
+func (s *ClientProviderFactoryService) GetUserDataProvider(ctx context.Context, clientID uint64, config types.ClientConfig) (providers.UserDataProvider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("cannot get user data provider: config is nil for clientID=%d", clientID)
+	}
+
+	client, err := s.GetClient(ctx, clientID, config)
+	if err != nil {
+		return nil, err
+	}
+
+	userDataProvider, ok := client.(providers.UserDataProvider)
+	if !ok {
+		return nil, fmt.Errorf("client does not implement UserDataProvider interface")
+	}
+
+	return userDataProvider, nil
+}
+
 func (s *ClientProviderFactoryService) GetListProviderPlaylist(ctx context.Context, clientID uint64, config types.ClientConfig) (providers.ListProvider[*mediatypes.Playlist], error) {
 	if config == nil {
 		return nil, fmt.Errorf("cannot get list provider: config is nil for clientID=%d", clientID)
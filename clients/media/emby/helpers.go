@@ -319,6 +319,12 @@ func ApplyClientQueryOptions(ctx context.Context, queryParams *embyclient.ItemsS
 		queryParams.Ids = optional.NewString(options.ItemIDs)
 	}
 
+	// Playlist and collection queries already use ParentId for the list itself
+	if libraryID := options.LibraryID(); libraryID != "" && !queryParams.ParentId.IsSet() {
+		log.Debug().Str("libraryID", libraryID).Msg("Applying library filter")
+		queryParams.ParentId = optional.NewString(libraryID)
+	}
+
 	if options.Limit > 0 {
 		log.Debug().Int("limit", options.Limit).Msg("Applying limit filter")
 		queryParams.Limit = optional.NewInt32(int32(options.Limit))
@@ -446,6 +452,12 @@ func ApplyClientQueryOptions(ctx context.Context, queryParams *embyclient.ItemsS
 		queryParams.MaxPremiereDate = optional.NewString(options.DateAddedBefore.Format(time.RFC3339))
 	}
 
+	// Items are saved when they are added and whenever their metadata changes
+	if options.UpdatedAfter != nil {
+		log.Debug().Time("updatedAfter", *options.UpdatedAfter).Msg("Applying updated after filter")
+		queryParams.MinDateLastSaved = optional.NewString(options.UpdatedAfter.Format(time.RFC3339))
+	}
+
 	if options.ReleasedAfter != nil {
 		log.Debug().Time("releasedAfter", *options.ReleasedAfter).Msg("Applying released after filter")
 		queryParams.MinPremiereDate = optional.NewString(options.ReleasedAfter.Format(time.RFC3339))
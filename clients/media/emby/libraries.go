@@ -0,0 +1,64 @@
+package emby
+
+import (
+	"context"
+	"fmt"
+
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+func (e *EmbyClient) SupportsLibraries() bool { return true }
+
+// GetLibraries returns the libraries the configured user can see on the Emby server
+func (e *EmbyClient) GetLibraries(ctx context.Context) ([]*models.MediaLibrary, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Debug().
+		Uint64("clientID", e.GetClientID()).
+		Msg("Retrieving libraries from Emby server")
+
+	userID := e.getUserID()
+	if userID == "" {
+		return nil, fmt.Errorf("failed to get emby libraries: missing user ID")
+	}
+
+	views, _, err := e.client.UserViewsServiceApi.GetUsersByUseridViews(ctx, userID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emby libraries: %w", err)
+	}
+
+	libraries := make([]*models.MediaLibrary, 0, len(views.Items))
+	for _, view := range views.Items {
+		libraries = append(libraries, &models.MediaLibrary{
+			ClientID:   e.GetClientID(),
+			ClientType: e.GetClientType(),
+			LibraryID:  view.Id,
+			Name:       view.Name,
+			Type:       embyLibraryType(view.CollectionType),
+			ItemCount:  int(view.ChildCount),
+		})
+	}
+
+	log.Debug().
+		Uint64("clientID", e.GetClientID()).
+		Int("libraryCount", len(libraries)).
+		Msg("Retrieved libraries from Emby server")
+
+	return libraries, nil
+}
+
+func embyLibraryType(collectionType string) models.MediaLibraryType {
+	switch collectionType {
+	case "movies":
+		return models.MediaLibraryTypeMovies
+	case "tvshows":
+		return models.MediaLibraryTypeSeries
+	case "music":
+		return models.MediaLibraryTypeMusic
+	case "", "mixed", "folders":
+		return models.MediaLibraryTypeMixed
+	default:
+		return models.MediaLibraryTypeOther
+	}
+}
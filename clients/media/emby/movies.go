@@ -14,6 +14,11 @@ import (
 
 // GetMovies retrieves movies from the Emby server
 func (e *EmbyClient) GetMovies(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Movie], error) {
+	// ParentId only takes a single library, query each selected library separately
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, e.GetMovies)
+	}
+
 	log := logger.LoggerFromContext(ctx)
 
 	log.Info().
@@ -16,6 +16,11 @@ func (e *EmbyClient) SupportsMusic() bool { return true }
 
 // GetMusic retrieves music tracks from the Emby server
 func (e *EmbyClient) GetMusicTracks(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Track], error) {
+	// ParentId only takes a single library, query each selected library separately
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, e.GetMusicTracks)
+	}
+
 	log := logger.LoggerFromContext(ctx)
 
 	log.Info().
@@ -68,6 +73,11 @@ func (e *EmbyClient) GetMusicTracks(ctx context.Context, options *types.QueryOpt
 }
 
 func (e *EmbyClient) GetMusicArtists(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Artist], error) {
+	// ParentId only takes a single library, query each selected library separately
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, e.GetMusicArtists)
+	}
+
 	log := logger.LoggerFromContext(ctx)
 
 	log.Info().
@@ -88,6 +98,9 @@ func (e *EmbyClient) GetMusicArtists(ctx context.Context, options *types.QueryOp
 		if options.Offset > 0 {
 			opts.StartIndex = optional.NewInt32(int32(options.Offset))
 		}
+		if libraryID := options.LibraryID(); libraryID != "" {
+			opts.ParentId = optional.NewString(libraryID)
+		}
 		if options.Sort != "" {
 			// TODO: work on translating types to external sortBy,
 			// they dont have any type definitions on this so we might need to look into it a bit
@@ -135,6 +148,11 @@ func (e *EmbyClient) GetMusicArtists(ctx context.Context, options *types.QueryOp
 }
 
 func (e *EmbyClient) GetMusicAlbums(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Album], error) {
+	// ParentId only takes a single library, query each selected library separately
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, e.GetMusicAlbums)
+	}
+
 	log := logger.LoggerFromContext(ctx)
 
 	log.Info().
@@ -0,0 +1,138 @@
+package emby
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antihax/optional"
+	"suasor/clients/media/types"
+	embyclient "suasor/internal/clients/embyAPI"
+	"suasor/utils/logger"
+)
+
+func (e *EmbyClient) SupportsPlayState() bool { return true }
+
+// MarkPlayed marks an item as played for the configured Emby user
+func (e *EmbyClient) MarkPlayed(ctx context.Context, itemID string) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", e.GetClientID()).
+		Str("itemID", itemID).
+		Msg("Marking item as played on Emby server")
+
+	userID := e.getUserID()
+	if userID == "" {
+		return fmt.Errorf("user ID is required to mark items as played")
+	}
+
+	_, _, err := e.client.PlaystateServiceApi.PostUsersByUseridPlayeditemsById(ctx, userID, itemID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to mark item %s as played: %w", itemID, err)
+	}
+	return nil
+}
+
+// MarkUnplayed marks an item as unplayed for the configured Emby user
+func (e *EmbyClient) MarkUnplayed(ctx context.Context, itemID string) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", e.GetClientID()).
+		Str("itemID", itemID).
+		Msg("Marking item as unplayed on Emby server")
+
+	userID := e.getUserID()
+	if userID == "" {
+		return fmt.Errorf("user ID is required to mark items as unplayed")
+	}
+
+	_, _, err := e.client.PlaystateServiceApi.DeleteUsersByUseridPlayeditemsById(ctx, userID, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to mark item %s as unplayed: %w", itemID, err)
+	}
+	return nil
+}
+
+// SetPlaybackPosition updates the resume position of an item for the configured Emby user
+func (e *EmbyClient) SetPlaybackPosition(ctx context.Context, itemID string, positionSeconds int) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", e.GetClientID()).
+		Str("itemID", itemID).
+		Int("positionSeconds", positionSeconds).
+		Msg("Setting playback position on Emby server")
+
+	userID := e.getUserID()
+	if userID == "" {
+		return fmt.Errorf("user ID is required to set playback position")
+	}
+
+	err := e.updateUserData(ctx, userID, itemID, func(data *embyclient.UserItemDataDto) {
+		data.PlaybackPositionTicks = int64(positionSeconds) * 10000000
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set playback position for item %s: %w", itemID, err)
+	}
+	return nil
+}
+
+// GetResumePoints returns the items on the configured Emby user's resume list
+func (e *EmbyClient) GetResumePoints(ctx context.Context) ([]types.ResumePoint, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	userID := e.getUserID()
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required to get resume points")
+	}
+
+	opts := embyclient.ItemsServiceApiGetUsersByUseridItemsResumeOpts{
+		Recursive:        optional.NewBool(true),
+		IncludeItemTypes: optional.NewString("Movie,Episode,Audio"),
+		EnableUserData:   optional.NewBool(true),
+	}
+	results, _, err := e.client.ItemsServiceApi.GetUsersByUseridItemsResume(ctx, userID, &opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume items: %w", err)
+	}
+
+	points := make([]types.ResumePoint, 0, len(results.Items))
+	for _, item := range results.Items {
+		if item.Id == "" || item.UserData == nil {
+			continue
+		}
+		mediaType := embyResumeMediaType(item.Type_)
+		if mediaType == "" {
+			continue
+		}
+		points = append(points, types.ResumePoint{
+			ItemID:          item.Id,
+			MediaType:       mediaType,
+			PositionSeconds: int(item.UserData.PlaybackPositionTicks / 10000000),
+			DurationSeconds: int(item.RunTimeTicks / 10000000),
+			LastPlayedAt:    item.UserData.LastPlayedDate,
+		})
+	}
+
+	log.Debug().
+		Uint64("clientID", e.GetClientID()).
+		Int("resumePoints", len(points)).
+		Msg("Retrieved resume points from Emby server")
+
+	return points, nil
+}
+
+// embyResumeMediaType maps the types of item that can be resumed, empty for the others
+func embyResumeMediaType(itemType string) types.MediaType {
+	switch itemType {
+	case "Movie":
+		return types.MediaTypeMovie
+	case "Episode":
+		return types.MediaTypeEpisode
+	case "Audio":
+		return types.MediaTypeTrack
+	default:
+		return ""
+	}
+}
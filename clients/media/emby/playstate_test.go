@@ -0,0 +1,36 @@
+package emby
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbySetPlaybackPositionKeepsUserData(t *testing.T) {
+	var posted map[string]any
+	client := newTestUserDataClient(t, map[string]any{
+		"Rating":                6,
+		"PlaybackPositionTicks": 600000000,
+		"IsFavorite":            true,
+	}, &posted)
+
+	err := client.SetPlaybackPosition(context.Background(), "42", 90)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(900000000), posted["PlaybackPositionTicks"])
+	assert.Equal(t, float64(6), posted["Rating"], "the rating is kept")
+	assert.Equal(t, true, posted["IsFavorite"], "the favorite is kept")
+}
+
+func TestEmbyResetPlaybackPosition(t *testing.T) {
+	var posted map[string]any
+	client := newTestUserDataClient(t, map[string]any{"PlaybackPositionTicks": 600000000}, &posted)
+
+	err := client.SetPlaybackPosition(context.Background(), "42", 0)
+	require.NoError(t, err)
+
+	require.Contains(t, posted, "PlaybackPositionTicks", "a zero position has to be sent to reset the old one")
+	assert.Equal(t, float64(0), posted["PlaybackPositionTicks"])
+}
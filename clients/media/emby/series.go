@@ -16,6 +16,11 @@ func (e *EmbyClient) SupportsTVShows() bool { return true }
 
 // GetSeriess retrieves TV shows from the Emby server
 func (e *EmbyClient) GetSeries(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Series], error) {
+	// ParentId only takes a single library, query each selected library separately
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, e.GetSeries)
+	}
+
 	log := logger.LoggerFromContext(ctx)
 
 	log.Info().
@@ -0,0 +1,91 @@
+package emby
+
+import (
+	"context"
+	"fmt"
+
+	mediatypes "suasor/clients/media/types"
+	embyclient "suasor/internal/clients/embyAPI"
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+func (e *EmbyClient) SupportsSessions() bool { return true }
+
+// GetSessions returns the sessions currently playing on the Emby server
+func (e *EmbyClient) GetSessions(ctx context.Context) ([]*models.PlaybackSession, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Debug().
+		Uint64("clientID", e.GetClientID()).
+		Msg("Retrieving active sessions from Emby server")
+
+	embySessions, _, err := e.client.SessionsServiceApi.GetSessions(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emby sessions: %w", err)
+	}
+
+	sessions := make([]*models.PlaybackSession, 0, len(embySessions))
+	for _, embySession := range embySessions {
+		// Idle sessions have nothing playing
+		if embySession.NowPlayingItem == nil {
+			continue
+		}
+		item := embySession.NowPlayingItem
+
+		session := &models.PlaybackSession{
+			ClientID:        e.GetClientID(),
+			ClientType:      e.GetClientType(),
+			SessionID:       embySession.Id,
+			UserName:        embySession.UserName,
+			DeviceName:      embySession.DeviceName,
+			DeviceID:        embySession.DeviceId,
+			Player:          embySession.Client,
+			ClientItemID:    item.Id,
+			Title:           item.Name,
+			MediaType:       embySessionMediaType(item.Type_),
+			DurationSeconds: int(item.RunTimeTicks / 10000000),
+			PlayMethod:      models.PlayMethodUnknown,
+		}
+		if item.SeriesName != "" {
+			session.Title = fmt.Sprintf("%s - %s", item.SeriesName, item.Name)
+		}
+		if playState := embySession.PlayState; playState != nil {
+			session.PositionSeconds = int(playState.PositionTicks / 10000000)
+			session.IsPaused = playState.IsPaused
+			if playState.PlayMethod != nil {
+				switch *playState.PlayMethod {
+				case embyclient.TRANSCODE_PlayMethod:
+					session.PlayMethod = models.PlayMethodTranscode
+				case embyclient.DIRECT_STREAM_PlayMethod:
+					session.PlayMethod = models.PlayMethodDirectStream
+				case embyclient.DIRECT_PLAY_PlayMethod:
+					session.PlayMethod = models.PlayMethodDirectPlay
+				}
+			}
+		}
+		session.UpdateProgress()
+
+		sessions = append(sessions, session)
+	}
+
+	log.Debug().
+		Uint64("clientID", e.GetClientID()).
+		Int("sessionCount", len(sessions)).
+		Msg("Retrieved active sessions from Emby server")
+
+	return sessions, nil
+}
+
+func embySessionMediaType(itemType string) mediatypes.MediaType {
+	switch itemType {
+	case "Movie":
+		return mediatypes.MediaTypeMovie
+	case "Episode":
+		return mediatypes.MediaTypeEpisode
+	case "Audio":
+		return mediatypes.MediaTypeTrack
+	default:
+		return mediatypes.MediaType(itemType)
+	}
+}
@@ -0,0 +1,82 @@
+package emby
+
+import (
+	"context"
+	"fmt"
+
+	embyclient "suasor/internal/clients/embyAPI"
+	"suasor/utils/logger"
+)
+
+func (e *EmbyClient) SupportsUserData() bool { return true }
+
+// SetFavorite marks or unmarks an item as a favorite for the configured Emby user
+func (e *EmbyClient) SetFavorite(ctx context.Context, itemID string, favorite bool) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", e.GetClientID()).
+		Str("itemID", itemID).
+		Bool("favorite", favorite).
+		Msg("Setting favorite status on Emby server")
+
+	userID := e.getUserID()
+	if userID == "" {
+		return fmt.Errorf("user ID is required to update favorites")
+	}
+
+	var err error
+	if favorite {
+		_, _, err = e.client.UserLibraryServiceApi.PostUsersByUseridFavoriteitemsById(ctx, userID, itemID)
+	} else {
+		_, _, err = e.client.UserLibraryServiceApi.DeleteUsersByUseridFavoriteitemsById(ctx, userID, itemID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update favorite status for item %s: %w", itemID, err)
+	}
+	return nil
+}
+
+// SetRating sets the user's rating for an item for the configured Emby user
+func (e *EmbyClient) SetRating(ctx context.Context, itemID string, rating float32) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", e.GetClientID()).
+		Str("itemID", itemID).
+		Float32("rating", rating).
+		Msg("Setting rating on Emby server")
+
+	userID := e.getUserID()
+	if userID == "" {
+		return fmt.Errorf("user ID is required to update ratings")
+	}
+
+	// A rating of zero clears it
+	err := e.updateUserData(ctx, userID, itemID, func(data *embyclient.UserItemDataDto) {
+		data.Rating = float64(max(rating, 0))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set rating for item %s: %w", itemID, err)
+	}
+	return nil
+}
+
+// updateUserData reads the user's data for an item, changes it and posts the whole record back.
+// The fields that aren't changed are sent with their current values instead of being left empty.
+func (e *EmbyClient) updateUserData(ctx context.Context, userID string, itemID string, update func(data *embyclient.UserItemDataDto)) error {
+	item, _, err := e.client.UserLibraryServiceApi.GetUsersByUseridItemsById(ctx, userID, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to get user data: %w", err)
+	}
+
+	data := embyclient.UserItemDataDto{}
+	if item.UserData != nil {
+		data = *item.UserData
+	}
+	data.ItemId = itemID
+	update(&data)
+
+	_, err = e.client.PlaystateServiceApi.PostUsersByUseridItemsByItemidUserdata(ctx, data, userID, itemID)
+	return err
+}
@@ -0,0 +1,65 @@
+package emby
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clienttypes "suasor/clients/types"
+)
+
+// newTestUserDataClient serves the user's data for item 42 and records what is posted back
+func newTestUserDataClient(t *testing.T, userData map[string]any, posted *map[string]any) *EmbyClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/Users/user-1/Items/42":
+			json.NewEncoder(w).Encode(map[string]any{"Id": "42", "UserData": userData})
+		case r.Method == http.MethodPost && r.URL.Path == "/Users/user-1/Items/42/UserData":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(posted))
+			w.Write([]byte("{}"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := clienttypes.NewEmbyConfig("", "user-1", server.URL, "test-key", true, false)
+	client, err := NewEmbyClient(context.Background(), nil, 1, &config)
+	require.NoError(t, err)
+	return client.(*EmbyClient)
+}
+
+func TestEmbySetRatingKeepsUserData(t *testing.T) {
+	var posted map[string]any
+	client := newTestUserDataClient(t, map[string]any{
+		"Rating":                4,
+		"PlaybackPositionTicks": 600000000,
+		"IsFavorite":            true,
+	}, &posted)
+
+	err := client.SetRating(context.Background(), "42", 8)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(8), posted["Rating"])
+	assert.Equal(t, float64(600000000), posted["PlaybackPositionTicks"], "the resume position is kept")
+	assert.Equal(t, true, posted["IsFavorite"], "the favorite is kept")
+}
+
+func TestEmbyClearRating(t *testing.T) {
+	var posted map[string]any
+	client := newTestUserDataClient(t, map[string]any{"Rating": 4}, &posted)
+
+	err := client.SetRating(context.Background(), "42", 0)
+	require.NoError(t, err)
+
+	require.Contains(t, posted, "Rating", "a zero rating has to be sent to clear the old one")
+	assert.Equal(t, float64(0), posted["Rating"])
+	assert.NotContains(t, posted, "LastPlayedDate")
+}
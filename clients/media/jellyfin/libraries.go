@@ -0,0 +1,64 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+func (j *JellyfinClient) SupportsLibraries() bool { return true }
+
+// GetLibraries returns the libraries the configured user can see on the Jellyfin server
+func (j *JellyfinClient) GetLibraries(ctx context.Context) ([]*models.MediaLibrary, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Debug().
+		Uint64("clientID", j.GetClientID()).
+		Msg("Retrieving libraries from Jellyfin server")
+
+	viewsReq := j.client.UserViewsAPI.GetUserViews(ctx)
+	if j.getUserID() != "" {
+		viewsReq = viewsReq.UserId(j.getUserID())
+	}
+
+	result, _, err := viewsReq.Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jellyfin libraries: %w", err)
+	}
+
+	libraries := make([]*models.MediaLibrary, 0, len(result.Items))
+	for _, view := range result.Items {
+		libraries = append(libraries, &models.MediaLibrary{
+			ClientID:   j.GetClientID(),
+			ClientType: j.GetClientType(),
+			LibraryID:  view.GetId(),
+			Name:       view.GetName(),
+			Type:       jellyfinLibraryType(string(view.GetCollectionType())),
+			ItemCount:  int(view.GetChildCount()),
+		})
+	}
+
+	log.Debug().
+		Uint64("clientID", j.GetClientID()).
+		Int("libraryCount", len(libraries)).
+		Msg("Retrieved libraries from Jellyfin server")
+
+	return libraries, nil
+}
+
+func jellyfinLibraryType(collectionType string) models.MediaLibraryType {
+	switch collectionType {
+	case "movies":
+		return models.MediaLibraryTypeMovies
+	case "tvshows":
+		return models.MediaLibraryTypeSeries
+	case "music":
+		return models.MediaLibraryTypeMusic
+	case "", "mixed", "folders":
+		return models.MediaLibraryTypeMixed
+	default:
+		return models.MediaLibraryTypeOther
+	}
+}
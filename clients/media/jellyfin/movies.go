@@ -23,6 +23,11 @@ var defaultExtraFields = []jellyfin.ItemFields{
 func (j *JellyfinClient) SupportsMovies() bool { return true }
 
 func (j *JellyfinClient) GetMovies(ctx context.Context, options *t.QueryOptions) ([]*models.MediaItem[*t.Movie], error) {
+	// ParentId only takes a single library, query each selected library separately
+	if options.HasMultipleLibraries() {
+		return t.ForEachLibrary(ctx, options, j.GetMovies)
+	}
+
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
 
@@ -117,6 +122,12 @@ func (j *JellyfinClient) GetMovies(ctx context.Context, options *t.QueryOptions)
 			requestBuilder = requestBuilder.Genres([]string{options.Genre})
 		}
 
+		// Library filter
+		if libraryID := options.LibraryID(); libraryID != "" {
+			log.Debug().Str("libraryID", libraryID).Msg("Adding library filter")
+			requestBuilder = requestBuilder.ParentId(libraryID)
+		}
+
 		// Favorite filter
 		if options.Favorites {
 			log.Debug().Msg("Adding favorites filter")
@@ -14,6 +14,10 @@ import (
 
 // Array of music types
 func (j *JellyfinClient) GetMusicTracks(ctx context.Context, options *t.QueryOptions) ([]models.MediaItem[*t.Track], error) {
+	// ParentId only takes a single library, query each selected library separately
+	if options.HasMultipleLibraries() {
+		return t.ForEachLibrary(ctx, options, j.GetMusicTracks)
+	}
 
 	log := logger.LoggerFromContext(ctx)
 
@@ -102,6 +106,10 @@ func (j *JellyfinClient) GetMusicTracks(ctx context.Context, options *t.QueryOpt
 }
 
 func (j *JellyfinClient) GetMusicArtists(ctx context.Context, options *t.QueryOptions) ([]models.MediaItem[*t.Artist], error) {
+	// ParentId only takes a single library, query each selected library separately
+	if options.HasMultipleLibraries() {
+		return t.ForEachLibrary(ctx, options, j.GetMusicArtists)
+	}
 
 	log := logger.LoggerFromContext(ctx)
 
@@ -172,6 +180,10 @@ func (j *JellyfinClient) GetMusicArtists(ctx context.Context, options *t.QueryOp
 }
 
 func (j *JellyfinClient) GetMusicAlbums(ctx context.Context, options *t.QueryOptions) ([]models.MediaItem[*t.Album], error) {
+	// ParentId only takes a single library, query each selected library separately
+	if options.HasMultipleLibraries() {
+		return t.ForEachLibrary(ctx, options, j.GetMusicAlbums)
+	}
 
 	log := logger.LoggerFromContext(ctx)
 
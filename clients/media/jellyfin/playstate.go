@@ -0,0 +1,140 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+
+	jellyfin "github.com/sj14/jellyfin-go/api"
+	"suasor/clients/media/types"
+	"suasor/utils/logger"
+)
+
+func (j *JellyfinClient) SupportsPlayState() bool { return true }
+
+// MarkPlayed marks an item as played for the configured Jellyfin user
+func (j *JellyfinClient) MarkPlayed(ctx context.Context, itemID string) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", j.GetClientID()).
+		Str("itemID", itemID).
+		Msg("Marking item as played on Jellyfin server")
+
+	userID := j.getUserID()
+	if userID == "" {
+		return fmt.Errorf("user ID is required to mark items as played")
+	}
+
+	_, _, err := j.client.PlaystateAPI.MarkPlayedItem(ctx, itemID).UserId(userID).Execute()
+	if err != nil {
+		return fmt.Errorf("failed to mark item %s as played: %w", itemID, err)
+	}
+	return nil
+}
+
+// MarkUnplayed marks an item as unplayed for the configured Jellyfin user
+func (j *JellyfinClient) MarkUnplayed(ctx context.Context, itemID string) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", j.GetClientID()).
+		Str("itemID", itemID).
+		Msg("Marking item as unplayed on Jellyfin server")
+
+	userID := j.getUserID()
+	if userID == "" {
+		return fmt.Errorf("user ID is required to mark items as unplayed")
+	}
+
+	_, _, err := j.client.PlaystateAPI.MarkUnplayedItem(ctx, itemID).UserId(userID).Execute()
+	if err != nil {
+		return fmt.Errorf("failed to mark item %s as unplayed: %w", itemID, err)
+	}
+	return nil
+}
+
+// SetPlaybackPosition updates the resume position of an item for the configured Jellyfin user
+func (j *JellyfinClient) SetPlaybackPosition(ctx context.Context, itemID string, positionSeconds int) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", j.GetClientID()).
+		Str("itemID", itemID).
+		Int("positionSeconds", positionSeconds).
+		Msg("Setting playback position on Jellyfin server")
+
+	userID := j.getUserID()
+	if userID == "" {
+		return fmt.Errorf("user ID is required to set playback position")
+	}
+
+	dto := jellyfin.NewUpdateUserItemDataDto()
+	dto.SetPlaybackPositionTicks(int64(positionSeconds) * 10000000)
+
+	_, _, err := j.client.ItemsAPI.UpdateItemUserData(ctx, itemID).
+		UserId(userID).
+		UpdateUserItemDataDto(*dto).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to set playback position for item %s: %w", itemID, err)
+	}
+	return nil
+}
+
+// GetResumePoints returns the items on the configured Jellyfin user's resume list
+func (j *JellyfinClient) GetResumePoints(ctx context.Context) ([]types.ResumePoint, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	userID := j.getUserID()
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required to get resume points")
+	}
+
+	results, _, err := j.client.ItemsAPI.GetResumeItems(ctx).
+		UserId(userID).
+		EnableUserData(true).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume items: %w", err)
+	}
+
+	points := make([]types.ResumePoint, 0, len(results.Items))
+	for _, item := range results.Items {
+		if item.Id == nil || item.Type == nil || !item.UserData.IsSet() || item.UserData.Get() == nil {
+			continue
+		}
+		mediaType := jellyfinResumeMediaType(*item.Type)
+		if mediaType == "" {
+			continue
+		}
+		userData := item.UserData.Get()
+		points = append(points, types.ResumePoint{
+			ItemID:          *item.Id,
+			MediaType:       mediaType,
+			PositionSeconds: int(userData.GetPlaybackPositionTicks() / 10000000),
+			DurationSeconds: int(item.GetRunTimeTicks() / 10000000),
+			LastPlayedAt:    userData.GetLastPlayedDate(),
+		})
+	}
+
+	log.Debug().
+		Uint64("clientID", j.GetClientID()).
+		Int("resumePoints", len(points)).
+		Msg("Retrieved resume points from Jellyfin server")
+
+	return points, nil
+}
+
+// jellyfinResumeMediaType maps the kinds of item that can be resumed, empty for the others
+func jellyfinResumeMediaType(kind jellyfin.BaseItemKind) types.MediaType {
+	switch kind {
+	case jellyfin.BASEITEMKIND_MOVIE:
+		return types.MediaTypeMovie
+	case jellyfin.BASEITEMKIND_EPISODE:
+		return types.MediaTypeEpisode
+	case jellyfin.BASEITEMKIND_AUDIO:
+		return types.MediaTypeTrack
+	default:
+		return ""
+	}
+}
@@ -681,6 +681,12 @@ func (j *JellyfinQueryOptions) FromQueryOptions(ctx context.Context, options *ty
 		j.Ids = &ids
 	}
 
+	// Library
+	if libraryID := options.LibraryID(); libraryID != "" {
+		log.Debug().Str("libraryID", libraryID).Msg("Applying library filter")
+		j.ParentId = &libraryID
+	}
+
 	// Limit
 	if options.Limit > 0 {
 		log.Debug().Int("limit", options.Limit).Msg("Applying limit filter")
@@ -856,6 +862,12 @@ func (j *JellyfinQueryOptions) FromQueryOptions(ctx context.Context, options *ty
 		j.MaxPremiereDate = options.DateAddedBefore
 	}
 
+	// Items are saved when they are added and whenever their metadata changes
+	if options.UpdatedAfter != nil && !options.UpdatedAfter.IsZero() {
+		log.Debug().Time("updatedAfter", *options.UpdatedAfter).Msg("Applying updated after filter")
+		j.MinDateLastSaved = options.UpdatedAfter
+	}
+
 	if options.ReleasedAfter != nil && !options.ReleasedAfter.IsZero() {
 		log.Debug().Time("releasedAfter", *options.ReleasedAfter).Msg("Applying released after filter")
 		j.MinPremiereDate = options.ReleasedAfter
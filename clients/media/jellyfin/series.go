@@ -14,6 +14,11 @@ import (
 func (j *JellyfinClient) SupportsSeries() bool { return true }
 
 func (j *JellyfinClient) GetSeries(ctx context.Context, options *t.QueryOptions) ([]*models.MediaItem[*t.Series], error) {
+	// ParentId only takes a single library, query each selected library separately
+	if options.HasMultipleLibraries() {
+		return t.ForEachLibrary(ctx, options, j.GetSeries)
+	}
+
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
 
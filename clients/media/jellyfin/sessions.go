@@ -0,0 +1,97 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+
+	jellyfin "github.com/sj14/jellyfin-go/api"
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+// activeSessionWindow limits sessions to ones that have reported activity recently
+const activeSessionWindow = 960
+
+func (j *JellyfinClient) SupportsSessions() bool { return true }
+
+// GetSessions returns the sessions currently playing on the Jellyfin server
+func (j *JellyfinClient) GetSessions(ctx context.Context) ([]*models.PlaybackSession, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Debug().
+		Uint64("clientID", j.GetClientID()).
+		Msg("Retrieving active sessions from Jellyfin server")
+
+	jellyfinSessions, _, err := j.client.SessionAPI.GetSessions(ctx).
+		ActiveWithinSeconds(activeSessionWindow).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jellyfin sessions: %w", err)
+	}
+
+	sessions := make([]*models.PlaybackSession, 0, len(jellyfinSessions))
+	for _, jellyfinSession := range jellyfinSessions {
+		// Idle sessions have nothing playing
+		if !jellyfinSession.HasNowPlayingItem() {
+			continue
+		}
+		item := jellyfinSession.GetNowPlayingItem()
+
+		session := &models.PlaybackSession{
+			ClientID:        j.GetClientID(),
+			ClientType:      j.GetClientType(),
+			SessionID:       jellyfinSession.GetId(),
+			UserName:        jellyfinSession.GetUserName(),
+			DeviceName:      jellyfinSession.GetDeviceName(),
+			DeviceID:        jellyfinSession.GetDeviceId(),
+			Player:          jellyfinSession.GetClient(),
+			ClientItemID:    item.GetId(),
+			Title:           item.GetName(),
+			MediaType:       jellyfinSessionMediaType(item.GetType()),
+			DurationSeconds: int(item.GetRunTimeTicks() / 10000000),
+			PlayMethod:      models.PlayMethodUnknown,
+		}
+		if item.GetSeriesName() != "" {
+			session.Title = fmt.Sprintf("%s - %s", item.GetSeriesName(), item.GetName())
+		}
+		if jellyfinSession.HasPlayState() {
+			playState := jellyfinSession.GetPlayState()
+			session.PositionSeconds = int(playState.GetPositionTicks() / 10000000)
+			session.IsPaused = playState.GetIsPaused()
+			if playState.HasPlayMethod() {
+				switch playState.GetPlayMethod() {
+				case jellyfin.PLAYMETHOD_TRANSCODE:
+					session.PlayMethod = models.PlayMethodTranscode
+				case jellyfin.PLAYMETHOD_DIRECT_STREAM:
+					session.PlayMethod = models.PlayMethodDirectStream
+				case jellyfin.PLAYMETHOD_DIRECT_PLAY:
+					session.PlayMethod = models.PlayMethodDirectPlay
+				}
+			}
+		}
+		session.UpdateProgress()
+
+		sessions = append(sessions, session)
+	}
+
+	log.Debug().
+		Uint64("clientID", j.GetClientID()).
+		Int("sessionCount", len(sessions)).
+		Msg("Retrieved active sessions from Jellyfin server")
+
+	return sessions, nil
+}
+
+func jellyfinSessionMediaType(kind jellyfin.BaseItemKind) mediatypes.MediaType {
+	switch kind {
+	case jellyfin.BASEITEMKIND_MOVIE:
+		return mediatypes.MediaTypeMovie
+	case jellyfin.BASEITEMKIND_EPISODE:
+		return mediatypes.MediaTypeEpisode
+	case jellyfin.BASEITEMKIND_AUDIO:
+		return mediatypes.MediaTypeTrack
+	default:
+		return mediatypes.MediaType(kind)
+	}
+}
@@ -0,0 +1,66 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+
+	jellyfin "github.com/sj14/jellyfin-go/api"
+	"suasor/utils/logger"
+)
+
+func (j *JellyfinClient) SupportsUserData() bool { return true }
+
+// SetFavorite marks or unmarks an item as a favorite for the configured Jellyfin user
+func (j *JellyfinClient) SetFavorite(ctx context.Context, itemID string, favorite bool) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", j.GetClientID()).
+		Str("itemID", itemID).
+		Bool("favorite", favorite).
+		Msg("Setting favorite status on Jellyfin server")
+
+	userID := j.getUserID()
+	if userID == "" {
+		return fmt.Errorf("user ID is required to update favorites")
+	}
+
+	var err error
+	if favorite {
+		_, _, err = j.client.UserLibraryAPI.MarkFavoriteItem(ctx, itemID).UserId(userID).Execute()
+	} else {
+		_, _, err = j.client.UserLibraryAPI.UnmarkFavoriteItem(ctx, itemID).UserId(userID).Execute()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update favorite status for item %s: %w", itemID, err)
+	}
+	return nil
+}
+
+// SetRating sets the user's rating for an item for the configured Jellyfin user
+func (j *JellyfinClient) SetRating(ctx context.Context, itemID string, rating float32) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", j.GetClientID()).
+		Str("itemID", itemID).
+		Float32("rating", rating).
+		Msg("Setting rating on Jellyfin server")
+
+	userID := j.getUserID()
+	if userID == "" {
+		return fmt.Errorf("user ID is required to update ratings")
+	}
+
+	dto := jellyfin.NewUpdateUserItemDataDto()
+	dto.SetRating(float64(rating))
+
+	_, _, err := j.client.ItemsAPI.UpdateItemUserData(ctx, itemID).
+		UserId(userID).
+		UpdateUserItemDataDto(*dto).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to set rating for item %s: %w", itemID, err)
+	}
+	return nil
+}
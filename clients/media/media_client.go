@@ -24,6 +24,10 @@ type ClientMedia interface {
 	SupportsPlaylists() bool
 	SupportsCollections() bool
 	SupportsHistory() bool
+	SupportsPlayState() bool
+	SupportsUserData() bool
+	SupportsSessions() bool
+	SupportsLibraries() bool
 
 	GetRegistry() *ClientItemRegistry
 
@@ -63,6 +67,10 @@ func (m *clientMedia) SupportsMusic() bool       { return false }
 func (m *clientMedia) SupportsPlaylists() bool   { return false }
 func (m *clientMedia) SupportsCollections() bool { return false }
 func (m *clientMedia) SupportsHistory() bool     { return false }
+func (m *clientMedia) SupportsPlayState() bool   { return false }
+func (m *clientMedia) SupportsUserData() bool    { return false }
+func (m *clientMedia) SupportsSessions() bool    { return false }
+func (m *clientMedia) SupportsLibraries() bool   { return false }
 
 func (b *clientMedia) GetRegistry() *ClientItemRegistry {
 	return b.ItemRegistry
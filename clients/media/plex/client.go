@@ -8,6 +8,7 @@ import (
 	"suasor/clients/media"
 	clienttypes "suasor/clients/types"
 	"suasor/utils/logger"
+	"time"
 
 	"github.com/unfaiyted/plexgo"
 )
@@ -44,6 +45,7 @@ func NewPlexClient(ctx context.Context, registry *media.ClientItemRegistry, clie
 
 	pClient := &PlexClient{
 		ClientMedia: clientMedia,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
 		plexAPI:     plexAPI,
 		config:      config,
 	}
@@ -58,10 +60,14 @@ func NewPlexClient(ctx context.Context, registry *media.ClientItemRegistry, clie
 }
 
 // Capability methods
-func (c *PlexClient) SupportsMovies() bool  { return true }
-func (c *PlexClient) SupportsSeries() bool  { return true }
-func (c *PlexClient) SupportsMusic() bool   { return true }
-func (c *PlexClient) SupportsHistory() bool { return true }
+func (c *PlexClient) SupportsMovies() bool    { return true }
+func (c *PlexClient) SupportsSeries() bool    { return true }
+func (c *PlexClient) SupportsMusic() bool     { return true }
+func (c *PlexClient) SupportsHistory() bool   { return true }
+func (c *PlexClient) SupportsPlayState() bool { return true }
+func (c *PlexClient) SupportsUserData() bool  { return true }
+func (c *PlexClient) SupportsSessions() bool  { return true }
+func (c *PlexClient) SupportsLibraries() bool { return true }
 
 func (c *PlexClient) plexConfig() *clienttypes.PlexConfig {
 	// First check if c.config is already set
@@ -3,6 +3,9 @@ package plex
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	media "suasor/clients/media"
 	mediatypes "suasor/clients/media/types"
@@ -194,7 +197,9 @@ func GetMediaItemListFromPlaylist[T mediatypes.MediaData](
 	return mediaItems, nil
 }
 
-func (c *PlexClient) findLibrarySectionByType(ctx context.Context, sectionType operations.GetAllLibrariesType) (string, error) {
+// findLibrarySectionByType returns the key of the first section of the given type.
+// When libraryID is set only that section is considered.
+func (c *PlexClient) findLibrarySectionByType(ctx context.Context, sectionType operations.GetAllLibrariesType, libraryID string) (string, error) {
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
 
@@ -222,6 +227,9 @@ func (c *PlexClient) findLibrarySectionByType(ctx context.Context, sectionType o
 		Msg("Retrieved libraries from Plex")
 
 	for _, dir := range libraries.Object.MediaContainer.Directory {
+		if libraryID != "" && dir.Key != libraryID {
+			continue
+		}
 		if dir.Type == sectionType {
 			log.Debug().
 				Uint64("clientID", c.GetClientID()).
@@ -322,3 +330,63 @@ func setMediaListToItemList(list *models.MediaItemResults, itemList *mediatypes.
 	})
 
 }
+
+// Plex item type numbers used by the library listing
+const (
+	plexTypeMovie = 1
+	plexTypeShow  = 2
+	plexTypeAlbum = 9
+	plexTypeTrack = 10
+)
+
+// plexLibraryItemsResponse is the JSON listing of a library section
+type plexLibraryItemsResponse struct {
+	MediaContainer struct {
+		Metadata []operations.GetLibraryItemsMetadata `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// getLibraryItemsUpdatedSince lists the items of a type in a section that were added or changed after options.UpdatedAfter,
+// paged with the options' offset and limit. plexgo can't send filters, so the section is listed directly with
+// the updatedAt>>= (after) filter.
+func (c *PlexClient) getLibraryItemsUpdatedSince(ctx context.Context, sectionKey int, plexType int, options *mediatypes.QueryOptions) ([]operations.GetLibraryItemsMetadata, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	since := *options.UpdatedAfter
+	path := fmt.Sprintf("/library/sections/%d/all?type=%d&includeGuids=1&updatedAt>>=%d", sectionKey, plexType, since.Unix())
+	params := url.Values{}
+	if options.Limit > 0 {
+		params.Set("X-Plex-Container-Start", strconv.Itoa(options.Offset))
+		params.Set("X-Plex-Container-Size", strconv.Itoa(options.Limit))
+	}
+
+	var res plexLibraryItemsResponse
+	if err := c.doPlexRequest(ctx, http.MethodGet, path, params, &res); err != nil {
+		return nil, fmt.Errorf("failed to get items updated since %s: %w", since.Format(time.RFC3339), err)
+	}
+
+	log.Debug().
+		Uint64("clientID", c.GetClientID()).
+		Int("sectionKey", sectionKey).
+		Int("plexType", plexType).
+		Time("since", since).
+		Int("itemCount", len(res.MediaContainer.Metadata)).
+		Msg("Retrieved updated items from Plex")
+
+	return res.MediaContainer.Metadata, nil
+}
+
+// getMediaItemsUpdatedSince lists and converts the items of a section that were added or changed after options.UpdatedAfter
+func getMediaItemsUpdatedSince[T mediatypes.MediaData](
+	ctx context.Context,
+	client *PlexClient,
+	sectionKey int,
+	plexType int,
+	options *mediatypes.QueryOptions,
+) ([]*models.MediaItem[T], error) {
+	metadata, err := client.getLibraryItemsUpdatedSince(ctx, sectionKey, plexType, options)
+	if err != nil {
+		return nil, err
+	}
+	return GetMediaItemList[T](ctx, client, metadata)
+}
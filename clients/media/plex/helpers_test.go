@@ -0,0 +1,35 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mediatypes "suasor/clients/media/types"
+)
+
+func TestPlexGetLibraryItemsUpdatedSince(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+	provider := newTestPlayStateProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/library/sections/3/all", r.URL.Path)
+		assert.Contains(t, r.URL.RawQuery, "updatedAt>>=1700000000")
+		assert.Equal(t, "10", r.URL.Query().Get("type"))
+		assert.Equal(t, "1000", r.URL.Query().Get("X-Plex-Container-Start"))
+		assert.Equal(t, "500", r.URL.Query().Get("X-Plex-Container-Size"))
+		assert.Equal(t, "test-token", r.URL.Query().Get("X-Plex-Token"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"MediaContainer": {"Metadata": [{"ratingKey": "7"}, {"ratingKey": "8"}]}}`))
+	})
+
+	metadata, err := provider.(*PlexClient).getLibraryItemsUpdatedSince(context.Background(), 3, plexTypeTrack, &mediatypes.QueryOptions{
+		UpdatedAfter: &since,
+		Offset:       1000,
+		Limit:        500,
+	})
+	require.NoError(t, err)
+	assert.Len(t, metadata, 2)
+}
@@ -0,0 +1,58 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+// GetLibraries returns the library sections on the Plex server
+func (c *PlexClient) GetLibraries(ctx context.Context) ([]*models.MediaLibrary, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Debug().
+		Uint64("clientID", c.GetClientID()).
+		Msg("Retrieving library sections from Plex server")
+
+	res, err := c.plexAPI.Library.GetAllLibraries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plex libraries: %w", err)
+	}
+
+	if res.Object == nil {
+		return []*models.MediaLibrary{}, nil
+	}
+
+	libraries := make([]*models.MediaLibrary, 0, len(res.Object.MediaContainer.Directory))
+	for _, dir := range res.Object.MediaContainer.Directory {
+		libraries = append(libraries, &models.MediaLibrary{
+			ClientID:   c.GetClientID(),
+			ClientType: c.GetClientType(),
+			LibraryID:  dir.Key,
+			Name:       dir.Title,
+			Type:       plexLibraryType(string(dir.Type)),
+		})
+	}
+
+	log.Debug().
+		Uint64("clientID", c.GetClientID()).
+		Int("libraryCount", len(libraries)).
+		Msg("Retrieved library sections from Plex server")
+
+	return libraries, nil
+}
+
+func plexLibraryType(sectionType string) models.MediaLibraryType {
+	switch sectionType {
+	case "movie":
+		return models.MediaLibraryTypeMovies
+	case "show":
+		return models.MediaLibraryTypeSeries
+	case "artist":
+		return models.MediaLibraryTypeMusic
+	default:
+		return models.MediaLibraryTypeOther
+	}
+}
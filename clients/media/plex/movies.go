@@ -13,6 +13,11 @@ import (
 
 // GetMovies retrieves movies from Plex
 func (c *PlexClient) GetMovies(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Movie], error) {
+	// Each selected library is its own section, fetch them one at a time
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, c.GetMovies)
+	}
+
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
 
@@ -23,7 +28,7 @@ func (c *PlexClient) GetMovies(ctx context.Context, options *types.QueryOptions)
 
 	// First, find the movie library section
 	log.Debug().Msg("Finding movie library section")
-	movieSectionKey, err := c.findLibrarySectionByType(ctx, "movie")
+	movieSectionKey, err := c.findLibrarySectionByType(ctx, "movie", options.LibraryID())
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -47,6 +52,11 @@ func (c *PlexClient) GetMovies(ctx context.Context, options *types.QueryOptions)
 		Int("sectionKey", sectionKey).
 		Msg("Making API request to Plex server for movies")
 
+	// Delta syncs only ask for what changed since their checkpoint
+	if options.UpdatedAfter != nil {
+		return getMediaItemsUpdatedSince[*types.Movie](ctx, c, sectionKey, plexTypeMovie, options)
+	}
+
 	// Handle pagination when fetching all movies (limit=0, offset=0)
 	if options.Limit == 0 && options.Offset == 0 {
 		log.Debug().Msg("Fetching all movies, NO LIMITS!")
@@ -245,7 +255,7 @@ func (c *PlexClient) GetMovieGenres(ctx context.Context) ([]string, error) {
 
 	// Find the movie library section
 	log.Debug().Msg("Finding movie library section")
-	movieSectionKey, err := c.findLibrarySectionByType(ctx, "movie")
+	movieSectionKey, err := c.findLibrarySectionByType(ctx, "movie", "")
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -14,6 +14,11 @@ import (
 
 // GetMusic retrieves music tracks from Plex
 func (c *PlexClient) GetMusicTracks(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Track], error) {
+	// Each selected library is its own section, fetch them one at a time
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, c.GetMusicTracks)
+	}
+
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
 
@@ -24,7 +29,7 @@ func (c *PlexClient) GetMusicTracks(ctx context.Context, options *types.QueryOpt
 
 	// Find the music library section
 	log.Debug().Msg("Finding music library section")
-	musicSectionKey, err := c.findLibrarySectionByType(ctx, "artist")
+	musicSectionKey, err := c.findLibrarySectionByType(ctx, "artist", options.LibraryID())
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -42,9 +47,15 @@ func (c *PlexClient) GetMusicTracks(ctx context.Context, options *types.QueryOpt
 		return nil, nil
 	}
 
-	// For tracks, we need to traverse the hierarchy: artists > albums > tracks
 	sectionKey, _ := strconv.Atoi(musicSectionKey)
 
+	// Delta syncs only ask for what changed since their checkpoint, tracks can be listed directly
+	if options.UpdatedAfter != nil {
+		return getMediaItemsUpdatedSince[*types.Track](ctx, c, sectionKey, plexTypeTrack, options)
+	}
+
+	// For tracks, we need to traverse the hierarchy: artists > albums > tracks
+
 	log.Debug().
 		Int("sectionKey", sectionKey).
 		Msg("Making API request to Plex server for music artists")
@@ -157,6 +168,11 @@ func (c *PlexClient) GetMusicTracks(ctx context.Context, options *types.QueryOpt
 
 // GetMusicArtists retrieves music artists from Plex
 func (c *PlexClient) GetMusicArtists(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Artist], error) {
+	// Each selected library is its own section, fetch them one at a time
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, c.GetMusicArtists)
+	}
+
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
 
@@ -167,7 +183,7 @@ func (c *PlexClient) GetMusicArtists(ctx context.Context, options *types.QueryOp
 
 	// Find the music library section
 	log.Debug().Msg("Finding music library section")
-	musicSectionKey, err := c.findLibrarySectionByType(ctx, "artist")
+	musicSectionKey, err := c.findLibrarySectionByType(ctx, "artist", options.LibraryID())
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -231,6 +247,11 @@ func (c *PlexClient) GetMusicArtists(ctx context.Context, options *types.QueryOp
 
 // GetMusicAlbums retrieves music albums from Plex
 func (c *PlexClient) GetMusicAlbums(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Album], error) {
+	// Each selected library is its own section, fetch them one at a time
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, c.GetMusicAlbums)
+	}
+
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
 
@@ -241,7 +262,7 @@ func (c *PlexClient) GetMusicAlbums(ctx context.Context, options *types.QueryOpt
 
 	// Find the music library section
 	log.Debug().Msg("Finding music library section")
-	musicSectionKey, err := c.findLibrarySectionByType(ctx, "artist")
+	musicSectionKey, err := c.findLibrarySectionByType(ctx, "artist", options.LibraryID())
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -259,9 +280,15 @@ func (c *PlexClient) GetMusicAlbums(ctx context.Context, options *types.QueryOpt
 		return nil, nil
 	}
 
-	// For albums, we need to traverse artists first
 	sectionKey, _ := strconv.Atoi(musicSectionKey)
 
+	// Delta syncs only ask for what changed since their checkpoint, albums can be listed directly
+	if options.UpdatedAfter != nil {
+		return getMediaItemsUpdatedSince[*types.Album](ctx, c, sectionKey, plexTypeAlbum, options)
+	}
+
+	// For albums, we need to traverse artists first
+
 	log.Debug().
 		Int("sectionKey", sectionKey).
 		Msg("Making API request to Plex server for music artists")
@@ -493,7 +520,7 @@ func (c *PlexClient) GetMusicGenres(ctx context.Context) ([]string, error) {
 
 	// Find the music library section
 	log.Debug().Msg("Finding music library section")
-	musicSectionKey, err := c.findLibrarySectionByType(ctx, "artist")
+	musicSectionKey, err := c.findLibrarySectionByType(ctx, "artist", "")
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -0,0 +1,179 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	mediatypes "suasor/clients/media/types"
+	"suasor/utils/logger"
+	"time"
+)
+
+const plexLibraryIdentifier = "com.plexapp.plugins.library"
+
+// plexOnDeckResponse is the subset of the /library/onDeck response that we use
+type plexOnDeckResponse struct {
+	MediaContainer struct {
+		Metadata []struct {
+			RatingKey    string `json:"ratingKey"`
+			Type         string `json:"type"`
+			Duration     int64  `json:"duration"`
+			ViewOffset   int64  `json:"viewOffset"`
+			LastViewedAt int64  `json:"lastViewedAt"`
+		} `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// MarkPlayed marks an item as watched on the Plex server
+func (c *PlexClient) MarkPlayed(ctx context.Context, itemID string) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", c.GetClientID()).
+		Str("itemID", itemID).
+		Msg("Marking item as played on Plex server")
+
+	params := url.Values{}
+	params.Set("key", plexRatingKey(itemID))
+	params.Set("identifier", plexLibraryIdentifier)
+
+	return c.doPlexAction(ctx, http.MethodGet, "/:/scrobble", params)
+}
+
+// MarkUnplayed marks an item as unwatched on the Plex server
+func (c *PlexClient) MarkUnplayed(ctx context.Context, itemID string) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", c.GetClientID()).
+		Str("itemID", itemID).
+		Msg("Marking item as unplayed on Plex server")
+
+	params := url.Values{}
+	params.Set("key", plexRatingKey(itemID))
+	params.Set("identifier", plexLibraryIdentifier)
+
+	return c.doPlexAction(ctx, http.MethodGet, "/:/unscrobble", params)
+}
+
+// SetPlaybackPosition updates the resume position of an item on the Plex server
+func (c *PlexClient) SetPlaybackPosition(ctx context.Context, itemID string, positionSeconds int) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", c.GetClientID()).
+		Str("itemID", itemID).
+		Int("positionSeconds", positionSeconds).
+		Msg("Setting playback position on Plex server")
+
+	params := url.Values{}
+	params.Set("key", plexRatingKey(itemID))
+	params.Set("identifier", plexLibraryIdentifier)
+	// Plex expects the offset in milliseconds
+	params.Set("time", strconv.Itoa(positionSeconds*1000))
+	params.Set("state", "stopped")
+
+	return c.doPlexAction(ctx, http.MethodGet, "/:/progress", params)
+}
+
+// GetResumePoints returns the items on the Plex user's On Deck that were stopped partway through.
+// On Deck also lists the next unwatched episode of a series, those have no view offset and are left out.
+func (c *PlexClient) GetResumePoints(ctx context.Context) ([]mediatypes.ResumePoint, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	var response plexOnDeckResponse
+	if err := c.doPlexRequest(ctx, http.MethodGet, "/library/onDeck", nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get plex on deck: %w", err)
+	}
+
+	points := make([]mediatypes.ResumePoint, 0, len(response.MediaContainer.Metadata))
+	for _, metadata := range response.MediaContainer.Metadata {
+		if metadata.RatingKey == "" || metadata.ViewOffset <= 0 {
+			continue
+		}
+		point := mediatypes.ResumePoint{
+			ItemID:          metadata.RatingKey,
+			MediaType:       plexSessionMediaType(metadata.Type),
+			PositionSeconds: int(metadata.ViewOffset / 1000),
+			DurationSeconds: int(metadata.Duration / 1000),
+		}
+		if metadata.LastViewedAt > 0 {
+			point.LastPlayedAt = time.Unix(metadata.LastViewedAt, 0)
+		}
+		points = append(points, point)
+	}
+
+	log.Debug().
+		Uint64("clientID", c.GetClientID()).
+		Int("resumePoints", len(points)).
+		Msg("Retrieved resume points from Plex server")
+
+	return points, nil
+}
+
+// doPlexAction calls one of the Plex "/:/" endpoints that are not covered by plexgo
+func (c *PlexClient) doPlexAction(ctx context.Context, method string, path string, params url.Values) error {
+	return c.doPlexRequest(ctx, method, path, params, nil)
+}
+
+// doPlexRequest calls the Plex server directly and decodes the JSON response into out when it is not nil
+func (c *PlexClient) doPlexRequest(ctx context.Context, method string, path string, params url.Values, out any) error {
+	config := c.plexConfig()
+	if config == nil {
+		return fmt.Errorf("plex config is nil")
+	}
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("X-Plex-Token", config.GetToken())
+	// Plex filters like updatedAt>>= can't go through url.Values, they are left in the path as is
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	reqURL := fmt.Sprintf("%s%s%s%s", strings.TrimRight(config.GetBaseURL(), "/"), path, separator, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create plex request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("plex request to %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("plex request to %s returned status %d", path, resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode plex response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// plexRatingKey extracts the rating key from either a bare key or a metadata path
+// such as "/library/metadata/123"
+func plexRatingKey(itemID string) string {
+	itemID = strings.TrimSuffix(itemID, "/")
+	if idx := strings.LastIndex(itemID, "/"); idx >= 0 {
+		return itemID[idx+1:]
+	}
+	return itemID
+}
@@ -0,0 +1,84 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"suasor/clients/media/providers"
+	mediatypes "suasor/clients/media/types"
+	clienttypes "suasor/clients/types"
+)
+
+func newTestPlayStateProvider(t *testing.T, handler http.HandlerFunc) providers.PlayStateProvider {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := clienttypes.NewPlexConfig(server.URL, "test-token", "tester", true, false)
+	client, err := NewPlexClient(context.Background(), nil, 1, &config)
+	require.NoError(t, err)
+
+	provider, ok := client.(providers.PlayStateProvider)
+	require.True(t, ok)
+	require.True(t, provider.SupportsPlayState())
+	return provider
+}
+
+func TestPlexMarkPlayed(t *testing.T) {
+	provider := newTestPlayStateProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/:/scrobble", r.URL.Path)
+		assert.Equal(t, "42", r.URL.Query().Get("key"))
+		assert.Equal(t, plexLibraryIdentifier, r.URL.Query().Get("identifier"))
+		assert.Equal(t, "test-token", r.URL.Query().Get("X-Plex-Token"))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	err := provider.MarkPlayed(context.Background(), "/library/metadata/42")
+	assert.NoError(t, err)
+}
+
+func TestPlexSetPlaybackPosition(t *testing.T) {
+	provider := newTestPlayStateProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/:/progress", r.URL.Path)
+		assert.Equal(t, "42", r.URL.Query().Get("key"))
+		assert.Equal(t, "90000", r.URL.Query().Get("time"))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	err := provider.SetPlaybackPosition(context.Background(), "42", 90)
+	assert.NoError(t, err)
+}
+
+func TestPlexMarkUnplayedServerError(t *testing.T) {
+	provider := newTestPlayStateProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/:/unscrobble", r.URL.Path)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	err := provider.MarkUnplayed(context.Background(), "42")
+	assert.Error(t, err)
+}
+
+func TestPlexGetResumePoints(t *testing.T) {
+	provider := newTestPlayStateProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/library/onDeck", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"MediaContainer":{"Metadata":[
+			{"ratingKey":"42","type":"movie","duration":6000000,"viewOffset":90000,"lastViewedAt":1700000000},
+			{"ratingKey":"43","type":"episode","duration":1800000,"viewOffset":0}
+		]}}`))
+	})
+
+	points, err := provider.GetResumePoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, points, 1, "next episodes on deck have no position")
+	assert.Equal(t, "42", points[0].ItemID)
+	assert.Equal(t, mediatypes.MediaTypeMovie, points[0].MediaType)
+	assert.Equal(t, 90, points[0].PositionSeconds)
+	assert.Equal(t, 6000, points[0].DurationSeconds)
+	assert.Equal(t, int64(1700000000), points[0].LastPlayedAt.Unix())
+}
@@ -95,7 +95,7 @@ func (c *PlexClient) searchMovies(ctx context.Context, options *types.QueryOptio
 	log := logger.LoggerFromContext(ctx)
 
 	// First, find the movie library section
-	movieSectionKey, err := c.findLibrarySectionByType(ctx, "movie")
+	movieSectionKey, err := c.findLibrarySectionByType(ctx, "movie", options.LibraryID())
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -303,7 +303,7 @@ func (c *PlexClient) searchSeries(ctx context.Context, options *types.QueryOptio
 	log := logger.LoggerFromContext(ctx)
 
 	// First, find the TV series library section
-	seriesSectionKey, err := c.findLibrarySectionByType(ctx, "show")
+	seriesSectionKey, err := c.findLibrarySectionByType(ctx, "show", options.LibraryID())
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -524,7 +524,7 @@ func (c *PlexClient) searchMusic(ctx context.Context, options *types.QueryOption
 	}
 
 	// First, find the music library section
-	musicSectionKey, err := c.findLibrarySectionByType(ctx, "artist")
+	musicSectionKey, err := c.findLibrarySectionByType(ctx, "artist", options.LibraryID())
 	if err != nil {
 		log.Error().
 			Err(err).
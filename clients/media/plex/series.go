@@ -13,6 +13,11 @@ import (
 
 // GetSeriess retrieves TV shows from Plex
 func (c *PlexClient) GetSeries(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Series], error) {
+	// Each selected library is its own section, fetch them one at a time
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, c.GetSeries)
+	}
+
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
 
@@ -23,7 +28,7 @@ func (c *PlexClient) GetSeries(ctx context.Context, options *types.QueryOptions)
 
 	// First, find the TV show library section
 	log.Debug().Msg("Finding TV show library section")
-	tvSectionKey, err := c.findLibrarySectionByType(ctx, "show")
+	tvSectionKey, err := c.findLibrarySectionByType(ctx, "show", options.LibraryID())
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -47,6 +52,11 @@ func (c *PlexClient) GetSeries(ctx context.Context, options *types.QueryOptions)
 		Int("sectionKey", sectionKey).
 		Msg("Making API request to Plex server for TV shows")
 
+	// Delta syncs only ask for what changed since their checkpoint
+	if options.UpdatedAfter != nil {
+		return getMediaItemsUpdatedSince[*types.Series](ctx, c, sectionKey, plexTypeShow, options)
+	}
+
 	res, err := c.plexAPI.Library.GetLibraryItems(ctx, operations.GetLibraryItemsRequest{
 		IncludeMeta: operations.GetLibraryItemsQueryParamIncludeMetaEnable.ToPointer(),
 		Tag:         "all",
@@ -0,0 +1,117 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+// plexSessionsResponse is the subset of the /status/sessions response that we use
+type plexSessionsResponse struct {
+	MediaContainer struct {
+		Size     int                   `json:"size"`
+		Metadata []plexSessionMetadata `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+type plexSessionMetadata struct {
+	RatingKey        string `json:"ratingKey"`
+	Type             string `json:"type"`
+	Title            string `json:"title"`
+	GrandparentTitle string `json:"grandparentTitle"`
+	Duration         int64  `json:"duration"`
+	ViewOffset       int64  `json:"viewOffset"`
+	User             *struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	} `json:"User"`
+	Player *struct {
+		Title             string `json:"title"`
+		MachineIdentifier string `json:"machineIdentifier"`
+		Product           string `json:"product"`
+		State             string `json:"state"`
+	} `json:"Player"`
+	Session *struct {
+		ID string `json:"id"`
+	} `json:"Session"`
+	TranscodeSession *struct {
+		VideoDecision string `json:"videoDecision"`
+		AudioDecision string `json:"audioDecision"`
+	} `json:"TranscodeSession"`
+}
+
+// GetSessions returns the sessions currently playing on the Plex server
+func (c *PlexClient) GetSessions(ctx context.Context) ([]*models.PlaybackSession, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Debug().
+		Uint64("clientID", c.GetClientID()).
+		Msg("Retrieving active sessions from Plex server")
+
+	var response plexSessionsResponse
+	if err := c.doPlexRequest(ctx, http.MethodGet, "/status/sessions", nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get plex sessions: %w", err)
+	}
+
+	sessions := make([]*models.PlaybackSession, 0, len(response.MediaContainer.Metadata))
+	for _, metadata := range response.MediaContainer.Metadata {
+		session := &models.PlaybackSession{
+			ClientID:        c.GetClientID(),
+			ClientType:      c.GetClientType(),
+			ClientItemID:    metadata.RatingKey,
+			Title:           metadata.Title,
+			MediaType:       plexSessionMediaType(metadata.Type),
+			PositionSeconds: int(metadata.ViewOffset / 1000),
+			DurationSeconds: int(metadata.Duration / 1000),
+			PlayMethod:      models.PlayMethodDirectPlay,
+		}
+		if metadata.GrandparentTitle != "" {
+			session.Title = fmt.Sprintf("%s - %s", metadata.GrandparentTitle, metadata.Title)
+		}
+		if metadata.Session != nil {
+			session.SessionID = metadata.Session.ID
+		}
+		if metadata.User != nil {
+			session.UserName = metadata.User.Title
+		}
+		if metadata.Player != nil {
+			session.DeviceName = metadata.Player.Title
+			session.DeviceID = metadata.Player.MachineIdentifier
+			session.Player = metadata.Player.Product
+			session.IsPaused = metadata.Player.State == "paused"
+		}
+		if ts := metadata.TranscodeSession; ts != nil {
+			if ts.VideoDecision == "transcode" || ts.AudioDecision == "transcode" {
+				session.PlayMethod = models.PlayMethodTranscode
+			} else {
+				session.PlayMethod = models.PlayMethodDirectStream
+			}
+		}
+		session.UpdateProgress()
+
+		sessions = append(sessions, session)
+	}
+
+	log.Debug().
+		Uint64("clientID", c.GetClientID()).
+		Int("sessionCount", len(sessions)).
+		Msg("Retrieved active sessions from Plex server")
+
+	return sessions, nil
+}
+
+func plexSessionMediaType(plexType string) mediatypes.MediaType {
+	switch plexType {
+	case "movie":
+		return mediatypes.MediaTypeMovie
+	case "episode":
+		return mediatypes.MediaTypeEpisode
+	case "track":
+		return mediatypes.MediaTypeTrack
+	default:
+		return mediatypes.MediaType(plexType)
+	}
+}
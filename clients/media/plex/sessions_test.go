@@ -0,0 +1,81 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"suasor/clients/media/providers"
+	mediatypes "suasor/clients/media/types"
+	clienttypes "suasor/clients/types"
+	"suasor/types/models"
+)
+
+const testSessionsResponse = `{
+  "MediaContainer": {
+    "size": 2,
+    "Metadata": [
+      {
+        "ratingKey": "42",
+        "type": "movie",
+        "title": "Heat",
+        "duration": 600000,
+        "viewOffset": 150000,
+        "User": {"id": "1", "title": "alice"},
+        "Player": {"title": "Living Room", "machineIdentifier": "abc", "product": "Plex for Roku", "state": "paused"},
+        "Session": {"id": "s1"}
+      },
+      {
+        "ratingKey": "7",
+        "type": "episode",
+        "title": "Pilot",
+        "grandparentTitle": "Severance",
+        "duration": 3000000,
+        "viewOffset": 0,
+        "User": {"id": "2", "title": "bob"},
+        "Player": {"title": "Phone", "machineIdentifier": "def", "product": "Plex for iOS", "state": "playing"},
+        "Session": {"id": "s2"},
+        "TranscodeSession": {"videoDecision": "transcode", "audioDecision": "copy"}
+      }
+    ]
+  }
+}`
+
+func TestPlexGetSessions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/status/sessions", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(testSessionsResponse))
+	}))
+	defer server.Close()
+
+	config := clienttypes.NewPlexConfig(server.URL, "test-token", "tester", true, false)
+	client, err := NewPlexClient(context.Background(), nil, 1, &config)
+	require.NoError(t, err)
+
+	provider, ok := client.(providers.SessionProvider)
+	require.True(t, ok)
+
+	sessions, err := provider.GetSessions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	movie := sessions[0]
+	assert.Equal(t, "42", movie.ClientItemID)
+	assert.Equal(t, mediatypes.MediaTypeMovie, movie.MediaType)
+	assert.Equal(t, "alice", movie.UserName)
+	assert.Equal(t, "Living Room", movie.DeviceName)
+	assert.Equal(t, 150, movie.PositionSeconds)
+	assert.Equal(t, 25.0, movie.Progress)
+	assert.True(t, movie.IsPaused)
+	assert.Equal(t, models.PlayMethodDirectPlay, movie.PlayMethod)
+
+	episode := sessions[1]
+	assert.Equal(t, "Severance - Pilot", episode.Title)
+	assert.Equal(t, mediatypes.MediaTypeEpisode, episode.MediaType)
+	assert.True(t, episode.IsTranscoding())
+}
@@ -0,0 +1,39 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"suasor/clients/media"
+	"suasor/utils/logger"
+)
+
+// SetFavorite is not supported, Plex has no per-item favorites for library content
+func (c *PlexClient) SetFavorite(ctx context.Context, itemID string, favorite bool) error {
+	return media.ErrFeatureNotSupported
+}
+
+// SetRating sets the user's rating for an item on the Plex server
+func (c *PlexClient) SetRating(ctx context.Context, itemID string, rating float32) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", c.GetClientID()).
+		Str("itemID", itemID).
+		Float32("rating", rating).
+		Msg("Setting rating on Plex server")
+
+	params := url.Values{}
+	params.Set("key", plexRatingKey(itemID))
+	params.Set("identifier", plexLibraryIdentifier)
+	if rating > 0 {
+		// Plex uses the same 0-10 scale, shown as five stars
+		params.Set("rating", strconv.FormatFloat(float64(rating), 'f', 1, 32))
+	} else {
+		// A negative rating clears the user rating
+		params.Set("rating", "-1")
+	}
+
+	return c.doPlexAction(ctx, http.MethodPut, "/:/rate", params)
+}
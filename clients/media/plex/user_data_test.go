@@ -0,0 +1,55 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"suasor/clients/media"
+	"suasor/clients/media/providers"
+)
+
+func newTestUserDataProvider(t *testing.T, handler http.HandlerFunc) providers.UserDataProvider {
+	client := newTestPlayStateProvider(t, handler)
+
+	provider, ok := client.(providers.UserDataProvider)
+	require.True(t, ok)
+	require.True(t, provider.SupportsUserData())
+	return provider
+}
+
+func TestPlexSetRating(t *testing.T) {
+	provider := newTestUserDataProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/:/rate", r.URL.Path)
+		assert.Equal(t, "42", r.URL.Query().Get("key"))
+		assert.Equal(t, plexLibraryIdentifier, r.URL.Query().Get("identifier"))
+		assert.Equal(t, "7.5", r.URL.Query().Get("rating"))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	err := provider.SetRating(context.Background(), "/library/metadata/42", 7.5)
+	assert.NoError(t, err)
+}
+
+func TestPlexClearRating(t *testing.T) {
+	provider := newTestUserDataProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "-1", r.URL.Query().Get("rating"))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	err := provider.SetRating(context.Background(), "42", 0)
+	assert.NoError(t, err)
+}
+
+func TestPlexSetFavoriteNotSupported(t *testing.T) {
+	provider := newTestUserDataProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+
+	err := provider.SetFavorite(context.Background(), "42", true)
+	assert.ErrorIs(t, err, media.ErrFeatureNotSupported, "the write-back skips clients without favorites")
+}
@@ -0,0 +1,12 @@
+package providers
+
+import (
+	"context"
+	"suasor/types/models"
+)
+
+// LibraryProvider defines access to the libraries, sections or music folders on a media server
+type LibraryProvider interface {
+	SupportsLibraries() bool
+	GetLibraries(ctx context.Context) ([]*models.MediaLibrary, error)
+}
@@ -0,0 +1,16 @@
+package providers
+
+import (
+	"context"
+	"suasor/clients/media/types"
+)
+
+// PlayStateProvider defines write-back of watched state and resume positions
+type PlayStateProvider interface {
+	SupportsPlayState() bool
+	MarkPlayed(ctx context.Context, itemID string) error
+	MarkUnplayed(ctx context.Context, itemID string) error
+	SetPlaybackPosition(ctx context.Context, itemID string, positionSeconds int) error
+	// GetResumePoints returns the items the configured user stopped partway through
+	GetResumePoints(ctx context.Context) ([]types.ResumePoint, error)
+}
@@ -0,0 +1,12 @@
+package providers
+
+import (
+	"context"
+	"suasor/types/models"
+)
+
+// SessionProvider defines access to the sessions currently playing on a media server
+type SessionProvider interface {
+	SupportsSessions() bool
+	GetSessions(ctx context.Context) ([]*models.PlaybackSession, error)
+}
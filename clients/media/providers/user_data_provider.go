@@ -0,0 +1,13 @@
+package providers
+
+import (
+	"context"
+)
+
+// UserDataProvider defines write-back of per-user favorites and ratings
+type UserDataProvider interface {
+	SupportsUserData() bool
+	SetFavorite(ctx context.Context, itemID string, favorite bool) error
+	// SetRating sets the user's rating on a 0-10 scale, 0 clears the rating
+	SetRating(ctx context.Context, itemID string, rating float32) error
+}
@@ -7,12 +7,18 @@ import (
 	"suasor/clients/media/types"
 	"suasor/types/models"
 	"suasor/utils/logger"
+	"time"
 
 	gosonic "github.com/supersonic-app/go-subsonic/subsonic"
 )
 
 // GetAlbums retrieves albums from Subsonic
 func (c *SubsonicClient) GetMusicAlbums(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Album], error) {
+	// musicFolderId only takes a single folder, query each selected folder separately
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, c.GetMusicAlbums)
+	}
+
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
 
@@ -21,6 +27,29 @@ func (c *SubsonicClient) GetMusicAlbums(ctx context.Context, options *types.Quer
 		Str("clientType", string(c.GetClientType())).
 		Msg("Retrieving albums from Subsonic server")
 
+	// Subsonic has no date filter, recently added albums come from the newest list instead
+	if options != nil && options.DateAddedAfter != nil {
+		recentAlbums, err := c.getAlbumsAddedSince(ctx, *options.DateAddedAfter, options.LibraryID())
+		if err != nil {
+			return nil, err
+		}
+
+		albums := make([]*models.MediaItem[*types.Album], 0, len(recentAlbums))
+		for _, album := range recentAlbums {
+			albumItem, err := GetAlbumItem(ctx, c, album)
+			if err != nil {
+				log.Warn().
+					Err(err).
+					Str("albumID", album.ID).
+					Str("albumName", album.Name).
+					Msg("Error converting album to MediaItem")
+				continue
+			}
+			albums = append(albums, albumItem)
+		}
+		return albums, nil
+	}
+
 	limit := 50
 	offset := 0
 	if options != nil && options.Limit > 0 {
@@ -35,6 +64,9 @@ func (c *SubsonicClient) GetMusicAlbums(ctx context.Context, options *types.Quer
 		"size":   strconv.Itoa(limit),
 		"offset": strconv.Itoa(offset),
 	}
+	if folderID := options.LibraryID(); folderID != "" {
+		params["musicFolderId"] = folderID
+	}
 
 	// List types:
 	// "random"               ,
@@ -154,3 +186,41 @@ func (c *SubsonicClient) GetMusicAlbumByID(albumID string) (*gosonic.AlbumID3, e
 
 	return resp, nil
 }
+
+// getAlbumsAddedSince walks the newest albums until it reaches ones added before since.
+// The newest list is ordered by when albums were added to the server.
+// An empty folderID covers every music folder.
+func (c *SubsonicClient) getAlbumsAddedSince(ctx context.Context, since time.Time, folderID string) ([]*gosonic.AlbumID3, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	pageSize := 500
+	var albums []*gosonic.AlbumID3
+	for offset := 0; ; offset += pageSize {
+		params := map[string]string{
+			"size":   strconv.Itoa(pageSize),
+			"offset": strconv.Itoa(offset),
+		}
+		if folderID != "" {
+			params["musicFolderId"] = folderID
+		}
+		page, err := c.client.GetAlbumList2("newest", params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve newest albums: %w", err)
+		}
+
+		for _, album := range page {
+			if album.Created.Before(since) {
+				log.Debug().
+					Time("since", since).
+					Int("albumCount", len(albums)).
+					Msg("Found albums added since checkpoint")
+				return albums, nil
+			}
+			albums = append(albums, album)
+		}
+
+		if len(page) < pageSize {
+			return albums, nil
+		}
+	}
+}
@@ -36,6 +36,11 @@ func (c *SubsonicClient) GetMusicArtistByID(ctx context.Context, artistID string
 
 // GetArtists retrieves all artists in the server.
 func (c *SubsonicClient) GetMusicArtists(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Artist], error) {
+	// musicFolderId only takes a single folder, query each selected folder separately
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, c.GetMusicArtists)
+	}
+
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
 
@@ -45,7 +50,11 @@ func (c *SubsonicClient) GetMusicArtists(ctx context.Context, options *types.Que
 		Msg("Retrieving artists from Subsonic server")
 
 	// Get artists using the API method
-	artistsResponse, err := c.client.GetArtists(nil)
+	params := map[string]string{}
+	if folderID := options.LibraryID(); folderID != "" {
+		params["musicFolderId"] = folderID
+	}
+	artistsResponse, err := c.client.GetArtists(params)
 	if err != nil {
 		log.Error().
 			Err(err).
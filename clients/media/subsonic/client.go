@@ -17,6 +17,8 @@ type SubsonicClient struct {
 	media.ClientMedia
 	httpClient *http.Client
 	client     *gosonic.Client
+	// addedTracks keeps the last delta listing so paging through it doesn't walk the albums again
+	addedTracks addedTracksCache
 }
 
 // NewSubsonicClient creates a new Subsonic client
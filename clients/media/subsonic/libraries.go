@@ -0,0 +1,43 @@
+package subsonic
+
+import (
+	"context"
+	"fmt"
+
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+func (c *SubsonicClient) SupportsLibraries() bool { return true }
+
+// GetLibraries returns the music folders on the Subsonic server
+func (c *SubsonicClient) GetLibraries(ctx context.Context) ([]*models.MediaLibrary, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Debug().
+		Uint64("clientID", c.GetClientID()).
+		Msg("Retrieving music folders from Subsonic server")
+
+	folders, err := c.client.GetMusicFolders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subsonic music folders: %w", err)
+	}
+
+	libraries := make([]*models.MediaLibrary, 0, len(folders))
+	for _, folder := range folders {
+		libraries = append(libraries, &models.MediaLibrary{
+			ClientID:   c.GetClientID(),
+			ClientType: c.GetClientType(),
+			LibraryID:  folder.ID,
+			Name:       folder.Name,
+			Type:       models.MediaLibraryTypeMusic,
+		})
+	}
+
+	log.Debug().
+		Uint64("clientID", c.GetClientID()).
+		Int("libraryCount", len(libraries)).
+		Msg("Retrieved music folders from Subsonic server")
+
+	return libraries, nil
+}
@@ -0,0 +1,99 @@
+package subsonic
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"suasor/clients/media"
+	mediatypes "suasor/clients/media/types"
+	"suasor/utils/logger"
+)
+
+func (c *SubsonicClient) SupportsPlayState() bool { return true }
+
+// MarkPlayed submits a scrobble for the track, which counts it as played
+func (c *SubsonicClient) MarkPlayed(ctx context.Context, itemID string) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", c.GetClientID()).
+		Str("itemID", itemID).
+		Msg("Scrobbling track on Subsonic server")
+
+	params := map[string]string{
+		"id":         itemID,
+		"submission": "true",
+	}
+	if _, err := c.client.Get("scrobble", params); err != nil {
+		return fmt.Errorf("failed to scrobble track %s: %w", itemID, err)
+	}
+	return nil
+}
+
+// MarkUnplayed is not supported, Subsonic has no way to remove a scrobble
+func (c *SubsonicClient) MarkUnplayed(ctx context.Context, itemID string) error {
+	return media.ErrFeatureNotSupported
+}
+
+// SetPlaybackPosition stores the resume position as a Subsonic bookmark
+func (c *SubsonicClient) SetPlaybackPosition(ctx context.Context, itemID string, positionSeconds int) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", c.GetClientID()).
+		Str("itemID", itemID).
+		Int("positionSeconds", positionSeconds).
+		Msg("Setting bookmark on Subsonic server")
+
+	if positionSeconds <= 0 {
+		if _, err := c.client.Get("deleteBookmark", map[string]string{"id": itemID}); err != nil {
+			return fmt.Errorf("failed to delete bookmark for track %s: %w", itemID, err)
+		}
+		return nil
+	}
+
+	params := map[string]string{
+		"id": itemID,
+		// Subsonic bookmarks are stored in milliseconds
+		"position": strconv.Itoa(positionSeconds * 1000),
+	}
+	if _, err := c.client.Get("createBookmark", params); err != nil {
+		return fmt.Errorf("failed to create bookmark for track %s: %w", itemID, err)
+	}
+	return nil
+}
+
+// GetResumePoints returns the user's Subsonic bookmarks
+func (c *SubsonicClient) GetResumePoints(ctx context.Context) ([]mediatypes.ResumePoint, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	resp, err := c.client.Get("getBookmarks", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subsonic bookmarks: %w", err)
+	}
+	if resp.Bookmarks == nil {
+		return []mediatypes.ResumePoint{}, nil
+	}
+
+	points := make([]mediatypes.ResumePoint, 0, len(resp.Bookmarks.Bookmark))
+	for _, bookmark := range resp.Bookmarks.Bookmark {
+		if bookmark == nil || bookmark.Entry == nil || bookmark.Entry.ID == "" {
+			continue
+		}
+		points = append(points, mediatypes.ResumePoint{
+			ItemID:    bookmark.Entry.ID,
+			MediaType: mediatypes.MediaTypeTrack,
+			// Subsonic bookmarks are stored in milliseconds
+			PositionSeconds: int(bookmark.Position / 1000),
+			DurationSeconds: bookmark.Entry.Duration,
+			LastPlayedAt:    bookmark.Changed,
+		})
+	}
+
+	log.Debug().
+		Uint64("clientID", c.GetClientID()).
+		Int("resumePoints", len(points)).
+		Msg("Retrieved bookmarks from Subsonic server")
+
+	return points, nil
+}
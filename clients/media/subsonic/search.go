@@ -60,6 +60,10 @@ func (c *SubsonicClient) search(ctx context.Context, searchType searchType, opti
 		params["artistCount"] = strconv.Itoa(limit)
 	}
 
+	if folderID := options.LibraryID(); folderID != "" {
+		params["musicFolderId"] = folderID
+	}
+
 	return c.client.Search3(options.Query, params)
 }
 
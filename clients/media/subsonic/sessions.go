@@ -0,0 +1,116 @@
+package subsonic
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+	"suasor/utils/logger"
+
+	gosonic "github.com/supersonic-app/go-subsonic/subsonic"
+)
+
+func (c *SubsonicClient) SupportsSessions() bool { return true }
+
+// GetSessions returns the tracks currently playing on the Subsonic server.
+// Subsonic does not report playback position or transcoding for now playing entries.
+func (c *SubsonicClient) GetSessions(ctx context.Context) ([]*models.PlaybackSession, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Debug().
+		Uint64("clientID", c.GetClientID()).
+		Msg("Retrieving now playing entries from Subsonic server")
+
+	resp, itemIDs, err := c.getNowPlaying()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subsonic now playing: %w", err)
+	}
+
+	if resp.NowPlaying == nil || len(resp.NowPlaying.Entry) == 0 {
+		return []*models.PlaybackSession{}, nil
+	}
+
+	sessions := make([]*models.PlaybackSession, 0, len(resp.NowPlaying.Entry))
+	for i, entry := range resp.NowPlaying.Entry {
+		if entry == nil {
+			continue
+		}
+		var itemID string
+		if i < len(itemIDs) {
+			itemID = itemIDs[i]
+		}
+
+		session := &models.PlaybackSession{
+			ClientID:        c.GetClientID(),
+			ClientType:      c.GetClientType(),
+			SessionID:       fmt.Sprintf("%s-%d", entry.Username, entry.PlayerID),
+			UserName:        entry.Username,
+			DeviceName:      entry.PlayerName,
+			DeviceID:        strconv.Itoa(entry.PlayerID),
+			ClientItemID:    itemID,
+			Title:           entry.Title,
+			MediaType:       mediatypes.MediaTypeTrack,
+			DurationSeconds: entry.Duration,
+			PlayMethod:      models.PlayMethodUnknown,
+		}
+		if entry.Artist != "" {
+			session.Title = fmt.Sprintf("%s - %s", entry.Artist, entry.Title)
+		}
+		session.UpdateProgress()
+
+		sessions = append(sessions, session)
+	}
+
+	log.Debug().
+		Uint64("clientID", c.GetClientID()).
+		Int("sessionCount", len(sessions)).
+		Msg("Retrieved now playing entries from Subsonic server")
+
+	return sessions, nil
+}
+
+// nowPlayingIDs picks the song IDs out of a getNowPlaying response. go-subsonic's
+// NowPlayingEntry doesn't carry the id attribute, so the body is read a second time for it.
+type nowPlayingIDs struct {
+	Entries []struct {
+		ID string `xml:"id,attr"`
+	} `xml:"nowPlaying>entry"`
+}
+
+// getNowPlaying fetches getNowPlaying and returns the parsed response together with the
+// song ID of each entry, in the same order as resp.NowPlaying.Entry.
+func (c *SubsonicClient) getNowPlaying() (*gosonic.Response, []string, error) {
+	httpResp, err := c.client.Request(http.MethodGet, "getNowPlaying", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := &gosonic.Response{}
+	if err := xml.Unmarshal(body, resp); err != nil {
+		return nil, nil, err
+	}
+	if resp.Error != nil {
+		return nil, nil, fmt.Errorf("Error #%d: %s", resp.Error.Code, resp.Error.Message)
+	}
+
+	var ids nowPlayingIDs
+	if err := xml.Unmarshal(body, &ids); err != nil {
+		return nil, nil, err
+	}
+	itemIDs := make([]string, len(ids.Entries))
+	for i, entry := range ids.Entries {
+		itemIDs[i] = entry.ID
+	}
+
+	return resp, itemIDs, nil
+}
@@ -6,11 +6,18 @@ import (
 	"suasor/clients/media/types"
 	"suasor/types/models"
 	"suasor/utils/logger"
+	"sync"
+	"time"
 
 	mediatypes "suasor/clients/media/types"
 )
 
 func (c *SubsonicClient) GetMusicTracks(ctx context.Context, options *types.QueryOptions) ([]*models.MediaItem[*types.Track], error) {
+	// musicFolderId only takes a single folder, query each selected folder separately
+	if options.HasMultipleLibraries() {
+		return types.ForEachLibrary(ctx, options, c.GetMusicTracks)
+	}
+
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
 
@@ -22,6 +29,15 @@ func (c *SubsonicClient) GetMusicTracks(ctx context.Context, options *types.Quer
 	var tracks []*models.MediaItem[*types.Track]
 	var err error
 
+	// Subsonic has no date filter, recently added tracks come from the newest albums instead
+	if options != nil && options.DateAddedAfter != nil && options.Query == "" && !hasAnyTypedFilter(options) {
+		tracks, err = c.getTracksAddedSince(ctx, *options.DateAddedAfter, options.LibraryID())
+		if err != nil {
+			return nil, err
+		}
+		return pageTracks(tracks, options.Offset, options.Limit), nil
+	}
+
 	// If query or typed filters provided, use search3
 	if options != nil && (options.Query != "" || hasAnyTypedFilter(options)) {
 		queryString := buildQueryString(options)
@@ -42,6 +58,57 @@ func (c *SubsonicClient) GetMusicTracks(ctx context.Context, options *types.Quer
 	return tracks, nil
 }
 
+// addedTracksCache holds the tracks found by the last walk of the newest albums.
+// A sync pages through the tracks with the same checkpoint, so only its first page walks the albums.
+type addedTracksCache struct {
+	mu       sync.Mutex
+	since    time.Time
+	folderID string
+	tracks   []*models.MediaItem[*types.Track]
+}
+
+// getTracksAddedSince returns the tracks of every album added since the given time
+func (c *SubsonicClient) getTracksAddedSince(ctx context.Context, since time.Time, folderID string) ([]*models.MediaItem[*types.Track], error) {
+	cache := &c.addedTracks
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.tracks != nil && cache.since.Equal(since) && cache.folderID == folderID {
+		return cache.tracks, nil
+	}
+
+	albums, err := c.getAlbumsAddedSince(ctx, since, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := []*models.MediaItem[*types.Track]{}
+	for _, album := range albums {
+		albumTracks, err := c.GetMusicTracksByAlbumID(ctx, album.ID)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, albumTracks...)
+	}
+
+	cache.since = since
+	cache.folderID = folderID
+	cache.tracks = tracks
+	return tracks, nil
+}
+
+// pageTracks applies an offset and limit to an already fetched list of tracks
+func pageTracks(tracks []*models.MediaItem[*types.Track], offset int, limit int) []*models.MediaItem[*types.Track] {
+	if offset >= len(tracks) {
+		return []*models.MediaItem[*types.Track]{}
+	}
+	tracks = tracks[offset:]
+	if limit > 0 && len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+	return tracks
+}
+
 func (c *SubsonicClient) GetMusicTracksByAlbumID(ctx context.Context, albumID string) ([]*models.MediaItem[*types.Track], error) {
 	// Get logger from context
 	log := logger.LoggerFromContext(ctx)
@@ -0,0 +1,58 @@
+package subsonic
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"suasor/utils/logger"
+)
+
+func (c *SubsonicClient) SupportsUserData() bool { return true }
+
+// SetFavorite stars or unstars a track on the Subsonic server
+func (c *SubsonicClient) SetFavorite(ctx context.Context, itemID string, favorite bool) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", c.GetClientID()).
+		Str("itemID", itemID).
+		Bool("favorite", favorite).
+		Msg("Setting starred status on Subsonic server")
+
+	endpoint := "unstar"
+	if favorite {
+		endpoint = "star"
+	}
+
+	if _, err := c.client.Get(endpoint, map[string]string{"id": itemID}); err != nil {
+		return fmt.Errorf("failed to %s item %s: %w", endpoint, itemID, err)
+	}
+	return nil
+}
+
+// SetRating sets the user's rating on the Subsonic server
+func (c *SubsonicClient) SetRating(ctx context.Context, itemID string, rating float32) error {
+	log := logger.LoggerFromContext(ctx)
+
+	log.Info().
+		Uint64("clientID", c.GetClientID()).
+		Str("itemID", itemID).
+		Float32("rating", rating).
+		Msg("Setting rating on Subsonic server")
+
+	// Subsonic ratings are 1-5 stars, 0 removes the rating
+	stars := int(math.Round(float64(rating) / 2))
+	if rating > 0 && stars == 0 {
+		stars = 1
+	}
+
+	params := map[string]string{
+		"id":     itemID,
+		"rating": strconv.Itoa(stars),
+	}
+	if _, err := c.client.Get("setRating", params); err != nil {
+		return fmt.Errorf("failed to set rating for item %s: %w", itemID, err)
+	}
+	return nil
+}
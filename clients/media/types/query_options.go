@@ -1,6 +1,7 @@
 package types
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -51,21 +52,26 @@ type QueryOptions struct {
 	RecentlyAdded   bool       `json:"recentlyAdded,omitempty"`   // Filter to recently added items
 	RecentlyPlayed  bool       `json:"recentlyPlayed,omitempty"`  // Filter to recently played items
 	Watched         bool       `json:"watched,omitempty"`         // Filter to watched items
+	Unwatched       bool       `json:"unwatched,omitempty"`       // Filter to items that were never watched
 	Watchlist       bool       `json:"watchlist,omitempty"`       // Filter to watchlist items
 	DateAddedAfter  *time.Time `json:"dateAddedAfter,omitempty"`  // Filter by date added after
 	DateAddedBefore *time.Time `json:"dateAddedBefore,omitempty"` // Filter by date added before
+	UpdatedAfter    *time.Time `json:"updatedAfter,omitempty"`    // Filter by date added or last updated after
 	ReleasedAfter   *time.Time `json:"releasedAfter,omitempty"`   // Filter by release date after
 	ReleasedBefore  *time.Time `json:"releasedBefore,omitempty"`  // Filter by release date before
 	PlayedAfter     *time.Time `json:"playedAfter,omitempty"`     // Filter by played date after
 	PlayedBefore    *time.Time `json:"playedBefore,omitempty"`    // Filter by played date before
 	MinimumRating   float32    `json:"minimumRating,omitempty"`   // Filter by minimum rating
 	MaximumRating   float32    `json:"maximumRating,omitempty"`   // Filter by maximum rating (10 is the highest)
+	MinimumDuration int        `json:"minimumDuration,omitempty"` // Filter by minimum runtime in minutes
+	MaximumDuration int        `json:"maximumDuration,omitempty"` // Filter by maximum runtime in minutes
 
 	// TODO: Add normalized rating logic . Get ratings from all clients and external sources (tmdb,imdb)
 	// going to scale all of them to a range of 0-100 and then take the average of these ratings.
 	// MinimumNormalizedRating float32 `json:"minimumNormalizedRating,omitempty"` // Filter by minimum normalized rating
 	IsPublic         bool   `json:"isPublic,omitempty"`         // Filter by public status
 	OwnerID          uint64 `json:"ownerID,omitempty"`          // Filter by owner ID
+	UserID           uint64 `json:"userID,omitempty"`           // User whose play history the watched filters use
 	ClientID         uint64 `json:"clientID,omitempty"`         // Filter by client ID
 	PersonID         uint64 `json:"personID,omitempty"`         // Filter by person ID
 	PersonType       string `json:"personType,omitempty"`       // Filter by person type (Actor, Director, etc.)
@@ -74,6 +80,9 @@ type QueryOptions struct {
 	ClientPersonID   string `json:"clientPersonID,omitempty"`   // Filter by person ID
 	ItemIDs          string `json:"itemIDs,omitempty"`          // Filter by external ID (emby, jellyfin, plex, etc.)
 	ExternalSourceID string `json:"externalSourceID,omitempty"` // Filter by external source ID (TMDB, IMDB, etc.)
+
+	// Libraries, sections or music folders on the client to limit results to. Empty means all libraries.
+	LibraryIDs []string `json:"libraryIDs,omitempty"`
 }
 
 func (opts *QueryOptions) WithQuery(query string) *QueryOptions {
@@ -119,6 +128,49 @@ func (opts *QueryOptions) WithMediaType(mediaType MediaType) *QueryOptions {
 	return opts
 }
 
+// HasMultipleLibraries returns true when more than one library is selected.
+// Clients can only filter by one library per request.
+func (opts *QueryOptions) HasMultipleLibraries() bool {
+	return opts != nil && len(opts.LibraryIDs) > 1
+}
+
+// LibraryID returns the selected library when exactly one is selected
+func (opts *QueryOptions) LibraryID() string {
+	if opts == nil || len(opts.LibraryIDs) != 1 {
+		return ""
+	}
+	return opts.LibraryIDs[0]
+}
+
+// ByLibrary splits the options into a copy per selected library
+func (opts *QueryOptions) ByLibrary() []*QueryOptions {
+	if opts == nil || len(opts.LibraryIDs) <= 1 {
+		return []*QueryOptions{opts}
+	}
+
+	split := make([]*QueryOptions, 0, len(opts.LibraryIDs))
+	for _, libraryID := range opts.LibraryIDs {
+		libraryOptions := *opts
+		libraryOptions.LibraryIDs = []string{libraryID}
+		split = append(split, &libraryOptions)
+	}
+	return split
+}
+
+// ForEachLibrary runs fetch once per selected library and merges the results.
+// Limit and offset apply to each library on its own.
+func ForEachLibrary[T any](ctx context.Context, options *QueryOptions, fetch func(context.Context, *QueryOptions) ([]T, error)) ([]T, error) {
+	var results []T
+	for _, libraryOptions := range options.ByLibrary() {
+		items, err := fetch(ctx, libraryOptions)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, items...)
+	}
+	return results, nil
+}
+
 // HasFilter checks if a specific filter is set
 func (opts *QueryOptions) HasFilter(filterName string) bool {
 	switch filterName {
@@ -148,10 +200,14 @@ func (opts *QueryOptions) HasFilter(filterName string) bool {
 		return opts.RecentlyPlayed
 	case "watched":
 		return opts.Watched
+	case "unwatched":
+		return opts.Unwatched
 	case "dateAddedAfter":
 		return !opts.DateAddedAfter.IsZero()
 	case "dateAddedBefore":
 		return !opts.DateAddedBefore.IsZero()
+	case "updatedAfter":
+		return opts.UpdatedAfter != nil && !opts.UpdatedAfter.IsZero()
 	case "releasedAfter":
 		return !opts.ReleasedAfter.IsZero()
 	case "releasedBefore":
@@ -162,6 +218,10 @@ func (opts *QueryOptions) HasFilter(filterName string) bool {
 		return !opts.PlayedBefore.IsZero()
 	case "minimumRating":
 		return opts.MinimumRating > 0
+	case "minimumDuration":
+		return opts.MinimumDuration > 0
+	case "maximumDuration":
+		return opts.MaximumDuration > 0
 	case "externalSourceID":
 		return opts.ExternalSourceID != ""
 	default:
@@ -216,11 +276,26 @@ func (opts *QueryOptions) GetFilterValue(filterName string) string {
 			return "true"
 		}
 		return ""
+	case "unwatched":
+		if opts.Unwatched {
+			return "true"
+		}
+		return ""
 	case "minimumRating":
 		if opts.MinimumRating > 0 {
 			return fmt.Sprintf("%.1f", opts.MinimumRating)
 		}
 		return ""
+	case "minimumDuration":
+		if opts.MinimumDuration > 0 {
+			return fmt.Sprintf("%d", opts.MinimumDuration)
+		}
+		return ""
+	case "maximumDuration":
+		if opts.MaximumDuration > 0 {
+			return fmt.Sprintf("%d", opts.MaximumDuration)
+		}
+		return ""
 	case "externalSourceID":
 		return opts.ExternalSourceID
 	default:
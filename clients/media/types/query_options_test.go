@@ -0,0 +1,40 @@
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryOptionsByLibrary(t *testing.T) {
+	var nilOptions *QueryOptions
+	assert.False(t, nilOptions.HasMultipleLibraries())
+	assert.Equal(t, "", nilOptions.LibraryID())
+
+	single := &QueryOptions{LibraryIDs: []string{"1"}}
+	assert.Equal(t, "1", single.LibraryID())
+	assert.Equal(t, []*QueryOptions{single}, single.ByLibrary())
+
+	options := &QueryOptions{Limit: 10, LibraryIDs: []string{"1", "2"}}
+	require.True(t, options.HasMultipleLibraries())
+	assert.Equal(t, "", options.LibraryID())
+
+	split := options.ByLibrary()
+	require.Len(t, split, 2)
+	assert.Equal(t, []string{"1"}, split[0].LibraryIDs)
+	assert.Equal(t, []string{"2"}, split[1].LibraryIDs)
+	assert.Equal(t, 10, split[1].Limit)
+	assert.Equal(t, []string{"1", "2"}, options.LibraryIDs, "the original options are not changed")
+}
+
+func TestForEachLibrary(t *testing.T) {
+	options := &QueryOptions{LibraryIDs: []string{"movies", "4k"}}
+
+	results, err := ForEachLibrary(context.Background(), options, func(ctx context.Context, o *QueryOptions) ([]string, error) {
+		return []string{o.LibraryID() + "-a", o.LibraryID() + "-b"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"movies-a", "movies-b", "4k-a", "4k-b"}, results)
+}
@@ -0,0 +1,22 @@
+package types
+
+import "time"
+
+// ResumePoint is an item the user stopped partway through, as a client's resume list reports it
+type ResumePoint struct {
+	// ItemID is the client's ID of the item
+	ItemID          string
+	MediaType       MediaType
+	PositionSeconds int
+	DurationSeconds int
+	// LastPlayedAt is when the position was saved, zero when the client doesn't report it
+	LastPlayedAt time.Time
+}
+
+// PlayedPercentage returns how much of the item was played, 0 when the duration is unknown
+func (p ResumePoint) PlayedPercentage() float64 {
+	if p.DurationSeconds <= 0 {
+		return 0
+	}
+	return float64(p.PositionSeconds) / float64(p.DurationSeconds) * 100
+}
@@ -0,0 +1,147 @@
+// Package musicbrainz looks up albums on the public MusicBrainz web service
+package musicbrainz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBaseURL = "https://musicbrainz.org/ws/2"
+	coverArtURL    = "https://coverartarchive.org/release-group/%s/front-500"
+
+	// requestInterval keeps the client under the one request per second MusicBrainz allows
+	requestInterval = time.Second
+)
+
+// ArtistCredit is an artist credited on a release group
+type ArtistCredit struct {
+	Name string `json:"name"`
+}
+
+// ReleaseGroup is an album, single or EP regardless of its editions
+type ReleaseGroup struct {
+	ID               string         `json:"id"`
+	Title            string         `json:"title"`
+	Score            int            `json:"score"`
+	PrimaryType      string         `json:"primary-type"`
+	FirstReleaseDate string         `json:"first-release-date"`
+	ArtistCredit     []ArtistCredit `json:"artist-credit"`
+}
+
+// Artist returns the names of the credited artists
+func (g *ReleaseGroup) Artist() string {
+	names := make([]string, 0, len(g.ArtistCredit))
+	for _, credit := range g.ArtistCredit {
+		names = append(names, credit.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Year returns the year of the first release, or 0 if it is unknown
+func (g *ReleaseGroup) Year() int {
+	var year int
+	if len(g.FirstReleaseDate) >= 4 {
+		fmt.Sscanf(g.FirstReleaseDate[:4], "%d", &year)
+	}
+	return year
+}
+
+// CoverArtURL returns the front cover of the release group on the Cover Art Archive
+func (g *ReleaseGroup) CoverArtURL() string {
+	return fmt.Sprintf(coverArtURL, g.ID)
+}
+
+// Client searches MusicBrainz, requests are spaced out to respect its rate limit
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	userAgent  string
+
+	mu          sync.Mutex
+	lastRequest time.Time
+}
+
+// NewClient creates a MusicBrainz client, userAgent identifies the application as MusicBrainz requires
+func NewClient(userAgent string) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    defaultBaseURL,
+		userAgent:  userAgent,
+	}
+}
+
+// SearchReleaseGroups searches release groups by title and, when given, artist. The best match comes first.
+func (c *Client) SearchReleaseGroups(ctx context.Context, title, artist string) ([]ReleaseGroup, error) {
+	query := fmt.Sprintf(`releasegroup:"%s"`, escapeQuery(title))
+	if artist != "" {
+		query += fmt.Sprintf(` AND artist:"%s"`, escapeQuery(artist))
+	}
+
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("limit", "5")
+	params.Set("fmt", "json")
+
+	var result struct {
+		ReleaseGroups []ReleaseGroup `json:"release-groups"`
+	}
+	if err := c.get(ctx, "/release-group?"+params.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("failed to search release groups: %w", err)
+	}
+	return result.ReleaseGroups, nil
+}
+
+// get sends a request once the rate limit allows it and decodes the JSON response
+func (c *Client) get(ctx context.Context, path string, target any) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from MusicBrainz", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// wait blocks until a second has passed since the previous request
+func (c *Client) wait(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if delay := requestInterval - time.Since(c.lastRequest); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	c.lastRequest = time.Now()
+	return nil
+}
+
+// escapeQuery escapes the characters that end a quoted Lucene phrase
+func escapeQuery(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
@@ -0,0 +1,52 @@
+package musicbrainz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchReleaseGroups(t *testing.T) {
+	var query, userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		userAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"release-groups":[{"id":"b84ee12a-09ef-421b-82de-0441a926375b","title":"OK Computer","score":100,` +
+			`"primary-type":"Album","first-release-date":"1997-05-21","artist-credit":[{"name":"Radiohead"}]}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("Suasor-Test/1.0")
+	client.baseURL = server.URL
+
+	groups, err := client.SearchReleaseGroups(context.Background(), `OK "Computer"`, "Radiohead")
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+
+	assert.Equal(t, `releasegroup:"OK \"Computer\"" AND artist:"Radiohead"`, query)
+	assert.Equal(t, "Suasor-Test/1.0", userAgent)
+
+	group := groups[0]
+	assert.Equal(t, "OK Computer", group.Title)
+	assert.Equal(t, "Radiohead", group.Artist())
+	assert.Equal(t, 1997, group.Year())
+	assert.Equal(t, "https://coverartarchive.org/release-group/b84ee12a-09ef-421b-82de-0441a926375b/front-500", group.CoverArtURL())
+}
+
+func TestSearchReleaseGroupsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient("Suasor-Test/1.0")
+	client.baseURL = server.URL
+
+	_, err := client.SearchReleaseGroups(context.Background(), "OK Computer", "")
+	assert.Error(t, err)
+}
@@ -7,6 +7,8 @@ import (
 	"suasor/clients"
 	"suasor/clients/ai"
 	claude "suasor/clients/ai/claude"
+	ollama "suasor/clients/ai/ollama"
+	openai "suasor/clients/ai/openai"
 	clienttypes "suasor/clients/types"
 	"suasor/di/container"
 	"suasor/utils/logger"
@@ -22,7 +24,17 @@ func RegisterAIClientFactories(ctx context.Context, c *container.Container) {
 		return claude.NewClaudeClient(ctx, clientID, *config)
 	})
 
-	// Register other AI clients here as needed (OpenAI, Ollama, etc.)
+	// Register OpenAI client
+	registerAIClientProvider(ctx, service, clienttypes.ClientTypeOpenAI, func(ctx context.Context, clientID uint64, config *clienttypes.OpenAIConfig) (ai.ClientAI, error) {
+		return openai.NewOpenAIClient(ctx, clientID, *config)
+	})
+
+	// Register Ollama client
+	registerAIClientProvider(ctx, service, clienttypes.ClientTypeOllama, func(ctx context.Context, clientID uint64, config *clienttypes.OllamaConfig) (ai.ClientAI, error) {
+		return ollama.NewOllamaClient(ctx, clientID, *config)
+	})
+
+	// Register other AI clients here as needed
 }
 
 // registerAIClientProvider registers an AI client provider with the factory service
@@ -27,4 +27,47 @@ func RegisterSystemHandlers(ctx context.Context, c *container.Container) {
 		searchService := container.MustGet[services.SearchService](c)
 		return handlers.NewSearchHandler(searchService)
 	})
+
+	// Similar items handler
+	container.RegisterFactory[*handlers.SimilarItemsHandler](c, func(c *container.Container) *handlers.SimilarItemsHandler {
+		embeddingService := container.MustGet[services.EmbeddingService](c)
+		return handlers.NewSimilarItemsHandler(embeddingService)
+	})
+
+	// AI usage handler
+	container.RegisterFactory[*handlers.AIUsageHandler](c, func(c *container.Container) *handlers.AIUsageHandler {
+		usageService := container.MustGet[services.AIUsageService](c)
+		return handlers.NewAIUsageHandler(usageService)
+	})
+
+	// Prompt template handler
+	container.RegisterFactory[*handlers.PromptTemplateHandler](c, func(c *container.Container) *handlers.PromptTemplateHandler {
+		promptService := container.MustGet[services.PromptTemplateService](c)
+		return handlers.NewPromptTemplateHandler(promptService)
+	})
+
+	// Session handler
+	container.RegisterFactory[*handlers.SessionHandler](c, func(c *container.Container) *handlers.SessionHandler {
+		sessionService := container.MustGet[services.SessionService](c)
+		return handlers.NewSessionHandler(sessionService)
+	})
+
+	// Library handler
+	container.RegisterFactory[*handlers.LibraryHandler](c, func(c *container.Container) *handlers.LibraryHandler {
+		libraryService := container.MustGet[services.LibraryService](c)
+		return handlers.NewLibraryHandler(libraryService)
+	})
+
+	// Calendar handler
+	container.RegisterFactory[*handlers.CalendarHandler](c, func(c *container.Container) *handlers.CalendarHandler {
+		calendarService := container.MustGet[services.CalendarService](c)
+		calendarFeedService := container.MustGet[services.CalendarFeedService](c)
+		return handlers.NewCalendarHandler(calendarService, calendarFeedService)
+	})
+
+	// Webhook handler
+	container.RegisterFactory[*handlers.WebhookHandler](c, func(c *container.Container) *handlers.WebhookHandler {
+		webhookService := container.MustGet[services.WebhookService](c)
+		return handlers.NewWebhookHandler(webhookService)
+	})
 }
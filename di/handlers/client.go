@@ -49,11 +49,15 @@ func RegisterClientHandlers(ctx context.Context, c *container.Container) {
 		clientFactory := container.MustGet[*clients.ClientProviderFactoryService](c)
 		clientService := container.MustGet[services.ClientService[*types.ClaudeConfig]](c)
 		conversationService := container.MustGet[services.AIConversationService](c)
+		usageService := container.MustGet[services.AIUsageService](c)
+		promptService := container.MustGet[services.PromptTemplateService](c)
 
 		handler := handlers.NewAIHandler(
 			clientFactory,
 			clientService,
 			conversationService,
+			usageService,
+			promptService,
 		)
 		return handler
 	})
@@ -62,11 +66,15 @@ func RegisterClientHandlers(ctx context.Context, c *container.Container) {
 		clientFactory := container.MustGet[*clients.ClientProviderFactoryService](c)
 		clientService := container.MustGet[services.ClientService[*types.OpenAIConfig]](c)
 		conversationService := container.MustGet[services.AIConversationService](c)
+		usageService := container.MustGet[services.AIUsageService](c)
+		promptService := container.MustGet[services.PromptTemplateService](c)
 
 		handler := handlers.NewAIHandler(
 			clientFactory,
 			clientService,
 			conversationService,
+			usageService,
+			promptService,
 		)
 		return handler
 	})
@@ -75,11 +83,15 @@ func RegisterClientHandlers(ctx context.Context, c *container.Container) {
 		clientFactory := container.MustGet[*clients.ClientProviderFactoryService](c)
 		clientService := container.MustGet[services.ClientService[*types.OllamaConfig]](c)
 		conversationService := container.MustGet[services.AIConversationService](c)
+		usageService := container.MustGet[services.AIUsageService](c)
+		promptService := container.MustGet[services.PromptTemplateService](c)
 
 		handler := handlers.NewAIHandler(
 			clientFactory,
 			clientService,
 			conversationService,
+			usageService,
+			promptService,
 		)
 		return handler
 	})
@@ -88,11 +100,15 @@ func RegisterClientHandlers(ctx context.Context, c *container.Container) {
 		clientFactory := container.MustGet[*clients.ClientProviderFactoryService](c)
 		clientService := container.MustGet[services.ClientService[*types.OllamaConfig]](c)
 		conversationService := container.MustGet[services.AIConversationService](c)
+		usageService := container.MustGet[services.AIUsageService](c)
+		promptService := container.MustGet[services.PromptTemplateService](c)
 
 		handler := handlers.NewAIHandler(
 			clientFactory,
 			clientService,
 			conversationService,
+			usageService,
+			promptService,
 		)
 		return handler
 	})
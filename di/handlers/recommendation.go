@@ -14,4 +14,9 @@ func RegisterRecommendationHandlers(ctx context.Context, c *container.Container)
 		recommendationService := container.MustGet[services.RecommendationService](c)
 		return handlers.NewRecommendationHandler(recommendationService)
 	})
+
+	container.RegisterFactory[*handlers.GroupRecommendationHandler](c, func(c *container.Container) *handlers.GroupRecommendationHandler {
+		groupRecommendationService := container.MustGet[services.GroupRecommendationService](c)
+		return handlers.NewGroupRecommendationHandler(groupRecommendationService)
+	})
 }
@@ -29,6 +29,30 @@ func RegisterMediaRepositories(ctx context.Context, c *container.Container) {
 		return repository.NewCreditRepository(db)
 	})
 
+	log.Info().Msg("Registering embedding repository")
+	container.RegisterFactory[repository.EmbeddingRepository](c, func(c *container.Container) repository.EmbeddingRepository {
+		db := container.MustGet[*gorm.DB](c)
+		return repository.NewEmbeddingRepository(db)
+	})
+
+	log.Info().Msg("Registering media item neighbor repository")
+	container.RegisterFactory[repository.NeighborRepository](c, func(c *container.Container) repository.NeighborRepository {
+		db := container.MustGet[*gorm.DB](c)
+		return repository.NewNeighborRepository(db)
+	})
+
+	log.Info().Msg("Registering recommendation feedback repository")
+	container.RegisterFactory[repository.RecommendationFeedbackRepository](c, func(c *container.Container) repository.RecommendationFeedbackRepository {
+		db := container.MustGet[*gorm.DB](c)
+		return repository.NewRecommendationFeedbackRepository(db)
+	})
+
+	log.Info().Msg("Registering household group repository")
+	container.RegisterFactory[repository.HouseholdGroupRepository](c, func(c *container.Container) repository.HouseholdGroupRepository {
+		db := container.MustGet[*gorm.DB](c)
+		return repository.NewHouseholdGroupRepository(db)
+	})
+
 	// Search repository
 	log.Info().Msg("Registering search repository")
 	container.RegisterFactory[repository.SearchRepository](c, func(c *container.Container) repository.SearchRepository {
@@ -28,6 +28,18 @@ func registerSystemRepositories(ctx context.Context, c *container.Container) {
 		return repository.NewUserConfigRepository(db)
 	})
 
+	log.Info().Msg("Registering calendar feed repository")
+	container.RegisterFactory[repository.CalendarFeedRepository](c, func(c *container.Container) repository.CalendarFeedRepository {
+		db := container.MustGet[*gorm.DB](c)
+		return repository.NewCalendarFeedRepository(db)
+	})
+
+	log.Info().Msg("Registering webhook token repository")
+	container.RegisterFactory[repository.WebhookTokenRepository](c, func(c *container.Container) repository.WebhookTokenRepository {
+		db := container.MustGet[*gorm.DB](c)
+		return repository.NewWebhookTokenRepository(db)
+	})
+
 	log.Info().Msg("Registering session repository")
 	container.RegisterSingleton[repository.SessionRepository](c, func(c *container.Container) repository.SessionRepository {
 		fmt.Println("Creating SessionRepository")
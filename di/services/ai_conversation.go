@@ -3,9 +3,11 @@ package services
 import (
 	"context"
 	"suasor/clients"
+	mediatypes "suasor/clients/media/types"
 	"suasor/clients/types"
 	"suasor/di/container"
 	"suasor/repository"
+	repobundles "suasor/repository/bundles"
 	"suasor/services"
 	"suasor/utils/logger"
 
@@ -23,6 +25,53 @@ func registerAIConversationService(ctx context.Context, c *container.Container)
 		return repository.NewAIConversationRepository(db)
 	})
 
+	// Register the AI token accounting
+	log.Info().Msg("Registering AI usage service")
+	container.RegisterFactory[repository.AIUsageRepository](c, func(c *container.Container) repository.AIUsageRepository {
+		db := container.MustGet[*gorm.DB](c)
+		return repository.NewAIUsageRepository(db)
+	})
+	container.RegisterFactory[services.AIUsageService](c, func(c *container.Container) services.AIUsageService {
+		usageRepo := container.MustGet[repository.AIUsageRepository](c)
+		userRepo := container.MustGet[repository.UserRepository](c)
+		return services.NewAIUsageService(usageRepo, userRepo)
+	})
+
+	// Register the AI router that picks a client per task
+	log.Info().Msg("Registering AI router")
+	container.RegisterFactory[services.AIRouter](c, func(c *container.Container) services.AIRouter {
+		configService := container.MustGet[services.ConfigService](c)
+		userConfigRepo := container.MustGet[repository.UserConfigRepository](c)
+		clientRepos := container.MustGet[repobundles.ClientRepositories](c)
+		clientHelper := container.MustGet[repository.ClientHelper](c)
+		clientFactory := container.MustGet[*clients.ClientProviderFactoryService](c)
+		return services.NewAIRouter(configService, userConfigRepo, clientRepos, clientHelper, clientFactory)
+	})
+
+	// Register the versioned prompt templates
+	log.Info().Msg("Registering prompt template service")
+	container.RegisterFactory[repository.PromptTemplateRepository](c, func(c *container.Container) repository.PromptTemplateRepository {
+		db := container.MustGet[*gorm.DB](c)
+		return repository.NewPromptTemplateRepository(db)
+	})
+	container.RegisterFactory[services.PromptTemplateService](c, func(c *container.Container) services.PromptTemplateService {
+		templateRepo := container.MustGet[repository.PromptTemplateRepository](c)
+		userConfigRepo := container.MustGet[repository.UserConfigRepository](c)
+		return services.NewPromptTemplateService(templateRepo, userConfigRepo)
+	})
+
+	// Register the tools AI conversations may call
+	log.Info().Msg("Registering AI tool registry")
+	container.RegisterFactory[services.AIToolRegistry](c, func(c *container.Container) services.AIToolRegistry {
+		itemRepos := container.MustGet[repobundles.CoreMediaItemRepositories](c)
+		userDataRepos := container.MustGet[repobundles.UserMediaDataRepositories](c)
+		playlistService := container.MustGet[services.UserListService[*mediatypes.Playlist]](c)
+		clientRepos := container.MustGet[repobundles.ClientRepositories](c)
+		clientFactory := container.MustGet[*clients.ClientProviderFactoryService](c)
+
+		return services.NewAIToolRegistry(itemRepos, userDataRepos, playlistService, clientRepos, clientFactory)
+	})
+
 	// Register the AI conversation service
 	log.Info().Msg("Registering AI conversation service")
 	container.RegisterFactory[services.AIConversationService](c, func(c *container.Container) services.AIConversationService {
@@ -32,7 +81,9 @@ func registerAIConversationService(ctx context.Context, c *container.Container)
 		openaiClientService := container.MustGet[services.ClientService[*types.OpenAIConfig]](c)
 		ollamaClientService := container.MustGet[services.ClientService[*types.OllamaConfig]](c)
 		clientHelper := container.MustGet[repository.ClientHelper](c)
+		tools := container.MustGet[services.AIToolRegistry](c)
+		usage := container.MustGet[services.AIUsageService](c)
 
-		return services.NewAIConversationService(repo, claudeClientService, openaiClientService, ollamaClientService, clientHelper, clientFactory)
+		return services.NewAIConversationService(repo, claudeClientService, openaiClientService, ollamaClientService, clientHelper, clientFactory, tools, usage)
 	})
 }
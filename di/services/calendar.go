@@ -0,0 +1,45 @@
+// app/di/services/calendar.go
+package services
+
+import (
+	"context"
+	"suasor/clients"
+	clienttypes "suasor/clients/types"
+	"suasor/di/container"
+	"suasor/repository"
+	apprepos "suasor/repository/bundles"
+	"suasor/services"
+)
+
+// registerCalendarService registers the release calendar service
+func registerCalendarService(ctx context.Context, c *container.Container) {
+	container.RegisterFactory[services.CalendarService](c, func(c *container.Container) services.CalendarService {
+		clientRepos := container.MustGet[apprepos.ClientRepositories](c)
+		tmdbRepo := container.MustGet[repository.ClientRepository[*clienttypes.TMDBConfig]](c)
+		dataRepos := container.MustGet[apprepos.UserMediaDataRepositories](c)
+		recommendationRepo := container.MustGet[repository.RecommendationRepository](c)
+		clientFactoryService := container.MustGet[*clients.ClientProviderFactoryService](c)
+		return services.NewCalendarService(
+			clientRepos,
+			tmdbRepo,
+			dataRepos,
+			recommendationRepo,
+			clientFactoryService,
+		)
+	})
+}
+
+// registerCalendarFeedService registers the ICS calendar feed service
+func registerCalendarFeedService(ctx context.Context, c *container.Container) {
+	container.RegisterFactory[services.CalendarFeedService](c, func(c *container.Container) services.CalendarFeedService {
+		configService := container.MustGet[services.ConfigService](c)
+		appConfig := configService.GetConfig()
+		feedRepo := container.MustGet[repository.CalendarFeedRepository](c)
+		calendarService := container.MustGet[services.CalendarService](c)
+		return services.NewCalendarFeedService(
+			appConfig.HTTP.BaseURL,
+			feedRepo,
+			calendarService,
+		)
+	})
+}
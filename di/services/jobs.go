@@ -3,9 +3,12 @@ package services
 import (
 	"context"
 	"suasor/clients"
+	"suasor/clients/metadata/musicbrainz"
+	clienttypes "suasor/clients/types"
 	"suasor/di/container"
 	"suasor/repository"
 	repobundles "suasor/repository/bundles"
+	"suasor/services"
 	svcbundles "suasor/services/bundles"
 	"suasor/services/jobs"
 	"suasor/services/jobs/recommendation"
@@ -107,6 +110,7 @@ func registerJobServices(ctx context.Context, c *container.Container) {
 		userRepo := container.MustGet[repository.UserRepository](c)
 		userConfigRepo := container.MustGet[repository.UserConfigRepository](c)
 		recommendationRepo := container.MustGet[repository.RecommendationRepository](c)
+		feedbackRepo := container.MustGet[repository.RecommendationFeedbackRepository](c)
 		clientRepos := container.MustGet[repobundles.ClientRepositories](c)
 		itemRepos := container.MustGet[repobundles.CoreMediaItemRepositories](c)
 		clientItemRepos := container.MustGet[repobundles.ClientMediaItemRepositories](c)
@@ -114,8 +118,45 @@ func registerJobServices(ctx context.Context, c *container.Container) {
 		clientFactories := container.MustGet[*clients.ClientProviderFactoryService](c)
 		creditRepo := container.MustGet[repository.CreditRepository](c)
 		peopleRepo := container.MustGet[repository.PersonRepository](c)
-		return recommendation.NewRecommendationJob(ctx, jobRepo, userRepo, userConfigRepo, recommendationRepo, clientRepos, itemRepos, clientItemRepos, dataRepos, clientFactories, creditRepo, peopleRepo)
+		tmdbRepo := container.MustGet[repository.ClientRepository[*clienttypes.TMDBConfig]](c)
+		musicBrainz := musicbrainz.NewClient("Suasor/1.0")
+		usageService := container.MustGet[services.AIUsageService](c)
+		aiRouter := container.MustGet[services.AIRouter](c)
+		promptService := container.MustGet[services.PromptTemplateService](c)
+		return recommendation.NewRecommendationJob(ctx, jobRepo, userRepo, userConfigRepo, recommendationRepo, feedbackRepo, clientRepos, itemRepos, clientItemRepos, dataRepos, clientFactories, creditRepo, peopleRepo, tmdbRepo, musicBrainz, usageService, aiRouter, promptService)
 
 	})
 
+	// Collaborative filtering recommendation job
+	log.Info().Msg("Registering collaborative filtering job service")
+	container.RegisterFactory[*recommendation.CollaborativeJob](c, func(c *container.Container) *recommendation.CollaborativeJob {
+		jobRepo := container.MustGet[repository.JobRepository](c)
+		userRepo := container.MustGet[repository.UserRepository](c)
+		userConfigRepo := container.MustGet[repository.UserConfigRepository](c)
+		recommendationRepo := container.MustGet[repository.RecommendationRepository](c)
+		feedbackRepo := container.MustGet[repository.RecommendationFeedbackRepository](c)
+		neighborRepo := container.MustGet[repository.NeighborRepository](c)
+		itemRepos := container.MustGet[repobundles.CoreMediaItemRepositories](c)
+		return recommendation.NewCollaborativeJob(jobRepo, userRepo, userConfigRepo, recommendationRepo, feedbackRepo, neighborRepo, itemRepos)
+	})
+
+	// Recommendation ranking job
+	log.Info().Msg("Registering recommendation ranking job service")
+	container.RegisterFactory[*recommendation.RankingJob](c, func(c *container.Container) *recommendation.RankingJob {
+		jobRepo := container.MustGet[repository.JobRepository](c)
+		userRepo := container.MustGet[repository.UserRepository](c)
+		userConfigRepo := container.MustGet[repository.UserConfigRepository](c)
+		recommendationRepo := container.MustGet[repository.RecommendationRepository](c)
+		feedbackRepo := container.MustGet[repository.RecommendationFeedbackRepository](c)
+		neighborRepo := container.MustGet[repository.NeighborRepository](c)
+		return recommendation.NewRankingJob(jobRepo, userRepo, userConfigRepo, recommendationRepo, feedbackRepo, neighborRepo)
+	})
+
+	// Embedding Job
+	log.Info().Msg("Registering embedding job service")
+	container.RegisterFactory[*jobs.EmbeddingJob](c, func(c *container.Container) *jobs.EmbeddingJob {
+		jobRepo := container.MustGet[repository.JobRepository](c)
+		embeddingService := container.MustGet[services.EmbeddingService](c)
+		return jobs.NewEmbeddingJob(jobRepo, embeddingService)
+	})
 }
@@ -0,0 +1,22 @@
+// app/di/services/library.go
+package services
+
+import (
+	"context"
+	"suasor/clients"
+	"suasor/di/container"
+	apprepos "suasor/repository/bundles"
+	"suasor/services"
+)
+
+// registerLibraryService registers the media client library service
+func registerLibraryService(ctx context.Context, c *container.Container) {
+	container.RegisterFactory[services.LibraryService](c, func(c *container.Container) services.LibraryService {
+		clientRepos := container.MustGet[apprepos.ClientRepositories](c)
+		clientFactoryService := container.MustGet[*clients.ClientProviderFactoryService](c)
+		return services.NewLibraryService(
+			clientRepos,
+			clientFactoryService,
+		)
+	})
+}
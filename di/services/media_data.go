@@ -7,6 +7,7 @@ import (
 	clienttypes "suasor/clients/types"
 	"suasor/di/container"
 	"suasor/repository"
+	repobundles "suasor/repository/bundles"
 	"suasor/services"
 	"suasor/utils/logger"
 )
@@ -14,6 +15,13 @@ import (
 func registerMediaDataServices(ctx context.Context, c *container.Container) {
 	log := logger.LoggerFromContext(ctx)
 
+	log.Info().Msg("Registering media write-back service")
+	container.RegisterFactory[services.MediaWriteBackService](c, func(c *container.Container) services.MediaWriteBackService {
+		clientRepos := container.MustGet[repobundles.ClientRepositories](c)
+		clientFactory := container.MustGet[*clients.ClientProviderFactoryService](c)
+		return services.NewMediaWriteBackService(clientRepos, clientFactory)
+	})
+
 	log.Info().Msg("Registering media Data services")
 	registerMediaDataService[*mediatypes.Movie](c)
 	registerMediaDataService[*mediatypes.Series](c)
@@ -76,7 +84,9 @@ func registerMediaDataService[T mediatypes.MediaData](c *container.Container) {
 	container.RegisterFactory[services.UserMediaItemDataService[T]](c, func(c *container.Container) services.UserMediaItemDataService[T] {
 		coreService := container.MustGet[services.CoreUserMediaItemDataService[T]](c)
 		repo := container.MustGet[repository.UserMediaItemDataRepository[T]](c)
-		return services.NewUserMediaItemDataService[T](coreService, repo)
+		itemService := container.MustGet[services.CoreMediaItemService[T]](c)
+		writeBackService := container.MustGet[services.MediaWriteBackService](c)
+		return services.NewUserMediaItemDataService[T](coreService, repo, itemService, writeBackService)
 	})
 }
 
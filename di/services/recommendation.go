@@ -5,13 +5,31 @@ import (
 	"context"
 	"suasor/di/container"
 	"suasor/repository"
+	repobundles "suasor/repository/bundles"
 	"suasor/services"
+	"suasor/services/jobs/recommendation"
 )
 
 // RegisterRecommendationService registers the recommendation service
 func registerRecommendationService(ctx context.Context, c *container.Container) {
 	container.RegisterFactory[services.RecommendationService](c, func(c *container.Container) services.RecommendationService {
 		recommendationRepo := container.MustGet[repository.RecommendationRepository](c)
-		return services.NewRecommendationService(recommendationRepo)
+		feedbackRepo := container.MustGet[repository.RecommendationFeedbackRepository](c)
+		return services.NewRecommendationService(recommendationRepo, feedbackRepo)
+	})
+
+	container.RegisterFactory[*recommendation.GroupRecommender](c, func(c *container.Container) *recommendation.GroupRecommender {
+		userConfigRepo := container.MustGet[repository.UserConfigRepository](c)
+		neighborRepo := container.MustGet[repository.NeighborRepository](c)
+		creditRepo := container.MustGet[repository.CreditRepository](c)
+		itemRepos := container.MustGet[repobundles.CoreMediaItemRepositories](c)
+		return recommendation.NewGroupRecommender(userConfigRepo, neighborRepo, creditRepo, itemRepos)
+	})
+
+	container.RegisterFactory[services.GroupRecommendationService](c, func(c *container.Container) services.GroupRecommendationService {
+		userRepo := container.MustGet[repository.UserRepository](c)
+		groupRepo := container.MustGet[repository.HouseholdGroupRepository](c)
+		recommender := container.MustGet[*recommendation.GroupRecommender](c)
+		return services.NewGroupRecommendationService(userRepo, groupRepo, recommender)
 	})
 }
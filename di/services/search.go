@@ -18,12 +18,36 @@ func registerSearchService(ctx context.Context, c *container.Container) {
 		itemRepos := container.MustGet[apprepos.CoreMediaItemRepositories](c)
 		personRepo := container.MustGet[repository.PersonRepository](c)
 		clientFactoryService := container.MustGet[*clients.ClientProviderFactoryService](c)
+		embeddingService := container.MustGet[services.EmbeddingService](c)
+		aiRouter := container.MustGet[services.AIRouter](c)
+		usageService := container.MustGet[services.AIUsageService](c)
 		return services.NewSearchService(
 			searchRepo,
 			clientRepos,
 			itemRepos,
 			personRepo,
 			clientFactoryService,
+			embeddingService,
+			aiRouter,
+			usageService,
+		)
+	})
+}
+
+// registerEmbeddingService registers the embedding service used for semantic search and similar items
+func registerEmbeddingService(ctx context.Context, c *container.Container) {
+	container.RegisterFactory[services.EmbeddingService](c, func(c *container.Container) services.EmbeddingService {
+		embeddingRepo := container.MustGet[repository.EmbeddingRepository](c)
+		itemRepos := container.MustGet[apprepos.CoreMediaItemRepositories](c)
+		creditRepo := container.MustGet[repository.CreditRepository](c)
+		clientRepos := container.MustGet[apprepos.ClientRepositories](c)
+		clientFactoryService := container.MustGet[*clients.ClientProviderFactoryService](c)
+		return services.NewEmbeddingService(
+			embeddingRepo,
+			itemRepos,
+			creditRepo,
+			clientRepos,
+			clientFactoryService,
 		)
 	})
 }
@@ -38,10 +38,32 @@ func RegisterServices(ctx context.Context, c *container.Container) {
 	log.Info().Msg("Registering jobs")
 	registerJobServices(ctx, c)
 
+	// Embedding service
+	log.Info().Msg("Registering embedding service")
+	registerEmbeddingService(ctx, c)
+
 	// Search service
 	log.Info().Msg("Registering search service")
 	registerSearchService(ctx, c)
 
+	// Session service
+	log.Info().Msg("Registering session service")
+	registerSessionService(ctx, c)
+
+	// Webhook service
+	log.Info().Msg("Registering webhook service")
+	registerWebhookService(ctx, c)
+
+	// Library service
+	log.Info().Msg("Registering library service")
+	registerLibraryService(ctx, c)
+
+	// Calendar service
+	log.Info().Msg("Registering calendar service")
+	registerCalendarService(ctx, c)
+	log.Info().Msg("Registering calendar feed service")
+	registerCalendarFeedService(ctx, c)
+
 	// Recommendation service
 	log.Info().Msg("Registering recommendation service")
 	registerRecommendationService(ctx, c)
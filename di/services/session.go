@@ -0,0 +1,24 @@
+// app/di/services/session.go
+package services
+
+import (
+	"context"
+	"suasor/clients"
+	"suasor/di/container"
+	apprepos "suasor/repository/bundles"
+	"suasor/services"
+)
+
+// registerSessionService registers the playback session service
+func registerSessionService(ctx context.Context, c *container.Container) {
+	container.RegisterFactory[services.SessionService](c, func(c *container.Container) services.SessionService {
+		clientRepos := container.MustGet[apprepos.ClientRepositories](c)
+		itemRepos := container.MustGet[apprepos.CoreMediaItemRepositories](c)
+		clientFactoryService := container.MustGet[*clients.ClientProviderFactoryService](c)
+		return services.NewSessionService(
+			clientRepos,
+			itemRepos,
+			clientFactoryService,
+		)
+	})
+}
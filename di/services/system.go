@@ -150,7 +150,8 @@ func registerSystemServices(ctx context.Context, c *container.Container) {
 	// Recommendation service
 	container.RegisterFactory[services.RecommendationService](c, func(c *container.Container) services.RecommendationService {
 		recommendationRepo := container.MustGet[repository.RecommendationRepository](c)
-		return services.NewRecommendationService(recommendationRepo)
+		feedbackRepo := container.MustGet[repository.RecommendationFeedbackRepository](c)
+		return services.NewRecommendationService(recommendationRepo, feedbackRepo)
 	})
 
 }
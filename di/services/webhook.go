@@ -0,0 +1,32 @@
+// app/di/services/webhook.go
+package services
+
+import (
+	"context"
+	"suasor/di/container"
+	"suasor/repository"
+	apprepos "suasor/repository/bundles"
+	"suasor/services"
+	"suasor/services/jobs/sync"
+)
+
+// registerWebhookService registers the inbound media server webhook service
+func registerWebhookService(ctx context.Context, c *container.Container) {
+	container.RegisterFactory[services.WebhookService](c, func(c *container.Container) services.WebhookService {
+		configService := container.MustGet[services.ConfigService](c)
+		appConfig := configService.GetConfig()
+		tokenRepo := container.MustGet[repository.WebhookTokenRepository](c)
+		clientRepos := container.MustGet[apprepos.ClientRepositories](c)
+		itemRepos := container.MustGet[apprepos.CoreMediaItemRepositories](c)
+		dataRepos := container.MustGet[apprepos.UserMediaDataRepositories](c)
+		mediaSyncJob := container.MustGet[*sync.MediaSyncJob](c)
+		return services.NewWebhookService(
+			appConfig.HTTP.BaseURL,
+			tokenRepo,
+			clientRepos,
+			itemRepos,
+			dataRepos,
+			mediaSyncJob,
+		)
+	})
+}
@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"suasor/clients"
 	"suasor/clients/ai"
 	aitypes "suasor/clients/ai/types"
 	"suasor/clients/types"
 	"suasor/services"
+	"suasor/types/models"
 	"suasor/types/requests"
 	"suasor/types/responses"
 	"suasor/utils/logger"
@@ -28,6 +31,10 @@ type aiHandler[T types.AIClientConfig] struct {
 	service services.ClientService[T]
 	// Conversation service for persistent storage
 	conversationService services.AIConversationService
+	// Usage service for token budgets and accounting
+	usageService services.AIUsageService
+	// Prompt service for the conversation system prompt and personality
+	promptService services.PromptTemplateService
 	// Map to track active conversations by conversationID
 	activeConversations map[string]uint64 // conversationID -> userID
 }
@@ -37,11 +44,15 @@ func NewAIHandler[T types.AIClientConfig](
 	factory *clients.ClientProviderFactoryService,
 	service services.ClientService[T],
 	conversationService services.AIConversationService,
+	usageService services.AIUsageService,
+	promptService services.PromptTemplateService,
 ) AIHandler[T] {
 	return &aiHandler[T]{
 		factory:             factory,
 		service:             service,
 		conversationService: conversationService,
+		usageService:        usageService,
+		promptService:       promptService,
 		activeConversations: make(map[string]uint64),
 	}
 }
@@ -87,6 +98,10 @@ func (h *aiHandler[T]) RequestRecommendation(c *gin.Context) {
 		responses.RespondInternalError(c, err, "Failed to initialize AI client")
 		return
 	}
+	ctx, ok := h.trackUsage(c, userID, aiClient, models.AIFeatureRecommendation)
+	if !ok {
+		return
+	}
 
 	// Get recommendations
 	aiRecommendationResponse, err := aiClient.GetRecommendations(ctx, &req)
@@ -144,6 +159,10 @@ func (h *aiHandler[T]) AnalyzeContent(c *gin.Context) {
 		responses.RespondInternalError(c, err, "Failed to initialize AI client")
 		return
 	}
+	ctx, ok := h.trackUsage(c, userID.(uint64), aiClient, models.AIFeatureAnalysis)
+	if !ok {
+		return
+	}
 
 	// Analyze content
 	analysis, err := aiClient.AnalyzeContent(ctx, req.ContentType, req.Content, req.Options)
@@ -205,6 +224,24 @@ func (h *aiHandler[T]) StartConversation(c *gin.Context) {
 		responses.RespondInternalError(c, err, "Failed to initialize AI client")
 		return
 	}
+	ctx, ok := h.trackUsage(c, userID, aiClient, models.AIFeatureConversation)
+	if !ok {
+		return
+	}
+
+	// Conversations without their own system prompt get the template with the user's personality
+	if req.SystemInstructions == "" {
+		instructions, err := h.promptService.ConversationInstructions(ctx, userID, req.ContentType)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to render conversation prompt, using the client's default")
+		} else {
+			req.SystemInstructions = instructions.Text
+			log.Debug().
+				Str("template", instructions.Name).
+				Int("version", instructions.Version).
+				Msg("Using conversation prompt template")
+		}
+	}
 
 	// Start the conversation with the AI client
 	conversationID, welcomeMessage, err := aiClient.StartRecommendationConversation(
@@ -250,6 +287,21 @@ func (h *aiHandler[T]) StartConversation(c *gin.Context) {
 	responses.RespondOK(c, response, "Conversation started successfully")
 }
 
+// trackUsage checks the user's AI token budget and returns a context that records the tokens spent on the feature.
+// It responds with 429 Too Many Requests and returns false when the budget is used up.
+func (h *aiHandler[T]) trackUsage(c *gin.Context, userID uint64, aiClient ai.ClientAI, feature models.AIFeature) (context.Context, bool) {
+	ctx := c.Request.Context()
+	if err := h.usageService.CheckBudget(ctx, userID); err != nil {
+		if errors.Is(err, services.ErrAIBudgetExceeded) {
+			responses.RespondWithError(c, http.StatusTooManyRequests, err, "AI token budget exceeded")
+		} else {
+			responses.RespondInternalError(c, err, "Failed to check AI token budget")
+		}
+		return ctx, false
+	}
+	return h.usageService.Track(ctx, userID, aiClient, feature), true
+}
+
 func (h *aiHandler[T]) getAIClient(ctx context.Context, userID uint64, clientType types.ClientType, clientID uint64) (ai.ClientAI, error) {
 	log := logger.LoggerFromContext(ctx)
 
@@ -356,6 +408,10 @@ func (h *aiHandler[T]) SendConversationMessage(c *gin.Context) {
 		responses.RespondInternalError(c, err, "Failed to initialize AI client")
 		return
 	}
+	ctx, ok := h.trackUsage(c, userID, aiClient, models.AIFeatureConversation)
+	if !ok {
+		return
+	}
 
 	// Set extractRecommendations to true by default if not specified
 	context := req.Context
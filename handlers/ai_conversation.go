@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"strconv"
 	"suasor/services"
 	"suasor/types/requests"
@@ -17,8 +20,11 @@ type AIConversationHandler interface {
 	GetConversationHistory(c *gin.Context)
 	GetUserRecommendations(c *gin.Context)
 	ContinueConversation(c *gin.Context)
+	StreamMessage(c *gin.Context)
 	ArchiveConversation(c *gin.Context)
 	DeleteConversation(c *gin.Context)
+	GetConversationMemory(c *gin.Context)
+	ResetConversationMemory(c *gin.Context)
 }
 
 // aiConversationHandler implements AIConversationHandler
@@ -267,6 +273,120 @@ func (h *aiConversationHandler) ContinueConversation(c *gin.Context) {
 	responses.RespondOK(c, apiResponse, "Conversation continued successfully")
 }
 
+// StreamMessage godoc
+//
+//	@Summary		Send a message and stream the reply
+//	@Description	Sends a message in a conversation and relays the reply as Server-Sent Events while it is generated.
+//	@Description	"delta" events carry {"text": "..."} chunks, a final "done" event carries the full message and recommendations
+//	@Description	and an "error" event ends a failed stream. The reply is saved to the history once the stream ends, even if the client disconnects.
+//	@Tags			ai, conversations
+//	@Accept			json
+//	@Produce		text/event-stream
+//	@Security		BearerAuth
+//	@Param			conversationId	path		string									true	"Conversation ID"
+//	@Param			request			body		requests.ContinueConversationRequest	true	"Message"
+//	@Success		200				{string}	string									"Event stream"
+//	@Failure		400				{object}	responses.ErrorResponse					"Invalid request or the AI client cannot stream"
+//	@Failure		401				{object}	responses.ErrorResponse					"Unauthorized"
+//	@Failure		403				{object}	responses.ErrorResponse					"Forbidden - conversation not owned by user"
+//	@Failure		404				{object}	responses.ErrorResponse					"Conversation not found"
+//	@Failure		500				{object}	responses.ErrorResponse					"Server error"
+//	@Router			/ai/conversations/{conversationId}/messages/stream [post]
+func (h *aiConversationHandler) StreamMessage(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.LoggerFromContext(ctx)
+
+	// Get authenticated user ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		responses.RespondUnauthorized(c, nil, "Authentication required")
+		return
+	}
+
+	// Get conversation ID from path
+	conversationID := c.Param("conversationId")
+	if conversationID == "" {
+		responses.RespondBadRequest(c, nil, "Conversation ID is required")
+		return
+	}
+
+	var req requests.ContinueConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.RespondValidationError(c, err)
+		return
+	}
+
+	log.Info().
+		Uint64("userID", userID.(uint64)).
+		Str("conversationID", conversationID).
+		Msg("Streaming conversation message")
+
+	// The stream starts with the first delta, so errors before it still get a regular error response
+	streaming := false
+	startStream := func() {
+		if streaming {
+			return
+		}
+		streaming = true
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		// Stop reverse proxies like nginx from buffering the stream
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+	}
+
+	// Generation carries on when the client disconnects so the reply is still saved
+	response, recommendations, err := h.service.SendMessageStream(
+		context.WithoutCancel(ctx),
+		conversationID,
+		userID.(uint64),
+		req.Message,
+		req.Context,
+		func(delta string) error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			startStream()
+			c.SSEvent("delta", gin.H{"text": delta})
+			c.Writer.Flush()
+			return nil
+		},
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to stream conversation message")
+		if streaming {
+			c.SSEvent("error", gin.H{"message": "Failed to generate reply"})
+			c.Writer.Flush()
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrConversationNotFound):
+			responses.RespondNotFound(c, err, "Conversation not found")
+		case errors.Is(err, services.ErrConversationAccessDenied):
+			responses.RespondForbidden(c, err, "You do not have access to this conversation")
+		case errors.Is(err, services.ErrStreamingNotSupported):
+			responses.RespondBadRequest(c, err, "The conversation's AI client does not support streaming")
+		case errors.Is(err, services.ErrAIBudgetExceeded):
+			responses.RespondWithError(c, http.StatusTooManyRequests, err, "AI token budget exceeded")
+		default:
+			responses.RespondInternalError(c, err, "Failed to continue conversation")
+		}
+		return
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+	startStream()
+	c.SSEvent("done", responses.ConversationMessageResponse{
+		Message:         response,
+		Recommendations: recommendations,
+		Context:         make(map[string]any),
+	})
+	c.Writer.Flush()
+}
+
 // ArchiveConversation godoc
 //
 //	@Summary		Archive a conversation
@@ -365,8 +485,108 @@ func (h *aiConversationHandler) DeleteConversation(c *gin.Context) {
 	responses.RespondOK[any](c, nil, "Conversation deleted successfully")
 }
 
+// GetConversationMemory godoc
+//
+//	@Summary		Get the memory of a conversation
+//	@Description	Returns the summary and the liked and disliked titles of the turns compacted out of a long conversation.
+//	@Description	The memory is null until the conversation first nears the model's context window.
+//	@Tags			ai, conversations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			conversationId	path		string											true	"Conversation ID"
+//	@Success		200				{object}	responses.APIResponse[models.ConversationMemory]	"Conversation memory retrieved"
+//	@Failure		401				{object}	responses.ErrorResponse							"Unauthorized"
+//	@Failure		403				{object}	responses.ErrorResponse							"Forbidden - conversation not owned by user"
+//	@Failure		404				{object}	responses.ErrorResponse							"Conversation not found"
+//	@Failure		500				{object}	responses.ErrorResponse							"Server error"
+//	@Router			/user/ai/conversations/{conversationId}/memory [get]
+func (h *aiConversationHandler) GetConversationMemory(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.LoggerFromContext(ctx)
+
+	// Get authenticated user ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		responses.RespondUnauthorized(c, nil, "Authentication required")
+		return
+	}
+
+	conversationID := c.Param("conversationId")
+	if conversationID == "" {
+		responses.RespondBadRequest(c, nil, "Conversation ID is required")
+		return
+	}
+
+	memory, err := h.service.GetConversationMemory(ctx, conversationID, userID.(uint64))
+	if err != nil {
+		log.Error().Err(err).Str("conversationID", conversationID).Msg("Failed to get conversation memory")
+		respondConversationError(c, err, "Failed to retrieve conversation memory")
+		return
+	}
+
+	responses.RespondOK(c, memory, "Conversation memory retrieved successfully")
+}
+
+// ResetConversationMemory godoc
+//
+//	@Summary		Reset the memory of a conversation
+//	@Description	Forgets the summary and the liked and disliked titles of the compacted turns.
+//	@Description	The compacted turns are not sent to the model again, only the messages since.
+//	@Tags			ai, conversations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			conversationId	path		string							true	"Conversation ID"
+//	@Success		200				{object}	responses.APIResponse			"Conversation memory reset"
+//	@Failure		401				{object}	responses.ErrorResponse			"Unauthorized"
+//	@Failure		403				{object}	responses.ErrorResponse			"Forbidden - conversation not owned by user"
+//	@Failure		404				{object}	responses.ErrorResponse			"Conversation not found"
+//	@Failure		500				{object}	responses.ErrorResponse			"Server error"
+//	@Router			/user/ai/conversations/{conversationId}/memory [delete]
+func (h *aiConversationHandler) ResetConversationMemory(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.LoggerFromContext(ctx)
+
+	// Get authenticated user ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		responses.RespondUnauthorized(c, nil, "Authentication required")
+		return
+	}
+
+	conversationID := c.Param("conversationId")
+	if conversationID == "" {
+		responses.RespondBadRequest(c, nil, "Conversation ID is required")
+		return
+	}
+
+	log.Info().
+		Uint64("userID", userID.(uint64)).
+		Str("conversationID", conversationID).
+		Msg("Resetting conversation memory")
+
+	if err := h.service.ResetConversationMemory(ctx, conversationID, userID.(uint64)); err != nil {
+		log.Error().Err(err).Msg("Failed to reset conversation memory")
+		respondConversationError(c, err, "Failed to reset conversation memory")
+		return
+	}
+
+	responses.RespondOK[any](c, nil, "Conversation memory reset successfully")
+}
+
 // Helper functions
 
+// respondConversationError maps the conversation service errors to responses
+func respondConversationError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, services.ErrConversationNotFound):
+		responses.RespondNotFound(c, err, "Conversation not found")
+	case errors.Is(err, services.ErrConversationAccessDenied):
+		responses.RespondForbidden(c, err, "You do not have access to this conversation")
+	default:
+		responses.RespondInternalError(c, err, msg)
+	}
+}
+
 // getPaginationParams extracts and validates pagination parameters
 func getPaginationParams(c *gin.Context) (limit, offset int) {
 	// Default values
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"suasor/services"
+	"suasor/types/requests"
+	"suasor/types/responses"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AIUsageHandler lets admins see the tokens spent on AI requests and set token budgets
+type AIUsageHandler struct {
+	service services.AIUsageService
+}
+
+// NewAIUsageHandler creates a new AI usage handler
+func NewAIUsageHandler(service services.AIUsageService) *AIUsageHandler {
+	return &AIUsageHandler{service: service}
+}
+
+// GetUsageReport godoc
+//
+//	@Summary		Get the AI token spend per user
+//	@Description	Returns the tokens spent per user between two dates, broken down by client, model and feature.
+//	@Description	Defaults to the current month.
+//	@Tags			admin, ai
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			start	query		string												false	"Start date, YYYY-MM-DD or RFC3339"
+//	@Param			end		query		string												false	"End date, YYYY-MM-DD or RFC3339"
+//	@Success		200		{object}	responses.APIResponse[models.AIUsageReport]			"AI usage report retrieved successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Invalid date"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		403		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Forbidden"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/admin/ai/usage [get]
+func (h *AIUsageHandler) GetUsageReport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	now := time.Now()
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	start, ok := checkCalendarDate(c, "start", startOfMonth, false)
+	if !ok {
+		return
+	}
+	end, ok := checkCalendarDate(c, "end", now, true)
+	if !ok {
+		return
+	}
+	if end.Before(start) {
+		responses.RespondBadRequest(c, nil, "End date must be after start date")
+		return
+	}
+
+	report, err := h.service.GetUsageReport(ctx, start, end)
+	if err != nil {
+		handleServiceError(c, err, "Getting AI usage report", "", "Failed to get AI usage report")
+		return
+	}
+
+	responses.RespondOK(c, report, "AI usage report retrieved successfully")
+}
+
+// GetBudgets godoc
+//
+//	@Summary		Get the AI token budgets
+//	@Description	Returns every token budget, the household budget has userId 0
+//	@Tags			admin, ai
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	responses.APIResponse[[]models.AIBudget]			"AI budgets retrieved successfully"
+//	@Failure		401	{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		403	{object}	responses.ErrorResponse[responses.ErrorDetails]		"Forbidden"
+//	@Failure		500	{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/admin/ai/budgets [get]
+func (h *AIUsageHandler) GetBudgets(c *gin.Context) {
+	budgets, err := h.service.GetBudgets(c.Request.Context())
+	if err != nil {
+		handleServiceError(c, err, "Getting AI budgets", "", "Failed to get AI budgets")
+		return
+	}
+
+	responses.RespondOK(c, budgets, "AI budgets retrieved successfully")
+}
+
+// SetGlobalBudget godoc
+//
+//	@Summary		Set the household AI token budget
+//	@Description	Limits the tokens all users together may spend per day and month, 0 is unlimited
+//	@Tags			admin, ai
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		requests.AIBudgetRequest							true	"Token limits"
+//	@Success		200		{object}	responses.APIResponse[models.AIBudget]				"AI budget saved successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Invalid request"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		403		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Forbidden"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/admin/ai/budgets/global [put]
+func (h *AIUsageHandler) SetGlobalBudget(c *gin.Context) {
+	h.setBudget(c, 0)
+}
+
+// SetUserBudget godoc
+//
+//	@Summary		Set a user's AI token budget
+//	@Description	Limits the tokens a user may spend per day and month, 0 is unlimited
+//	@Tags			admin, ai
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			userID	path		int													true	"User ID"
+//	@Param			request	body		requests.AIBudgetRequest							true	"Token limits"
+//	@Success		200		{object}	responses.APIResponse[models.AIBudget]				"AI budget saved successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Invalid request"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		403		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Forbidden"
+//	@Failure		404		{object}	responses.ErrorResponse[responses.ErrorDetails]		"User not found"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/admin/ai/budgets/user/{userID} [put]
+func (h *AIUsageHandler) SetUserBudget(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userID"), 10, 64)
+	if err != nil || userID == 0 {
+		responses.RespondBadRequest(c, err, "Invalid user ID")
+		return
+	}
+	h.setBudget(c, userID)
+}
+
+// setBudget saves the budget in the request body for the user, or for the household when userID is 0
+func (h *AIUsageHandler) setBudget(c *gin.Context, userID uint64) {
+	var req requests.AIBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.RespondValidationError(c, err)
+		return
+	}
+
+	budget, err := h.service.SetBudget(c.Request.Context(), userID, req.DailyTokens, req.MonthlyTokens)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			responses.RespondNotFound(c, err, "User not found")
+		case errors.Is(err, services.ErrInvalidAIBudget):
+			responses.RespondBadRequest(c, err, "Token limits cannot be negative")
+		default:
+			handleServiceError(c, err, "Saving AI budget", "", "Failed to save AI budget")
+		}
+		return
+	}
+
+	responses.RespondOK(c, budget, "AI budget saved successfully")
+}
@@ -1,29 +1,251 @@
 package handlers
 
 import (
+	"errors"
+	"net/http"
+	"strings"
+	mediatypes "suasor/clients/media/types"
+	"suasor/services"
+	"suasor/types/models"
+	"suasor/types/responses"
+	"suasor/utils/logger"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
+// defaultCalendarDays is how far ahead the calendar looks when no end date is given
+const defaultCalendarDays = 30
+
 // CalendarHandler handles all calendar operations
 type CalendarHandler struct {
-	// Probably going to need to create a calendar service that handles most of the logic.
+	service     services.CalendarService
+	feedService services.CalendarFeedService
 }
 
 // NewCalendarHandler creates a new calendar handler
-func NewCalendarHandler() *CalendarHandler {
-	return &CalendarHandler{}
+func NewCalendarHandler(service services.CalendarService, feedService services.CalendarFeedService) *CalendarHandler {
+	return &CalendarHandler{
+		service:     service,
+		feedService: feedService,
+	}
 }
 
-// We should be able to get all calendar items for a user or all users.
-// We should be able to pull the calendar items from our configured calendar sources.
-// Specifically we have the calendar info in our automation integrations.
-// We also should be able to pull up items that are upcoming from the tmdb metadata service integration.
-// Maybe look into calendar items that are based on items we recommened or think the user would be interesting that are coming up. Use the recommendations to look for items that are coming out and map those to the calendar.
-
-// GetCalendarItems handles retrieving calendar items
+// GetCalendarItems godoc
+//
+//	@Summary		Get the user's release calendar
+//	@Description	Merges the calendars of the user's Radarr, Sonarr and Lidarr clients, upcoming and recent releases from their metadata client,
+//	@Description	and release dates of items they have been recommended or put on their watchlist. Entries are deduplicated by external ID.
+//	@Tags			calendar
+//	@Produce		json
+//	@Param			start	query		string												false	"Start date (YYYY-MM-DD or RFC3339), defaults to today"
+//	@Param			end		query		string												false	"End date (YYYY-MM-DD or RFC3339), defaults to 30 days after start"
+//	@Param			types	query		string												false	"Comma separated media types (movie, series, episode, album)"
+//	@Param			sources	query		string												false	"Comma separated sources (automation, tmdb, recommendation, watchlist)"
+//	@Success		200		{object}	responses.APIResponse[[]models.CalendarEntry]		"Calendar retrieved successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Invalid date range"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/calendar [get]
 func (h *CalendarHandler) GetCalendarItems(c *gin.Context) {
-	// We should be able to get calendar items by a user ID and a start and end date.
-	// The calendar items should be returned in a list of calendar items.
+	ctx := c.Request.Context()
+
+	userID, ok := checkUserAccess(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	start, ok := checkCalendarDate(c, "start", today, false)
+	if !ok {
+		return
+	}
+	end, ok := checkCalendarDate(c, "end", start.AddDate(0, 0, defaultCalendarDays), true)
+	if !ok {
+		return
+	}
+
+	filter, ok := checkCalendarFilter(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.service.GetCalendar(ctx, userID, start, end, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCalendarRange):
+			responses.RespondBadRequest(c, err, "End must be after start and at most a year later")
+		default:
+			handleServiceError(c, err, "Retrieving calendar", "", "Error retrieving calendar")
+		}
+		return
+	}
+
+	responses.RespondListOK(c, entries, len(entries), "Calendar retrieved successfully")
+}
+
+// GetFeed godoc
+//
+//	@Summary		Get the user's calendar as an ICS feed
+//	@Description	Read-only RFC 5545 feed for subscribing from Google Calendar, Apple Calendar and the like.
+//	@Description	The token in the URL replaces authentication, get it from /calendar/feed. The feed covers the past 30 and next 180 days.
+//	@Tags			calendar
+//	@Produce		text/calendar
+//	@Param			token	path		string												true	"Feed token followed by .ics"
+//	@Param			types	query		string												false	"Comma separated media types (movie, series, episode, album)"
+//	@Param			sources	query		string												false	"Comma separated sources (automation, tmdb, recommendation, watchlist)"
+//	@Success		200		{string}	string												"ICS feed"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Invalid filter"
+//	@Failure		404		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Feed not found"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/calendar/{token}.ics [get]
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.LoggerFromContext(ctx)
+
+	token, ok := strings.CutSuffix(c.Param("token"), ".ics")
+	if !ok {
+		responses.RespondNotFound(c, services.ErrCalendarFeedNotFound, "Calendar feed not found")
+		return
+	}
+
+	filter, ok := checkCalendarFilter(c)
+	if !ok {
+		return
+	}
+
+	feed, err := h.feedService.GetFeed(ctx, token, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCalendarFeedNotFound):
+			responses.RespondNotFound(c, err, "Calendar feed not found")
+		default:
+			log.Error().Err(err).Msg("Failed to render calendar feed")
+			responses.RespondInternalError(c, err, "Failed to render calendar feed")
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="suasor.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
+
+// GetFeedURL godoc
+//
+//	@Summary		Get the user's ICS feed URL
+//	@Description	Returns the secret URL to subscribe to from a calendar app, creating it the first time
+//	@Tags			calendar
+//	@Produce		json
+//	@Success		200	{object}	responses.APIResponse[string]					"Feed URL retrieved"
+//	@Failure		401	{object}	responses.ErrorResponse[responses.ErrorDetails]	"Unauthorized"
+//	@Failure		500	{object}	responses.ErrorResponse[responses.ErrorDetails]	"Internal server error"
+//	@Router			/calendar/feed [get]
+func (h *CalendarHandler) GetFeedURL(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID, ok := checkUserAccess(c)
+	if !ok {
+		return
+	}
+
+	url, err := h.feedService.GetFeedURL(ctx, userID)
+	if err != nil {
+		handleServiceError(c, err, "Getting calendar feed URL", "", "Error getting calendar feed URL")
+		return
+	}
+
+	responses.RespondOK(c, url, "Feed URL retrieved")
+}
+
+// RotateFeedToken godoc
+//
+//	@Summary		Rotate the user's ICS feed URL
+//	@Description	Issues a new feed token. Calendar apps subscribed to the old URL stop receiving updates.
+//	@Tags			calendar
+//	@Produce		json
+//	@Success		200	{object}	responses.APIResponse[string]					"Feed URL rotated"
+//	@Failure		401	{object}	responses.ErrorResponse[responses.ErrorDetails]	"Unauthorized"
+//	@Failure		500	{object}	responses.ErrorResponse[responses.ErrorDetails]	"Internal server error"
+//	@Router			/calendar/feed/rotate [post]
+func (h *CalendarHandler) RotateFeedToken(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID, ok := checkUserAccess(c)
+	if !ok {
+		return
+	}
+
+	url, err := h.feedService.RotateFeedToken(ctx, userID)
+	if err != nil {
+		handleServiceError(c, err, "Rotating calendar feed token", "", "Error rotating calendar feed URL")
+		return
+	}
+
+	responses.RespondOK(c, url, "Feed URL rotated")
+}
+
+// checkCalendarFilter parses the comma separated types and sources query parameters
+func checkCalendarFilter(c *gin.Context) (*models.CalendarFilter, bool) {
+	filter := &models.CalendarFilter{}
+
+	for _, value := range splitQueryList(c.Query("types")) {
+		mediaType := mediatypes.MediaType(value)
+		switch mediaType {
+		case mediatypes.MediaTypeMovie, mediatypes.MediaTypeSeries, mediatypes.MediaTypeEpisode, mediatypes.MediaTypeAlbum:
+			filter.MediaTypes = append(filter.MediaTypes, mediaType)
+		default:
+			responses.RespondBadRequest(c, nil, "Invalid media type: "+value)
+			return nil, false
+		}
+	}
+
+	for _, value := range splitQueryList(c.Query("sources")) {
+		source, ok := models.ParseCalendarSource(value)
+		if !ok {
+			responses.RespondBadRequest(c, nil, "Invalid calendar source: "+value)
+			return nil, false
+		}
+		filter.Sources = append(filter.Sources, source)
+	}
+
+	return filter, true
+}
+
+// splitQueryList splits a comma separated query value, dropping blanks
+func splitQueryList(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(strings.ToLower(part)); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// checkCalendarDate parses a date query parameter, either a plain date or RFC3339.
+// A plain end date covers the whole day.
+func checkCalendarDate(c *gin.Context, paramName string, defaultValue time.Time, endOfDay bool) (time.Time, bool) {
+	log := logger.LoggerFromContext(c.Request.Context())
+
+	value := c.Query(paramName)
+	if value == "" {
+		return defaultValue, true
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if endOfDay {
+			date = date.Add(24*time.Hour - time.Nanosecond)
+		}
+		return date, true
+	}
 
-	// We will need to create a calendar service that handles most of the logic.
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Warn().Err(err).Str(paramName, value).Msg("Invalid calendar date")
+		responses.RespondBadRequest(c, err, "Invalid "+paramName+" date, expected YYYY-MM-DD or RFC3339")
+		return time.Time{}, false
+	}
+	return date, true
 }
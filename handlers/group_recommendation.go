@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	mediatypes "suasor/clients/media/types"
+	"suasor/services"
+	"suasor/types/models"
+	"suasor/types/requests"
+	"suasor/types/responses"
+)
+
+// GroupRecommendationHandler handles API requests for recommendations for groups of users who watch together
+type GroupRecommendationHandler struct {
+	groupRecommendationService services.GroupRecommendationService
+}
+
+// NewGroupRecommendationHandler creates a new handler for group recommendations
+func NewGroupRecommendationHandler(groupRecommendationService services.GroupRecommendationService) *GroupRecommendationHandler {
+	return &GroupRecommendationHandler{
+		groupRecommendationService: groupRecommendationService,
+	}
+}
+
+// GetGroupRecommendations godoc
+//
+//	@Summary		Get recommendations for a group
+//	@Description	Ranks library movies or series for a group to watch together, such as a household's movie night.
+//	@Description	The group is a saved group or the user and the given users. Items must be allowed by every member's content rating and excluded genres,
+//	@Description	and nothing any member has finished is recommended. Each pick has the fit of every member.
+//	@Tags			recommendations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		requests.GroupRecommendationsRequest							true	"User IDs or group ID, media type, strategy (least_misery, average) and limit"
+//	@Success		200		{object}	responses.APIResponse[recommendation.GroupRecommendations]	"Group recommendations retrieved successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]				"Invalid request parameters"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]				"Unauthorized"
+//	@Failure		403		{object}	responses.ErrorResponse[responses.ErrorDetails]				"Not a member of the group"
+//	@Failure		404		{object}	responses.ErrorResponse[responses.ErrorDetails]				"Group or member not found"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]				"Server error"
+//	@Router			/recommendations/group [post]
+func (h *GroupRecommendationHandler) GetGroupRecommendations(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		responses.RespondUnauthorized(c, nil, "Authentication required")
+		return
+	}
+
+	// Parse request body
+	var req requests.GroupRecommendationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.RespondValidationError(c, err)
+		return
+	}
+	if req.GroupID == 0 && len(req.UserIDs) == 0 {
+		responses.RespondBadRequest(c, nil, "User IDs or a group ID is required")
+		return
+	}
+
+	mediaType := mediatypes.MediaTypeMovie
+	if req.MediaType != "" {
+		mediaType = mediatypes.MediaType(req.MediaType)
+	}
+	strategy := models.GroupStrategy(req.Strategy)
+
+	ctx := c.Request.Context()
+	var err error
+	var result any
+	if req.GroupID > 0 {
+		result, err = h.groupRecommendationService.RecommendForGroup(ctx, userID.(uint64), req.GroupID, mediaType, strategy, req.Limit)
+	} else {
+		result, err = h.groupRecommendationService.RecommendForMembers(ctx, userID.(uint64), req.UserIDs, mediaType, strategy, req.Limit)
+	}
+	if err != nil {
+		respondGroupError(c, err, "Failed to get group recommendations")
+		return
+	}
+
+	responses.RespondOK(c, result, "Group recommendations retrieved successfully")
+}
+
+// GetGroups godoc
+//
+//	@Summary		Get household groups
+//	@Description	Returns the saved groups the user owns or is a member of
+//	@Tags			recommendations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	responses.APIResponse[[]models.HouseholdGroup]	"Groups retrieved successfully"
+//	@Failure		401	{object}	responses.ErrorResponse[responses.ErrorDetails]	"Unauthorized"
+//	@Failure		500	{object}	responses.ErrorResponse[responses.ErrorDetails]	"Server error"
+//	@Router			/recommendations/groups [get]
+func (h *GroupRecommendationHandler) GetGroups(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		responses.RespondUnauthorized(c, nil, "Authentication required")
+		return
+	}
+
+	groups, err := h.groupRecommendationService.GetGroups(c.Request.Context(), userID.(uint64))
+	if err != nil {
+		responses.RespondInternalError(c, err, "Failed to retrieve groups")
+		return
+	}
+
+	responses.RespondOK(c, groups, "Groups retrieved successfully")
+}
+
+// CreateGroup godoc
+//
+//	@Summary		Create a household group
+//	@Description	Saves a group of users who watch together, the user owns it and is always a member
+//	@Tags			recommendations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		requests.CreateHouseholdGroupRequest			true	"Name, member IDs and default strategy (least_misery, average)"
+//	@Success		201		{object}	responses.APIResponse[models.HouseholdGroup]	"Group created successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Invalid request parameters"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Unauthorized"
+//	@Failure		404		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Member not found"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Server error"
+//	@Router			/recommendations/groups [post]
+func (h *GroupRecommendationHandler) CreateGroup(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		responses.RespondUnauthorized(c, nil, "Authentication required")
+		return
+	}
+
+	// Parse request body
+	var req requests.CreateHouseholdGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.RespondValidationError(c, err)
+		return
+	}
+
+	group, err := h.groupRecommendationService.CreateGroup(
+		c.Request.Context(),
+		userID.(uint64),
+		req.Name,
+		req.MemberIDs,
+		models.GroupStrategy(req.Strategy),
+	)
+	if err != nil {
+		respondGroupError(c, err, "Failed to create group")
+		return
+	}
+
+	responses.RespondCreated(c, group, "Group created successfully")
+}
+
+// DeleteGroup godoc
+//
+//	@Summary		Delete a household group
+//	@Description	Deletes a saved group, only its owner can
+//	@Tags			recommendations
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			groupID	path		int												true	"Group ID"
+//	@Success		200		{object}	responses.APIResponse[any]						"Group deleted successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Invalid group ID"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Unauthorized"
+//	@Failure		403		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Not the owner of the group"
+//	@Failure		404		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Group not found"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Server error"
+//	@Router			/recommendations/groups/{groupID} [delete]
+func (h *GroupRecommendationHandler) DeleteGroup(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		responses.RespondUnauthorized(c, nil, "Authentication required")
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("groupID"), 10, 64)
+	if err != nil {
+		responses.RespondValidationError(c, err)
+		return
+	}
+
+	if err := h.groupRecommendationService.DeleteGroup(c.Request.Context(), userID.(uint64), groupID); err != nil {
+		respondGroupError(c, err, "Failed to delete group")
+		return
+	}
+
+	responses.RespondOK(c, http.StatusOK, "Group deleted successfully")
+}
+
+// respondGroupError maps the group recommendation service errors to responses
+func respondGroupError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, services.ErrGroupNotFound):
+		responses.RespondNotFound(c, err, "Group not found")
+	case errors.Is(err, services.ErrGroupMemberNotFound):
+		responses.RespondNotFound(c, err, "Group member not found")
+	case errors.Is(err, services.ErrGroupAccessDenied):
+		responses.RespondForbidden(c, err, "You do not have access to this group")
+	case errors.Is(err, services.ErrInvalidGroupStrategy):
+		responses.RespondBadRequest(c, err, "Invalid group strategy")
+	default:
+		responses.RespondInternalError(c, err, msg)
+	}
+}
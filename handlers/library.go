@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"suasor/services"
+	"suasor/types/responses"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LibraryHandler handles media client library operations
+type LibraryHandler struct {
+	service services.LibraryService
+}
+
+// NewLibraryHandler creates a new library handler
+func NewLibraryHandler(service services.LibraryService) *LibraryHandler {
+	return &LibraryHandler{service: service}
+}
+
+// GetLibraries godoc
+//
+//	@Summary		Get the libraries on a media client
+//	@Description	Lists the libraries, sections or music folders on a client. Their IDs can be set as libraryIDs in a media sync job's filters to limit what the job syncs.
+//	@Tags			libraries
+//	@Produce		json
+//	@Param			clientID	path		int												true	"Client ID"
+//	@Success		200			{object}	responses.APIResponse[[]models.MediaLibrary]	"Libraries retrieved successfully"
+//	@Failure		400			{object}	responses.ErrorResponse[responses.ErrorDetails]	"Client does not support libraries"
+//	@Failure		401			{object}	responses.ErrorResponse[responses.ErrorDetails]	"Unauthorized"
+//	@Failure		404			{object}	responses.ErrorResponse[responses.ErrorDetails]	"Client not found"
+//	@Failure		500			{object}	responses.ErrorResponse[responses.ErrorDetails]	"Internal server error"
+//	@Router			/libraries/{clientID} [get]
+func (h *LibraryHandler) GetLibraries(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID, ok := checkUserAccess(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := checkClientID(c)
+	if !ok {
+		return
+	}
+
+	libraries, err := h.service.GetLibraries(ctx, userID, clientID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrLibraryClientNotFound):
+			responses.RespondNotFound(c, err, "Client not found")
+		case errors.Is(err, services.ErrLibrariesNotSupported):
+			responses.RespondBadRequest(c, err, "Client does not support libraries")
+		default:
+			handleServiceError(c, err, "Retrieving libraries", "", "Error retrieving libraries")
+		}
+		return
+	}
+
+	responses.RespondListOK(c, libraries, len(libraries), "Libraries retrieved successfully")
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"errors"
+	"suasor/services"
+	"suasor/types/requests"
+	"suasor/types/responses"
+	"suasor/utils/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromptTemplateHandler lets admins edit the prompt templates and users write their own chat personality
+type PromptTemplateHandler struct {
+	service services.PromptTemplateService
+}
+
+// NewPromptTemplateHandler creates a new prompt template handler
+func NewPromptTemplateHandler(service services.PromptTemplateService) *PromptTemplateHandler {
+	return &PromptTemplateHandler{service: service}
+}
+
+// GetTemplates godoc
+//
+//	@Summary		Get the prompt templates
+//	@Description	Returns every prompt template with its typed variables, the active body and the built-in default.
+//	@Description	An active version of 0 means the built-in default is in use.
+//	@Tags			admin, ai
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	responses.APIResponse[[]models.PromptTemplateInfo]	"Prompt templates retrieved successfully"
+//	@Failure		401	{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		403	{object}	responses.ErrorResponse[responses.ErrorDetails]		"Forbidden"
+//	@Failure		500	{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/admin/ai/prompts [get]
+func (h *PromptTemplateHandler) GetTemplates(c *gin.Context) {
+	templates, err := h.service.GetTemplates(c.Request.Context())
+	if err != nil {
+		handleServiceError(c, err, "Getting prompt templates", "", "Failed to get prompt templates")
+		return
+	}
+
+	responses.RespondOK(c, templates, "Prompt templates retrieved successfully")
+}
+
+// GetVersions godoc
+//
+//	@Summary		Get the versions of a prompt template
+//	@Description	Returns every saved version of a prompt template, the newest first
+//	@Tags			admin, ai
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string												true	"Template name, e.g. recommendation.movie"
+//	@Success		200		{object}	responses.APIResponse[[]models.PromptTemplate]		"Prompt template versions retrieved successfully"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		403		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Forbidden"
+//	@Failure		404		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Template not found"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/admin/ai/prompts/{name}/versions [get]
+func (h *PromptTemplateHandler) GetVersions(c *gin.Context) {
+	versions, err := h.service.GetVersions(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		h.respondError(c, err, "Getting prompt template versions", "Failed to get prompt template versions")
+		return
+	}
+
+	responses.RespondOK(c, versions, "Prompt template versions retrieved successfully")
+}
+
+// SaveTemplate godoc
+//
+//	@Summary		Save a prompt template
+//	@Description	Saves the body as a new version of the template and makes it active. The body is a Go text/template
+//	@Description	and may only use the template's variables.
+//	@Tags			admin, ai
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string												true	"Template name, e.g. recommendation.movie"
+//	@Param			request	body		requests.PromptTemplateRequest						true	"Template body"
+//	@Success		200		{object}	responses.APIResponse[models.PromptTemplate]		"Prompt template saved successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Invalid template"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		403		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Forbidden"
+//	@Failure		404		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Template not found"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/admin/ai/prompts/{name} [put]
+func (h *PromptTemplateHandler) SaveTemplate(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.LoggerFromContext(ctx)
+
+	userID, ok := checkUserAccess(c)
+	if !ok {
+		return
+	}
+
+	var req requests.PromptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.RespondValidationError(c, err)
+		return
+	}
+
+	template, err := h.service.SaveTemplate(ctx, c.Param("name"), req.Body, userID)
+	if err != nil {
+		h.respondError(c, err, "Saving prompt template", "Failed to save prompt template")
+		return
+	}
+
+	log.Info().
+		Str("template", template.Name).
+		Int("version", template.Version).
+		Uint64("userID", userID).
+		Msg("Saved prompt template")
+	responses.RespondOK(c, template, "Prompt template saved successfully")
+}
+
+// ActivateVersion godoc
+//
+//	@Summary		Activate a version of a prompt template
+//	@Description	Makes a saved version of the template active again, version 0 restores the built-in default
+//	@Tags			admin, ai
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string												true	"Template name, e.g. recommendation.movie"
+//	@Param			request	body		requests.PromptVersionRequest						true	"Version to activate"
+//	@Success		200		{object}	responses.APIResponse[any]							"Prompt template version activated successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Invalid request"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		403		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Forbidden"
+//	@Failure		404		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Template or version not found"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/admin/ai/prompts/{name}/active [put]
+func (h *PromptTemplateHandler) ActivateVersion(c *gin.Context) {
+	var req requests.PromptVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.RespondValidationError(c, err)
+		return
+	}
+
+	if err := h.service.ActivateVersion(c.Request.Context(), c.Param("name"), req.Version); err != nil {
+		h.respondError(c, err, "Activating prompt template version", "Failed to activate prompt template version")
+		return
+	}
+
+	responses.RespondOK(c, gin.H{"name": c.Param("name"), "version": req.Version}, "Prompt template version activated successfully")
+}
+
+// GetPersonalities godoc
+//
+//	@Summary		Get the AI chat personalities
+//	@Description	Returns the personalities the aiChatPersonality setting can pick, including the user's custom one
+//	@Tags			ai
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	responses.APIResponse[[]models.PromptTemplateInfo]	"Personalities retrieved successfully"
+//	@Failure		401	{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		500	{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/user/ai/personalities [get]
+func (h *PromptTemplateHandler) GetPersonalities(c *gin.Context) {
+	userID, ok := checkUserAccess(c)
+	if !ok {
+		return
+	}
+
+	personalities, err := h.service.GetPersonalities(c.Request.Context(), userID)
+	if err != nil {
+		handleServiceError(c, err, "Getting AI chat personalities", "", "Failed to get AI chat personalities")
+		return
+	}
+
+	responses.RespondOK(c, personalities, "Personalities retrieved successfully")
+}
+
+// SaveCustomPersonality godoc
+//
+//	@Summary		Save the custom AI chat personality
+//	@Description	Saves a new version of the user's own personality, used when aiChatPersonality is "custom"
+//	@Tags			ai
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		requests.PromptTemplateRequest						true	"Personality template body"
+//	@Success		200		{object}	responses.APIResponse[models.PromptTemplate]		"Personality saved successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Invalid template"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Router			/user/ai/personalities/custom [put]
+func (h *PromptTemplateHandler) SaveCustomPersonality(c *gin.Context) {
+	userID, ok := checkUserAccess(c)
+	if !ok {
+		return
+	}
+
+	var req requests.PromptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.RespondValidationError(c, err)
+		return
+	}
+
+	template, err := h.service.SaveCustomPersonality(c.Request.Context(), userID, req.Body)
+	if err != nil {
+		h.respondError(c, err, "Saving custom AI chat personality", "Failed to save personality")
+		return
+	}
+
+	responses.RespondOK(c, template, "Personality saved successfully")
+}
+
+// respondError maps the prompt template service errors to responses
+func (h *PromptTemplateHandler) respondError(c *gin.Context, err error, logMsg string, msg string) {
+	switch {
+	case errors.Is(err, services.ErrPromptTemplateNotFound):
+		responses.RespondNotFound(c, err, "Prompt template not found")
+	case errors.Is(err, services.ErrPromptVersionNotFound):
+		responses.RespondNotFound(c, err, "Prompt template version not found")
+	case errors.Is(err, services.ErrInvalidPromptTemplate):
+		responses.RespondBadRequest(c, err, err.Error())
+	default:
+		handleServiceError(c, err, logMsg, "", msg)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"suasor/services"
+	"suasor/types/models"
 	"suasor/types/requests"
 	"suasor/types/responses"
 )
@@ -424,3 +425,108 @@ func (h *RecommendationHandler) RateRecommendation(c *gin.Context) {
 
 	responses.RespondOK(c, http.StatusOK, "Recommendation rated successfully")
 }
+
+// DismissRecommendation godoc
+//
+//	@Summary		Dismiss a recommendation
+//	@Description	Dismisses a recommendation with a reason. The title is not recommended again and the reason is used by the recommenders.
+//	@Tags			recommendations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		requests.DismissRecommendationRequest			true	"Recommendation ID and reason (seen_it, not_interested, dislike_genre)"
+//	@Success		200		{object}	responses.APIResponse[any]						"Recommendation dismissed successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Invalid request parameters"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Unauthorized"
+//	@Failure		404		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Recommendation not found"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Server error"
+//	@Router			/recommendations/dismiss [post]
+func (h *RecommendationHandler) DismissRecommendation(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		responses.RespondUnauthorized(c, nil, "Authentication required")
+		return
+	}
+
+	// Parse request body
+	var req requests.DismissRecommendationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.RespondValidationError(c, err)
+		return
+	}
+
+	// Dismiss recommendation
+	err := h.recommendationService.DismissRecommendation(
+		c.Request.Context(),
+		req.RecommendationID,
+		userID.(uint64),
+		models.DismissReason(req.Reason),
+	)
+	if err != nil {
+		if err.Error() == "record not found" {
+			responses.RespondNotFound(c, nil, "Recommendation not found")
+			return
+		}
+		if err.Error() == "recommendation does not belong to the user" {
+			responses.RespondNotFound(c, nil, "Recommendation not found")
+			return
+		}
+		responses.RespondInternalError(c, err, "Failed to dismiss recommendation")
+		return
+	}
+
+	responses.RespondOK(c, http.StatusOK, "Recommendation dismissed successfully")
+}
+
+// RecordRecommendationPlay godoc
+//
+//	@Summary		Record a recommendation play
+//	@Description	Records that the user clicked through from a recommendation to play it
+//	@Tags			recommendations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		requests.RecordRecommendationPlayRequest		true	"Recommendation ID that was played"
+//	@Success		200		{object}	responses.APIResponse[any]						"Recommendation play recorded successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Invalid request parameters"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Unauthorized"
+//	@Failure		404		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Recommendation not found"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]	"Server error"
+//	@Router			/recommendations/play [post]
+func (h *RecommendationHandler) RecordRecommendationPlay(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		responses.RespondUnauthorized(c, nil, "Authentication required")
+		return
+	}
+
+	// Parse request body
+	var req requests.RecordRecommendationPlayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.RespondValidationError(c, err)
+		return
+	}
+
+	// Record play
+	err := h.recommendationService.RecordPlay(
+		c.Request.Context(),
+		req.RecommendationID,
+		userID.(uint64),
+	)
+	if err != nil {
+		if err.Error() == "record not found" {
+			responses.RespondNotFound(c, nil, "Recommendation not found")
+			return
+		}
+		if err.Error() == "recommendation does not belong to the user" {
+			responses.RespondNotFound(c, nil, "Recommendation not found")
+			return
+		}
+		responses.RespondInternalError(c, err, "Failed to record recommendation play")
+		return
+	}
+
+	responses.RespondOK(c, http.StatusOK, "Recommendation play recorded successfully")
+}
@@ -2,13 +2,17 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
+	"strings"
 	"suasor/clients/media/types"
 	"suasor/services"
 	"suasor/types/responses"
 	"suasor/utils"
 	"suasor/utils/logger"
+	"time"
 )
 
 // SearchHandler handles all search operations
@@ -24,17 +28,30 @@ func NewSearchHandler(service services.SearchService) *SearchHandler {
 // Search godoc
 //
 //	@Summary		Search for content across all sources
-//	@Description	Searches for content in the database, media clients, and metadata sources
+//	@Description	Searches for content in the database, media clients, and metadata sources.
+//	@Description	The natural mode parses a query like "unwatched 80s horror under 100 minutes on Plex" into filters with an AI client
+//	@Description	and returns them with the results, it searches the query as plain text when no AI client is configured.
+//	@Description	The filter parameters apply to the local library, they let the parsed filters be edited.
 //	@Tags			search
 //	@Accept			json
 //	@Produce		json
-//	@Param			query		query		string	true	"Search query"
-//	@Param			mediaType	query		string	false	"Limit search to specific media type (movie, series, music, person)"
-//	@Param			limit		query		int		false	"Maximum number of results"	default(20)
-//	@Param			offset		query		int		false	"Offset for pagination"		default(0)
-//	@Success		200			{object}	responses.SearchResponse
-//	@Failure		400			{object}	responses.ErrorResponse[any]
-//	@Failure		500			{object}	responses.ErrorResponse[any]
+//	@Param			query			query		string	true	"Search query"
+//	@Param			mode			query		string	false	"Search mode (title, semantic, natural)"	default(title)
+//	@Param			mediaType		query		string	false	"Limit search to specific media type (movie, series, music, person)"
+//	@Param			limit			query		int		false	"Maximum number of results"	default(20)
+//	@Param			offset			query		int		false	"Offset for pagination"		default(0)
+//	@Param			semantic		query		bool	false	"Same as mode=semantic"	default(false)
+//	@Param			genres			query		string	false	"Comma separated genres, any of them matches"
+//	@Param			releasedAfter	query		int		false	"First release year"
+//	@Param			releasedBefore	query		int		false	"Last release year"
+//	@Param			watched			query		bool	false	"Only watched (true) or unwatched (false) items"
+//	@Param			clientId		query		int		false	"Only items synced from this media client"
+//	@Param			minDuration		query		int		false	"Minimum runtime in minutes"
+//	@Param			maxDuration		query		int		false	"Maximum runtime in minutes"
+//	@Success		200				{object}	responses.SearchResponse
+//	@Failure		400				{object}	responses.ErrorResponse[any]
+//	@Failure		500				{object}	responses.ErrorResponse[any]
+//	@Failure		503				{object}	responses.ErrorResponse[any]
 //	@Router			/search [get]
 func (h *SearchHandler) Search(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -57,6 +74,17 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	limit := utils.GetLimit(c, 20, 100, true)
 	offset := utils.GetOffset(c, 0)
 	mediaType := c.Query("mediaType")
+	mode := c.DefaultQuery("mode", "title")
+	if value := c.Query("semantic"); value != "" {
+		semantic, err := strconv.ParseBool(value)
+		if err != nil {
+			responses.RespondBadRequest(c, err, "Invalid semantic value")
+			return
+		}
+		if semantic {
+			mode = "semantic"
+		}
+	}
 
 	// Create query options
 	options := types.QueryOptions{
@@ -64,6 +92,40 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		Limit:     limit,
 		Offset:    offset,
 		MediaType: types.MediaType(mediaType),
+		UserID:    userID,
+	}
+	if err := parseSearchFilters(c, &options); err != nil {
+		responses.RespondBadRequest(c, err, err.Error())
+		return
+	}
+
+	switch mode {
+	case "title":
+	case "semantic":
+		// Semantic search only covers the local library, it needs an AI client with embeddings
+		results, err := h.service.SemanticSearch(ctx, userID, options)
+		if errors.Is(err, services.ErrNoEmbeddingClient) {
+			responses.RespondServiceUnavailable(c, err, "Semantic search needs an enabled OpenAI or Ollama client")
+			return
+		}
+		if err != nil {
+			handleServiceError(c, err, "Performing semantic search", "", "Error performing semantic search")
+			return
+		}
+		c.JSON(http.StatusOK, responses.ConvertToSearchResponse(results))
+		return
+	case "natural":
+		results, filters, err := h.service.NaturalSearch(ctx, userID, options)
+		if err != nil {
+			handleServiceError(c, err, "Performing natural search", "", "Error performing natural search")
+			return
+		}
+		c.JSON(http.StatusOK, responses.ConvertToNaturalSearchResponse(results, filters))
+		return
+	default:
+		err := fmt.Errorf("unknown search mode: %s", mode)
+		responses.RespondBadRequest(c, err, "Invalid search mode")
+		return
 	}
 
 	// Perform search
@@ -80,6 +142,68 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// parseSearchFilters reads the optional filter parameters of a search into the query options
+func parseSearchFilters(c *gin.Context, options *types.QueryOptions) error {
+	if value := c.Query("genres"); value != "" {
+		for _, genre := range strings.Split(value, ",") {
+			if genre = strings.TrimSpace(genre); genre != "" {
+				options.Genres = append(options.Genres, genre)
+			}
+		}
+	}
+
+	if value := c.Query("releasedAfter"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid releasedAfter year: %s", value)
+		}
+		releasedAfter := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		options.ReleasedAfter = &releasedAfter
+	}
+	if value := c.Query("releasedBefore"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid releasedBefore year: %s", value)
+		}
+		releasedBefore := time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC).Add(-time.Second)
+		options.ReleasedBefore = &releasedBefore
+	}
+
+	if value := c.Query("watched"); value != "" {
+		watched, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid watched value: %s", value)
+		}
+		options.Watched = watched
+		options.Unwatched = !watched
+	}
+
+	if value := c.Query("clientId"); value != "" {
+		clientID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid clientId: %s", value)
+		}
+		options.ClientID = clientID
+	}
+
+	if value := c.Query("minDuration"); value != "" {
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes < 0 {
+			return fmt.Errorf("invalid minDuration: %s", value)
+		}
+		options.MinimumDuration = minutes
+	}
+	if value := c.Query("maxDuration"); value != "" {
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes < 0 {
+			return fmt.Errorf("invalid maxDuration: %s", value)
+		}
+		options.MaximumDuration = minutes
+	}
+
+	return nil
+}
+
 // GetRecentSearches godoc
 //
 //	@Summary		Get recent searches for the current user
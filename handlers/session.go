@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"suasor/services"
+	"suasor/types/responses"
+	"suasor/utils/logger"
+)
+
+// SessionHandler handles now playing session operations
+type SessionHandler struct {
+	service services.SessionService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(service services.SessionService) *SessionHandler {
+	return &SessionHandler{service: service}
+}
+
+// GetSessions godoc
+//
+//	@Summary		Get active playback sessions
+//	@Description	Returns what is currently playing on all of the user's media clients, merged into one list
+//	@Tags			sessions
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	responses.APIResponse[[]models.PlaybackSession]	"Active sessions retrieved successfully"
+//	@Failure		401	{object}	responses.ErrorResponse[responses.ErrorDetails]	"Unauthorized"
+//	@Failure		500	{object}	responses.ErrorResponse[responses.ErrorDetails]	"Internal server error"
+//	@Router			/sessions [get]
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.LoggerFromContext(ctx)
+
+	userID, ok := checkUserAccess(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.service.GetActiveSessions(ctx, userID)
+	if err != nil {
+		handleServiceError(c, err, "Retrieving active sessions", "", "Error retrieving active sessions")
+		return
+	}
+
+	log.Debug().
+		Uint64("userID", userID).
+		Int("sessionCount", len(sessions)).
+		Msg("Active sessions retrieved successfully")
+
+	responses.RespondListOK(c, sessions, len(sessions), "Active sessions retrieved successfully")
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"suasor/services"
+	"suasor/types/responses"
+	"suasor/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SimilarItemsHandler finds library items similar to an item by the meaning of their text
+type SimilarItemsHandler struct {
+	service services.EmbeddingService
+}
+
+// NewSimilarItemsHandler creates a new similar items handler
+func NewSimilarItemsHandler(service services.EmbeddingService) *SimilarItemsHandler {
+	return &SimilarItemsHandler{service: service}
+}
+
+// GetSimilarItems godoc
+//
+//	@Summary		Get items similar to a media item
+//	@Description	Returns the library items of the same type nearest to the item, compared by the embeddings of their title,
+//	@Description	description, genres and credits. Items that were not embedded yet are embedded on demand.
+//	@Tags			media
+//	@Produce		json
+//	@Param			id		path		int													true	"Media item ID"
+//	@Param			limit	query		int													false	"Maximum number of results"	default(20)
+//	@Success		200		{object}	responses.APIResponse[[]models.EmbeddingMatch]		"Similar items retrieved successfully"
+//	@Failure		400		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Invalid item ID"
+//	@Failure		401		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Unauthorized"
+//	@Failure		404		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Item not found"
+//	@Failure		500		{object}	responses.ErrorResponse[responses.ErrorDetails]		"Internal server error"
+//	@Failure		503		{object}	responses.ErrorResponse[responses.ErrorDetails]		"No AI client supports embeddings"
+//	@Router			/item/{id}/similar [get]
+func (h *SimilarItemsHandler) GetSimilarItems(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if _, ok := checkUserAccess(c); !ok {
+		return
+	}
+	itemID, err := checkItemID(c, "id")
+	if err != nil {
+		return
+	}
+	limit := utils.GetLimit(c, 20, 100, true)
+
+	matches, err := h.service.GetSimilarItems(ctx, itemID, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmbeddingItemNotFound):
+			responses.RespondNotFound(c, err, "Media item not found")
+		case errors.Is(err, services.ErrNoEmbeddingClient):
+			responses.RespondServiceUnavailable(c, err, "Similar items need an enabled OpenAI or Ollama client")
+		default:
+			handleServiceError(c, err, "Getting similar items", "", "Failed to get similar items")
+		}
+		return
+	}
+
+	responses.RespondOK(c, matches, "Similar items retrieved successfully")
+}
@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"net/http"
 
 	mediatypes "suasor/clients/media/types"
 	"suasor/services"
@@ -313,7 +312,7 @@ func (h *userMediaItemDataHandler[T]) RecordMediaPlay(c *gin.Context) {
 //	@Param			mediaType	path		string																true	"Media type like movie, series, track, etc."
 //	@Param			itemID		path		int																	true	"Media Item ID"
 //	@Param			userID		query		int																	false	"User ID (optional, uses authenticated user ID if not provided)"
-//	@Success		200			{object}	responses.APIResponse[models.UserMediaItemData[mediatypes.MediaData]]	"Favorite status updated successfully"
+//	@Success		200			{object}	responses.APIResponse[responses.ClientWriteBackResponse]				"Favorite status updated successfully, with per-client write-back results"
 //	@Failure		400			{object}	responses.ErrorResponse[responses.ErrorDetails]						"Bad request"
 //	@Failure		401			{object}	responses.ErrorResponse[responses.ErrorDetails]						"Unauthorized"
 //	@Failure		500			{object}	responses.ErrorResponse[responses.ErrorDetails]						"Internal server error"
@@ -341,7 +340,7 @@ func (h *userMediaItemDataHandler[T]) ToggleFavorite(c *gin.Context) {
 		Bool("favorite", favorite).
 		Msg("Toggling favorite status")
 
-	err = h.service.ToggleFavorite(ctx, itemID, userID, favorite)
+	results, err := h.service.ToggleFavorite(ctx, itemID, userID, favorite)
 	if err != nil {
 		log.Error().Err(err).
 			Uint64("userID", userID).
@@ -355,9 +354,10 @@ func (h *userMediaItemDataHandler[T]) ToggleFavorite(c *gin.Context) {
 		Uint64("userID", userID).
 		Uint64("itemID", itemID).
 		Bool("favorite", favorite).
+		Int("clients", len(results)).
 		Msg("Favorite status updated successfully")
 
-	responses.RespondOK(c, http.StatusOK, "Favorite status updated successfully")
+	responses.RespondOK(c, responses.NewClientWriteBackResponse(results), "Favorite status updated successfully")
 }
 
 // UpdateUserRating godoc
@@ -371,7 +371,7 @@ func (h *userMediaItemDataHandler[T]) ToggleFavorite(c *gin.Context) {
 //	@Param			mediaType	path		string																true	"Media type like movie, series, track, etc."
 //	@Param			itemID		path		int																	true	"Media Item ID"
 //	@Param			userID		query		int																	false	"User ID (optional, uses authenticated user ID if not provided)"
-//	@Success		200			{object}	responses.APIResponse[models.UserMediaItemData[mediatypes.MediaData]]	"Rating updated successfully"
+//	@Success		200			{object}	responses.APIResponse[responses.ClientWriteBackResponse]				"Rating updated successfully, with per-client write-back results"
 //	@Failure		400			{object}	responses.ErrorResponse[responses.ErrorDetails]						"Bad request"
 //	@Failure		401			{object}	responses.ErrorResponse[responses.ErrorDetails]						"Unauthorized"
 //	@Failure		500			{object}	responses.ErrorResponse[responses.ErrorDetails]						"Internal server error"
@@ -413,7 +413,7 @@ func (h *userMediaItemDataHandler[T]) UpdateUserRating(c *gin.Context) {
 		Float64("rating", rating).
 		Msg("Updating user rating")
 
-	err = h.service.UpdateRating(ctx, itemID, userID, float32(rating))
+	results, err := h.service.UpdateRating(ctx, itemID, userID, float32(rating))
 	if err != nil {
 		log.Error().Err(err).
 			Uint64("userID", userID).
@@ -427,9 +427,10 @@ func (h *userMediaItemDataHandler[T]) UpdateUserRating(c *gin.Context) {
 		Uint64("userID", userID).
 		Uint64("itemID", itemID).
 		Float64("rating", rating).
+		Int("clients", len(results)).
 		Msg("Rating updated successfully")
 
-	responses.RespondOK(c, http.StatusOK, "Rating updated successfully")
+	responses.RespondOK(c, responses.NewClientWriteBackResponse(results), "Rating updated successfully")
 }
 
 // GetFavorites godoc
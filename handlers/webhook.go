@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"suasor/services"
+	"suasor/types/responses"
+	"suasor/utils/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBodySize caps webhook bodies, Plex attaches a thumbnail to some events
+const maxWebhookBodySize = 16 << 20
+
+// WebhookHandler handles webhooks pushed by media servers
+type WebhookHandler struct {
+	service services.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(service services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// ReceiveWebhook godoc
+//
+//	@Summary		Receive a media server webhook
+//	@Description	Accepts Plex, Jellyfin and Emby webhooks and applies them to the client owner's play history, ratings and library.
+//	@Description	Events for media server users other than the one the client is configured with are ignored.
+//	@Description	Requests must carry the client's webhook token as the token query parameter, or an X-Webhook-Signature header with the hex HMAC-SHA256 of the body keyed with that token.
+//	@Tags			webhooks
+//	@Accept			json,mpfd
+//	@Produce		json
+//	@Param			clientID				path		int												true	"Client ID"
+//	@Param			token					query		string											false	"Webhook token"
+//	@Param			X-Webhook-Signature		header		string											false	"Hex HMAC-SHA256 of the body"
+//	@Success		200						{object}	responses.APIResponse[models.WebhookEvent]		"Webhook processed"
+//	@Failure		400						{object}	responses.ErrorResponse[responses.ErrorDetails]	"Invalid payload"
+//	@Failure		401						{object}	responses.ErrorResponse[responses.ErrorDetails]	"Invalid signature"
+//	@Failure		404						{object}	responses.ErrorResponse[responses.ErrorDetails]	"Client not found"
+//	@Failure		500						{object}	responses.ErrorResponse[responses.ErrorDetails]	"Internal server error"
+//	@Router			/webhooks/{clientID} [post]
+func (h *WebhookHandler) ReceiveWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := logger.LoggerFromContext(ctx)
+
+	clientID, ok := checkClientID(c)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		responses.RespondBadRequest(c, err, "Failed to read webhook body")
+		return
+	}
+
+	verified, err := h.service.VerifyWebhook(ctx, clientID, c.Query("token"), c.GetHeader("X-Webhook-Signature"), body)
+	if err != nil {
+		log.Error().Err(err).Uint64("clientID", clientID).Msg("Failed to verify webhook")
+		responses.RespondInternalError(c, err, "Failed to verify webhook")
+		return
+	}
+	if !verified {
+		log.Warn().
+			Uint64("clientID", clientID).
+			Str("remoteAddr", c.ClientIP()).
+			Msg("Rejected webhook with invalid signature")
+		responses.RespondUnauthorized(c, services.ErrInvalidWebhookSignature, "Invalid webhook signature")
+		return
+	}
+
+	event, err := h.service.HandleWebhook(ctx, clientID, c.ContentType(), body)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWebhookClientNotFound):
+			responses.RespondNotFound(c, err, "Client not found")
+		case errors.Is(err, services.ErrUnsupportedWebhookClient):
+			responses.RespondBadRequest(c, err, "Client type does not support webhooks")
+		case event == nil:
+			responses.RespondBadRequest(c, err, "Invalid webhook payload")
+		default:
+			log.Error().Err(err).
+				Uint64("clientID", clientID).
+				Str("event", string(event.Event)).
+				Msg("Failed to apply webhook")
+			responses.RespondInternalError(c, err, "Failed to apply webhook")
+		}
+		return
+	}
+
+	responses.RespondOK(c, *event, "Webhook processed")
+}
+
+// GetWebhookURL godoc
+//
+//	@Summary		Get the webhook URL for a client
+//	@Description	Returns the signed URL to configure as a webhook on the media server, creating it the first time
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			clientID	path		int												true	"Client ID"
+//	@Success		200			{object}	responses.APIResponse[string]					"Webhook URL retrieved"
+//	@Failure		400			{object}	responses.ErrorResponse[responses.ErrorDetails]	"Invalid client ID"
+//	@Failure		401			{object}	responses.ErrorResponse[responses.ErrorDetails]	"Unauthorized"
+//	@Failure		404			{object}	responses.ErrorResponse[responses.ErrorDetails]	"Client not found"
+//	@Router			/webhooks/{clientID}/url [get]
+func (h *WebhookHandler) GetWebhookURL(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID, ok := checkUserAccess(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := checkClientID(c)
+	if !ok {
+		return
+	}
+
+	url, err := h.service.GetWebhookURL(ctx, userID, clientID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWebhookClientNotFound):
+			responses.RespondNotFound(c, err, "Client not found")
+		case errors.Is(err, services.ErrUnsupportedWebhookClient):
+			responses.RespondBadRequest(c, err, "Client type does not support webhooks")
+		default:
+			handleServiceError(c, err, "Getting webhook URL", "", "Error getting webhook URL")
+		}
+		return
+	}
+
+	responses.RespondOK(c, url, "Webhook URL retrieved")
+}
+
+// RotateWebhookToken godoc
+//
+//	@Summary		Rotate the webhook URL for a client
+//	@Description	Issues a new webhook token. The webhook configured on the media server with the old URL stops working.
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			clientID	path		int												true	"Client ID"
+//	@Success		200			{object}	responses.APIResponse[string]					"Webhook URL rotated"
+//	@Failure		400			{object}	responses.ErrorResponse[responses.ErrorDetails]	"Invalid client ID"
+//	@Failure		401			{object}	responses.ErrorResponse[responses.ErrorDetails]	"Unauthorized"
+//	@Failure		404			{object}	responses.ErrorResponse[responses.ErrorDetails]	"Client not found"
+//	@Router			/webhooks/{clientID}/url/rotate [post]
+func (h *WebhookHandler) RotateWebhookToken(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID, ok := checkUserAccess(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := checkClientID(c)
+	if !ok {
+		return
+	}
+
+	url, err := h.service.RotateWebhookToken(ctx, userID, clientID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWebhookClientNotFound):
+			responses.RespondNotFound(c, err, "Client not found")
+		case errors.Is(err, services.ErrUnsupportedWebhookClient):
+			responses.RespondBadRequest(c, err, "Client type does not support webhooks")
+		default:
+			handleServiceError(c, err, "Rotating webhook token", "", "Error rotating webhook URL")
+		}
+		return
+	}
+
+	responses.RespondOK(c, url, "Webhook URL rotated")
+}
@@ -0,0 +1,32 @@
+package embyclient
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MarshalJSON leaves LastPlayedDate out when it isn't set. omitempty doesn't drop a zero time.Time,
+// and Emby would store 0001-01-01 as the date the item was last played.
+// Rating and PlaybackPositionTicks are always sent so they can be reset to zero, omitempty would leave
+// the old value in place. Callers post the whole record they read, so the other values are kept.
+// Not generated, keep this file when the client is regenerated.
+func (d UserItemDataDto) MarshalJSON() ([]byte, error) {
+	type userItemDataDto UserItemDataDto
+
+	var lastPlayedDate *time.Time
+	if !d.LastPlayedDate.IsZero() {
+		lastPlayedDate = &d.LastPlayedDate
+	}
+
+	return json.Marshal(struct {
+		userItemDataDto
+		LastPlayedDate        *time.Time `json:"LastPlayedDate,omitempty"`
+		Rating                float64    `json:"Rating"`
+		PlaybackPositionTicks int64      `json:"PlaybackPositionTicks"`
+	}{
+		userItemDataDto:       userItemDataDto(d),
+		LastPlayedDate:        lastPlayedDate,
+		Rating:                d.Rating,
+		PlaybackPositionTicks: d.PlaybackPositionTicks,
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"suasor/router/middleware"
 	"suasor/services"
 	"suasor/services/jobs"
+	"suasor/services/jobs/recommendation"
 	"suasor/types"
 	"suasor/utils/db"
 	logger "suasor/utils/logger"
@@ -92,6 +93,17 @@ func main() {
 	jobService := container.MustGet[jobs.JobService](deps.GetContainer())
 	log.Info().Msg("Job service initialized")
 
+	// Register the system jobs
+	if err := jobService.RegisterJob(container.MustGet[*jobs.EmbeddingJob](deps.GetContainer())); err != nil {
+		log.Error().Err(err).Msg("Failed to register embedding job")
+	}
+	if err := jobService.RegisterJob(container.MustGet[*recommendation.CollaborativeJob](deps.GetContainer())); err != nil {
+		log.Error().Err(err).Msg("Failed to register collaborative filtering job")
+	}
+	if err := jobService.RegisterJob(container.MustGet[*recommendation.RankingJob](deps.GetContainer())); err != nil {
+		log.Error().Err(err).Msg("Failed to register recommendation ranking job")
+	}
+
 	// Start the job scheduler
 	log.Info().Msg("Starting job scheduler")
 	if err := jobService.StartScheduler(); err != nil {
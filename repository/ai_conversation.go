@@ -18,6 +18,8 @@ type AIConversationRepository interface {
 	GetConversationByID(ctx context.Context, conversationID string) (*models.AIConversation, error)
 	GetConversationsByUserID(ctx context.Context, userID uint64, limit, offset int) ([]*models.AIConversation, int, error)
 	UpdateConversationStatus(ctx context.Context, conversationID string, status string) error
+	// UpdateConversationMemory stores the compacted memory of a conversation, nil resets it
+	UpdateConversationMemory(ctx context.Context, conversationID string, memory *models.ConversationMemory) error
 	DeleteConversation(ctx context.Context, conversationID string) error
 	ArchiveOldConversations(ctx context.Context, olderThan time.Duration) (int, error)
 
@@ -155,6 +157,23 @@ func (r *aiConversationRepository) UpdateConversationStatus(ctx context.Context,
 	return nil
 }
 
+// UpdateConversationMemory stores the compacted memory of a conversation, nil resets it
+func (r *aiConversationRepository) UpdateConversationMemory(ctx context.Context, conversationID string, memory *models.ConversationMemory) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.AIConversation{ID: conversationID}).
+		Select("memory", "updated_at").
+		Updates(&models.AIConversation{
+			Memory:    memory,
+			UpdatedAt: time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update conversation memory: %w", result.Error)
+	}
+
+	return nil
+}
+
 // DeleteConversation deletes a conversation and all related data
 func (r *aiConversationRepository) DeleteConversation(ctx context.Context, conversationID string) error {
 	log := logger.LoggerFromContext(ctx)
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"suasor/types/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AIUsageRepository stores the token usage of AI requests and the token budgets of users
+type AIUsageRepository interface {
+	// CreateRecord saves the token usage of a request
+	CreateRecord(ctx context.Context, record *models.AIUsageRecord) error
+	// SumTokens returns the tokens used since the given time, by every user when userID is 0
+	SumTokens(ctx context.Context, userID uint64, since time.Time) (int64, error)
+	// GetSummaries returns the usage between two times grouped by user, client, model and feature
+	GetSummaries(ctx context.Context, start, end time.Time) ([]*models.AIUsageSummary, error)
+
+	// GetBudget retrieves the budget of a user, or the global budget for userID 0, or nil if there is none
+	GetBudget(ctx context.Context, userID uint64) (*models.AIBudget, error)
+	// GetBudgets retrieves every budget including the global one
+	GetBudgets(ctx context.Context) ([]*models.AIBudget, error)
+	// SaveBudget creates or replaces the budget of the budget's user
+	SaveBudget(ctx context.Context, budget *models.AIBudget) error
+}
+
+type aiUsageRepository struct {
+	db *gorm.DB
+}
+
+// NewAIUsageRepository creates a new AI usage repository
+func NewAIUsageRepository(db *gorm.DB) AIUsageRepository {
+	return &aiUsageRepository{db: db}
+}
+
+// CreateRecord saves the token usage of a request
+func (r *aiUsageRepository) CreateRecord(ctx context.Context, record *models.AIUsageRecord) error {
+	if err := r.db.WithContext(ctx).Create(record).Error; err != nil {
+		return fmt.Errorf("error creating AI usage record: %w", err)
+	}
+	return nil
+}
+
+// SumTokens returns the tokens used since the given time, by every user when userID is 0
+func (r *aiUsageRepository) SumTokens(ctx context.Context, userID uint64, since time.Time) (int64, error) {
+	var total int64
+	query := r.db.WithContext(ctx).
+		Model(&models.AIUsageRecord{}).
+		Select("COALESCE(SUM(total_tokens), 0)").
+		Where("created_at >= ?", since)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("error summing AI token usage: %w", err)
+	}
+	return total, nil
+}
+
+// GetSummaries returns the usage between two times grouped by user, client, model and feature
+func (r *aiUsageRepository) GetSummaries(ctx context.Context, start, end time.Time) ([]*models.AIUsageSummary, error) {
+	var summaries []*models.AIUsageSummary
+	result := r.db.WithContext(ctx).
+		Model(&models.AIUsageRecord{}).
+		Select("user_id, client_id, client_type, model, feature, COUNT(*) AS requests, "+
+			"SUM(prompt_tokens) AS prompt_tokens, SUM(completion_tokens) AS completion_tokens, SUM(total_tokens) AS total_tokens").
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Group("user_id, client_id, client_type, model, feature").
+		Order("user_id, SUM(total_tokens) DESC").
+		Scan(&summaries)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting AI usage summaries: %w", result.Error)
+	}
+	return summaries, nil
+}
+
+// GetBudget retrieves the budget of a user, or the global budget for userID 0, or nil if there is none
+func (r *aiUsageRepository) GetBudget(ctx context.Context, userID uint64) (*models.AIBudget, error) {
+	var budget models.AIBudget
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&budget)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting AI budget: %w", result.Error)
+	}
+	return &budget, nil
+}
+
+// GetBudgets retrieves every budget including the global one
+func (r *aiUsageRepository) GetBudgets(ctx context.Context) ([]*models.AIBudget, error) {
+	var budgets []*models.AIBudget
+	if err := r.db.WithContext(ctx).Order("user_id").Find(&budgets).Error; err != nil {
+		return nil, fmt.Errorf("error getting AI budgets: %w", err)
+	}
+	return budgets, nil
+}
+
+// SaveBudget creates or replaces the budget of the budget's user
+func (r *aiUsageRepository) SaveBudget(ctx context.Context, budget *models.AIBudget) error {
+	existing, err := r.GetBudget(ctx, budget.UserID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		budget.ID = existing.ID
+		budget.CreatedAt = existing.CreatedAt
+	}
+
+	if err := r.db.WithContext(ctx).Save(budget).Error; err != nil {
+		return fmt.Errorf("error saving AI budget: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"suasor/types/models"
+	"suasor/utils/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAIUsageRepository(t *testing.T) {
+	ctx := context.Background()
+	testDB, err := database.InitializeInMemoryDB(ctx)
+	require.NoError(t, err)
+	repo := NewAIUsageRepository(testDB)
+
+	record := func(userID uint64, model string, feature models.AIFeature, tokens int64) {
+		require.NoError(t, repo.CreateRecord(ctx, &models.AIUsageRecord{
+			UserID:           userID,
+			ClientID:         7,
+			Model:            model,
+			Feature:          feature,
+			PromptTokens:     tokens - 10,
+			CompletionTokens: 10,
+			TotalTokens:      tokens,
+		}))
+	}
+	record(1, "gpt-4o-mini", models.AIFeatureConversation, 100)
+	record(1, "gpt-4o-mini", models.AIFeatureConversation, 50)
+	record(1, "gpt-4o-mini", models.AIFeatureRecommendation, 300)
+	record(2, "llama3.2", models.AIFeatureAnalysis, 40)
+
+	hourAgo := time.Now().Add(-time.Hour)
+	total, err := repo.SumTokens(ctx, 1, hourAgo)
+	require.NoError(t, err)
+	assert.Equal(t, int64(450), total)
+
+	total, err = repo.SumTokens(ctx, 0, hourAgo)
+	require.NoError(t, err)
+	assert.Equal(t, int64(490), total)
+
+	total, err = repo.SumTokens(ctx, 1, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	summaries, err := repo.GetSummaries(ctx, hourAgo, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, summaries, 3)
+	assert.Equal(t, models.AIFeatureRecommendation, summaries[0].Feature)
+	assert.Equal(t, int64(1), summaries[0].Requests)
+	assert.Equal(t, models.AIFeatureConversation, summaries[1].Feature)
+	assert.Equal(t, int64(2), summaries[1].Requests)
+	assert.Equal(t, int64(150), summaries[1].TotalTokens)
+	assert.Equal(t, int64(130), summaries[1].PromptTokens)
+	assert.Equal(t, uint64(2), summaries[2].UserID)
+
+	// Budgets are replaced per user, the global budget has user ID 0
+	budget, err := repo.GetBudget(ctx, 0)
+	require.NoError(t, err)
+	assert.Nil(t, budget)
+
+	require.NoError(t, repo.SaveBudget(ctx, &models.AIBudget{UserID: 0, MonthlyTokens: 1000}))
+	require.NoError(t, repo.SaveBudget(ctx, &models.AIBudget{UserID: 1, DailyTokens: 100}))
+	require.NoError(t, repo.SaveBudget(ctx, &models.AIBudget{UserID: 1, DailyTokens: 200}))
+
+	budget, err = repo.GetBudget(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), budget.DailyTokens)
+
+	budgets, err := repo.GetBudgets(ctx)
+	require.NoError(t, err)
+	require.Len(t, budgets, 2)
+	assert.Equal(t, int64(1000), budgets[0].MonthlyTokens)
+}
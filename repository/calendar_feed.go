@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"suasor/types/models"
+
+	"gorm.io/gorm"
+)
+
+// CalendarFeedRepository stores the secret tokens of users' ICS feeds
+type CalendarFeedRepository interface {
+	// GetByUserID retrieves a user's feed token, or nil if they have none
+	GetByUserID(ctx context.Context, userID uint64) (*models.CalendarFeedToken, error)
+	// GetByToken retrieves the feed token with the given secret, or nil if none exists
+	GetByToken(ctx context.Context, token string) (*models.CalendarFeedToken, error)
+	// Save creates or updates a feed token
+	Save(ctx context.Context, token *models.CalendarFeedToken) error
+}
+
+type calendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository creates a new calendar feed repository
+func NewCalendarFeedRepository(db *gorm.DB) CalendarFeedRepository {
+	return &calendarFeedRepository{db: db}
+}
+
+// GetByUserID retrieves a user's feed token, or nil if they have none
+func (r *calendarFeedRepository) GetByUserID(ctx context.Context, userID uint64) (*models.CalendarFeedToken, error) {
+	var token models.CalendarFeedToken
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&token)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting calendar feed token: %w", result.Error)
+	}
+	return &token, nil
+}
+
+// GetByToken retrieves the feed token with the given secret, or nil if none exists
+func (r *calendarFeedRepository) GetByToken(ctx context.Context, token string) (*models.CalendarFeedToken, error) {
+	var feedToken models.CalendarFeedToken
+	result := r.db.WithContext(ctx).Where("token = ?", token).First(&feedToken)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting calendar feed token: %w", result.Error)
+	}
+	return &feedToken, nil
+}
+
+// Save creates or updates a feed token
+func (r *calendarFeedRepository) Save(ctx context.Context, token *models.CalendarFeedToken) error {
+	result := r.db.WithContext(ctx).Save(token)
+	if result.Error != nil {
+		return fmt.Errorf("error saving calendar feed token: %w", result.Error)
+	}
+	return nil
+}
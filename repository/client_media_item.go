@@ -61,7 +61,7 @@ func (r *clientMediaItemRepository[T]) GetByClientID(ctx context.Context, client
 
 	// Use JSON contains operator to find items where clientIDs contains an entry with the given client ID
 	query := r.db.WithContext(ctx).
-		Where("sync_clients @> ?", fmt.Sprintf(`[{"id":%d}]`, clientID)).
+		Where("sync_clients @> ?", fmt.Sprintf(`[{"clientID":%d}]`, clientID)).
 		Find(&items)
 
 	if err := query.Error; err != nil {
@@ -234,8 +234,20 @@ func (s *clientMediaItemRepository[T]) getSyncClientByClientID(ctx context.Conte
 	}
 }
 
+// DeleteClientItem unlinks a client from a media item after the item was removed from that client.
+// The item itself is kept so that history and ratings recorded against it survive.
 func (s *clientMediaItemRepository[T]) DeleteClientItem(ctx context.Context, clientID uint64, itemID string) error {
-	// TODO: Implement this, maybe should remove the records from SyncClients
-	return nil
+	item, err := s.GetByClientItemID(ctx, clientID, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to find client item: %w", err)
+	}
 
+	if !item.SyncClients.RemoveClient(clientID) {
+		return nil
+	}
+
+	if _, err := s.Update(ctx, item); err != nil {
+		return fmt.Errorf("failed to unlink client item: %w", err)
+	}
+	return nil
 }
@@ -161,6 +161,8 @@ func (r *mediaItemRepository[T]) Search(ctx context.Context, query types.QueryOp
 		dbQuery = dbQuery.Where("title ILIKE ?", "%"+query.Query+"%")
 	}
 
+	dbQuery = applySearchFilters(dbQuery, query)
+
 	// Add pagination
 	if query.Limit > 0 {
 		dbQuery = dbQuery.Limit(query.Limit)
@@ -719,3 +721,46 @@ func (r *mediaItemRepository[T]) GetByTitle(ctx context.Context, clientID uint64
 
 	return items[0], nil
 }
+
+// applySearchFilters adds the typed filters of the query options that the local library can answer
+func applySearchFilters(dbQuery *gorm.DB, query types.QueryOptions) *gorm.DB {
+	// Any of the genres matches, genre names differ in case between clients
+	if len(query.Genres) > 0 {
+		genres := make([]string, 0, len(query.Genres))
+		for _, genre := range query.Genres {
+			genres = append(genres, strings.ToLower(genre))
+		}
+		dbQuery = dbQuery.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(COALESCE(data->'details'->'genres', '[]'::jsonb)) AS genre WHERE lower(genre) IN ?)", genres)
+	}
+
+	if query.ReleasedAfter != nil && !query.ReleasedAfter.IsZero() {
+		dbQuery = dbQuery.Where("release_date >= ?", *query.ReleasedAfter)
+	}
+	if query.ReleasedBefore != nil && !query.ReleasedBefore.IsZero() {
+		dbQuery = dbQuery.Where("release_date <= ?", *query.ReleasedBefore)
+	}
+
+	// Durations are stored in seconds, the filters are in minutes
+	if query.MinimumDuration > 0 {
+		dbQuery = dbQuery.Where("(data->'details'->>'durationSeconds')::bigint >= ?", query.MinimumDuration*60)
+	}
+	if query.MaximumDuration > 0 {
+		dbQuery = dbQuery.Where("(data->'details'->>'durationSeconds')::bigint <= ?", query.MaximumDuration*60)
+	}
+
+	if query.ClientID > 0 {
+		dbQuery = dbQuery.Where("sync_clients @> ?::jsonb", fmt.Sprintf(`[{"clientID":%d}]`, query.ClientID))
+	}
+
+	// Watched state is per user, without one the filters can't apply
+	if query.UserID > 0 && (query.Watched || query.Unwatched) {
+		played := "SELECT media_item_id FROM user_media_item_data WHERE user_id = ? AND (completed OR play_count > 0)"
+		if query.Watched {
+			dbQuery = dbQuery.Where("id IN ("+played+")", query.UserID)
+		} else {
+			dbQuery = dbQuery.Where("id NOT IN ("+played+")", query.UserID)
+		}
+	}
+
+	return dbQuery
+}
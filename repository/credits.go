@@ -18,6 +18,7 @@ type CreditRepository interface {
 
 	// Query operations
 	GetByMediaItemID(ctx context.Context, mediaItemID uint64) ([]*models.Credit, error)
+	GetByMediaItemIDs(ctx context.Context, mediaItemIDs []uint64) ([]*models.Credit, error)
 	GetByPersonID(ctx context.Context, personID uint64) ([]*models.Credit, error)
 	GetByRole(ctx context.Context, role string) ([]*models.Credit, error)
 	GetByDepartment(ctx context.Context, department string) ([]*models.Credit, error)
@@ -112,6 +113,23 @@ func (r *creditRepository) GetByMediaItemID(ctx context.Context, mediaItemID uin
 	return credits, nil
 }
 
+// GetByMediaItemIDs gets all credits for several media items at once
+func (r *creditRepository) GetByMediaItemIDs(ctx context.Context, mediaItemIDs []uint64) ([]*models.Credit, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	if len(mediaItemIDs) == 0 {
+		return nil, nil
+	}
+
+	var credits []*models.Credit
+	if err := r.db.Where("media_item_id IN ?", mediaItemIDs).Find(&credits).Error; err != nil {
+		log.Error().Err(err).Int("mediaItems", len(mediaItemIDs)).Msg("Failed to get credits for media items")
+		return nil, err
+	}
+
+	return credits, nil
+}
+
 // GetByPersonID gets all credits for a person
 func (r *creditRepository) GetByPersonID(ctx context.Context, personID uint64) ([]*models.Credit, error) {
 	log := logger.LoggerFromContext(ctx)
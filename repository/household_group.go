@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"suasor/types/models"
+
+	"gorm.io/gorm"
+)
+
+// HouseholdGroupRepository stores the saved groups of users who watch together
+type HouseholdGroupRepository interface {
+	// Create stores a new group
+	Create(ctx context.Context, group *models.HouseholdGroup) error
+	// GetByID returns a group
+	GetByID(ctx context.Context, id uint64) (*models.HouseholdGroup, error)
+	// GetByUserID returns the groups a user owns or is a member of
+	GetByUserID(ctx context.Context, userID uint64) ([]*models.HouseholdGroup, error)
+	// Delete deletes a group
+	Delete(ctx context.Context, id uint64) error
+}
+
+type householdGroupRepository struct {
+	db *gorm.DB
+}
+
+// NewHouseholdGroupRepository creates a new household group repository
+func NewHouseholdGroupRepository(db *gorm.DB) HouseholdGroupRepository {
+	return &householdGroupRepository{db: db}
+}
+
+// Create stores a new group
+func (r *householdGroupRepository) Create(ctx context.Context, group *models.HouseholdGroup) error {
+	if err := r.db.WithContext(ctx).Create(group).Error; err != nil {
+		return fmt.Errorf("error creating household group: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a group
+func (r *householdGroupRepository) GetByID(ctx context.Context, id uint64) (*models.HouseholdGroup, error) {
+	var group models.HouseholdGroup
+	if err := r.db.WithContext(ctx).First(&group, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error getting household group %d: %w", id, err)
+	}
+	return &group, nil
+}
+
+// GetByUserID returns the groups a user owns or is a member of, the oldest first
+func (r *householdGroupRepository) GetByUserID(ctx context.Context, userID uint64) ([]*models.HouseholdGroup, error) {
+	var groups []*models.HouseholdGroup
+	result := r.db.WithContext(ctx).
+		Where("owner_id = ? OR member_ids @> ?::jsonb", userID, fmt.Sprintf("[%d]", userID)).
+		Order("created_at ASC, id ASC").
+		Find(&groups)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting household groups for user %d: %w", userID, result.Error)
+	}
+	return groups, nil
+}
+
+// Delete deletes a group
+func (r *householdGroupRepository) Delete(ctx context.Context, id uint64) error {
+	result := r.db.WithContext(ctx).Delete(&models.HouseholdGroup{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting household group %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
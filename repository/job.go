@@ -70,12 +70,20 @@ type JobRepository interface {
 	CreateMediaSyncJob(ctx context.Context, syncJob *models.MediaSyncJob) error
 	// GetMediaSyncJobsByUser retrieves media sync jobs for a specific user
 	GetMediaSyncJobsByUser(ctx context.Context, userID uint64) ([]models.MediaSyncJob, error)
+	// GetMediaSyncJobByClient retrieves the media sync job for a client and sync type, or nil if none exists
+	GetMediaSyncJobByClient(ctx context.Context, clientID uint64, syncType models.SyncType) (*models.MediaSyncJob, error)
 	// UpdateMediaSyncJob updates an existing media sync job
 	UpdateMediaSyncJob(ctx context.Context, syncJob *models.MediaSyncJob) error
 	// UpdateMediaSyncLastRunTime updates the last run time for a media sync job
 	UpdateMediaSyncLastRunTime(ctx context.Context, syncJobID uint64, lastRunTime time.Time) error
 	// DeleteMediaSyncJob deletes a media sync job
 	DeleteMediaSyncJob(ctx context.Context, syncJobID uint64) error
+
+	// Sync checkpoint methods
+	// GetSyncCheckpoint retrieves the checkpoint for a client and sync type, or nil if none exists
+	GetSyncCheckpoint(ctx context.Context, clientID uint64, syncType models.SyncType) (*models.SyncCheckpoint, error)
+	// SaveSyncCheckpoint creates or updates a sync checkpoint
+	SaveSyncCheckpoint(ctx context.Context, checkpoint *models.SyncCheckpoint) error
 }
 
 type jobRepository struct {
@@ -281,20 +289,29 @@ func (r *jobRepository) GetRecommendationsByJobRun(ctx context.Context, jobRunID
 	return recommendations, nil
 }
 
-// DismissRecommendation marks a recommendation as dismissed
+// DismissRecommendation marks a recommendation as dismissed and records the dismissal as feedback
 func (r *jobRepository) DismissRecommendation(ctx context.Context, recommendationID uint64) error {
-	result := r.db.WithContext(ctx).
-		Model(&models.Recommendation{}).
-		Where("id = ?", recommendationID).
-		Updates(map[string]interface{}{
-			"dismissed": true,
-			"active":    false,
-		})
-	
-	if result.Error != nil {
-		return fmt.Errorf("error dismissing recommendation %d: %w", recommendationID, result.Error)
-	}
-	return nil
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var recommendation models.Recommendation
+		if err := tx.First(&recommendation, recommendationID).Error; err != nil {
+			return fmt.Errorf("error dismissing recommendation %d: %w", recommendationID, err)
+		}
+
+		result := tx.Model(&recommendation).
+			Updates(map[string]interface{}{
+				"dismissed": true,
+				"active":    false,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("error dismissing recommendation %d: %w", recommendationID, result.Error)
+		}
+
+		feedback := models.NewRecommendationFeedback(&recommendation, models.FeedbackTypeDismissed)
+		if err := tx.Create(feedback).Error; err != nil {
+			return fmt.Errorf("error recording dismissal of recommendation %d: %w", recommendationID, err)
+		}
+		return nil
+	})
 }
 
 // UpdateRecommendationViewedStatus updates whether the recommendation has been viewed
@@ -357,6 +374,22 @@ func (r *jobRepository) GetMediaSyncJobsByUser(ctx context.Context, userID uint6
 	return syncJobs, nil
 }
 
+// GetMediaSyncJobByClient retrieves the media sync job for a client and sync type, or nil if none exists
+func (r *jobRepository) GetMediaSyncJobByClient(ctx context.Context, clientID uint64, syncType models.SyncType) (*models.MediaSyncJob, error) {
+	var syncJob models.MediaSyncJob
+	result := r.db.WithContext(ctx).
+		Where("client_id = ? AND sync_type = ?", clientID, syncType).
+		First(&syncJob)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting media sync job: %w", result.Error)
+	}
+	return &syncJob, nil
+}
+
 // UpdateMediaSyncJob updates an existing media sync job
 func (r *jobRepository) UpdateMediaSyncJob(ctx context.Context, syncJob *models.MediaSyncJob) error {
 	result := r.db.WithContext(ctx).Save(syncJob)
@@ -391,6 +424,31 @@ func (r *jobRepository) DeleteMediaSyncJob(ctx context.Context, syncJobID uint64
 	return nil
 }
 
+// GetSyncCheckpoint retrieves the checkpoint for a client and sync type, or nil if none exists
+func (r *jobRepository) GetSyncCheckpoint(ctx context.Context, clientID uint64, syncType models.SyncType) (*models.SyncCheckpoint, error) {
+	var checkpoint models.SyncCheckpoint
+	result := r.db.WithContext(ctx).
+		Where("client_id = ? AND sync_type = ?", clientID, syncType).
+		First(&checkpoint)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting sync checkpoint: %w", result.Error)
+	}
+	return &checkpoint, nil
+}
+
+// SaveSyncCheckpoint creates or updates a sync checkpoint
+func (r *jobRepository) SaveSyncCheckpoint(ctx context.Context, checkpoint *models.SyncCheckpoint) error {
+	result := r.db.WithContext(ctx).Save(checkpoint)
+	if result.Error != nil {
+		return fmt.Errorf("error saving sync checkpoint: %w", result.Error)
+	}
+	return nil
+}
+
 // GetJobRunByID retrieves a specific job run by ID
 func (r *jobRepository) GetJobRunByID(ctx context.Context, jobRunID uint64) (*models.JobRun, error) {
 	var jobRun models.JobRun
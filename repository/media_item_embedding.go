@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"suasor/clients/media/types"
+	"suasor/types/models"
+
+	"gorm.io/gorm"
+)
+
+// embeddingBatchSize is the number of vectors loaded at once by similarity queries
+const embeddingBatchSize = 500
+
+// EmbeddingRepository stores the embeddings of media items and finds the items nearest to a vector.
+// Similarity is computed in Go so the same queries work on Postgres and SQLite without a vector extension.
+type EmbeddingRepository interface {
+	// Save creates or replaces the embedding of an item for the embedding's model
+	Save(ctx context.Context, embedding *models.MediaItemEmbedding) error
+	// GetByMediaItemID retrieves the embedding of an item for a model, or nil if it has none
+	GetByMediaItemID(ctx context.Context, mediaItemID uint64, model string) (*models.MediaItemEmbedding, error)
+	// GetContentHashes returns the content hash of every item embedded with the model, by media item ID
+	GetContentHashes(ctx context.Context, model string) (map[uint64]string, error)
+	// FindNearest returns the items most similar to the vector, best first.
+	// Only embeddings of the model and of the given media types, if any, are compared.
+	FindNearest(ctx context.Context, vector models.Vector, model string, mediaTypes []types.MediaType, excludeIDs []uint64, limit int) ([]models.EmbeddingMatch, error)
+}
+
+type embeddingRepository struct {
+	db *gorm.DB
+}
+
+// NewEmbeddingRepository creates a new embedding repository
+func NewEmbeddingRepository(db *gorm.DB) EmbeddingRepository {
+	return &embeddingRepository{db: db}
+}
+
+// Save creates or replaces the embedding of an item for the embedding's model
+func (r *embeddingRepository) Save(ctx context.Context, embedding *models.MediaItemEmbedding) error {
+	existing, err := r.GetByMediaItemID(ctx, embedding.MediaItemID, embedding.Model)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		embedding.ID = existing.ID
+		embedding.CreatedAt = existing.CreatedAt
+	}
+	embedding.Dimensions = len(embedding.Vector)
+
+	result := r.db.WithContext(ctx).Save(embedding)
+	if result.Error != nil {
+		return fmt.Errorf("error saving media item embedding: %w", result.Error)
+	}
+	return nil
+}
+
+// GetByMediaItemID retrieves the embedding of an item for a model, or nil if it has none
+func (r *embeddingRepository) GetByMediaItemID(ctx context.Context, mediaItemID uint64, model string) (*models.MediaItemEmbedding, error) {
+	var embedding models.MediaItemEmbedding
+	result := r.db.WithContext(ctx).
+		Where("media_item_id = ? AND model = ?", mediaItemID, model).
+		First(&embedding)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting media item embedding: %w", result.Error)
+	}
+	return &embedding, nil
+}
+
+// GetContentHashes returns the content hash of every item embedded with the model, by media item ID
+func (r *embeddingRepository) GetContentHashes(ctx context.Context, model string) (map[uint64]string, error) {
+	var rows []struct {
+		MediaItemID uint64
+		ContentHash string
+	}
+	result := r.db.WithContext(ctx).
+		Model(&models.MediaItemEmbedding{}).
+		Select("media_item_id, content_hash").
+		Where("model = ?", model).
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting media item embedding hashes: %w", result.Error)
+	}
+
+	hashes := make(map[uint64]string, len(rows))
+	for _, row := range rows {
+		hashes[row.MediaItemID] = row.ContentHash
+	}
+	return hashes, nil
+}
+
+// FindNearest returns the items most similar to the vector, best first
+func (r *embeddingRepository) FindNearest(ctx context.Context, vector models.Vector, model string, mediaTypes []types.MediaType, excludeIDs []uint64, limit int) ([]models.EmbeddingMatch, error) {
+	query := r.db.WithContext(ctx).
+		Select("id, media_item_id, media_type, vector").
+		Where("model = ? AND dimensions = ?", model, len(vector))
+	if len(mediaTypes) > 0 {
+		query = query.Where("media_type IN ?", mediaTypes)
+	}
+	if len(excludeIDs) > 0 {
+		query = query.Where("media_item_id NOT IN ?", excludeIDs)
+	}
+
+	var matches []models.EmbeddingMatch
+	var batch []models.MediaItemEmbedding
+	result := query.FindInBatches(&batch, embeddingBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, embedding := range batch {
+			matches = append(matches, models.EmbeddingMatch{
+				MediaItemID: embedding.MediaItemID,
+				MediaType:   embedding.MediaType,
+				Score:       vector.CosineSimilarity(embedding.Vector),
+			})
+		}
+		// Only keep the best matches between batches
+		matches = topMatches(matches, limit)
+		return nil
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error finding nearest media item embeddings: %w", result.Error)
+	}
+
+	return topMatches(matches, limit), nil
+}
+
+// topMatches sorts the matches by score and keeps the first limit of them
+func topMatches(matches []models.EmbeddingMatch, limit int) []models.EmbeddingMatch {
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"suasor/clients/media/types"
+	"suasor/types/models"
+	"suasor/utils/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorRoundTrip(t *testing.T) {
+	vector := models.Vector{0.25, -1.5, 3}
+	value, err := vector.Value()
+	require.NoError(t, err)
+	assert.Len(t, value, 12)
+
+	var scanned models.Vector
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, vector, scanned)
+
+	assert.InDelta(t, 1, vector.CosineSimilarity(models.Vector{0.5, -3, 6}), 1e-9)
+	assert.InDelta(t, 0, models.Vector{1, 0}.CosineSimilarity(models.Vector{0, 1}), 1e-9)
+	assert.Equal(t, float64(0), vector.CosineSimilarity(models.Vector{1, 2}))
+}
+
+func TestEmbeddingRepository(t *testing.T) {
+	ctx := context.Background()
+	testDB, err := database.InitializeInMemoryDB(ctx)
+	require.NoError(t, err)
+	repo := NewEmbeddingRepository(testDB)
+
+	save := func(itemID uint64, mediaType types.MediaType, model string, vector models.Vector) {
+		require.NoError(t, repo.Save(ctx, &models.MediaItemEmbedding{
+			MediaItemID: itemID,
+			MediaType:   mediaType,
+			Model:       model,
+			ContentHash: "hash",
+			Vector:      vector,
+		}))
+	}
+	save(1, types.MediaTypeMovie, "small", models.Vector{1, 0})
+	save(2, types.MediaTypeMovie, "small", models.Vector{0.9, 0.1})
+	save(3, types.MediaTypeMovie, "small", models.Vector{0, 1})
+	save(4, types.MediaTypeSeries, "small", models.Vector{1, 0})
+	save(5, types.MediaTypeMovie, "large", models.Vector{1, 0})
+	// Saving again replaces the embedding of the model
+	save(3, types.MediaTypeMovie, "small", models.Vector{-1, 0})
+
+	embedding, err := repo.GetByMediaItemID(ctx, 3, "small")
+	require.NoError(t, err)
+	assert.Equal(t, models.Vector{-1, 0}, embedding.Vector)
+	assert.Equal(t, 2, embedding.Dimensions)
+
+	hashes, err := repo.GetContentHashes(ctx, "small")
+	require.NoError(t, err)
+	assert.Len(t, hashes, 4)
+
+	matches, err := repo.FindNearest(ctx, models.Vector{1, 0}, "small", []types.MediaType{types.MediaTypeMovie}, []uint64{1}, 10)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, uint64(2), matches[0].MediaItemID)
+	assert.Equal(t, uint64(3), matches[1].MediaItemID)
+	assert.Less(t, matches[1].Score, matches[0].Score)
+
+	matches, err = repo.FindNearest(ctx, models.Vector{1, 0}, "small", nil, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.ElementsMatch(t, []uint64{1, 4}, []uint64{matches[0].MediaItemID, matches[1].MediaItemID})
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"suasor/clients/media/types"
+	"suasor/types/models"
+
+	"gorm.io/gorm"
+)
+
+// neighborBatchSize is the number of neighbours inserted at once
+const neighborBatchSize = 1000
+
+// NeighborRepository stores the precomputed item-item neighbours of collaborative filtering
+// and reads the play history of all users they are computed from
+type NeighborRepository interface {
+	// GetInteractions returns every user's play history of the media types, without the items
+	GetInteractions(ctx context.Context, mediaTypes []types.MediaType) ([]models.ItemInteraction, error)
+	// GetUserInteractions returns the play history of the media types of some users, without the items
+	GetUserInteractions(ctx context.Context, userIDs []uint64, mediaTypes []types.MediaType) ([]models.ItemInteraction, error)
+	// ReplaceNeighbors replaces all neighbours of a media type in one transaction
+	ReplaceNeighbors(ctx context.Context, mediaType types.MediaType, neighbors []*models.MediaItemNeighbor) error
+	// GetNeighbors returns the neighbours of the items, the most similar first
+	GetNeighbors(ctx context.Context, mediaItemIDs []uint64) ([]models.MediaItemNeighbor, error)
+	// GetWatcherCounts returns the number of users who watched each of the items, items nobody watched are left out
+	GetWatcherCounts(ctx context.Context, mediaItemIDs []uint64) (map[uint64]int, error)
+}
+
+type neighborRepository struct {
+	db *gorm.DB
+}
+
+// NewNeighborRepository creates a new media item neighbor repository
+func NewNeighborRepository(db *gorm.DB) NeighborRepository {
+	return &neighborRepository{db: db}
+}
+
+// GetInteractions returns every user's play history of the media types
+func (r *neighborRepository) GetInteractions(ctx context.Context, mediaTypes []types.MediaType) ([]models.ItemInteraction, error) {
+	var interactions []models.ItemInteraction
+	result := r.db.WithContext(ctx).
+		Table("user_media_item_data").
+		Select("user_id, media_item_id, type, play_count, played_percentage, completed, is_favorite, is_disliked, user_rating").
+		Where("type IN ?", mediaTypes).
+		Scan(&interactions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting media item interactions: %w", result.Error)
+	}
+	return interactions, nil
+}
+
+// GetUserInteractions returns the play history of the media types of some users
+func (r *neighborRepository) GetUserInteractions(ctx context.Context, userIDs []uint64, mediaTypes []types.MediaType) ([]models.ItemInteraction, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var interactions []models.ItemInteraction
+	result := r.db.WithContext(ctx).
+		Table("user_media_item_data").
+		Select("user_id, media_item_id, type, play_count, played_percentage, completed, is_favorite, is_disliked, user_rating").
+		Where("user_id IN ? AND type IN ?", userIDs, mediaTypes).
+		Scan(&interactions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting media item interactions of users: %w", result.Error)
+	}
+	return interactions, nil
+}
+
+// ReplaceNeighbors replaces all neighbours of a media type in one transaction
+func (r *neighborRepository) ReplaceNeighbors(ctx context.Context, mediaType types.MediaType, neighbors []*models.MediaItemNeighbor) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("media_type = ?", mediaType).Delete(&models.MediaItemNeighbor{}).Error; err != nil {
+			return fmt.Errorf("error deleting media item neighbors: %w", err)
+		}
+		if len(neighbors) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(neighbors, neighborBatchSize).Error; err != nil {
+			return fmt.Errorf("error saving media item neighbors: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetNeighbors returns the neighbours of the items, the most similar first
+func (r *neighborRepository) GetNeighbors(ctx context.Context, mediaItemIDs []uint64) ([]models.MediaItemNeighbor, error) {
+	if len(mediaItemIDs) == 0 {
+		return nil, nil
+	}
+
+	var neighbors []models.MediaItemNeighbor
+	result := r.db.WithContext(ctx).
+		Where("media_item_id IN ?", mediaItemIDs).
+		Order("score DESC").
+		Find(&neighbors)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting media item neighbors: %w", result.Error)
+	}
+	return neighbors, nil
+}
+
+// GetWatcherCounts returns the number of users who watched each of the items
+func (r *neighborRepository) GetWatcherCounts(ctx context.Context, mediaItemIDs []uint64) (map[uint64]int, error) {
+	counts := make(map[uint64]int)
+	if len(mediaItemIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		MediaItemID uint64
+		Watchers    int
+	}
+	result := r.db.WithContext(ctx).
+		Table("user_media_item_data").
+		Select("media_item_id, COUNT(DISTINCT user_id) AS watchers").
+		Where("media_item_id IN ? AND (completed = ? OR play_count > 0)", mediaItemIDs, true).
+		Group("media_item_id").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting media item watcher counts: %w", result.Error)
+	}
+
+	for _, row := range rows {
+		counts[row.MediaItemID] = row.Watchers
+	}
+	return counts, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"suasor/types/models"
+
+	"gorm.io/gorm"
+)
+
+// PromptTemplateRepository stores the versions of prompt templates, shared templates have userID 0
+type PromptTemplateRepository interface {
+	// GetActive returns the active version of a template, or nil if the built-in default is in use
+	GetActive(ctx context.Context, name string, userID uint64) (*models.PromptTemplate, error)
+	// GetAllActive returns the active version of every shared template
+	GetAllActive(ctx context.Context) ([]*models.PromptTemplate, error)
+	// GetVersions returns every version of a template, the newest first
+	GetVersions(ctx context.Context, name string, userID uint64) ([]*models.PromptTemplate, error)
+	// CreateVersion saves the template as the next version of its name and makes it the active one
+	CreateVersion(ctx context.Context, template *models.PromptTemplate) error
+	// Activate makes a version the active one, version 0 switches back to the built-in default.
+	// ErrNotFound is returned when the version does not exist.
+	Activate(ctx context.Context, name string, userID uint64, version int) error
+}
+
+type promptTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewPromptTemplateRepository creates a new prompt template repository
+func NewPromptTemplateRepository(db *gorm.DB) PromptTemplateRepository {
+	return &promptTemplateRepository{db: db}
+}
+
+// GetActive returns the active version of a template, or nil if the built-in default is in use
+func (r *promptTemplateRepository) GetActive(ctx context.Context, name string, userID uint64) (*models.PromptTemplate, error) {
+	var template models.PromptTemplate
+	result := r.db.WithContext(ctx).
+		Where("name = ? AND user_id = ? AND active = ?", name, userID, true).
+		First(&template)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting active prompt template: %w", result.Error)
+	}
+	return &template, nil
+}
+
+// GetAllActive returns the active version of every shared template
+func (r *promptTemplateRepository) GetAllActive(ctx context.Context) ([]*models.PromptTemplate, error) {
+	var templates []*models.PromptTemplate
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND active = ?", 0, true).
+		Order("name").
+		Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("error getting active prompt templates: %w", err)
+	}
+	return templates, nil
+}
+
+// GetVersions returns every version of a template, the newest first
+func (r *promptTemplateRepository) GetVersions(ctx context.Context, name string, userID uint64) ([]*models.PromptTemplate, error) {
+	var templates []*models.PromptTemplate
+	if err := r.db.WithContext(ctx).
+		Where("name = ? AND user_id = ?", name, userID).
+		Order("version DESC").
+		Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("error getting prompt template versions: %w", err)
+	}
+	return templates, nil
+}
+
+// CreateVersion saves the template as the next version of its name and makes it the active one
+func (r *promptTemplateRepository) CreateVersion(ctx context.Context, template *models.PromptTemplate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&models.PromptTemplate{}).
+			Select("COALESCE(MAX(version), 0)").
+			Where("name = ? AND user_id = ?", template.Name, template.UserID).
+			Scan(&latest).Error; err != nil {
+			return fmt.Errorf("error getting latest prompt template version: %w", err)
+		}
+
+		if err := tx.Model(&models.PromptTemplate{}).
+			Where("name = ? AND user_id = ?", template.Name, template.UserID).
+			Update("active", false).Error; err != nil {
+			return fmt.Errorf("error deactivating prompt template versions: %w", err)
+		}
+
+		template.ID = 0
+		template.Version = latest + 1
+		template.Active = true
+		if err := tx.Create(template).Error; err != nil {
+			return fmt.Errorf("error creating prompt template version: %w", err)
+		}
+		return nil
+	})
+}
+
+// Activate makes a version the active one, version 0 switches back to the built-in default
+func (r *promptTemplateRepository) Activate(ctx context.Context, name string, userID uint64, version int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if version != 0 {
+			var count int64
+			if err := tx.Model(&models.PromptTemplate{}).
+				Where("name = ? AND user_id = ? AND version = ?", name, userID, version).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("error getting prompt template version: %w", err)
+			}
+			if count == 0 {
+				return ErrNotFound
+			}
+		}
+
+		if err := tx.Model(&models.PromptTemplate{}).
+			Where("name = ? AND user_id = ?", name, userID).
+			Update("active", false).Error; err != nil {
+			return fmt.Errorf("error deactivating prompt template versions: %w", err)
+		}
+		if version == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.PromptTemplate{}).
+			Where("name = ? AND user_id = ? AND version = ?", name, userID, version).
+			Update("active", true).Error; err != nil {
+			return fmt.Errorf("error activating prompt template version: %w", err)
+		}
+		return nil
+	})
+}
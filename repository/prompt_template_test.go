@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"suasor/types/models"
+	"suasor/utils/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptTemplateRepository(t *testing.T) {
+	ctx := context.Background()
+	testDB, err := database.InitializeInMemoryDB(ctx)
+	require.NoError(t, err)
+	repo := NewPromptTemplateRepository(testDB)
+
+	active, err := repo.GetActive(ctx, "conversation.system", 0)
+	require.NoError(t, err)
+	assert.Nil(t, active)
+
+	first := &models.PromptTemplate{Name: "conversation.system", Body: "Recommend {{.ContentType}}", CreatedBy: 1}
+	require.NoError(t, repo.CreateVersion(ctx, first))
+	second := &models.PromptTemplate{Name: "conversation.system", Body: "Suggest {{.ContentType}}", CreatedBy: 1}
+	require.NoError(t, repo.CreateVersion(ctx, second))
+	custom := &models.PromptTemplate{Name: "personality.custom", UserID: 2, Body: "Talk like a pirate", CreatedBy: 2}
+	require.NoError(t, repo.CreateVersion(ctx, custom))
+
+	assert.Equal(t, 1, first.Version)
+	assert.Equal(t, 2, second.Version)
+	assert.Equal(t, 1, custom.Version)
+
+	active, err = repo.GetActive(ctx, "conversation.system", 0)
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	assert.Equal(t, 2, active.Version)
+	assert.Equal(t, "Suggest {{.ContentType}}", active.Body)
+
+	versions, err := repo.GetVersions(ctx, "conversation.system", 0)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+
+	// Shared templates don't include custom personalities
+	shared, err := repo.GetAllActive(ctx)
+	require.NoError(t, err)
+	require.Len(t, shared, 1)
+	assert.Equal(t, "conversation.system", shared[0].Name)
+
+	// Roll back to the first version
+	require.NoError(t, repo.Activate(ctx, "conversation.system", 0, 1))
+	active, err = repo.GetActive(ctx, "conversation.system", 0)
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	assert.Equal(t, 1, active.Version)
+
+	assert.ErrorIs(t, repo.Activate(ctx, "conversation.system", 0, 5), ErrNotFound)
+
+	// Version 0 switches back to the built-in default
+	require.NoError(t, repo.Activate(ctx, "conversation.system", 0, 0))
+	active, err = repo.GetActive(ctx, "conversation.system", 0)
+	require.NoError(t, err)
+	assert.Nil(t, active)
+}
@@ -30,10 +30,20 @@ type RecommendationRepository interface {
 	MarkAsViewed(ctx context.Context, id uint64) error
 	// RateRecommendation sets a user rating for a recommendation
 	RateRecommendation(ctx context.Context, id uint64, rating float32) error
+	// Dismiss marks a recommendation as dismissed so it is no longer shown
+	Dismiss(ctx context.Context, id uint64) error
 	// DeleteByJobRunID deletes recommendations by job run ID
 	DeleteByJobRunID(ctx context.Context, jobRunID uint64) error
 	// DeleteByUserID deletes all recommendations for a user
 	DeleteByUserID(ctx context.Context, userID uint64) error
+	// GetActiveByUserID retrieves a user's active, unexpired recommendations from every recommender.
+	// Dismissed recommendations are included so their items can be left out of other lists.
+	GetActiveByUserID(ctx context.Context, userID uint64) ([]models.Recommendation, error)
+	// GetRanked retrieves a user's ranked recommendations in order, optionally of one media type
+	GetRanked(ctx context.Context, userID uint64, mediaType string, limit, offset int) ([]models.Recommendation, error)
+	// ReplaceByRecommender replaces a user's recommendations from one recommender with new ones.
+	// Recommendations the user dismissed are kept and their items are not recommended again.
+	ReplaceByRecommender(ctx context.Context, userID uint64, recommendedBy string, recommendations []*models.Recommendation) error
 	// DeleteExpired deletes all expired recommendations
 	DeleteExpired(ctx context.Context) error
 	// GetCount returns the count of recommendations for a user
@@ -207,6 +217,27 @@ func (r *recommendationRepository) RateRecommendation(ctx context.Context, id ui
 	return nil
 }
 
+// Dismiss marks a recommendation as dismissed so it is no longer shown
+func (r *recommendationRepository) Dismiss(ctx context.Context, id uint64) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Recommendation{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"dismissed": true,
+			"active":    false,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to dismiss recommendation: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 // DeleteByJobRunID deletes recommendations by job run ID
 func (r *recommendationRepository) DeleteByJobRunID(ctx context.Context, jobRunID uint64) error {
 	result := r.db.WithContext(ctx).
@@ -233,6 +264,81 @@ func (r *recommendationRepository) DeleteByUserID(ctx context.Context, userID ui
 	return nil
 }
 
+// GetActiveByUserID retrieves a user's active, unexpired recommendations from every recommender
+func (r *recommendationRepository) GetActiveByUserID(ctx context.Context, userID uint64) ([]models.Recommendation, error) {
+	var recommendations []models.Recommendation
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND active = ?", userID, true).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&recommendations)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get active recommendations: %w", result.Error)
+	}
+	return recommendations, nil
+}
+
+// GetRanked retrieves a user's ranked recommendations in order, optionally of one media type
+func (r *recommendationRepository) GetRanked(ctx context.Context, userID uint64, mediaType string, limit, offset int) ([]models.Recommendation, error) {
+	var recommendations []models.Recommendation
+
+	if limit <= 0 {
+		limit = 20 // Default limit
+	}
+
+	query := r.db.WithContext(ctx).
+		Where("user_id = ? AND rank > 0 AND active = ? AND dismissed = ?", userID, true, false)
+	if mediaType != "" {
+		query = query.Where("media_type = ?", mediaType)
+	}
+	result := query.
+		Order("rank ASC").
+		Order("media_type ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&recommendations)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get ranked recommendations: %w", result.Error)
+	}
+	return recommendations, nil
+}
+
+// ReplaceByRecommender replaces a user's recommendations from one recommender with new ones
+func (r *recommendationRepository) ReplaceByRecommender(ctx context.Context, userID uint64, recommendedBy string, recommendations []*models.Recommendation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dismissed []uint64
+		if err := tx.Model(&models.Recommendation{}).
+			Where("user_id = ? AND recommended_by = ? AND dismissed = ?", userID, recommendedBy, true).
+			Pluck("media_item_id", &dismissed).Error; err != nil {
+			return fmt.Errorf("failed to get dismissed recommendations: %w", err)
+		}
+
+		if err := tx.Where("user_id = ? AND recommended_by = ? AND dismissed = ?", userID, recommendedBy, false).
+			Delete(&models.Recommendation{}).Error; err != nil {
+			return fmt.Errorf("failed to delete recommendations by recommender: %w", err)
+		}
+
+		skip := make(map[uint64]bool, len(dismissed))
+		for _, id := range dismissed {
+			skip[id] = true
+		}
+		kept := make([]*models.Recommendation, 0, len(recommendations))
+		for _, recommendation := range recommendations {
+			if recommendation.MediaItemID == 0 || !skip[recommendation.MediaItemID] {
+				kept = append(kept, recommendation)
+			}
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(kept).Error; err != nil {
+			return fmt.Errorf("failed to create recommendations in batch: %w", err)
+		}
+		return nil
+	})
+}
+
 // DeleteExpired deletes all expired recommendations
 func (r *recommendationRepository) DeleteExpired(ctx context.Context) error {
 	now := time.Now()
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"suasor/types/models"
+
+	"gorm.io/gorm"
+)
+
+// RecommendationFeedbackRepository stores the signals users give on their recommendations
+type RecommendationFeedbackRepository interface {
+	// Create stores a feedback signal
+	Create(ctx context.Context, feedback *models.RecommendationFeedback) error
+	// GetByUserID returns all feedback of a user, the oldest first
+	GetByUserID(ctx context.Context, userID uint64) ([]*models.RecommendationFeedback, error)
+}
+
+type recommendationFeedbackRepository struct {
+	db *gorm.DB
+}
+
+// NewRecommendationFeedbackRepository creates a new recommendation feedback repository
+func NewRecommendationFeedbackRepository(db *gorm.DB) RecommendationFeedbackRepository {
+	return &recommendationFeedbackRepository{db: db}
+}
+
+// Create stores a feedback signal
+func (r *recommendationFeedbackRepository) Create(ctx context.Context, feedback *models.RecommendationFeedback) error {
+	if err := r.db.WithContext(ctx).Create(feedback).Error; err != nil {
+		return fmt.Errorf("error creating recommendation feedback: %w", err)
+	}
+	return nil
+}
+
+// GetByUserID returns all feedback of a user, the oldest first
+func (r *recommendationFeedbackRepository) GetByUserID(ctx context.Context, userID uint64) ([]*models.RecommendationFeedback, error) {
+	var feedback []*models.RecommendationFeedback
+	result := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC, id ASC").
+		Find(&feedback)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting recommendation feedback for user %d: %w", userID, result.Error)
+	}
+	return feedback, nil
+}
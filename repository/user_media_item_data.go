@@ -40,6 +40,9 @@ type UserMediaItemDataRepository[T types.MediaData] interface {
 	// GetFavorites retrieves favorite media items for a user
 	GetFavorites(ctx context.Context, userID uint64, limit, offset int) ([]*models.UserMediaItemData[T], error)
 
+	// GetWatchlist retrieves media items on a user's watchlist
+	GetWatchlist(ctx context.Context, userID uint64, limit, offset int) ([]*models.UserMediaItemData[T], error)
+
 	HasUserViewedMedia(ctx context.Context, userID, mediaItemID uint64) (bool, error)
 
 	GetItemPlayCount(ctx context.Context, userID, mediaItemID uint64) (int, error)
@@ -393,6 +396,31 @@ func (r *userMediaItemDataRepository[T]) GetFavorites(ctx context.Context, userI
 	return favorites, nil
 }
 
+// GetWatchlist retrieves media items on a user's watchlist
+func (r *userMediaItemDataRepository[T]) GetWatchlist(ctx context.Context, userID uint64, limit, offset int) ([]*models.UserMediaItemData[T], error) {
+	var watchlist []*models.UserMediaItemData[T]
+
+	query := r.db.WithContext(ctx).Table("user_media_item_data").
+		Where("user_id = ? AND watchlist = ?", userID, true).
+		Order("updated_at DESC").
+		Limit(limit).Offset(offset)
+
+	result := query.Find(&watchlist)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get user watchlist: %w", result.Error)
+	}
+
+	// Load associated media items
+	for i := range watchlist {
+		if err := watchlist[i].LoadItem(r.db); err != nil {
+			// Log the error but continue
+			fmt.Printf("Error loading media item for watchlist entry %d: %v\n", watchlist[i].ID, err)
+		}
+	}
+
+	return watchlist, nil
+}
+
 // ClearUserHistory removes all data for a user
 func (r *userMediaItemDataRepository[T]) ClearUserHistory(ctx context.Context, userID uint64) error {
 	result := r.db.WithContext(ctx).Table("user_media_item_data").
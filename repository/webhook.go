@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"suasor/types/models"
+
+	"gorm.io/gorm"
+)
+
+// WebhookTokenRepository stores the secret tokens of clients' webhook URLs
+type WebhookTokenRepository interface {
+	// GetByClientID retrieves a client's webhook token, or nil if it has none
+	GetByClientID(ctx context.Context, clientID uint64) (*models.WebhookToken, error)
+	// Save creates or updates a webhook token
+	Save(ctx context.Context, token *models.WebhookToken) error
+}
+
+type webhookTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookTokenRepository creates a new webhook token repository
+func NewWebhookTokenRepository(db *gorm.DB) WebhookTokenRepository {
+	return &webhookTokenRepository{db: db}
+}
+
+// GetByClientID retrieves a client's webhook token, or nil if it has none
+func (r *webhookTokenRepository) GetByClientID(ctx context.Context, clientID uint64) (*models.WebhookToken, error) {
+	var token models.WebhookToken
+	result := r.db.WithContext(ctx).Where("client_id = ?", clientID).First(&token)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting webhook token: %w", result.Error)
+	}
+	return &token, nil
+}
+
+// Save creates or updates a webhook token
+func (r *webhookTokenRepository) Save(ctx context.Context, token *models.WebhookToken) error {
+	result := r.db.WithContext(ctx).Save(token)
+	if result.Error != nil {
+		return fmt.Errorf("error saving webhook token: %w", result.Error)
+	}
+	return nil
+}
@@ -38,6 +38,14 @@ func RegisterAIConversationRoutes(r *gin.RouterGroup, c *container.Container) {
 		userGroup.POST("/conversations/:conversationId/continue", handler.ContinueConversation)
 		userGroup.PUT("/conversations/:conversationId/archive", handler.ArchiveConversation)
 		userGroup.DELETE("/conversations/:conversationId", handler.DeleteConversation)
+		userGroup.GET("/conversations/:conversationId/memory", handler.GetConversationMemory)
+		userGroup.DELETE("/conversations/:conversationId/memory", handler.ResetConversationMemory)
+	}
+
+	// Streaming replies, relayed as Server-Sent Events
+	aiGroup := r.Group("/ai")
+	{
+		aiGroup.POST("/conversations/:conversationId/messages/stream", handler.StreamMessage)
 	}
 }
 
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"suasor/di/container"
+	"suasor/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAIUsageRoutes registers the admin routes for AI token spend and budgets
+func RegisterAIUsageRoutes(rg *gin.RouterGroup, c *container.Container) {
+	handler := container.MustGet[*handlers.AIUsageHandler](c)
+	ai := rg.Group("/ai")
+	{
+		ai.GET("/usage", handler.GetUsageReport)
+		ai.GET("/budgets", handler.GetBudgets)
+		ai.PUT("/budgets/global", handler.SetGlobalBudget)
+		ai.PUT("/budgets/user/:userID", handler.SetUserBudget)
+	}
+}
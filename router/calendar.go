@@ -0,0 +1,27 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"suasor/di/container"
+	"suasor/handlers"
+)
+
+// RegisterCalendarRoutes registers the release calendar routes
+func RegisterCalendarRoutes(unauth *gin.RouterGroup, auth *gin.RouterGroup, c *container.Container) {
+	handler := container.MustGet[*handlers.CalendarHandler](c)
+
+	// Calendar apps cannot log in, the feed is read-only and found by its secret token instead
+	unauthCalendar := unauth.Group("/calendar")
+	{
+		unauthCalendar.GET("/:token", handler.GetFeed)
+	}
+
+	calendar := auth.Group("/calendar")
+	{
+		// Upcoming releases from automation clients, metadata, recommendations and the watchlist
+		calendar.GET("", handler.GetCalendarItems)
+		// Secret ICS feed URL and its rotation
+		calendar.GET("/feed", handler.GetFeedURL)
+		calendar.POST("/feed/rotate", handler.RotateFeedToken)
+	}
+}
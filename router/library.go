@@ -0,0 +1,17 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"suasor/di/container"
+	"suasor/handlers"
+)
+
+// RegisterLibraryRoutes registers the media client library routes
+func RegisterLibraryRoutes(rg *gin.RouterGroup, c *container.Container) {
+	handler := container.MustGet[*handlers.LibraryHandler](c)
+	libraries := rg.Group("/libraries")
+	{
+		// Libraries available for sync selection on a client
+		libraries.GET("/:clientID", handler.GetLibraries)
+	}
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"suasor/di/container"
+	"suasor/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPromptTemplateAdminRoutes registers the admin routes for editing prompt templates
+func RegisterPromptTemplateAdminRoutes(rg *gin.RouterGroup, c *container.Container) {
+	handler := container.MustGet[*handlers.PromptTemplateHandler](c)
+	prompts := rg.Group("/ai/prompts")
+	{
+		prompts.GET("", handler.GetTemplates)
+		prompts.GET("/:name/versions", handler.GetVersions)
+		prompts.PUT("/:name", handler.SaveTemplate)
+		prompts.PUT("/:name/active", handler.ActivateVersion)
+	}
+}
+
+// RegisterPromptTemplateRoutes registers the routes users pick and write their AI chat personality with
+func RegisterPromptTemplateRoutes(rg *gin.RouterGroup, c *container.Container) {
+	handler := container.MustGet[*handlers.PromptTemplateHandler](c)
+	personalities := rg.Group("/user/ai/personalities")
+	{
+		personalities.GET("", handler.GetPersonalities)
+		personalities.PUT("/custom", handler.SaveCustomPersonality)
+	}
+}
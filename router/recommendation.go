@@ -11,6 +11,7 @@ import (
 func RegisterRecommendationRoutes(rg *gin.RouterGroup, c *container.Container) {
 	// Get the recommendation handler from dependencies
 	recommendationHandler := container.MustGet[*handlers.RecommendationHandler](c)
+	groupHandler := container.MustGet[*handlers.GroupRecommendationHandler](c)
 
 	recommendations := rg.Group("/recommendations")
 	{
@@ -25,5 +26,13 @@ func RegisterRecommendationRoutes(rg *gin.RouterGroup, c *container.Container) {
 		// Action routes
 		recommendations.POST("/view", recommendationHandler.MarkRecommendationAsViewed)
 		recommendations.POST("/rate", recommendationHandler.RateRecommendation)
+		recommendations.POST("/dismiss", recommendationHandler.DismissRecommendation)
+		recommendations.POST("/play", recommendationHandler.RecordRecommendationPlay)
+
+		// Group routes
+		recommendations.POST("/group", groupHandler.GetGroupRecommendations)
+		recommendations.GET("/groups", groupHandler.GetGroups)
+		recommendations.POST("/groups", groupHandler.CreateGroup)
+		recommendations.DELETE("/groups/:groupID", groupHandler.DeleteGroup)
 	}
 }
@@ -89,9 +89,25 @@ func Setup(ctx context.Context, c *container.Container) *gin.Engine {
 		// {base}/search/
 		RegisterSearchRoutes(authenticated, c) // Register search routes
 
+		// {base}/item/:id/similar
+		RegisterSimilarItemRoutes(authenticated, c) // Register similar item routes
+
+		// {base}/sessions/
+		RegisterSessionRoutes(authenticated, c) // Register now playing session routes
+
+		// {base}/libraries/
+		RegisterLibraryRoutes(authenticated, c) // Register media client library routes
+
+		// {base}/calendar/
+		RegisterCalendarRoutes(v1, authenticated, c) // Register release calendar and ICS feed routes
+
+		// {base}/webhooks/
+		RegisterWebhookRoutes(v1, authenticated, c) // Register media server webhook routes
+
 		// AI routes for clients and users
 		RegisterAIClientRoutes(ctx, authenticated, c)  // Register AI client routes (/client/:clientID/ai/...)
 		RegisterAIConversationRoutes(authenticated, c) // Register AI conversation history routes
+		RegisterPromptTemplateRoutes(authenticated, c) // Register AI chat personality routes (/user/ai/personalities)
 	}
 
 	//Admin Routes
@@ -102,6 +118,10 @@ func Setup(ctx context.Context, c *container.Container) *gin.Engine {
 		RegisterConfigRoutes(adminRoutes, configService)
 		// {base}/admin/client/
 		RegisterClientRoutes(ctx, adminRoutes, c)
+		// {base}/admin/ai/
+		RegisterAIUsageRoutes(adminRoutes, c)
+		// {base}/admin/ai/prompts/
+		RegisterPromptTemplateAdminRoutes(adminRoutes, c)
 		// {base}/admin/clients/
 		RegisterClientsRoutes(authenticated, c) // Register all clients route
 	}
@@ -0,0 +1,17 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"suasor/di/container"
+	"suasor/handlers"
+)
+
+// RegisterSessionRoutes registers the now playing session routes
+func RegisterSessionRoutes(rg *gin.RouterGroup, c *container.Container) {
+	handler := container.MustGet[*handlers.SessionHandler](c)
+	sessions := rg.Group("/sessions")
+	{
+		// Active sessions across all of the user's media clients
+		sessions.GET("", handler.GetSessions)
+	}
+}
@@ -0,0 +1,17 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"suasor/di/container"
+	"suasor/handlers"
+)
+
+// RegisterSimilarItemRoutes registers the "more like this" routes of media items
+func RegisterSimilarItemRoutes(rg *gin.RouterGroup, c *container.Container) {
+	handler := container.MustGet[*handlers.SimilarItemsHandler](c)
+	items := rg.Group("/item")
+	{
+		// Nearest neighbours by embedding, any media item type
+		items.GET("/:id/similar", handler.GetSimilarItems)
+	}
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"suasor/di/container"
+	"suasor/handlers"
+)
+
+// RegisterWebhookRoutes registers the media server webhook routes
+func RegisterWebhookRoutes(unauth *gin.RouterGroup, auth *gin.RouterGroup, c *container.Container) {
+	handler := container.MustGet[*handlers.WebhookHandler](c)
+
+	// Media servers cannot log in, these are verified with the per-client webhook token instead
+	unauthWebhooks := unauth.Group("/webhooks")
+	{
+		unauthWebhooks.POST("/:clientID", handler.ReceiveWebhook)
+	}
+
+	webhooks := auth.Group("/webhooks")
+	{
+		// Signed URL to configure on the media server
+		webhooks.GET("/:clientID/url", handler.GetWebhookURL)
+		webhooks.POST("/:clientID/url/rotate", handler.RotateWebhookToken)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"suasor/clients"
 	"suasor/clients/ai"
+	aitypes "suasor/clients/ai/types"
 	clienttypes "suasor/clients/types"
 	"suasor/repository"
 	"suasor/types/models"
@@ -15,6 +16,20 @@ import (
 	"time"
 )
 
+var (
+	// ErrConversationNotFound is returned when the conversation does not exist
+	ErrConversationNotFound = errors.New("conversation not found")
+	// ErrConversationAccessDenied is returned when the conversation belongs to another user
+	ErrConversationAccessDenied = errors.New("user does not own this conversation")
+	// ErrStreamingNotSupported is returned when the conversation's AI client cannot stream replies
+	ErrStreamingNotSupported = errors.New("AI client does not support streaming")
+	// ErrToolCallLimit is returned when the model keeps calling tools instead of replying
+	ErrToolCallLimit = errors.New("AI client exceeded the tool call limit")
+)
+
+// maxToolRounds bounds how many times the model may call tools before it has to reply
+const maxToolRounds = 5
+
 // AIConversationService defines the interface for AI conversation related operations
 type AIConversationService interface {
 	// Core conversation methods
@@ -22,10 +37,21 @@ type AIConversationService interface {
 		preferences map[string]any, systemInstructions string) (string, string, error)
 	StartConversationWithID(ctx context.Context, conversationID string, userID uint64, clientID uint64, contentType string,
 		preferences map[string]any, systemInstructions string, welcomeMessage string) (string, error)
+	// SendMessage lets the model call the tool registry before it replies when its client supports tools,
+	// set "useTools" to false in the context to turn them off
 	SendMessage(ctx context.Context, conversationID string, userID uint64, message string,
 		context map[string]any) (string, []map[string]any, error)
+	// SendMessageStream is SendMessage with the reply passed to onDelta as it is generated.
+	// The reply, recommendations and analytics are saved once the stream ends.
+	SendMessageStream(ctx context.Context, conversationID string, userID uint64, message string,
+		context map[string]any, onDelta aitypes.StreamHandler) (string, []map[string]any, error)
 	GetConversationHistory(ctx context.Context, conversationID string, userID uint64) ([]*models.AIMessage, error)
 
+	// Memory methods, a conversation nearing the model's context window has its older turns compacted
+	// into a summary with the titles the user liked and disliked
+	GetConversationMemory(ctx context.Context, conversationID string, userID uint64) (*models.ConversationMemory, error)
+	ResetConversationMemory(ctx context.Context, conversationID string, userID uint64) error
+
 	// User history methods
 	GetUserConversations(ctx context.Context, userID uint64, limit, offset int) ([]*models.AIConversation, int, error)
 	GetUserRecommendationHistory(ctx context.Context, userID uint64, itemType string, limit, offset int) ([]*models.AIRecommendation, int, error)
@@ -47,6 +73,8 @@ type aiConversationService struct {
 	ollamaClientService ClientService[*clienttypes.OllamaConfig]
 	clientHelper        repository.ClientHelper
 	clientFactory       *clients.ClientProviderFactoryService
+	tools               AIToolRegistry
+	usage               AIUsageService
 	activeClients       map[string]ai.ClientAI // conversationID -> client
 }
 
@@ -58,6 +86,8 @@ func NewAIConversationService(
 	ollamaClientService ClientService[*clienttypes.OllamaConfig],
 	clientHelper repository.ClientHelper,
 	clientFactory *clients.ClientProviderFactoryService,
+	tools AIToolRegistry,
+	usage AIUsageService,
 ) AIConversationService {
 	return &aiConversationService{
 		repo:                repo,
@@ -66,6 +96,8 @@ func NewAIConversationService(
 		ollamaClientService: ollamaClientService,
 		clientHelper:        clientHelper,
 		clientFactory:       clientFactory,
+		tools:               tools,
+		usage:               usage,
 		activeClients:       make(map[string]ai.ClientAI),
 	}
 }
@@ -93,6 +125,12 @@ func (s *aiConversationService) StartConversation(
 		return "", "", err
 	}
 
+	if err := s.usage.CheckBudget(ctx, userID); err != nil {
+		log.Warn().Err(err).Msg("AI budget check failed")
+		return "", "", err
+	}
+	ctx = s.usage.Track(ctx, userID, aiClient, models.AIFeatureConversation)
+
 	// Start conversation with the AI provider
 	conversationID, welcomeMessage, err := aiClient.StartRecommendationConversation(
 		ctx,
@@ -230,27 +268,245 @@ func (s *aiConversationService) SendMessage(
 		Str("message", message).
 		Msg("Sending message in AI conversation")
 
+	conversation, aiClient, messageContext, err := s.prepareMessage(ctx, conversationID, userID, message, messageContext, false)
+	if err != nil {
+		return "", nil, err
+	}
+	ctx = s.usage.Track(ctx, userID, aiClient, models.AIFeatureConversation)
+	s.compactConversation(ctx, conversation, aiClient, message)
+
+	// Send message to AI client, with tools when it can call them unless the caller turned them off
+	useTools, ok := messageContext["useTools"].(bool)
+	useTools = (useTools || !ok) && s.tools != nil && aiClient.GetCapabilities().SupportsToolCalling
+
+	startTime := time.Now()
+	var aiResponse string
+	var recommendations []map[string]any
+	if useTools {
+		aiResponse, recommendations, err = s.continueWithTools(ctx, conversation, aiClient, message, messageContext)
+	} else {
+		aiResponse, recommendations, err = aiClient.ContinueRecommendationConversation(
+			ctx,
+			conversationID,
+			message,
+			messageContext,
+		)
+	}
+	responseDuration := time.Since(startTime)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to continue conversation with AI")
+		return "", nil, err
+	}
+
+	// Add response time and other metadata
+	metadata := map[string]any{
+		"responseTime": responseDuration.Milliseconds(),
+	}
+	s.saveReply(ctx, conversation, aiResponse, recommendations, metadata)
+
+	// Update analytics
+	s.updateConversationAnalytics(ctx, conversationID, "user", responseDuration)
+	s.updateConversationAnalytics(ctx, conversationID, "assistant", responseDuration)
+
+	return aiResponse, recommendations, nil
+}
+
+// SendMessageStream sends a message in an existing conversation, passing the reply to onDelta as it is generated
+func (s *aiConversationService) SendMessageStream(
+	ctx context.Context,
+	conversationID string,
+	userID uint64,
+	message string,
+	messageContext map[string]any,
+	onDelta aitypes.StreamHandler,
+) (string, []map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("conversationID", conversationID).
+		Uint64("userID", userID).
+		Str("message", message).
+		Msg("Streaming message in AI conversation")
+
+	conversation, aiClient, messageContext, err := s.prepareMessage(ctx, conversationID, userID, message, messageContext, true)
+	if err != nil {
+		return "", nil, err
+	}
+	ctx = s.usage.Track(ctx, userID, aiClient, models.AIFeatureConversation)
+	s.compactConversation(ctx, conversation, aiClient, message)
+
+	// Track how long the first token took, that is the wait the user actually sees
+	startTime := time.Now()
+	var firstDelta time.Duration
+	aiResponse, recommendations, err := aiClient.StreamRecommendationConversation(
+		ctx,
+		conversationID,
+		message,
+		messageContext,
+		func(delta string) error {
+			if firstDelta == 0 {
+				firstDelta = time.Since(startTime)
+			}
+			return onDelta(delta)
+		},
+	)
+	responseDuration := time.Since(startTime)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to stream conversation with AI")
+		return "", nil, err
+	}
+
+	metadata := map[string]any{
+		"responseTime":   responseDuration.Milliseconds(),
+		"firstTokenTime": firstDelta.Milliseconds(),
+		"streamed":       true,
+	}
+	s.saveReply(ctx, conversation, aiResponse, recommendations, metadata)
+
+	s.updateConversationAnalytics(ctx, conversationID, "user", responseDuration)
+	s.updateConversationAnalytics(ctx, conversationID, "assistant", responseDuration)
+
+	return aiResponse, recommendations, nil
+}
+
+// continueWithTools sends the message with the registry's tools and runs the calls the model makes
+// until it replies with text. Every call is saved in the conversation history as a "tool" message.
+func (s *aiConversationService) continueWithTools(
+	ctx context.Context,
+	conversation *models.AIConversation,
+	aiClient ai.ClientAI,
+	message string,
+	messageContext map[string]any,
+) (string, []map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+	tools := s.tools.Tools()
+
+	turn, err := aiClient.ContinueConversationWithTools(ctx, conversation.ID, message, nil, tools)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to continue conversation with tools")
+		return "", nil, err
+	}
+
+	for round := 0; len(turn.ToolCalls) > 0; round++ {
+		if round == maxToolRounds {
+			return "", nil, ErrToolCallLimit
+		}
+
+		results := make([]aitypes.ToolResult, 0, len(turn.ToolCalls))
+		for _, call := range turn.ToolCalls {
+			result := s.tools.Execute(ctx, conversation.UserID, call)
+			s.saveToolCall(ctx, conversation.ID, call, result)
+			results = append(results, result)
+		}
+
+		turn, err = aiClient.ContinueConversationWithTools(ctx, conversation.ID, "", results, tools)
+		if err != nil {
+			log.Error().Err(err).Int("round", round).Msg("Failed to send tool results")
+			return "", nil, err
+		}
+	}
+
+	var recommendations []map[string]any
+	if extract, _ := messageContext["extractRecommendations"].(bool); extract {
+		recommendations = s.extractRecommendations(ctx, aiClient, turn.Text, conversation.ContentType)
+	}
+
+	return turn.Text, recommendations, nil
+}
+
+// saveToolCall records a tool call and its result in the conversation history
+func (s *aiConversationService) saveToolCall(ctx context.Context, conversationID string, call aitypes.ToolCall, result aitypes.ToolResult) {
+	log := logger.LoggerFromContext(ctx)
+
+	toolMsg := models.NewAIMessage(
+		utils.GenerateRandomID(16),
+		conversationID,
+		"tool",
+		result.Content,
+	)
+	metadata := map[string]any{
+		"toolCallId": call.ID,
+		"tool":       call.Name,
+		"arguments":  string(call.Arguments),
+		"isError":    result.IsError,
+	}
+	if err := toolMsg.SetMetadataMap(metadata); err != nil {
+		log.Warn().Err(err).Msg("Failed to set tool message metadata")
+	}
+
+	if _, err := s.repo.AddMessage(ctx, toolMsg); err != nil {
+		log.Error().Err(err).Str("tool", call.Name).Msg("Failed to save tool call")
+	}
+}
+
+// extractRecommendations asks the AI client for the recommendations made in a reply as structured data
+func (s *aiConversationService) extractRecommendations(ctx context.Context, aiClient ai.ClientAI, text string, contentType string) []map[string]any {
+	log := logger.LoggerFromContext(ctx)
+
+	prompt := fmt.Sprintf(
+		"From the following assistant response, extract the %s recommendations as structured data:\n\n%s\n\n"+
+			"Return a JSON object with a \"recommendations\" array. Each object should have appropriate fields for %s items.",
+		contentType, text, contentType)
+
+	var output struct {
+		Recommendations []map[string]any `json:"recommendations"`
+	}
+	err := aiClient.GenerateStructured(ctx, prompt, &output, &aitypes.GenerationOptions{
+		Temperature:        0.1,
+		MaxTokens:          1000,
+		SystemInstructions: "You are a helpful data extraction assistant. Your job is to extract structured recommendations from text.",
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to extract recommendations from reply")
+		return nil
+	}
+
+	return output.Recommendations
+}
+
+// prepareMessage checks the user owns the conversation and has tokens left, gets its AI client and saves the user's message.
+// With requireStreaming the message is refused before it is saved when the client can't stream.
+// It returns the message context with recommendation extraction enabled unless the caller disabled it.
+func (s *aiConversationService) prepareMessage(
+	ctx context.Context,
+	conversationID string,
+	userID uint64,
+	message string,
+	messageContext map[string]any,
+	requireStreaming bool,
+) (*models.AIConversation, ai.ClientAI, map[string]any, error) {
+	log := logger.LoggerFromContext(ctx)
+
 	// Get the conversation
 	conversation, err := s.repo.GetConversationByID(ctx, conversationID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get conversation")
-		return "", nil, err
+		return nil, nil, nil, err
 	}
 
 	if conversation == nil {
-		return "", nil, errors.New("conversation not found")
+		return nil, nil, nil, ErrConversationNotFound
 	}
 
 	// Verify user owns the conversation
 	if conversation.UserID != userID {
-		return "", nil, errors.New("user does not own this conversation")
+		return nil, nil, nil, ErrConversationAccessDenied
 	}
 
 	// Get the AI client
 	aiClient, err := s.getClientForConversation(ctx, conversationID, conversation.ClientID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get AI client for conversation")
-		return "", nil, err
+		return nil, nil, nil, err
+	}
+
+	if requireStreaming && !aiClient.GetCapabilities().SupportsStreaming {
+		return nil, nil, nil, ErrStreamingNotSupported
+	}
+
+	// Refuse the message before saving it when the user is out of tokens
+	if err := s.usage.CheckBudget(ctx, userID); err != nil {
+		log.Warn().Err(err).Msg("AI budget check failed")
+		return nil, nil, nil, err
 	}
 
 	// Save user message to database
@@ -275,19 +531,20 @@ func (s *aiConversationService) SendMessage(
 		messageContext["extractRecommendations"] = true
 	}
 
-	// Send message to AI client
-	startTime := time.Now()
-	aiResponse, recommendations, err := aiClient.ContinueRecommendationConversation(
-		ctx,
-		conversationID,
-		message,
-		messageContext,
-	)
-	responseDuration := time.Since(startTime)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to continue conversation with AI")
-		return "", nil, err
-	}
+	return conversation, aiClient, messageContext, nil
+}
+
+// saveReply saves the AI response and the recommendations extracted from it
+func (s *aiConversationService) saveReply(
+	ctx context.Context,
+	conversation *models.AIConversation,
+	aiResponse string,
+	recommendations []map[string]any,
+	metadata map[string]any,
+) {
+	log := logger.LoggerFromContext(ctx)
+	conversationID := conversation.ID
+	userID := conversation.UserID
 
 	// Save AI response to database
 	aiMsg := models.NewAIMessage(
@@ -296,11 +553,6 @@ func (s *aiConversationService) SendMessage(
 		"assistant",
 		aiResponse,
 	)
-
-	// Add response time and other metadata
-	metadata := map[string]any{
-		"responseTime": responseDuration.Milliseconds(),
-	}
 	if err := aiMsg.SetMetadataMap(metadata); err != nil {
 		log.Warn().Err(err).Msg("Failed to set message metadata")
 	}
@@ -366,11 +618,6 @@ func (s *aiConversationService) SendMessage(
 			}
 		}
 	}
-
-	// Update analytics
-	s.updateConversationAnalytics(ctx, conversationID, "user", responseDuration)
-
-	return aiResponse, recommendations, nil
 }
 
 // GetConversationHistory retrieves the message history for a conversation
@@ -393,12 +640,12 @@ func (s *aiConversationService) GetConversationHistory(
 	}
 
 	if conversation == nil {
-		return nil, errors.New("conversation not found")
+		return nil, ErrConversationNotFound
 	}
 
 	// Verify user owns the conversation
 	if conversation.UserID != userID {
-		return nil, errors.New("user does not own this conversation")
+		return nil, ErrConversationAccessDenied
 	}
 
 	// Get messages
@@ -485,12 +732,12 @@ func (s *aiConversationService) ArchiveConversation(
 	}
 
 	if conversation == nil {
-		return errors.New("conversation not found")
+		return ErrConversationNotFound
 	}
 
 	// Verify user owns the conversation
 	if conversation.UserID != userID {
-		return errors.New("user does not own this conversation")
+		return ErrConversationAccessDenied
 	}
 
 	// Update status
@@ -517,12 +764,12 @@ func (s *aiConversationService) DeleteConversation(
 	}
 
 	if conversation == nil {
-		return errors.New("conversation not found")
+		return ErrConversationNotFound
 	}
 
 	// Verify user owns the conversation
 	if conversation.UserID != userID {
-		return errors.New("user does not own this conversation")
+		return ErrConversationAccessDenied
 	}
 
 	// Remove from active clients if present
@@ -552,12 +799,12 @@ func (s *aiConversationService) GetConversationInsights(
 	}
 
 	if conversation == nil {
-		return nil, errors.New("conversation not found")
+		return nil, ErrConversationNotFound
 	}
 
 	// Verify user owns the conversation
 	if conversation.UserID != userID {
-		return nil, errors.New("user does not own this conversation")
+		return nil, ErrConversationAccessDenied
 	}
 
 	// Get analytics
@@ -719,6 +966,21 @@ func (s *aiConversationService) getAIClient(ctx context.Context, clientID uint64
 	return aiClient, nil
 }
 
+// getOwnedConversation returns the conversation if it exists and belongs to the user
+func (s *aiConversationService) getOwnedConversation(ctx context.Context, conversationID string, userID uint64) (*models.AIConversation, error) {
+	conversation, err := s.repo.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation == nil {
+		return nil, ErrConversationNotFound
+	}
+	if conversation.UserID != userID {
+		return nil, ErrConversationAccessDenied
+	}
+	return conversation, nil
+}
+
 // getClientForConversation retrieves the client for a specific conversation
 func (s *aiConversationService) getClientForConversation(ctx context.Context, conversationID string, clientID uint64) (ai.ClientAI, error) {
 	// Check if we have an active client
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"suasor/clients/ai"
+	aitypes "suasor/clients/ai/types"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"time"
+)
+
+const (
+	// compactionThreshold is the share of the model's context window a conversation may fill before it is compacted
+	compactionThreshold = 0.75
+	// liveWindowShare is the share of the context window the recent messages are kept in after a compaction
+	liveWindowShare = 0.4
+	// minLiveMessages is the number of recent messages always sent as they are
+	minLiveMessages = 4
+	// charsPerToken estimates the tokens of a text, the AI clients have no tokenizer
+	charsPerToken = 4
+)
+
+// GetConversationMemory returns the memory of a conversation's compacted turns, nil if it was never compacted
+func (s *aiConversationService) GetConversationMemory(ctx context.Context, conversationID string, userID uint64) (*models.ConversationMemory, error) {
+	conversation, err := s.getOwnedConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return conversation.Memory, nil
+}
+
+// ResetConversationMemory forgets the summary and preferences of the compacted turns.
+// The turns stay compacted, the model only gets the messages since.
+func (s *aiConversationService) ResetConversationMemory(ctx context.Context, conversationID string, userID uint64) error {
+	log := logger.LoggerFromContext(ctx)
+
+	conversation, err := s.getOwnedConversation(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	if conversation.Memory == nil {
+		return nil
+	}
+
+	memory := &models.ConversationMemory{
+		CompactedMessages: conversation.Memory.CompactedMessages,
+		CompactedAt:       conversation.Memory.CompactedAt,
+	}
+	if err := s.repo.UpdateConversationMemory(ctx, conversationID, memory); err != nil {
+		return err
+	}
+
+	// A conversation the client doesn't hold starts from the stored memory anyway
+	aiClient, ok := s.activeClients[conversationID]
+	if !ok {
+		return nil
+	}
+	messages, err := s.repo.GetConversationHistory(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	history := chatHistory(messages[min(memory.CompactedMessages, len(messages)):])
+	if err := aiClient.CompactConversation(ctx, conversationID, "", history); err != nil && !errors.Is(err, ai.ErrFeatureNotSupported) {
+		log.Warn().Err(err).Str("conversationID", conversationID).Msg("Failed to reset the client's conversation memory")
+	}
+
+	return nil
+}
+
+// compactConversation summarises the older turns of a conversation that nears the model's context window.
+// The summary and the liked and disliked titles are stored on the conversation and the client keeps only
+// the summary and the recent messages. The message being sent was already saved and is left to the client.
+// Failing to compact is logged, the message is sent with the full history.
+func (s *aiConversationService) compactConversation(ctx context.Context, conversation *models.AIConversation, aiClient ai.ClientAI, message string) {
+	log := logger.LoggerFromContext(ctx).With().Str("conversationID", conversation.ID).Logger()
+
+	contextTokens := aiClient.GetCapabilities().MaxContextTokens
+	if contextTokens <= 0 {
+		return
+	}
+
+	messages, err := s.repo.GetConversationHistory(ctx, conversation.ID)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to get conversation history for compaction")
+		return
+	}
+	if n := len(messages); n > 0 && messages[n-1].Role == "user" && messages[n-1].Content == message {
+		messages = messages[:n-1]
+	}
+
+	compacted := 0
+	if conversation.Memory != nil {
+		compacted = min(conversation.Memory.CompactedMessages, len(messages))
+	}
+
+	used := estimateTokens(conversation.SystemPrompt) + estimateTokens(memoryPrompt(conversation.Memory)) + estimateTokens(message)
+	for _, msg := range messages[compacted:] {
+		used += estimateTokens(msg.Content)
+	}
+	if used < int(float64(contextTokens)*compactionThreshold) {
+		return
+	}
+
+	split := liveWindowStart(messages, int(float64(contextTokens)*liveWindowShare))
+	if split <= compacted {
+		return
+	}
+
+	log.Info().
+		Int("estimatedTokens", used).
+		Int("contextTokens", contextTokens).
+		Int("compactedMessages", split).
+		Msg("Compacting conversation")
+
+	memory, err := s.summarizeTurns(ctx, aiClient, conversation.Memory, messages[compacted:split])
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to summarise conversation, sending the full history")
+		return
+	}
+	memory.CompactedMessages = split
+	memory.CompactedAt = time.Now()
+
+	if err := s.repo.UpdateConversationMemory(ctx, conversation.ID, memory); err != nil {
+		log.Warn().Err(err).Msg("Failed to save conversation memory")
+		return
+	}
+	conversation.Memory = memory
+
+	if err := aiClient.CompactConversation(ctx, conversation.ID, memoryPrompt(memory), chatHistory(messages[split:])); err != nil {
+		log.Warn().Err(err).Msg("AI client could not compact the conversation")
+	}
+}
+
+// summarizeTurns asks the model to fold the turns into the previous memory
+func (s *aiConversationService) summarizeTurns(ctx context.Context, aiClient ai.ClientAI, previous *models.ConversationMemory, turns []*models.AIMessage) (*models.ConversationMemory, error) {
+	var prompt strings.Builder
+	prompt.WriteString("Summarise the following part of a media recommendation conversation so it can be continued without it. ")
+	prompt.WriteString("Keep what the user asked for, what was recommended and how the user reacted. ")
+	prompt.WriteString("List the titles the user said they liked and the ones they disliked or did not want.\n\n")
+	if previous != nil && previous.Summary != "" {
+		prompt.WriteString("Summary of the conversation before this part:\n")
+		prompt.WriteString(previous.Summary)
+		prompt.WriteString("\n\n")
+	}
+	prompt.WriteString("Conversation:\n")
+	for _, msg := range chatHistory(turns) {
+		fmt.Fprintf(&prompt, "%s: %s\n", msg.Role, msg.Content)
+	}
+	prompt.WriteString("\nReturn a JSON object with a \"summary\" string covering the earlier summary and this part, ")
+	prompt.WriteString("and \"likedTitles\" and \"dislikedTitles\" arrays of titles.")
+
+	var output struct {
+		Summary        string   `json:"summary"`
+		LikedTitles    []string `json:"likedTitles"`
+		DislikedTitles []string `json:"dislikedTitles"`
+	}
+	err := aiClient.GenerateStructured(ctx, prompt.String(), &output, &aitypes.GenerationOptions{
+		Temperature:        0.2,
+		MaxTokens:          1000,
+		SystemInstructions: "You are a helpful assistant that summarises conversations accurately and concisely.",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if output.Summary == "" {
+		return nil, fmt.Errorf("AI client returned an empty summary")
+	}
+
+	memory := &models.ConversationMemory{Summary: output.Summary}
+	if previous != nil {
+		memory.LikedTitles = previous.LikedTitles
+		memory.DislikedTitles = previous.DislikedTitles
+	}
+	// A title the user changed their mind about moves to the other list
+	memory.LikedTitles = mergeTitles(removeTitles(memory.LikedTitles, output.DislikedTitles), output.LikedTitles)
+	memory.DislikedTitles = mergeTitles(removeTitles(memory.DislikedTitles, output.LikedTitles), output.DislikedTitles)
+
+	return memory, nil
+}
+
+// liveWindowStart returns the index of the first message kept as it is, the most recent messages that fit in
+// the token budget but at least minLiveMessages of them. The window starts with a user message.
+func liveWindowStart(messages []*models.AIMessage, budget int) int {
+	start := len(messages)
+	tokens, kept := 0, 0
+	for start > 0 {
+		msg := messages[start-1]
+		if msg.Role == "user" || msg.Role == "assistant" {
+			if kept >= minLiveMessages && tokens+estimateTokens(msg.Content) > budget {
+				break
+			}
+			tokens += estimateTokens(msg.Content)
+			kept++
+		}
+		start--
+	}
+	for start < len(messages) && messages[start].Role != "user" {
+		start++
+	}
+	return start
+}
+
+// chatHistory returns the user and assistant messages, tool calls are only kept for the turn that made them
+func chatHistory(messages []*models.AIMessage) []aitypes.ChatMessage {
+	history := make([]aitypes.ChatMessage, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Role == "user" || msg.Role == "assistant" {
+			history = append(history, aitypes.ChatMessage{Role: msg.Role, Content: msg.Content})
+		}
+	}
+	return history
+}
+
+// memoryPrompt is the memory as the model gets it with the system instructions
+func memoryPrompt(memory *models.ConversationMemory) string {
+	if memory == nil || (memory.Summary == "" && len(memory.LikedTitles) == 0 && len(memory.DislikedTitles) == 0) {
+		return ""
+	}
+
+	var b strings.Builder
+	if memory.Summary != "" {
+		b.WriteString("Summary of the earlier conversation:\n")
+		b.WriteString(memory.Summary)
+		b.WriteString("\n")
+	}
+	if len(memory.LikedTitles) > 0 {
+		fmt.Fprintf(&b, "The user liked: %s\n", strings.Join(memory.LikedTitles, ", "))
+	}
+	if len(memory.DislikedTitles) > 0 {
+		fmt.Fprintf(&b, "The user disliked: %s\n", strings.Join(memory.DislikedTitles, ", "))
+	}
+	return b.String()
+}
+
+// estimateTokens estimates the tokens of a text from its length
+func estimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// mergeTitles adds the titles missing from the list, comparing them case-insensitively
+func mergeTitles(titles []string, added []string) []string {
+	merged := append([]string{}, titles...)
+	for _, title := range added {
+		if title = strings.TrimSpace(title); title != "" && !containsTitle(merged, title) {
+			merged = append(merged, title)
+		}
+	}
+	return merged
+}
+
+// removeTitles returns the titles that are not in removed, comparing them case-insensitively
+func removeTitles(titles []string, removed []string) []string {
+	var kept []string
+	for _, title := range titles {
+		if !containsTitle(removed, title) {
+			kept = append(kept, title)
+		}
+	}
+	return kept
+}
+
+func containsTitle(titles []string, title string) bool {
+	for _, t := range titles {
+		if strings.EqualFold(strings.TrimSpace(t), strings.TrimSpace(title)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"suasor/types/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveWindowStart(t *testing.T) {
+	long := strings.Repeat("x", 400) // 100 tokens
+	messages := []*models.AIMessage{
+		{Role: "assistant", Content: "Welcome"},
+		{Role: "user", Content: long},
+		{Role: "assistant", Content: long},
+		{Role: "user", Content: long},
+		{Role: "tool", Content: long},
+		{Role: "assistant", Content: long},
+		{Role: "user", Content: long},
+		{Role: "assistant", Content: long},
+	}
+
+	// The minimum number of messages is kept even over the budget
+	assert.Equal(t, 3, liveWindowStart(messages, 0))
+	// The window grows with the budget but starts with a user message
+	assert.Equal(t, 1, liveWindowStart(messages, 10000))
+	assert.Equal(t, 3, liveWindowStart(messages, 450))
+}
+
+func TestMemoryTitles(t *testing.T) {
+	liked := mergeTitles([]string{"Arrival"}, []string{"arrival", " Dune ", ""})
+	assert.Equal(t, []string{"Arrival", "Dune"}, liked)
+
+	// A title the user changed their mind about leaves the other list
+	assert.Equal(t, []string{"Dune"}, removeTitles(liked, []string{"ARRIVAL"}))
+
+	prompt := memoryPrompt(&models.ConversationMemory{Summary: "Wants slow science fiction.", LikedTitles: liked})
+	assert.Contains(t, prompt, "Wants slow science fiction.")
+	assert.Contains(t, prompt, "The user liked: Arrival, Dune")
+	assert.NotContains(t, prompt, "disliked")
+	assert.Empty(t, memoryPrompt(nil))
+}
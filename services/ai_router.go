@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"suasor/clients"
+	"suasor/clients/ai"
+	clienttypes "suasor/clients/types"
+	"suasor/repository"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"time"
+)
+
+// ErrNoAIClient is returned when the user has no enabled AI client for a task
+var ErrNoAIClient = errors.New("no AI client available")
+
+// defaultRoute is the order AI client types are tried in when the policy has no route for a task
+var defaultRoute = []string{
+	string(clienttypes.ClientTypeClaude),
+	string(clienttypes.ClientTypeOpenAI),
+	string(clienttypes.ClientTypeOllama),
+}
+
+// AIRouter picks the AI clients of a task from the routing policy, falling back to the next client when one fails.
+// The server policy is the "ai.routing" config, a user's AIModelPreferences.Routing overrides it.
+type AIRouter interface {
+	// Clients returns the user's enabled AI clients for the task in the order they are tried
+	Clients(ctx context.Context, userID uint64, task models.AIFeature) ([]ai.ClientAI, error)
+	// Route calls fn with the task's clients in turn until one succeeds. A client that fails or runs out of
+	// its attempt timeout falls back to the next one, the error of the last client is returned if all fail.
+	Route(ctx context.Context, userID uint64, task models.AIFeature, fn func(ctx context.Context, client ai.ClientAI) error) error
+}
+
+type aiRouter struct {
+	configService  ConfigService
+	userConfigRepo repository.UserConfigRepository
+	clientRepos    repobundles.ClientRepositories
+	clientHelper   repository.ClientHelper
+	clientFactory  *clients.ClientProviderFactoryService
+}
+
+// NewAIRouter creates a new AI router
+func NewAIRouter(
+	configService ConfigService,
+	userConfigRepo repository.UserConfigRepository,
+	clientRepos repobundles.ClientRepositories,
+	clientHelper repository.ClientHelper,
+	clientFactory *clients.ClientProviderFactoryService,
+) AIRouter {
+	return &aiRouter{
+		configService:  configService,
+		userConfigRepo: userConfigRepo,
+		clientRepos:    clientRepos,
+		clientHelper:   clientHelper,
+		clientFactory:  clientFactory,
+	}
+}
+
+// routedClient is an AI client configuration in routing order
+type routedClient struct {
+	id     uint64
+	config clienttypes.ClientConfig
+}
+
+// Clients returns the user's enabled AI clients for the task in the order they are tried
+func (r *aiRouter) Clients(ctx context.Context, userID uint64, task models.AIFeature) ([]ai.ClientAI, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	configs, err := r.routeConfigs(ctx, userID, task)
+	if err != nil {
+		return nil, err
+	}
+
+	aiClients := make([]ai.ClientAI, 0, len(configs))
+	for _, config := range configs {
+		client, err := r.clientFactory.GetClient(ctx, config.id, config.config)
+		if err != nil {
+			log.Warn().Err(err).Uint64("clientID", config.id).Msg("Failed to create AI client, skipping it")
+			continue
+		}
+		aiClient, ok := client.(ai.ClientAI)
+		if !ok {
+			continue
+		}
+		aiClients = append(aiClients, aiClient)
+	}
+
+	return aiClients, nil
+}
+
+// Route calls fn with the task's clients in turn until one succeeds
+func (r *aiRouter) Route(ctx context.Context, userID uint64, task models.AIFeature, fn func(ctx context.Context, client ai.ClientAI) error) error {
+	aiClients, err := r.Clients(ctx, userID, task)
+	if err != nil {
+		return err
+	}
+	if len(aiClients) == 0 {
+		return fmt.Errorf("%w for %s for user %d", ErrNoAIClient, task, userID)
+	}
+
+	return r.tryClients(ctx, task, aiClients, fn)
+}
+
+// tryClients calls fn with the clients in order until one succeeds, the error of the last client is returned if all fail
+func (r *aiRouter) tryClients(ctx context.Context, task models.AIFeature, aiClients []ai.ClientAI, fn func(ctx context.Context, client ai.ClientAI) error) error {
+	log := logger.LoggerFromContext(ctx)
+	timeout := time.Duration(r.configService.GetConfig().AI.AttemptTimeout) * time.Second
+
+	var lastErr error
+	for i, client := range aiClients {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		err := fn(attemptCtx, client)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		// Stop when the caller gave up or the user is out of tokens, the next client would fail the same way
+		if ctx.Err() != nil || errors.Is(err, ErrAIBudgetExceeded) {
+			return err
+		}
+
+		lastErr = err
+		event := log.Warn().Err(err).
+			Str("task", string(task)).
+			Uint64("clientID", client.GetClientID()).
+			Str("clientType", client.GetClientType().String()).
+			Bool("timedOut", errors.Is(err, context.DeadlineExceeded))
+		if i < len(aiClients)-1 {
+			event.Msg("AI client failed, falling back to the next client")
+		} else {
+			event.Msg("AI client failed, no clients left to fall back to")
+		}
+	}
+
+	return lastErr
+}
+
+// routeConfigs returns the configurations of the task's clients in routing order.
+// A user route for the task replaces the policy, otherwise the user's default AI client goes first.
+func (r *aiRouter) routeConfigs(ctx context.Context, userID uint64, task models.AIFeature) ([]routedClient, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	var route []string
+	var defaultClientID uint64
+	userConfig, err := r.userConfigRepo.GetUserConfig(ctx, userID)
+	if err != nil {
+		log.Warn().Err(err).Uint64("userID", userID).Msg("Failed to get user config, using the server AI routing policy")
+	} else if userConfig != nil {
+		if userConfig.AIModelPreferences != nil {
+			route = userConfig.AIModelPreferences.Routing[string(task)]
+		}
+		if len(route) == 0 && userConfig.DefaultClients != nil {
+			defaultClientID = userConfig.DefaultClients.AIClientID
+		}
+	}
+	if len(route) == 0 {
+		route = r.policyRoute(task)
+	}
+
+	var configs []routedClient
+	seen := make(map[uint64]bool)
+	add := func(candidates []routedClient) {
+		for _, candidate := range candidates {
+			if !seen[candidate.id] {
+				seen[candidate.id] = true
+				configs = append(configs, candidate)
+			}
+		}
+	}
+
+	if defaultClientID != 0 {
+		clientType, err := r.clientHelper.GetClientTypeByClientID(ctx, defaultClientID)
+		if err != nil {
+			log.Warn().Err(err).Uint64("clientID", defaultClientID).Msg("Failed to get the default AI client")
+		} else {
+			candidates, err := r.clientsOfType(ctx, userID, clientType)
+			if err != nil {
+				return nil, err
+			}
+			for _, candidate := range candidates {
+				if candidate.id == defaultClientID {
+					add([]routedClient{candidate})
+				}
+			}
+		}
+	}
+
+	for _, clientType := range route {
+		candidates, err := r.clientsOfType(ctx, userID, clienttypes.ClientType(clientType))
+		if err != nil {
+			return nil, err
+		}
+		add(candidates)
+	}
+
+	return configs, nil
+}
+
+// policyRoute returns the server's route for the task
+func (r *aiRouter) policyRoute(task models.AIFeature) []string {
+	routing := r.configService.GetConfig().AI.Routing
+	if route := routing[string(task)]; len(route) > 0 {
+		return route
+	}
+	if route := routing["default"]; len(route) > 0 {
+		return route
+	}
+	return defaultRoute
+}
+
+// clientsOfType returns the user's enabled AI clients of a type, the lowest ID first
+func (r *aiRouter) clientsOfType(ctx context.Context, userID uint64, clientType clienttypes.ClientType) ([]routedClient, error) {
+	switch clientType {
+	case clienttypes.ClientTypeClaude:
+		return enabledUserClients(ctx, r.clientRepos.ClaudeRepo(), userID)
+	case clienttypes.ClientTypeOpenAI:
+		return enabledUserClients(ctx, r.clientRepos.OpenAIRepo(), userID)
+	case clienttypes.ClientTypeOllama:
+		return enabledUserClients(ctx, r.clientRepos.OllamaRepo(), userID)
+	default:
+		return nil, nil
+	}
+}
+
+func enabledUserClients[T clienttypes.ClientConfig](ctx context.Context, repo repository.ClientRepository[T], userID uint64) ([]routedClient, error) {
+	clientList, err := repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []routedClient
+	for _, client := range clientList {
+		if client.IsEnabled {
+			configs = append(configs, routedClient{id: client.ID, config: client.Config})
+		}
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].id < configs[j].id
+	})
+	return configs, nil
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"suasor/clients/ai"
+	clienttypes "suasor/clients/types"
+	"suasor/repository"
+	repobundles "suasor/repository/bundles"
+	"suasor/types"
+	"suasor/types/models"
+)
+
+type mockRouterConfigService struct {
+	ConfigService
+	config *types.Configuration
+}
+
+func (m *mockRouterConfigService) GetConfig() *types.Configuration {
+	return m.config
+}
+
+type mockRouterUserConfigRepository struct {
+	repository.UserConfigRepository
+	configs map[uint64]*models.UserConfig
+}
+
+func (m *mockRouterUserConfigRepository) GetUserConfig(ctx context.Context, userID uint64) (*models.UserConfig, error) {
+	return m.configs[userID], nil
+}
+
+type mockRouterClientHelper struct {
+	types map[uint64]clienttypes.ClientType
+}
+
+func (m *mockRouterClientHelper) GetClientTypeByClientID(ctx context.Context, clientID uint64) (clienttypes.ClientType, error) {
+	return m.types[clientID], nil
+}
+
+type mockAIClientRepository[T clienttypes.ClientConfig] struct {
+	repository.ClientRepository[T]
+	clients []*models.Client[T]
+}
+
+func (m *mockAIClientRepository[T]) GetByUserID(ctx context.Context, userID uint64) ([]*models.Client[T], error) {
+	return m.clients, nil
+}
+
+type mockRouterClientRepositories struct {
+	repobundles.ClientRepositories
+	claude *mockAIClientRepository[*clienttypes.ClaudeConfig]
+	openai *mockAIClientRepository[*clienttypes.OpenAIConfig]
+	ollama *mockAIClientRepository[*clienttypes.OllamaConfig]
+}
+
+func (m *mockRouterClientRepositories) ClaudeRepo() repository.ClientRepository[*clienttypes.ClaudeConfig] {
+	return m.claude
+}
+
+func (m *mockRouterClientRepositories) OpenAIRepo() repository.ClientRepository[*clienttypes.OpenAIConfig] {
+	return m.openai
+}
+
+func (m *mockRouterClientRepositories) OllamaRepo() repository.ClientRepository[*clienttypes.OllamaConfig] {
+	return m.ollama
+}
+
+func aiClientRecord[T clienttypes.ClientConfig](id uint64, enabled bool) *models.Client[T] {
+	client := &models.Client[T]{IsEnabled: enabled}
+	client.ID = id
+	return client
+}
+
+type fakeRoutedClientAI struct {
+	ai.ClientAI
+	id uint64
+}
+
+func (c *fakeRoutedClientAI) GetClientID() uint64 {
+	return c.id
+}
+
+func (c *fakeRoutedClientAI) GetClientType() clienttypes.ClientType {
+	return clienttypes.ClientTypeClaude
+}
+
+func newTestAIRouter() *aiRouter {
+	config := &types.Configuration{}
+	config.AI.Routing = map[string][]string{"recommendation": {"openai", "claude"}}
+	config.AI.AttemptTimeout = 60
+
+	return &aiRouter{
+		configService: &mockRouterConfigService{config: config},
+		userConfigRepo: &mockRouterUserConfigRepository{configs: map[uint64]*models.UserConfig{
+			2: {DefaultClients: &models.DefaultClients{AIClientID: 3}},
+			3: {
+				DefaultClients:     &models.DefaultClients{AIClientID: 3},
+				AIModelPreferences: &models.AIModelPreferences{Routing: map[string][]string{"recommendation": {"ollama"}}},
+			},
+		}},
+		clientRepos: &mockRouterClientRepositories{
+			claude: &mockAIClientRepository[*clienttypes.ClaudeConfig]{clients: []*models.Client[*clienttypes.ClaudeConfig]{
+				aiClientRecord[*clienttypes.ClaudeConfig](3, true),
+				aiClientRecord[*clienttypes.ClaudeConfig](1, true),
+				aiClientRecord[*clienttypes.ClaudeConfig](2, false),
+			}},
+			openai: &mockAIClientRepository[*clienttypes.OpenAIConfig]{clients: []*models.Client[*clienttypes.OpenAIConfig]{
+				aiClientRecord[*clienttypes.OpenAIConfig](5, true),
+			}},
+			ollama: &mockAIClientRepository[*clienttypes.OllamaConfig]{clients: []*models.Client[*clienttypes.OllamaConfig]{
+				aiClientRecord[*clienttypes.OllamaConfig](7, true),
+			}},
+		},
+		clientHelper: &mockRouterClientHelper{types: map[uint64]clienttypes.ClientType{3: clienttypes.ClientTypeClaude}},
+	}
+}
+
+func TestAIRouterRouteConfigs(t *testing.T) {
+	ctx := context.Background()
+	router := newTestAIRouter()
+
+	routeIDs := func(userID uint64, task models.AIFeature) []uint64 {
+		configs, err := router.routeConfigs(ctx, userID, task)
+		require.NoError(t, err)
+		ids := make([]uint64, 0, len(configs))
+		for _, config := range configs {
+			ids = append(ids, config.id)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []uint64{5, 1, 3}, routeIDs(1, models.AIFeatureRecommendation), "the policy route, disabled clients are skipped")
+	assert.Equal(t, []uint64{1, 3, 5, 7}, routeIDs(1, models.AIFeatureSearch), "tasks without a policy route use the default route")
+	assert.Equal(t, []uint64{3, 1, 5, 7}, routeIDs(2, models.AIFeatureSearch), "the user's default client goes first")
+	assert.Equal(t, []uint64{7}, routeIDs(3, models.AIFeatureRecommendation), "a user route replaces the policy")
+	assert.Equal(t, []uint64{3, 1, 5, 7}, routeIDs(3, models.AIFeatureSearch), "the user route only covers its task")
+}
+
+func TestAIRouterRouteWithoutClients(t *testing.T) {
+	router := newTestAIRouter()
+	router.clientRepos = &mockRouterClientRepositories{
+		claude: &mockAIClientRepository[*clienttypes.ClaudeConfig]{},
+		openai: &mockAIClientRepository[*clienttypes.OpenAIConfig]{},
+		ollama: &mockAIClientRepository[*clienttypes.OllamaConfig]{},
+	}
+
+	err := router.Route(context.Background(), 1, models.AIFeatureSearch, func(ctx context.Context, client ai.ClientAI) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrNoAIClient)
+}
+
+func TestAIRouterFallback(t *testing.T) {
+	router := newTestAIRouter()
+	aiClients := []ai.ClientAI{&fakeRoutedClientAI{id: 1}, &fakeRoutedClientAI{id: 2}, &fakeRoutedClientAI{id: 3}}
+
+	var tried []uint64
+	route := func(ctx context.Context, errs map[uint64]error) error {
+		tried = nil
+		return router.tryClients(ctx, models.AIFeatureSearch, aiClients, func(ctx context.Context, client ai.ClientAI) error {
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline, "each attempt has the attempt timeout")
+			tried = append(tried, client.GetClientID())
+			return errs[client.GetClientID()]
+		})
+	}
+
+	err := route(context.Background(), map[uint64]error{
+		1: errors.New("rate limited"),
+		2: fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, tried, "generic errors and timeouts fall back to the next client")
+
+	lastErr := errors.New("overloaded")
+	err = route(context.Background(), map[uint64]error{1: errors.New("rate limited"), 2: errors.New("invalid key"), 3: lastErr})
+	assert.Equal(t, lastErr, err, "the last client's error is returned")
+
+	err = route(context.Background(), map[uint64]error{1: fmt.Errorf("claude: %w", ErrAIBudgetExceeded)})
+	assert.ErrorIs(t, err, ErrAIBudgetExceeded)
+	assert.Equal(t, []uint64{1}, tried, "the next client would run out of budget as well")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err = router.tryClients(ctx, models.AIFeatureSearch, aiClients, func(attemptCtx context.Context, client ai.ClientAI) error {
+		attempts++
+		cancel()
+		return attemptCtx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts, "the caller gave up, no client is tried after the first")
+}
@@ -0,0 +1,680 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"suasor/clients"
+	aitypes "suasor/clients/ai/types"
+	"suasor/clients/automation/providers"
+	automationtypes "suasor/clients/automation/types"
+	mediatypes "suasor/clients/media/types"
+	clienttypes "suasor/clients/types"
+	"suasor/repository"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+	"suasor/types/requests"
+	"suasor/utils/logger"
+)
+
+const (
+	// aiToolDefaultLimit is used when the model does not ask for a number of results
+	aiToolDefaultLimit = 10
+	// aiToolMaxLimit caps the results of a tool call, they are sent back to the model in full
+	aiToolMaxLimit = 25
+)
+
+// ErrUnknownAITool is returned for calls to a tool that is not registered
+var ErrUnknownAITool = errors.New("unknown tool")
+
+// AIToolHandler runs a tool on behalf of a user, arguments is the JSON object sent by the model.
+// The returned value is encoded as JSON for the model.
+type AIToolHandler func(ctx context.Context, userID uint64, arguments json.RawMessage) (any, error)
+
+// AITool is a tool offered to the model along with the handler that runs it
+type AITool struct {
+	aitypes.Tool
+	Handler AIToolHandler
+}
+
+// AIToolRegistry holds the tools AI conversations may call.
+// Tools always run for the conversation's user, the model cannot choose whose data is read or changed.
+type AIToolRegistry interface {
+	// Register adds a tool, replacing a tool with the same name
+	Register(tool AITool)
+	// Tools returns the definitions sent to the model, sorted by name
+	Tools() []aitypes.Tool
+	// Execute runs a call for the user. Failures are returned as error results for the model to read.
+	Execute(ctx context.Context, userID uint64, call aitypes.ToolCall) aitypes.ToolResult
+}
+
+type aiToolRegistry struct {
+	tools map[string]AITool
+
+	itemRepos       repobundles.CoreMediaItemRepositories
+	userDataRepos   repobundles.UserMediaDataRepositories
+	playlistService UserListService[*mediatypes.Playlist]
+	clientRepos     repobundles.ClientRepositories
+	clientFactory   *clients.ClientProviderFactoryService
+}
+
+// NewAIToolRegistry creates a registry with the built-in library, history, watchlist, playlist and download tools
+func NewAIToolRegistry(
+	itemRepos repobundles.CoreMediaItemRepositories,
+	userDataRepos repobundles.UserMediaDataRepositories,
+	playlistService UserListService[*mediatypes.Playlist],
+	clientRepos repobundles.ClientRepositories,
+	clientFactory *clients.ClientProviderFactoryService,
+) AIToolRegistry {
+	r := &aiToolRegistry{
+		tools:           make(map[string]AITool),
+		itemRepos:       itemRepos,
+		userDataRepos:   userDataRepos,
+		playlistService: playlistService,
+		clientRepos:     clientRepos,
+		clientFactory:   clientFactory,
+	}
+	r.registerBuiltinTools()
+	return r
+}
+
+func (r *aiToolRegistry) Register(tool AITool) {
+	r.tools[tool.Name] = tool
+}
+
+func (r *aiToolRegistry) Tools() []aitypes.Tool {
+	tools := make([]aitypes.Tool, 0, len(r.tools))
+	for _, tool := range r.tools {
+		tools = append(tools, tool.Tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+func (r *aiToolRegistry) Execute(ctx context.Context, userID uint64, call aitypes.ToolCall) aitypes.ToolResult {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Uint64("userID", userID).
+		Str("tool", call.Name).
+		Str("callID", call.ID).
+		Msg("Executing AI tool call")
+
+	result := aitypes.ToolResult{CallID: call.ID, Name: call.Name}
+
+	tool, exists := r.tools[call.Name]
+	if !exists {
+		result.Content = fmt.Sprintf("%s: %s", ErrUnknownAITool, call.Name)
+		result.IsError = true
+		return result
+	}
+
+	output, err := tool.Handler(ctx, userID, call.Arguments)
+	if err != nil {
+		log.Warn().Err(err).Str("tool", call.Name).Msg("AI tool call failed")
+		result.Content = err.Error()
+		result.IsError = true
+		return result
+	}
+
+	content, err := json.Marshal(output)
+	if err != nil {
+		result.Content = fmt.Sprintf("failed to encode tool result: %v", err)
+		result.IsError = true
+		return result
+	}
+	result.Content = string(content)
+	return result
+}
+
+func (r *aiToolRegistry) registerBuiltinTools() {
+	r.Register(AITool{
+		Tool: aitypes.Tool{
+			Name:        "search_library",
+			Description: "Search the user's local media library by title. Returns item IDs for the other tools.",
+			Parameters: objectSchema(map[string]any{
+				"query":      map[string]any{"type": "string", "description": "Part of the title to search for"},
+				"media_type": enumSchema("Type of media, movies and series are searched when empty", "movie", "series", "track", "album", "artist"),
+				"limit":      limitSchema(),
+			}, "query"),
+		},
+		Handler: r.searchLibrary,
+	})
+	r.Register(AITool{
+		Tool: aitypes.Tool{
+			Name:        "get_watch_history",
+			Description: "Get what the user recently watched or listened to, most recent first.",
+			Parameters: objectSchema(map[string]any{
+				"media_type": enumSchema("Type of media, movies and episodes are returned when empty", "movie", "series", "music"),
+				"limit":      limitSchema(),
+			}),
+		},
+		Handler: r.getWatchHistory,
+	})
+	r.Register(AITool{
+		Tool: aitypes.Tool{
+			Name:        "get_favorites",
+			Description: "Get the items the user marked as favourites.",
+			Parameters: objectSchema(map[string]any{
+				"media_type": enumSchema("Type of media, movies and series are returned when empty", "movie", "series", "music"),
+				"limit":      limitSchema(),
+			}),
+		},
+		Handler: r.getFavorites,
+	})
+	r.Register(AITool{
+		Tool: aitypes.Tool{
+			Name:        "add_to_watchlist",
+			Description: "Add a movie or series from the library to the user's watchlist.",
+			Parameters: objectSchema(map[string]any{
+				"media_type": enumSchema("Type of the item", "movie", "series"),
+				"item_id":    map[string]any{"type": "integer", "description": "Library item ID from search_library"},
+			}, "media_type", "item_id"),
+		},
+		Handler: r.addToWatchlist,
+	})
+	r.Register(AITool{
+		Tool: aitypes.Tool{
+			Name:        "create_playlist",
+			Description: "Create a playlist for the user, optionally with library items.",
+			Parameters: objectSchema(map[string]any{
+				"name":        map[string]any{"type": "string", "description": "Name of the playlist"},
+				"description": map[string]any{"type": "string"},
+				"item_ids":    itemIDsSchema(),
+			}, "name"),
+		},
+		Handler: r.createPlaylist,
+	})
+	r.Register(AITool{
+		Tool: aitypes.Tool{
+			Name:        "add_to_playlist",
+			Description: "Append library items to one of the user's playlists.",
+			Parameters: objectSchema(map[string]any{
+				"playlist_id": map[string]any{"type": "integer"},
+				"item_ids":    itemIDsSchema(),
+			}, "playlist_id", "item_ids"),
+		},
+		Handler: r.addToPlaylist,
+	})
+	r.Register(AITool{
+		Tool: aitypes.Tool{
+			Name:        "request_download",
+			Description: "Send a movie to Radarr or a series to Sonarr so it gets downloaded. Use this only when the user asks for it.",
+			Parameters: objectSchema(map[string]any{
+				"media_type": enumSchema("Movies go to Radarr, series to Sonarr", "movie", "series"),
+				"title":      map[string]any{"type": "string"},
+				"year":       map[string]any{"type": "integer"},
+				"tmdb_id":    map[string]any{"type": "integer", "description": "TMDB ID, required for movies"},
+				"tvdb_id":    map[string]any{"type": "integer", "description": "TVDB ID, required for series"},
+			}, "media_type", "title"),
+		},
+		Handler: r.requestDownload,
+	})
+}
+
+// aiToolItem is the summary of a library item returned to the model
+type aiToolItem struct {
+	ID           uint64   `json:"id"`
+	Type         string   `json:"type"`
+	Title        string   `json:"title"`
+	Year         int      `json:"year,omitempty"`
+	Genres       []string `json:"genres,omitempty"`
+	LastPlayedAt string   `json:"lastPlayedAt,omitempty"`
+	PlayCount    int32    `json:"playCount,omitempty"`
+	Rating       float32  `json:"rating,omitempty"`
+}
+
+type searchLibraryArgs struct {
+	Query     string `json:"query"`
+	MediaType string `json:"media_type"`
+	Limit     int    `json:"limit"`
+}
+
+func (r *aiToolRegistry) searchLibrary(ctx context.Context, userID uint64, arguments json.RawMessage) (any, error) {
+	var args searchLibraryArgs
+	if err := decodeToolArguments(arguments, &args); err != nil {
+		return nil, err
+	}
+	if args.Query == "" {
+		return nil, errors.New("query is required")
+	}
+	limit := toolLimit(args.Limit)
+
+	switch args.MediaType {
+	case "":
+		movies, err := searchToolItems(ctx, r.itemRepos.MovieRepo(), mediatypes.MediaTypeMovie, args.Query, limit)
+		if err != nil {
+			return nil, err
+		}
+		series, err := searchToolItems(ctx, r.itemRepos.SeriesRepo(), mediatypes.MediaTypeSeries, args.Query, limit)
+		if err != nil {
+			return nil, err
+		}
+		return append(movies, series...), nil
+	case "movie":
+		return searchToolItems(ctx, r.itemRepos.MovieRepo(), mediatypes.MediaTypeMovie, args.Query, limit)
+	case "series":
+		return searchToolItems(ctx, r.itemRepos.SeriesRepo(), mediatypes.MediaTypeSeries, args.Query, limit)
+	case "track":
+		return searchToolItems(ctx, r.itemRepos.TrackRepo(), mediatypes.MediaTypeTrack, args.Query, limit)
+	case "album":
+		return searchToolItems(ctx, r.itemRepos.AlbumRepo(), mediatypes.MediaTypeAlbum, args.Query, limit)
+	case "artist":
+		return searchToolItems(ctx, r.itemRepos.ArtistRepo(), mediatypes.MediaTypeArtist, args.Query, limit)
+	}
+	return nil, fmt.Errorf("unsupported media type: %s", args.MediaType)
+}
+
+type userItemsArgs struct {
+	MediaType string `json:"media_type"`
+	Limit     int    `json:"limit"`
+}
+
+func (r *aiToolRegistry) getWatchHistory(ctx context.Context, userID uint64, arguments json.RawMessage) (any, error) {
+	var args userItemsArgs
+	if err := decodeToolArguments(arguments, &args); err != nil {
+		return nil, err
+	}
+	limit := toolLimit(args.Limit)
+
+	movies := r.userDataRepos.MovieDataRepo().GetUserHistory
+	episodes := r.userDataRepos.EpisodeDataRepo().GetUserHistory
+	switch args.MediaType {
+	case "":
+		items, err := userToolItems(ctx, movies, userID, limit)
+		if err != nil {
+			return nil, err
+		}
+		more, err := userToolItems(ctx, episodes, userID, limit)
+		if err != nil {
+			return nil, err
+		}
+		return append(items, more...), nil
+	case "movie":
+		return userToolItems(ctx, movies, userID, limit)
+	case "series":
+		return userToolItems(ctx, episodes, userID, limit)
+	case "music":
+		return userToolItems(ctx, r.userDataRepos.TrackDataRepo().GetUserHistory, userID, limit)
+	}
+	return nil, fmt.Errorf("unsupported media type: %s", args.MediaType)
+}
+
+func (r *aiToolRegistry) getFavorites(ctx context.Context, userID uint64, arguments json.RawMessage) (any, error) {
+	var args userItemsArgs
+	if err := decodeToolArguments(arguments, &args); err != nil {
+		return nil, err
+	}
+	limit := toolLimit(args.Limit)
+
+	movies := r.userDataRepos.MovieDataRepo().GetFavorites
+	series := r.userDataRepos.SeriesDataRepo().GetFavorites
+	switch args.MediaType {
+	case "":
+		items, err := userToolItems(ctx, movies, userID, limit)
+		if err != nil {
+			return nil, err
+		}
+		more, err := userToolItems(ctx, series, userID, limit)
+		if err != nil {
+			return nil, err
+		}
+		return append(items, more...), nil
+	case "movie":
+		return userToolItems(ctx, movies, userID, limit)
+	case "series":
+		return userToolItems(ctx, series, userID, limit)
+	case "music":
+		return userToolItems(ctx, r.userDataRepos.TrackDataRepo().GetFavorites, userID, limit)
+	}
+	return nil, fmt.Errorf("unsupported media type: %s", args.MediaType)
+}
+
+type addToWatchlistArgs struct {
+	MediaType string `json:"media_type"`
+	ItemID    uint64 `json:"item_id"`
+}
+
+func (r *aiToolRegistry) addToWatchlist(ctx context.Context, userID uint64, arguments json.RawMessage) (any, error) {
+	var args addToWatchlistArgs
+	if err := decodeToolArguments(arguments, &args); err != nil {
+		return nil, err
+	}
+
+	switch args.MediaType {
+	case "movie":
+		return addToolItemToWatchlist(ctx, r.itemRepos.MovieRepo(), r.userDataRepos.MovieDataRepo(), userID, args.ItemID)
+	case "series":
+		return addToolItemToWatchlist(ctx, r.itemRepos.SeriesRepo(), r.userDataRepos.SeriesDataRepo(), userID, args.ItemID)
+	}
+	return nil, fmt.Errorf("unsupported media type: %s", args.MediaType)
+}
+
+type createPlaylistArgs struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	ItemIDs     []uint64 `json:"item_ids"`
+}
+
+func (r *aiToolRegistry) createPlaylist(ctx context.Context, userID uint64, arguments json.RawMessage) (any, error) {
+	var args createPlaylistArgs
+	if err := decodeToolArguments(arguments, &args); err != nil {
+		return nil, err
+	}
+	if args.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	details := &mediatypes.MediaDetails{Title: args.Name, Description: args.Description}
+	playlist := models.NewMediaItem(mediatypes.NewPlaylist(details, userID, false, false))
+	playlist.OwnerID = userID
+	playlist.IsPublic = false
+
+	created, err := r.playlistService.Create(ctx, userID, playlist)
+	if err != nil {
+		return nil, err
+	}
+
+	// The playlist exists from here on, a failed item is reported with what was added
+	added, err := r.addPlaylistItems(ctx, userID, created.ID, args.ItemIDs)
+	result := map[string]any{"playlistId": created.ID, "name": created.Title, "itemCount": len(added), "addedItemIds": added}
+	if err != nil {
+		result["error"] = fmt.Sprintf("added %d of %d items: %v", len(added), len(args.ItemIDs), err)
+	}
+	return result, nil
+}
+
+type addToPlaylistArgs struct {
+	PlaylistID uint64   `json:"playlist_id"`
+	ItemIDs    []uint64 `json:"item_ids"`
+}
+
+func (r *aiToolRegistry) addToPlaylist(ctx context.Context, userID uint64, arguments json.RawMessage) (any, error) {
+	var args addToPlaylistArgs
+	if err := decodeToolArguments(arguments, &args); err != nil {
+		return nil, err
+	}
+	if len(args.ItemIDs) == 0 {
+		return nil, errors.New("item_ids is required")
+	}
+
+	// AddItem checks the user may modify the playlist
+	added, err := r.addPlaylistItems(ctx, userID, args.PlaylistID, args.ItemIDs)
+	if err != nil && len(added) == 0 {
+		return nil, err
+	}
+
+	result := map[string]any{"playlistId": args.PlaylistID, "added": len(added), "addedItemIds": added}
+	if err != nil {
+		result["error"] = fmt.Sprintf("added %d of %d items: %v", len(added), len(args.ItemIDs), err)
+	}
+	return result, nil
+}
+
+// addPlaylistItems adds the items in order and stops at the first failure,
+// it returns the items added before it so the model is told what the playlist holds
+func (r *aiToolRegistry) addPlaylistItems(ctx context.Context, userID uint64, playlistID uint64, itemIDs []uint64) ([]uint64, error) {
+	added := make([]uint64, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		if err := r.playlistService.AddItem(ctx, userID, playlistID, itemID); err != nil {
+			return added, err
+		}
+		added = append(added, itemID)
+	}
+	return added, nil
+}
+
+// downloadProvider is what request_download needs from an automation client
+type downloadProvider interface {
+	providers.MediaProvider
+	providers.LibraryProvider
+	providers.ProfileProvider
+}
+
+type requestDownloadArgs struct {
+	MediaType string `json:"media_type"`
+	Title     string `json:"title"`
+	Year      int    `json:"year"`
+	TMDBID    int64  `json:"tmdb_id"`
+	TVDBID    int64  `json:"tvdb_id"`
+}
+
+// requestDownload adds the title to the user's first enabled Radarr or Sonarr client
+func (r *aiToolRegistry) requestDownload(ctx context.Context, userID uint64, arguments json.RawMessage) (any, error) {
+	var args requestDownloadArgs
+	if err := decodeToolArguments(arguments, &args); err != nil {
+		return nil, err
+	}
+	if args.Title == "" {
+		return nil, errors.New("title is required")
+	}
+
+	clientList, err := r.clientRepos.GetAllAutomationClientsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := requests.AutomationMediaAddRequest{
+		Title:          args.Title,
+		Year:           args.Year,
+		Monitored:      true,
+		SearchForMedia: true,
+	}
+	var clientID uint64
+	var config clienttypes.ClientConfig
+	switch args.MediaType {
+	case "movie":
+		if args.TMDBID == 0 {
+			return nil, errors.New("tmdb_id is required for movies")
+		}
+		request.TMDBID = args.TMDBID
+		clientID, config = firstEnabledClient(clientList.Radarr)
+	case "series":
+		if args.TVDBID == 0 {
+			return nil, errors.New("tvdb_id is required for series")
+		}
+		request.TVDBID = args.TVDBID
+		clientID, config = firstEnabledClient(clientList.Sonarr)
+	default:
+		return nil, fmt.Errorf("unsupported media type: %s", args.MediaType)
+	}
+	if config == nil {
+		return nil, fmt.Errorf("the user has no enabled automation client for %s", args.MediaType)
+	}
+
+	client, err := r.clientFactory.GetClient(ctx, clientID, config)
+	if err != nil {
+		return nil, err
+	}
+	provider, ok := client.(downloadProvider)
+	if !ok {
+		return nil, ErrAutomationUnsupportedFeature
+	}
+
+	// The model cannot know the client's settings, so use the first quality profile and the
+	// root folder of an item that is already in the library
+	profiles, err := provider.GetQualityProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) > 0 {
+		request.QualityProfileID = profiles[0].ID
+	}
+	library, err := provider.GetLibraryItems(ctx, &automationtypes.LibraryQueryOptions{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(library) > 0 && library[0].Path != "" {
+		request.Path = path.Dir(library[0].Path)
+	}
+
+	added, err := provider.AddMedia(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"clientId": clientID, "title": added.Title, "year": added.Year, "added": true}, nil
+}
+
+// searchToolItems searches the library items of one type by title
+func searchToolItems[T mediatypes.MediaData](
+	ctx context.Context,
+	repo repository.CoreMediaItemRepository[T],
+	mediaType mediatypes.MediaType,
+	query string,
+	limit int,
+) ([]aiToolItem, error) {
+	items, err := repo.Search(ctx, mediatypes.QueryOptions{Query: query, MediaType: mediaType, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]aiToolItem, 0, len(items))
+	for _, item := range items {
+		results = append(results, newAIToolItem(item))
+	}
+	return results, nil
+}
+
+// userToolItems lists the user's data from one of the user data repository queries
+func userToolItems[T mediatypes.MediaData](
+	ctx context.Context,
+	list func(ctx context.Context, userID uint64, limit, offset int) ([]*models.UserMediaItemData[T], error),
+	userID uint64,
+	limit int,
+) ([]aiToolItem, error) {
+	data, err := list(ctx, userID, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]aiToolItem, 0, len(data))
+	for _, entry := range data {
+		if entry == nil || entry.Item == nil {
+			continue
+		}
+		item := newAIToolItem(entry.Item)
+		if !entry.LastPlayedAt.IsZero() {
+			item.LastPlayedAt = entry.LastPlayedAt.Format("2006-01-02")
+		}
+		item.PlayCount = entry.PlayCount
+		item.Rating = entry.UserRating
+		results = append(results, item)
+	}
+	return results, nil
+}
+
+// addToolItemToWatchlist sets the watchlist flag on the user's data for the item, creating the data when needed
+func addToolItemToWatchlist[T mediatypes.MediaData](
+	ctx context.Context,
+	itemRepo repository.CoreMediaItemRepository[T],
+	dataRepo repository.UserMediaItemDataRepository[T],
+	userID uint64,
+	itemID uint64,
+) (any, error) {
+	item, err := itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("item %d not found in the library", itemID)
+	}
+
+	exists, err := dataRepo.HasUserMediaItemData(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		data, err := dataRepo.GetByUserIDAndMediaItemID(ctx, userID, itemID)
+		if err != nil {
+			return nil, err
+		}
+		data.Watchlist = true
+		if _, err := dataRepo.Update(ctx, data); err != nil {
+			return nil, err
+		}
+	} else {
+		data := models.NewUserMediaItemData(item, userID)
+		data.Watchlist = true
+		if _, err := dataRepo.Create(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]any{"itemId": itemID, "title": item.Title, "watchlist": true}, nil
+}
+
+func newAIToolItem[T mediatypes.MediaData](item *models.MediaItem[T]) aiToolItem {
+	result := aiToolItem{
+		ID:    item.ID,
+		Type:  string(item.Type),
+		Title: item.Title,
+		Year:  item.ReleaseYear,
+	}
+	if details := item.GetData().GetDetails(); details != nil {
+		if result.Year == 0 {
+			result.Year = details.ReleaseYear
+		}
+		result.Genres = details.Genres
+	}
+	return result
+}
+
+// firstEnabledClient returns the enabled client with the lowest ID, so the choice is stable
+func firstEnabledClient[T clienttypes.ClientConfig](clientMap map[uint64]*models.Client[T]) (uint64, clienttypes.ClientConfig) {
+	var clientID uint64
+	var config clienttypes.ClientConfig
+	for id, client := range clientMap {
+		if client.IsEnabled && (config == nil || id < clientID) {
+			clientID, config = id, client.Config
+		}
+	}
+	return clientID, config
+}
+
+func decodeToolArguments(arguments json.RawMessage, out any) error {
+	if len(arguments) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(arguments, out); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func toolLimit(limit int) int {
+	if limit <= 0 {
+		return aiToolDefaultLimit
+	}
+	if limit > aiToolMaxLimit {
+		return aiToolMaxLimit
+	}
+	return limit
+}
+
+// objectSchema is the JSON schema of an arguments object
+func objectSchema(properties map[string]any, required ...string) map[string]any {
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func enumSchema(description string, values ...string) map[string]any {
+	return map[string]any{"type": "string", "description": description, "enum": values}
+}
+
+func limitSchema() map[string]any {
+	return map[string]any{"type": "integer", "description": fmt.Sprintf("Maximum number of results, at most %d", aiToolMaxLimit)}
+}
+
+func itemIDsSchema() map[string]any {
+	return map[string]any{
+		"type":        "array",
+		"description": "Library item IDs from search_library",
+		"items":       map[string]any{"type": "integer"},
+	}
+}
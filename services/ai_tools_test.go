@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"suasor/clients/ai"
+	aitypes "suasor/clients/ai/types"
+	mediatypes "suasor/clients/media/types"
+	"suasor/repository"
+	"suasor/types/models"
+)
+
+// fakeToolClientAI asks for the same tool calls on every turn
+type fakeToolClientAI struct {
+	ai.ClientAI
+	calls []aitypes.ToolCall
+	turns int
+}
+
+func (c *fakeToolClientAI) ContinueConversationWithTools(ctx context.Context, conversationID string, message string, results []aitypes.ToolResult, tools []aitypes.Tool) (*aitypes.ToolTurn, error) {
+	c.turns++
+	return &aitypes.ToolTurn{ToolCalls: c.calls}, nil
+}
+
+type mockToolMessageRepository struct {
+	repository.AIConversationRepository
+	messages []*models.AIMessage
+}
+
+func (m *mockToolMessageRepository) AddMessage(ctx context.Context, message *models.AIMessage) (string, error) {
+	m.messages = append(m.messages, message)
+	return message.ID, nil
+}
+
+// mockPlaylistService creates playlists and fails to add the item failItemID
+type mockPlaylistService struct {
+	UserListService[*mediatypes.Playlist]
+	failItemID uint64
+	added      []uint64
+}
+
+func (m *mockPlaylistService) Create(ctx context.Context, userID uint64, list *models.MediaItem[*mediatypes.Playlist]) (*models.MediaItem[*mediatypes.Playlist], error) {
+	list.ID = 42
+	return list, nil
+}
+
+func (m *mockPlaylistService) AddItem(ctx context.Context, userID uint64, listID uint64, itemID uint64) error {
+	if itemID == m.failItemID {
+		return errors.New("item not found")
+	}
+	m.added = append(m.added, itemID)
+	return nil
+}
+
+type mockPlaylistLookup struct {
+	CoreListService[*mediatypes.Playlist]
+	playlist *models.MediaItem[*mediatypes.Playlist]
+}
+
+func (m *mockPlaylistLookup) GetByID(ctx context.Context, listID uint64) (*models.MediaItem[*mediatypes.Playlist], error) {
+	return m.playlist, nil
+}
+
+type mockToolUserRepository struct {
+	repository.UserRepository
+}
+
+func (m *mockToolUserRepository) FindByID(ctx context.Context, id uint64) (*models.User, error) {
+	user := &models.User{Role: "user"}
+	user.ID = id
+	return user, nil
+}
+
+func TestContinueWithToolsCallLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockToolMessageRepository{}
+	tools := NewAIToolRegistry(nil, nil, nil, nil, nil)
+	service := &aiConversationService{repo: repo, tools: tools}
+	client := &fakeToolClientAI{calls: []aitypes.ToolCall{
+		{ID: "call-1", Name: "unknown_tool", Arguments: json.RawMessage(`{}`)},
+		{ID: "call-2", Name: "create_playlist", Arguments: json.RawMessage(`{}`)},
+	}}
+	conversation := &models.AIConversation{ID: "conversation-1", UserID: 1}
+
+	_, _, err := service.continueWithTools(ctx, conversation, client, "Make me a playlist", map[string]any{})
+	assert.ErrorIs(t, err, ErrToolCallLimit)
+	assert.Equal(t, maxToolRounds+1, client.turns, "the first turn and one turn per round of results")
+
+	require.Len(t, repo.messages, 2*maxToolRounds, "one tool message per call")
+	for _, message := range repo.messages {
+		assert.Equal(t, "tool", message.Role)
+		assert.Equal(t, "conversation-1", message.ConversationID)
+	}
+	metadata, err := repo.messages[1].GetMetadataMap()
+	require.NoError(t, err)
+	assert.Equal(t, "call-2", metadata["toolCallId"])
+	assert.Equal(t, true, metadata["isError"])
+}
+
+func TestAIToolExecuteErrors(t *testing.T) {
+	ctx := context.Background()
+	tools := NewAIToolRegistry(nil, nil, nil, nil, nil)
+
+	result := tools.Execute(ctx, 1, aitypes.ToolCall{ID: "call-1", Name: "delete_library"})
+	assert.True(t, result.IsError)
+	assert.Equal(t, "call-1", result.CallID)
+	assert.Contains(t, result.Content, ErrUnknownAITool.Error())
+
+	result = tools.Execute(ctx, 1, aitypes.ToolCall{ID: "call-2", Name: "add_to_playlist", Arguments: json.RawMessage(`{"playlist_id": "seven"}`)})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content, "invalid arguments")
+
+	result = tools.Execute(ctx, 1, aitypes.ToolCall{ID: "call-3", Name: "create_playlist", Arguments: json.RawMessage(`{"item_ids": [1]}`)})
+	assert.True(t, result.IsError)
+	assert.Equal(t, "name is required", result.Content)
+}
+
+func TestAIToolAddToPlaylistPermission(t *testing.T) {
+	ctx := context.Background()
+	playlist := models.NewMediaItem(mediatypes.NewPlaylist(&mediatypes.MediaDetails{Title: "Theirs"}, 2, false, false))
+	playlist.ID = 7
+	playlist.OwnerID = 2
+	playlistService := NewUserListService[*mediatypes.Playlist](&mockPlaylistLookup{playlist: playlist}, &mockToolUserRepository{}, nil, nil, nil)
+	tools := NewAIToolRegistry(nil, nil, playlistService, nil, nil)
+
+	result := tools.Execute(ctx, 1, aitypes.ToolCall{ID: "call-1", Name: "add_to_playlist", Arguments: json.RawMessage(`{"playlist_id": 7, "item_ids": [10]}`)})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content, "permission")
+	assert.Empty(t, playlist.Data.GetItemList().Items)
+}
+
+func TestAIToolPlaylistPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	playlistService := &mockPlaylistService{failItemID: 12}
+	tools := NewAIToolRegistry(nil, nil, playlistService, nil, nil)
+
+	result := tools.Execute(ctx, 1, aitypes.ToolCall{ID: "call-1", Name: "create_playlist", Arguments: json.RawMessage(`{"name": "Rainy day", "item_ids": [10, 11, 12, 13]}`)})
+	assert.False(t, result.IsError, "the playlist was created")
+
+	var output map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result.Content), &output))
+	assert.Equal(t, float64(42), output["playlistId"])
+	assert.Equal(t, float64(2), output["itemCount"])
+	assert.Equal(t, []any{float64(10), float64(11)}, output["addedItemIds"])
+	assert.Contains(t, output["error"], "added 2 of 4 items")
+
+	playlistService.added = nil
+	result = tools.Execute(ctx, 1, aitypes.ToolCall{ID: "call-2", Name: "add_to_playlist", Arguments: json.RawMessage(`{"playlist_id": 42, "item_ids": [12, 13]}`)})
+	assert.True(t, result.IsError, "nothing was added")
+	assert.Empty(t, playlistService.added)
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"suasor/clients"
+	aitypes "suasor/clients/ai/types"
+	"suasor/repository"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"time"
+)
+
+var (
+	// ErrAIBudgetExceeded is returned when a user or the household has used up a daily or monthly token budget
+	ErrAIBudgetExceeded = errors.New("AI token budget exceeded")
+	// ErrInvalidAIBudget is returned when a budget limit is negative
+	ErrInvalidAIBudget = errors.New("token limits cannot be negative")
+)
+
+// AIUsageService records the tokens spent on AI requests and enforces the token budgets
+type AIUsageService interface {
+	// CheckBudget returns ErrAIBudgetExceeded when the user's or the global budget is used up
+	CheckBudget(ctx context.Context, userID uint64) error
+	// Track returns a context that records the token usage of the client's requests for the user and feature
+	Track(ctx context.Context, userID uint64, client clients.Client, feature models.AIFeature) context.Context
+
+	// GetBudgets returns every budget, the global budget has UserID 0
+	GetBudgets(ctx context.Context) ([]*models.AIBudget, error)
+	// SetBudget sets the daily and monthly token limits of a user, or of the household for userID 0
+	SetBudget(ctx context.Context, userID uint64, dailyTokens, monthlyTokens int64) (*models.AIBudget, error)
+	// GetUsageReport returns the tokens spent per user between two times
+	GetUsageReport(ctx context.Context, start, end time.Time) (*models.AIUsageReport, error)
+}
+
+type aiUsageService struct {
+	usageRepo repository.AIUsageRepository
+	userRepo  repository.UserRepository
+}
+
+// NewAIUsageService creates a new AI usage service
+func NewAIUsageService(usageRepo repository.AIUsageRepository, userRepo repository.UserRepository) AIUsageService {
+	return &aiUsageService{
+		usageRepo: usageRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// CheckBudget returns ErrAIBudgetExceeded when the user's or the global budget is used up
+func (s *aiUsageService) CheckBudget(ctx context.Context, userID uint64) error {
+	now := time.Now()
+
+	budget, err := s.usageRepo.GetBudget(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkBudget(ctx, budget, userID, now); err != nil {
+		return err
+	}
+
+	globalBudget, err := s.usageRepo.GetBudget(ctx, 0)
+	if err != nil {
+		return err
+	}
+	return s.checkBudget(ctx, globalBudget, 0, now)
+}
+
+// checkBudget compares the tokens used today and this month with the budget's limits
+func (s *aiUsageService) checkBudget(ctx context.Context, budget *models.AIBudget, userID uint64, now time.Time) error {
+	if budget == nil {
+		return nil
+	}
+
+	owner := "user"
+	if userID == 0 {
+		owner = "household"
+	}
+
+	if budget.DailyTokens > 0 {
+		startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		used, err := s.usageRepo.SumTokens(ctx, userID, startOfDay)
+		if err != nil {
+			return err
+		}
+		if used >= budget.DailyTokens {
+			return fmt.Errorf("%w: %s used %d of %d daily tokens", ErrAIBudgetExceeded, owner, used, budget.DailyTokens)
+		}
+	}
+
+	if budget.MonthlyTokens > 0 {
+		startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		used, err := s.usageRepo.SumTokens(ctx, userID, startOfMonth)
+		if err != nil {
+			return err
+		}
+		if used >= budget.MonthlyTokens {
+			return fmt.Errorf("%w: %s used %d of %d monthly tokens", ErrAIBudgetExceeded, owner, used, budget.MonthlyTokens)
+		}
+	}
+
+	return nil
+}
+
+// Track returns a context that records the token usage of the client's requests for the user and feature
+func (s *aiUsageService) Track(ctx context.Context, userID uint64, client clients.Client, feature models.AIFeature) context.Context {
+	clientID := client.GetClientID()
+	clientType := client.GetClientType()
+
+	return aitypes.WithUsageReporter(ctx, func(ctx context.Context, model string, usage aitypes.TokenUsage) {
+		// Streams report their usage at the end, the record is saved even if the caller has gone away
+		ctx = context.WithoutCancel(ctx)
+		log := logger.LoggerFromContext(ctx)
+
+		record := &models.AIUsageRecord{
+			UserID:           userID,
+			ClientID:         clientID,
+			ClientType:       clientType,
+			Model:            model,
+			Feature:          feature,
+			PromptTokens:     int64(usage.PromptTokens),
+			CompletionTokens: int64(usage.CompletionTokens),
+			TotalTokens:      int64(usage.TotalTokens),
+		}
+		if err := s.usageRepo.CreateRecord(ctx, record); err != nil {
+			log.Error().Err(err).
+				Uint64("userID", userID).
+				Uint64("clientID", clientID).
+				Msg("Failed to record AI token usage")
+		}
+	})
+}
+
+// GetBudgets returns every budget, the global budget has UserID 0
+func (s *aiUsageService) GetBudgets(ctx context.Context) ([]*models.AIBudget, error) {
+	return s.usageRepo.GetBudgets(ctx)
+}
+
+// SetBudget sets the daily and monthly token limits of a user, or of the household for userID 0
+func (s *aiUsageService) SetBudget(ctx context.Context, userID uint64, dailyTokens, monthlyTokens int64) (*models.AIBudget, error) {
+	if dailyTokens < 0 || monthlyTokens < 0 {
+		return nil, ErrInvalidAIBudget
+	}
+
+	if userID != 0 {
+		if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrUserNotFound
+			}
+			return nil, err
+		}
+	}
+
+	budget := &models.AIBudget{
+		UserID:        userID,
+		DailyTokens:   dailyTokens,
+		MonthlyTokens: monthlyTokens,
+	}
+	if err := s.usageRepo.SaveBudget(ctx, budget); err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+// GetUsageReport returns the tokens spent per user between two times, the biggest spender first
+func (s *aiUsageService) GetUsageReport(ctx context.Context, start, end time.Time) (*models.AIUsageReport, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	summaries, err := s.usageRepo.GetSummaries(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	budgets, err := s.usageRepo.GetBudgets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	budgetsByUser := make(map[uint64]*models.AIBudget, len(budgets))
+	for _, budget := range budgets {
+		budgetsByUser[budget.UserID] = budget
+	}
+
+	report := &models.AIUsageReport{
+		Start:        start,
+		End:          end,
+		GlobalBudget: budgetsByUser[0],
+		Users:        []*models.AIUserSpend{},
+	}
+
+	spendByUser := make(map[uint64]*models.AIUserSpend)
+	for _, summary := range summaries {
+		spend, ok := spendByUser[summary.UserID]
+		if !ok {
+			spend = &models.AIUserSpend{
+				UserID: summary.UserID,
+				Budget: budgetsByUser[summary.UserID],
+			}
+			if user, err := s.userRepo.FindByID(ctx, summary.UserID); err != nil {
+				log.Warn().Err(err).Uint64("userID", summary.UserID).Msg("Failed to get user for AI usage report")
+			} else {
+				spend.Username = user.Username
+			}
+			spendByUser[summary.UserID] = spend
+			report.Users = append(report.Users, spend)
+		}
+
+		spend.Requests += summary.Requests
+		spend.TotalTokens += summary.TotalTokens
+		spend.Breakdown = append(spend.Breakdown, summary)
+		report.TotalTokens += summary.TotalTokens
+	}
+
+	sort.SliceStable(report.Users, func(i, j int) bool {
+		return report.Users[i].TotalTokens > report.Users[j].TotalTokens
+	})
+
+	return report, nil
+}
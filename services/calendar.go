@@ -0,0 +1,549 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"suasor/clients"
+	"suasor/clients/automation/providers"
+	automationtypes "suasor/clients/automation/types"
+	mediatypes "suasor/clients/media/types"
+	"suasor/clients/metadata"
+	clienttypes "suasor/clients/types"
+	"suasor/repository"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"sync"
+	"time"
+)
+
+const (
+	// maxCalendarRange caps how far apart start and end can be
+	maxCalendarRange = 366 * 24 * time.Hour
+	// calendarWatchlistLimit caps how many watchlist items are checked per media type
+	calendarWatchlistLimit = 500
+	// calendarRecommendationLimit caps how many recommendations are looked up on the metadata client
+	calendarRecommendationLimit = 50
+	// calendarLookupConcurrency caps how many recommendations are looked up at the same time
+	calendarLookupConcurrency = 8
+	// metadataDateLayout is the date format metadata clients use for release dates
+	metadataDateLayout = "2006-01-02"
+)
+
+// ErrInvalidCalendarRange is returned when end is before start or the range is too large
+var ErrInvalidCalendarRange = errors.New("invalid calendar range")
+
+// CalendarService builds a user's release calendar from their automation clients,
+// the metadata client and the items they have been recommended or watchlisted
+type CalendarService interface {
+	// GetCalendar returns the releases between start and end, merged by external ID and sorted by date.
+	// A nil filter returns everything.
+	GetCalendar(ctx context.Context, userID uint64, start, end time.Time, filter *models.CalendarFilter) ([]*models.CalendarEntry, error)
+}
+
+type calendarService struct {
+	clientRepos        repobundles.ClientRepositories
+	tmdbRepo           repository.ClientRepository[*clienttypes.TMDBConfig]
+	userDataRepos      repobundles.UserMediaDataRepositories
+	recommendationRepo repository.RecommendationRepository
+	clientFactory      *clients.ClientProviderFactoryService
+}
+
+// NewCalendarService creates a new calendar service
+func NewCalendarService(
+	clientRepos repobundles.ClientRepositories,
+	tmdbRepo repository.ClientRepository[*clienttypes.TMDBConfig],
+	userDataRepos repobundles.UserMediaDataRepositories,
+	recommendationRepo repository.RecommendationRepository,
+	clientFactory *clients.ClientProviderFactoryService,
+) CalendarService {
+	return &calendarService{
+		clientRepos:        clientRepos,
+		tmdbRepo:           tmdbRepo,
+		userDataRepos:      userDataRepos,
+		recommendationRepo: recommendationRepo,
+		clientFactory:      clientFactory,
+	}
+}
+
+// GetCalendar returns the releases between start and end, merged by external ID and sorted by date.
+// Every source is best effort, a client that is down only drops its own entries.
+func (s *calendarService) GetCalendar(ctx context.Context, userID uint64, start, end time.Time, filter *models.CalendarFilter) ([]*models.CalendarEntry, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	if end.Before(start) || end.Sub(start) > maxCalendarRange {
+		return nil, ErrInvalidCalendarRange
+	}
+
+	var metadataClient metadata.ClientMetadata
+	if filter.IncludesSource(models.CalendarSourceMetadata) || filter.IncludesSource(models.CalendarSourceRecommendation) {
+		metadataClient = s.getMetadataClient(ctx, userID)
+	}
+
+	// Automation clients go first since they have the most precise dates for what the user tracks
+	var entries []*models.CalendarEntry
+	if filter.IncludesSource(models.CalendarSourceAutomation) {
+		entries = append(entries, s.getAutomationEntries(ctx, userID, start, end)...)
+	}
+	if filter.IncludesSource(models.CalendarSourceWatchlist) {
+		entries = append(entries, s.getWatchlistEntries(ctx, userID)...)
+	}
+	if filter.IncludesSource(models.CalendarSourceRecommendation) {
+		entries = append(entries, s.getRecommendationEntries(ctx, userID, metadataClient, start, end)...)
+	}
+	if metadataClient != nil && filter.IncludesSource(models.CalendarSourceMetadata) {
+		entries = append(entries, getMetadataEntries(ctx, metadataClient, start, end)...)
+	}
+
+	calendar := make([]*models.CalendarEntry, 0, len(entries))
+	for _, entry := range mergeCalendarEntries(start, end, entries) {
+		if filter.Matches(entry) {
+			calendar = append(calendar, entry)
+		}
+	}
+
+	log.Debug().
+		Uint64("userID", userID).
+		Time("start", start).
+		Time("end", end).
+		Int("sourceEntries", len(entries)).
+		Int("calendarEntries", len(calendar)).
+		Msg("Built calendar")
+
+	return calendar, nil
+}
+
+// getAutomationEntries pulls the calendar from every Radarr, Sonarr and Lidarr client the user has
+func (s *calendarService) getAutomationEntries(ctx context.Context, userID uint64, start, end time.Time) []*models.CalendarEntry {
+	log := logger.LoggerFromContext(ctx)
+
+	clientList, err := s.clientRepos.GetAllAutomationClientsForUser(ctx, userID)
+	if err != nil {
+		log.Warn().Err(err).Uint64("userID", userID).Msg("Failed to get automation clients for calendar")
+		return nil
+	}
+
+	configs := make(map[uint64]clienttypes.ClientConfig)
+	for clientID, client := range clientList.Radarr {
+		if client.IsEnabled {
+			configs[clientID] = client.Config
+		}
+	}
+	for clientID, client := range clientList.Sonarr {
+		if client.IsEnabled {
+			configs[clientID] = client.Config
+		}
+	}
+	for clientID, client := range clientList.Lidarr {
+		if client.IsEnabled {
+			configs[clientID] = client.Config
+		}
+	}
+
+	var entries []*models.CalendarEntry
+	for clientID, config := range configs {
+		client, err := s.clientFactory.GetClient(ctx, clientID, config)
+		if err != nil {
+			log.Warn().Err(err).Uint64("clientID", clientID).Msg("Failed to get automation client for calendar")
+			continue
+		}
+
+		provider, ok := client.(providers.CalendarProvider)
+		if !ok {
+			continue
+		}
+
+		items, err := provider.GetCalendar(ctx, start, end)
+		if err != nil {
+			log.Warn().Err(err).Uint64("clientID", clientID).Msg("Failed to get automation calendar")
+			continue
+		}
+
+		for _, item := range items {
+			if entry := automationCalendarEntry(clientID, item); entry != nil {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return entries
+}
+
+// automationCalendarEntry converts an item from an automation calendar, it returns nil for items without a release date
+func automationCalendarEntry(clientID uint64, item models.AutomationMediaItem[automationtypes.AutomationData]) *models.CalendarEntry {
+	entry := &models.CalendarEntry{
+		Title:      item.Title,
+		Overview:   item.Overview,
+		Sources:    []models.CalendarSource{models.CalendarSourceAutomation},
+		ClientID:   clientID,
+		ClientType: item.ClientType.AsGenericClient(),
+		Monitored:  item.Monitored,
+	}
+
+	switch data := item.Data.(type) {
+	case automationtypes.AutomationMovie:
+		entry.MediaType = mediatypes.MediaTypeMovie
+		entry.ReleaseDate = data.ReleaseDate
+	case automationtypes.AutomationTVShow:
+		entry.MediaType = mediatypes.MediaTypeSeries
+		entry.ReleaseDate = data.ReleaseDate
+	case automationtypes.AutomationEpisode:
+		entry.MediaType = mediatypes.MediaTypeEpisode
+		entry.ReleaseDate = data.ReleaseDate
+	case automationtypes.AutomationAlbum:
+		entry.MediaType = mediatypes.MediaTypeAlbum
+		entry.ReleaseDate = data.ReleaseDate
+	default:
+		return nil
+	}
+	if entry.ReleaseDate.IsZero() {
+		return nil
+	}
+
+	for _, id := range item.ExternalIDs {
+		if id.Value != "" {
+			entry.ExternalIDs.AddOrUpdate(id.Source, id.Value)
+		}
+	}
+
+	return entry
+}
+
+// getMetadataClient returns the user's first enabled metadata client, or nil if they have none
+func (s *calendarService) getMetadataClient(ctx context.Context, userID uint64) metadata.ClientMetadata {
+	log := logger.LoggerFromContext(ctx)
+
+	tmdbClients, err := s.tmdbRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Warn().Err(err).Uint64("userID", userID).Msg("Failed to get metadata clients for calendar")
+		return nil
+	}
+
+	for _, tmdbClient := range tmdbClients {
+		if !tmdbClient.IsEnabled {
+			continue
+		}
+		client, err := s.clientFactory.GetClient(ctx, tmdbClient.ID, tmdbClient.Config)
+		if err != nil {
+			log.Warn().Err(err).Uint64("clientID", tmdbClient.ID).Msg("Failed to get metadata client for calendar")
+			continue
+		}
+		if metadataClient, ok := client.(metadata.ClientMetadata); ok {
+			return metadataClient
+		}
+	}
+
+	return nil
+}
+
+// getMetadataEntries pulls upcoming movies and recently premiered shows from the metadata client.
+// The client only looks forward for movies and back for shows, so each is only asked for when the range covers it.
+func getMetadataEntries(ctx context.Context, client metadata.ClientMetadata, start, end time.Time) []*models.CalendarEntry {
+	log := logger.LoggerFromContext(ctx)
+	now := time.Now()
+
+	var entries []*models.CalendarEntry
+
+	if end.After(now) && client.SupportsMovieMetadata() {
+		movies, err := client.GetUpcomingMovies(ctx, daysBetween(now, end))
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to get upcoming movies for calendar")
+		}
+		for _, movie := range movies {
+			if entry := metadataMovieEntry(movie, models.CalendarSourceMetadata); entry != nil {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	if start.Before(now) && client.SupportsTVMetadata() {
+		shows, err := client.GetRecentTVShows(ctx, daysBetween(start, now))
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to get recent TV shows for calendar")
+		}
+		for _, show := range shows {
+			if entry := metadataTVShowEntry(show, models.CalendarSourceMetadata); entry != nil {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return entries
+}
+
+// metadataMovieEntry converts a metadata movie, it returns nil when the release date is missing
+func metadataMovieEntry(movie *metadata.Movie, source models.CalendarSource) *models.CalendarEntry {
+	if movie == nil {
+		return nil
+	}
+	releaseDate, err := time.Parse(metadataDateLayout, movie.ReleaseDate)
+	if err != nil {
+		return nil
+	}
+
+	entry := &models.CalendarEntry{
+		Title:       movie.Title,
+		Overview:    movie.Overview,
+		MediaType:   mediatypes.MediaTypeMovie,
+		ReleaseDate: releaseDate,
+		Sources:     []models.CalendarSource{source},
+	}
+	entry.ExternalIDs.AddOrUpdate("tmdb", movie.ID)
+	if movie.ExternalIDs.IMDBID != "" {
+		entry.ExternalIDs.AddOrUpdate("imdb", movie.ExternalIDs.IMDBID)
+	}
+	return entry
+}
+
+// metadataTVShowEntry converts a metadata show using its premiere date, it returns nil when the date is missing
+func metadataTVShowEntry(show *metadata.TVShow, source models.CalendarSource) *models.CalendarEntry {
+	if show == nil {
+		return nil
+	}
+	firstAirDate, err := time.Parse(metadataDateLayout, show.FirstAirDate)
+	if err != nil {
+		return nil
+	}
+
+	entry := &models.CalendarEntry{
+		Title:       show.Name,
+		Overview:    show.Overview,
+		MediaType:   mediatypes.MediaTypeSeries,
+		ReleaseDate: firstAirDate,
+		Sources:     []models.CalendarSource{source},
+	}
+	entry.ExternalIDs.AddOrUpdate("tmdb", show.ID)
+	if show.ExternalIDs.TVDBId != "" {
+		entry.ExternalIDs.AddOrUpdate("tvdb", show.ExternalIDs.TVDBId)
+	}
+	if show.ExternalIDs.IMDBID != "" {
+		entry.ExternalIDs.AddOrUpdate("imdb", show.ExternalIDs.IMDBID)
+	}
+	return entry
+}
+
+// getWatchlistEntries returns the movies and series on the user's watchlist, the range is applied when merging
+func (s *calendarService) getWatchlistEntries(ctx context.Context, userID uint64) []*models.CalendarEntry {
+	var entries []*models.CalendarEntry
+	entries = append(entries, watchlistCalendarEntries(ctx, s.userDataRepos.MovieDataRepo(), userID)...)
+	entries = append(entries, watchlistCalendarEntries(ctx, s.userDataRepos.SeriesDataRepo(), userID)...)
+	return entries
+}
+
+func watchlistCalendarEntries[T mediatypes.MediaData](ctx context.Context, repo repository.UserMediaItemDataRepository[T], userID uint64) []*models.CalendarEntry {
+	log := logger.LoggerFromContext(ctx)
+
+	watchlist, err := repo.GetWatchlist(ctx, userID, calendarWatchlistLimit, 0)
+	if err != nil {
+		log.Warn().Err(err).Uint64("userID", userID).Msg("Failed to get watchlist for calendar")
+		return nil
+	}
+
+	entries := make([]*models.CalendarEntry, 0, len(watchlist))
+	for _, data := range watchlist {
+		if data == nil || data.Item == nil {
+			continue
+		}
+		details := data.Item.GetData().GetDetails()
+		if details == nil || details.ReleaseDate.IsZero() {
+			continue
+		}
+		entries = append(entries, &models.CalendarEntry{
+			Title:       details.Title,
+			Overview:    details.Description,
+			MediaType:   data.Item.Type,
+			ReleaseDate: details.ReleaseDate,
+			ExternalIDs: append(mediatypes.ExternalIDs(nil), details.ExternalIDs...),
+			Sources:     []models.CalendarSource{models.CalendarSourceWatchlist},
+			MediaItemID: data.MediaItemID,
+		})
+	}
+	return entries
+}
+
+// getRecommendationEntries looks up release dates for the user's movie and series recommendations.
+// Recommendations only store a year, so the date comes from the metadata client. The lookups run in
+// parallel, at most calendarLookupConcurrency at a time.
+func (s *calendarService) getRecommendationEntries(ctx context.Context, userID uint64, client metadata.ClientMetadata, start, end time.Time) []*models.CalendarEntry {
+	log := logger.LoggerFromContext(ctx)
+
+	if client == nil {
+		return nil
+	}
+
+	recommendations, err := s.recommendationRepo.GetByUserID(ctx, userID, calendarRecommendationLimit, 0)
+	if err != nil {
+		log.Warn().Err(err).Uint64("userID", userID).Msg("Failed to get recommendations for calendar")
+		return nil
+	}
+
+	var (
+		wg      sync.WaitGroup
+		limit   = make(chan struct{}, calendarLookupConcurrency)
+		results = make([]*models.CalendarEntry, len(recommendations))
+	)
+	for i, recommendation := range recommendations {
+		// Anything already in the library has been released
+		if recommendation.InLibrary || recommendation.ExternalIDs == nil {
+			continue
+		}
+		if recommendation.Year != 0 && (recommendation.Year < start.Year() || recommendation.Year > end.Year()) {
+			continue
+		}
+		if (*recommendation.ExternalIDs)["tmdb"] == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, recommendation *models.Recommendation) {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			results[i] = recommendationCalendarEntry(ctx, client, recommendation)
+		}(i, &recommendations[i])
+	}
+	wg.Wait()
+
+	entries := make([]*models.CalendarEntry, 0, len(results))
+	for _, entry := range results {
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// recommendationCalendarEntry looks up a recommended movie or series on the metadata client
+func recommendationCalendarEntry(ctx context.Context, client metadata.ClientMetadata, recommendation *models.Recommendation) *models.CalendarEntry {
+	log := logger.LoggerFromContext(ctx)
+
+	tmdbID := (*recommendation.ExternalIDs)["tmdb"]
+
+	var entry *models.CalendarEntry
+	switch recommendation.MediaType {
+	case mediatypes.MediaTypeMovie:
+		movie, err := client.GetMovie(ctx, tmdbID)
+		if err != nil {
+			log.Debug().Err(err).Str("tmdbID", tmdbID).Msg("Failed to look up recommended movie")
+			return nil
+		}
+		entry = metadataMovieEntry(movie, models.CalendarSourceRecommendation)
+	case mediatypes.MediaTypeSeries:
+		show, err := client.GetTVShow(ctx, tmdbID)
+		if err != nil {
+			log.Debug().Err(err).Str("tmdbID", tmdbID).Msg("Failed to look up recommended series")
+			return nil
+		}
+		entry = metadataTVShowEntry(show, models.CalendarSourceRecommendation)
+	}
+	if entry == nil {
+		return nil
+	}
+
+	entry.MediaItemID = recommendation.MediaItemID
+	for source, id := range *recommendation.ExternalIDs {
+		if id != "" && entry.ExternalIDs.GetID(source) == "" {
+			entry.ExternalIDs.AddOrUpdate(source, id)
+		}
+	}
+	return entry
+}
+
+// mergeCalendarEntries drops entries outside the range, merges entries that share an external ID
+// and sorts the rest by release date. The first entry seen for a release keeps its date.
+func mergeCalendarEntries(start, end time.Time, entries []*models.CalendarEntry) []*models.CalendarEntry {
+	merged := make([]*models.CalendarEntry, 0, len(entries))
+	index := make(map[string]*models.CalendarEntry)
+
+	for _, entry := range entries {
+		if entry == nil || entry.ReleaseDate.Before(start) || entry.ReleaseDate.After(end) {
+			continue
+		}
+
+		var existing *models.CalendarEntry
+		for _, key := range calendarEntryKeys(entry) {
+			if found, ok := index[key]; ok {
+				existing = found
+				break
+			}
+		}
+
+		if existing == nil {
+			existing = entry
+			merged = append(merged, entry)
+		} else {
+			mergeCalendarEntry(existing, entry)
+		}
+
+		for _, key := range calendarEntryKeys(existing) {
+			index[key] = existing
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if !merged[i].ReleaseDate.Equal(merged[j].ReleaseDate) {
+			return merged[i].ReleaseDate.Before(merged[j].ReleaseDate)
+		}
+		return merged[i].Title < merged[j].Title
+	})
+
+	return merged
+}
+
+// calendarEntryKeys returns the keys an entry is deduplicated on.
+// Entries without external IDs fall back to their title and date.
+func calendarEntryKeys(entry *models.CalendarEntry) []string {
+	date := entry.ReleaseDate.Format(metadataDateLayout)
+
+	// Episodes carry their series' IDs, so the air date is what tells them apart
+	suffix := ""
+	if entry.MediaType == mediatypes.MediaTypeEpisode {
+		suffix = "/" + date
+	}
+
+	keys := make([]string, 0, len(entry.ExternalIDs))
+	for _, id := range entry.ExternalIDs {
+		if id.ID != "" {
+			keys = append(keys, fmt.Sprintf("%s:%s:%s%s", entry.MediaType, id.Source, id.ID, suffix))
+		}
+	}
+	if len(keys) == 0 {
+		keys = append(keys, fmt.Sprintf("%s:title:%s/%s", entry.MediaType, strings.ToLower(entry.Title), date))
+	}
+	return keys
+}
+
+// mergeCalendarEntry fills in what dst is missing from src
+func mergeCalendarEntry(dst, src *models.CalendarEntry) {
+	for _, source := range src.Sources {
+		if !dst.HasSource(source) {
+			dst.Sources = append(dst.Sources, source)
+		}
+	}
+	for _, id := range src.ExternalIDs {
+		if id.ID != "" && dst.ExternalIDs.GetID(id.Source) == "" {
+			dst.ExternalIDs.AddOrUpdate(id.Source, id.ID)
+		}
+	}
+	if dst.Overview == "" {
+		dst.Overview = src.Overview
+	}
+	if dst.ClientID == 0 {
+		dst.ClientID = src.ClientID
+		dst.ClientType = src.ClientType
+		dst.Monitored = src.Monitored
+	}
+	if dst.MediaItemID == 0 {
+		dst.MediaItemID = src.MediaItemID
+	}
+}
+
+// daysBetween returns the whole number of days from a to b, rounded up
+func daysBetween(a, b time.Time) int {
+	return int(math.Ceil(b.Sub(a).Hours() / 24))
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	mediatypes "suasor/clients/media/types"
+	"suasor/repository"
+	"suasor/types/models"
+	"suasor/utils/ical"
+	"suasor/utils/logger"
+	"time"
+)
+
+const (
+	// calendarFeedPastDays is how far back the ICS feed reaches
+	calendarFeedPastDays = 30
+	// calendarFeedFutureDays is how far ahead the ICS feed reaches
+	calendarFeedFutureDays = 180
+	// calendarFeedRefreshInterval is how often subscribers are asked to poll the feed
+	calendarFeedRefreshInterval = 6 * time.Hour
+)
+
+// ErrCalendarFeedNotFound is returned when no user has the feed token
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// CalendarFeedService publishes a user's calendar as an ICS feed behind a secret URL,
+// so calendar apps can subscribe without logging in
+type CalendarFeedService interface {
+	// GetFeedURL returns the user's feed URL, creating a token the first time
+	GetFeedURL(ctx context.Context, userID uint64) (string, error)
+	// RotateFeedToken replaces the user's token, existing subscriptions stop working
+	RotateFeedToken(ctx context.Context, userID uint64) (string, error)
+	// GetFeed renders the calendar of the token's owner as an ICS document
+	GetFeed(ctx context.Context, token string, filter *models.CalendarFilter) ([]byte, error)
+}
+
+type calendarFeedService struct {
+	baseURL         string
+	feedRepo        repository.CalendarFeedRepository
+	calendarService CalendarService
+}
+
+// NewCalendarFeedService creates a new calendar feed service
+func NewCalendarFeedService(
+	baseURL string,
+	feedRepo repository.CalendarFeedRepository,
+	calendarService CalendarService,
+) CalendarFeedService {
+	return &calendarFeedService{
+		baseURL:         strings.TrimRight(baseURL, "/"),
+		feedRepo:        feedRepo,
+		calendarService: calendarService,
+	}
+}
+
+// GetFeedURL returns the user's feed URL, creating a token the first time
+func (s *calendarFeedService) GetFeedURL(ctx context.Context, userID uint64) (string, error) {
+	feedToken, err := s.feedRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if feedToken == nil {
+		return s.RotateFeedToken(ctx, userID)
+	}
+	return s.feedURL(feedToken.Token), nil
+}
+
+// RotateFeedToken replaces the user's token, existing subscriptions stop working
+func (s *calendarFeedService) RotateFeedToken(ctx context.Context, userID uint64) (string, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	feedToken, err := s.feedRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if feedToken == nil {
+		feedToken = &models.CalendarFeedToken{UserID: userID}
+	}
+
+	token, err := newCalendarFeedToken()
+	if err != nil {
+		return "", err
+	}
+	feedToken.Token = token
+
+	if err := s.feedRepo.Save(ctx, feedToken); err != nil {
+		return "", err
+	}
+
+	log.Info().Uint64("userID", userID).Msg("Issued calendar feed token")
+
+	return s.feedURL(token), nil
+}
+
+// GetFeed renders the calendar of the token's owner as an ICS document
+func (s *calendarFeedService) GetFeed(ctx context.Context, token string, filter *models.CalendarFilter) ([]byte, error) {
+	if token == "" {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	feedToken, err := s.feedRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if feedToken == nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	entries, err := s.calendarService.GetCalendar(
+		ctx,
+		feedToken.UserID,
+		today.AddDate(0, 0, -calendarFeedPastDays),
+		today.AddDate(0, 0, calendarFeedFutureDays),
+		filter,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{
+		ProdID:          "-//Suasor//Release Calendar//EN",
+		Name:            "Suasor releases",
+		RefreshInterval: calendarFeedRefreshInterval,
+		Events:          make([]ical.Event, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		calendar.Events = append(calendar.Events, calendarEvent(entry))
+	}
+
+	return calendar.Bytes(), nil
+}
+
+func (s *calendarFeedService) feedURL(token string) string {
+	return fmt.Sprintf("%s/api/v1/calendar/%s.ics", s.baseURL, token)
+}
+
+// newCalendarFeedToken generates a random 256 bit token
+func newCalendarFeedToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate calendar feed token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// calendarEvent converts a calendar entry to an all-day event
+func calendarEvent(entry *models.CalendarEntry) ical.Event {
+	// The UID is derived from the dedup key so an event stays the same across refreshes
+	uid := sha256.Sum256([]byte(calendarEntryKeys(entry)[0]))
+
+	summary := entry.Title
+	if entry.MediaType == mediatypes.MediaTypeEpisode {
+		summary += " (new episode)"
+	}
+
+	sources := make([]string, len(entry.Sources))
+	for i, source := range entry.Sources {
+		sources[i] = string(source)
+	}
+	description := entry.Overview
+	if len(sources) > 0 {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += "From: " + strings.Join(sources, ", ")
+	}
+
+	return ical.Event{
+		UID:         hex.EncodeToString(uid[:16]) + "@suasor",
+		Summary:     summary,
+		Description: description,
+		Categories:  []string{string(entry.MediaType)},
+		Date:        entry.ReleaseDate,
+	}
+}
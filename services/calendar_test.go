@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mediatypes "suasor/clients/media/types"
+	"suasor/clients/metadata"
+	"suasor/repository"
+	"suasor/types/models"
+)
+
+func TestMergeCalendarEntries(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 30)
+
+	radarr := &models.CalendarEntry{
+		Title:       "Dune",
+		MediaType:   mediatypes.MediaTypeMovie,
+		ReleaseDate: start.AddDate(0, 0, 10),
+		ExternalIDs: mediatypes.ExternalIDs{{Source: "tmdb", ID: "438631"}},
+		Sources:     []models.CalendarSource{models.CalendarSourceAutomation},
+		ClientID:    3,
+	}
+	tmdb := &models.CalendarEntry{
+		Title:       "Dune",
+		Overview:    "Paul Atreides arrives on Arrakis",
+		MediaType:   mediatypes.MediaTypeMovie,
+		ReleaseDate: start.AddDate(0, 0, 2),
+		ExternalIDs: mediatypes.ExternalIDs{{Source: "tmdb", ID: "438631"}, {Source: "imdb", ID: "tt1160419"}},
+		Sources:     []models.CalendarSource{models.CalendarSourceMetadata},
+	}
+	episode := func(day int) *models.CalendarEntry {
+		return &models.CalendarEntry{
+			Title:       "Severance",
+			MediaType:   mediatypes.MediaTypeEpisode,
+			ReleaseDate: start.AddDate(0, 0, day),
+			ExternalIDs: mediatypes.ExternalIDs{{Source: "tvdb", ID: "371980"}},
+			Sources:     []models.CalendarSource{models.CalendarSourceAutomation},
+		}
+	}
+	outOfRange := &models.CalendarEntry{
+		Title:       "Later",
+		MediaType:   mediatypes.MediaTypeMovie,
+		ReleaseDate: end.AddDate(0, 0, 1),
+	}
+
+	merged := mergeCalendarEntries(start, end, []*models.CalendarEntry{radarr, episode(5), episode(12), tmdb, outOfRange})
+	require.Len(t, merged, 3)
+
+	assert.Equal(t, "Severance", merged[0].Title)
+	assert.Same(t, radarr, merged[1], "the first entry for a release keeps its date")
+	assert.Equal(t, "Severance", merged[2].Title, "episodes of one series are kept apart by air date")
+
+	assert.Equal(t, start.AddDate(0, 0, 10), radarr.ReleaseDate)
+	assert.Equal(t, "Paul Atreides arrives on Arrakis", radarr.Overview)
+	assert.Equal(t, "tt1160419", radarr.ExternalIDs.GetID("imdb"))
+	assert.True(t, radarr.HasSource(models.CalendarSourceMetadata))
+	assert.Equal(t, uint64(3), radarr.ClientID)
+}
+
+type mockCalendarRecommendationRepository struct {
+	repository.RecommendationRepository
+	recommendations []models.Recommendation
+}
+
+func (m *mockCalendarRecommendationRepository) GetByUserID(ctx context.Context, userID uint64, limit, offset int) ([]models.Recommendation, error) {
+	return m.recommendations, nil
+}
+
+// fakeMetadataClient releases every movie on the first of the month and records how many lookups ran at once
+type fakeMetadataClient struct {
+	metadata.ClientMetadata
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (c *fakeMetadataClient) GetMovie(ctx context.Context, id string) (*metadata.Movie, error) {
+	c.mu.Lock()
+	c.inFlight++
+	c.maxInFlight = max(c.maxInFlight, c.inFlight)
+	c.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return &metadata.Movie{ID: id, Title: "Movie " + id, ReleaseDate: "2026-03-01"}, nil
+}
+
+func TestGetRecommendationEntries(t *testing.T) {
+	var recommendations []models.Recommendation
+	for i := 0; i < 3*calendarLookupConcurrency; i++ {
+		recommendations = append(recommendations, models.Recommendation{
+			MediaType:   mediatypes.MediaTypeMovie,
+			Year:        2026,
+			ExternalIDs: &models.ExternalIDMap{"tmdb": strconv.Itoa(i)},
+		})
+	}
+	recommendations = append(recommendations, models.Recommendation{
+		MediaType:   mediatypes.MediaTypeMovie,
+		ExternalIDs: &models.ExternalIDMap{"tmdb": "owned"},
+		InLibrary:   true,
+	})
+
+	service := &calendarService{recommendationRepo: &mockCalendarRecommendationRepository{recommendations: recommendations}}
+	client := &fakeMetadataClient{}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	entries := service.getRecommendationEntries(context.Background(), 1, client, start, start.AddDate(1, 0, 0))
+
+	require.Len(t, entries, 3*calendarLookupConcurrency, "items in the library are skipped")
+	assert.Equal(t, "Movie 0", entries[0].Title, "the recommendation order is kept")
+	assert.Equal(t, "Movie 1", entries[1].Title)
+	assert.Greater(t, client.maxInFlight, 1, "lookups run in parallel")
+	assert.LessOrEqual(t, client.maxInFlight, calendarLookupConcurrency)
+}
@@ -30,7 +30,7 @@ type AutomationClientService interface {
 	GetMetadataProfiles(ctx context.Context, userID uint64, clientID uint64) ([]automationtypes.MetadataProfile, error)
 	GetTags(ctx context.Context, userID uint64, clientID uint64) ([]automationtypes.Tag, error)
 	CreateTag(ctx context.Context, userID uint64, clientID uint64, req requests.AutomationCreateTagRequest) (*automationtypes.Tag, error)
-	GetCalendar(ctx context.Context, userID uint64, clientID uint64, startDate, endDate time.Time) ([]*models.AutomationCalendarItem[automationtypes.AutomationData], error)
+	GetCalendar(ctx context.Context, userID uint64, clientID uint64, startDate, endDate time.Time) ([]models.AutomationMediaItem[automationtypes.AutomationData], error)
 	ExecuteCommand(ctx context.Context, userID uint64, clientID uint64, req requests.AutomationExecuteCommandRequest) (*automationtypes.CommandResult, error)
 }
 
@@ -249,7 +249,7 @@ func (s *automationClientService) CreateTag(ctx context.Context, userID uint64,
 	return &tag, nil
 }
 
-func (s *automationClientService) GetCalendar(ctx context.Context, userID uint64, clientID uint64, startDate, endDate time.Time) ([]*models.AutomationCalendarItem[automationtypes.AutomationData], error) {
+func (s *automationClientService) GetCalendar(ctx context.Context, userID uint64, clientID uint64, startDate, endDate time.Time) ([]models.AutomationMediaItem[automationtypes.AutomationData], error) {
 	client, err := s.getAutomationClient(ctx, userID, clientID)
 	if err != nil {
 		return nil, err
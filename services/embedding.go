@@ -0,0 +1,451 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"suasor/clients"
+	"suasor/clients/ai"
+	mediatypes "suasor/clients/media/types"
+	clienttypes "suasor/clients/types"
+	"suasor/repository"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+const (
+	// embeddingRequestSize is the number of texts sent in one embeddings request
+	embeddingRequestSize = 64
+	// embeddingPageSize is the number of library items loaded at once while indexing
+	embeddingPageSize = 500
+	// embeddingMaxCast is the number of billed cast members included in the embedded text
+	embeddingMaxCast = 8
+	// similarItemsMaxLimit caps similar item and semantic search results
+	similarItemsMaxLimit = 100
+)
+
+var (
+	// ErrNoEmbeddingClient is returned when no enabled AI client can create embeddings
+	ErrNoEmbeddingClient = errors.New("no enabled AI client supports embeddings")
+	// ErrEmbeddingItemNotFound is returned when the item to find similar items for does not exist
+	ErrEmbeddingItemNotFound = errors.New("media item not found")
+)
+
+// embeddedMediaTypes are the media types indexed by IndexMediaItems and searched by default
+var embeddedMediaTypes = []mediatypes.MediaType{
+	mediatypes.MediaTypeMovie,
+	mediatypes.MediaTypeSeries,
+	mediatypes.MediaTypeAlbum,
+	mediatypes.MediaTypeArtist,
+}
+
+// EmbeddingService embeds library items with an AI client and finds items by meaning.
+// The library is shared, so the embeddings are created with the first enabled OpenAI client, or Ollama client if there is none.
+type EmbeddingService interface {
+	// IndexMediaItems embeds the movies, series, albums and artists whose text changed since they were last embedded.
+	// It returns the number of items embedded.
+	IndexMediaItems(ctx context.Context) (int, error)
+	// GetSimilarItems returns the items of the same type nearest to the item, best first.
+	// Items that were not indexed yet are embedded on demand.
+	GetSimilarItems(ctx context.Context, itemID uint64, limit int) ([]models.EmbeddingMatch, error)
+	// SemanticSearch returns the items nearest to the meaning of the query, best first.
+	// An empty media type searches all indexed types.
+	SemanticSearch(ctx context.Context, query string, mediaType mediatypes.MediaType, limit int) ([]models.EmbeddingMatch, error)
+}
+
+type embeddingService struct {
+	embeddingRepo repository.EmbeddingRepository
+	itemRepos     repobundles.CoreMediaItemRepositories
+	creditRepo    repository.CreditRepository
+	clientRepos   repobundles.ClientRepositories
+	clientFactory *clients.ClientProviderFactoryService
+}
+
+// NewEmbeddingService creates a new embedding service
+func NewEmbeddingService(
+	embeddingRepo repository.EmbeddingRepository,
+	itemRepos repobundles.CoreMediaItemRepositories,
+	creditRepo repository.CreditRepository,
+	clientRepos repobundles.ClientRepositories,
+	clientFactory *clients.ClientProviderFactoryService,
+) EmbeddingService {
+	return &embeddingService{
+		embeddingRepo: embeddingRepo,
+		itemRepos:     itemRepos,
+		creditRepo:    creditRepo,
+		clientRepos:   clientRepos,
+		clientFactory: clientFactory,
+	}
+}
+
+// embeddingSource is the text of a media item to embed
+type embeddingSource struct {
+	MediaItemID uint64
+	MediaType   mediatypes.MediaType
+	Text        string
+	Hash        string
+}
+
+func (s *embeddingService) IndexMediaItems(ctx context.Context) (int, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	aiClient, model, err := s.embeddingClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+	hashes, err := s.embeddingRepo.GetContentHashes(ctx, model)
+	if err != nil {
+		return 0, err
+	}
+
+	// Collect the items whose text changed
+	var changed []embeddingSource
+	collect := func(sources []embeddingSource) {
+		for _, source := range sources {
+			if hashes[source.MediaItemID] != source.Hash {
+				changed = append(changed, source)
+			}
+		}
+	}
+	if err := collectSources(ctx, s.itemRepos.MovieRepo(), collect); err != nil {
+		return 0, err
+	}
+	if err := collectSources(ctx, s.itemRepos.SeriesRepo(), collect); err != nil {
+		return 0, err
+	}
+	if err := collectSources(ctx, s.itemRepos.AlbumRepo(), collect); err != nil {
+		return 0, err
+	}
+	if err := collectSources(ctx, s.itemRepos.ArtistRepo(), collect); err != nil {
+		return 0, err
+	}
+
+	log.Info().
+		Str("model", model).
+		Int("items", len(changed)).
+		Msg("Embedding changed media items")
+
+	embedded := 0
+	for start := 0; start < len(changed); start += embeddingRequestSize {
+		end := min(start+embeddingRequestSize, len(changed))
+		if err := s.embedSources(ctx, aiClient, model, changed[start:end]); err != nil {
+			return embedded, err
+		}
+		embedded += end - start
+	}
+	return embedded, nil
+}
+
+func (s *embeddingService) GetSimilarItems(ctx context.Context, itemID uint64, limit int) ([]models.EmbeddingMatch, error) {
+	aiClient, model, err := s.embeddingClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	embedding, err := s.embeddingRepo.GetByMediaItemID(ctx, itemID, model)
+	if err != nil {
+		return nil, err
+	}
+	if embedding == nil {
+		if embedding, err = s.embedItem(ctx, aiClient, model, itemID); err != nil {
+			return nil, err
+		}
+	}
+
+	matches, err := s.embeddingRepo.FindNearest(ctx, embedding.Vector, model,
+		[]mediatypes.MediaType{embedding.MediaType}, []uint64{itemID}, similarLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	return s.withItems(ctx, matches)
+}
+
+func (s *embeddingService) SemanticSearch(ctx context.Context, query string, mediaType mediatypes.MediaType, limit int) ([]models.EmbeddingMatch, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().
+		Str("query", query).
+		Str("mediaType", string(mediaType)).
+		Msg("Searching media items by meaning")
+
+	aiClient, model, err := s.embeddingClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response, err := aiClient.CreateEmbeddings(ctx, []string{query}, model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed search query: %w", err)
+	}
+	if len(response.Embeddings) != 1 {
+		return nil, fmt.Errorf("expected 1 embedding for the search query, got %d", len(response.Embeddings))
+	}
+
+	mediaTypes := embeddedMediaTypes
+	if mediaType != "" {
+		mediaTypes = []mediatypes.MediaType{mediaType}
+	}
+	matches, err := s.embeddingRepo.FindNearest(ctx, response.Embeddings[0], model, mediaTypes, nil, similarLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	return s.withItems(ctx, matches)
+}
+
+// embeddingClient returns the AI client used for all embeddings and its embedding model
+func (s *embeddingService) embeddingClient(ctx context.Context) (ai.ClientAI, string, error) {
+	openaiClients, err := s.clientRepos.OpenAIRepo().GetAll(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	clientID, config := lowestEnabledClient(openaiClients)
+	if config == nil {
+		ollamaClients, err := s.clientRepos.OllamaRepo().GetAll(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		clientID, config = lowestEnabledClient(ollamaClients)
+	}
+	if config == nil {
+		return nil, "", ErrNoEmbeddingClient
+	}
+
+	client, err := s.clientFactory.GetClient(ctx, clientID, config)
+	if err != nil {
+		return nil, "", err
+	}
+	aiClient, ok := client.(ai.ClientAI)
+	if !ok {
+		return nil, "", ErrNoEmbeddingClient
+	}
+	capabilities := aiClient.GetCapabilities()
+	if !capabilities.SupportsEmbeddings || capabilities.EmbeddingModel == "" {
+		return nil, "", ErrNoEmbeddingClient
+	}
+	return aiClient, capabilities.EmbeddingModel, nil
+}
+
+// embedItem embeds a single item of any type and stores its embedding
+func (s *embeddingService) embedItem(ctx context.Context, aiClient ai.ClientAI, model string, itemID uint64) (*models.MediaItemEmbedding, error) {
+	results, err := s.itemRepos.MovieRepo().GetMixedMediaItemsByIDs(ctx, []uint64{itemID})
+	if err != nil {
+		return nil, err
+	}
+	sources := resultSources(results)
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrEmbeddingItemNotFound, itemID)
+	}
+	s.addCredits(ctx, sources)
+
+	embeddings, err := s.createEmbeddings(ctx, aiClient, model, sources)
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// embedSources embeds a batch of sources with their credits and stores the embeddings
+func (s *embeddingService) embedSources(ctx context.Context, aiClient ai.ClientAI, model string, sources []embeddingSource) error {
+	s.addCredits(ctx, sources)
+	_, err := s.createEmbeddings(ctx, aiClient, model, sources)
+	return err
+}
+
+// createEmbeddings embeds the texts of the sources and stores the embeddings
+func (s *embeddingService) createEmbeddings(ctx context.Context, aiClient ai.ClientAI, model string, sources []embeddingSource) ([]*models.MediaItemEmbedding, error) {
+	texts := make([]string, len(sources))
+	for i, source := range sources {
+		texts[i] = source.Text
+	}
+	response, err := aiClient.CreateEmbeddings(ctx, texts, model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings: %w", err)
+	}
+	if len(response.Embeddings) != len(sources) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(sources), len(response.Embeddings))
+	}
+
+	embeddings := make([]*models.MediaItemEmbedding, len(sources))
+	for i, source := range sources {
+		embeddings[i] = &models.MediaItemEmbedding{
+			MediaItemID: source.MediaItemID,
+			MediaType:   source.MediaType,
+			Model:       model,
+			ContentHash: source.Hash,
+			Vector:      response.Embeddings[i],
+		}
+		if err := s.embeddingRepo.Save(ctx, embeddings[i]); err != nil {
+			return nil, err
+		}
+	}
+	return embeddings, nil
+}
+
+// addCredits appends the directors and billed cast to the texts of the sources.
+// The hash is taken before, so credits that were synced later do not cause the item to be embedded again.
+func (s *embeddingService) addCredits(ctx context.Context, sources []embeddingSource) {
+	log := logger.LoggerFromContext(ctx)
+	for i := range sources {
+		credits, err := s.creditRepo.GetByMediaItemID(ctx, sources[i].MediaItemID)
+		if err != nil {
+			log.Warn().Err(err).Uint64("mediaItemID", sources[i].MediaItemID).Msg("Failed to get credits for embedding")
+			continue
+		}
+		sources[i].Text += creditsText(credits)
+	}
+}
+
+// withItems loads the media items of the matches, dropping matches whose item no longer exists
+func (s *embeddingService) withItems(ctx context.Context, matches []models.EmbeddingMatch) ([]models.EmbeddingMatch, error) {
+	if len(matches) == 0 {
+		return matches, nil
+	}
+	ids := make([]uint64, len(matches))
+	for i, match := range matches {
+		ids[i] = match.MediaItemID
+	}
+	results, err := s.itemRepos.MovieRepo().GetMixedMediaItemsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[uint64]any, len(ids))
+	indexResultItems(items, results.Movies)
+	indexResultItems(items, results.Series)
+	indexResultItems(items, results.Seasons)
+	indexResultItems(items, results.Episodes)
+	indexResultItems(items, results.Tracks)
+	indexResultItems(items, results.Albums)
+	indexResultItems(items, results.Artists)
+	indexResultItems(items, results.Playlists)
+	indexResultItems(items, results.Collections)
+
+	found := make([]models.EmbeddingMatch, 0, len(matches))
+	for _, match := range matches {
+		if item, ok := items[match.MediaItemID]; ok {
+			match.Item = item
+			found = append(found, match)
+		}
+	}
+	return found, nil
+}
+
+// collectSources pages through all items of one repository
+func collectSources[T mediatypes.MediaData](ctx context.Context, repo repository.CoreMediaItemRepository[T], collect func([]embeddingSource)) error {
+	for offset := 0; ; offset += embeddingPageSize {
+		items, err := repo.GetAll(ctx, embeddingPageSize, offset, false)
+		if err != nil {
+			return err
+		}
+		collect(appendSources(nil, items))
+		if len(items) < embeddingPageSize {
+			return nil
+		}
+	}
+}
+
+func similarLimit(limit int) int {
+	if limit <= 0 {
+		return 20
+	}
+	return min(limit, similarItemsMaxLimit)
+}
+
+// lowestEnabledClient returns the enabled client with the lowest ID, so the choice is stable
+func lowestEnabledClient[T clienttypes.ClientConfig](clientList []*models.Client[T]) (uint64, clienttypes.ClientConfig) {
+	clientMap := make(map[uint64]*models.Client[T], len(clientList))
+	for _, client := range clientList {
+		clientMap[client.ID] = client
+	}
+	return firstEnabledClient(clientMap)
+}
+
+// resultSources returns the sources of all items in the results
+func resultSources(results *models.MediaItemResults) []embeddingSource {
+	var sources []embeddingSource
+	sources = appendSources(sources, results.Movies)
+	sources = appendSources(sources, results.Series)
+	sources = appendSources(sources, results.Seasons)
+	sources = appendSources(sources, results.Episodes)
+	sources = appendSources(sources, results.Tracks)
+	sources = appendSources(sources, results.Albums)
+	sources = appendSources(sources, results.Artists)
+	sources = appendSources(sources, results.Playlists)
+	sources = appendSources(sources, results.Collections)
+	return sources
+}
+
+func appendSources[T mediatypes.MediaData](sources []embeddingSource, items []*models.MediaItem[T]) []embeddingSource {
+	for _, item := range items {
+		text := embeddingText(item)
+		hash := sha256.Sum256([]byte(text))
+		sources = append(sources, embeddingSource{
+			MediaItemID: item.ID,
+			MediaType:   item.Type,
+			Text:        text,
+			Hash:        hex.EncodeToString(hash[:]),
+		})
+	}
+	return sources
+}
+
+func indexResultItems[T mediatypes.MediaData](items map[uint64]any, results []*models.MediaItem[T]) {
+	for _, item := range results {
+		items[item.ID] = item
+	}
+}
+
+// embeddingText describes an item by its title, year, description and genres
+func embeddingText[T mediatypes.MediaData](item *models.MediaItem[T]) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s: %s", item.Type, item.Title)
+	year := item.ReleaseYear
+	details := item.GetData().GetDetails()
+	if details != nil && year == 0 {
+		year = details.ReleaseYear
+	}
+	if year > 0 {
+		fmt.Fprintf(&text, " (%d)", year)
+	}
+	text.WriteString("\n")
+	if details == nil {
+		return text.String()
+	}
+	if len(details.Genres) > 0 {
+		fmt.Fprintf(&text, "Genres: %s\n", strings.Join(details.Genres, ", "))
+	}
+	if len(details.Tags) > 0 {
+		fmt.Fprintf(&text, "Tags: %s\n", strings.Join(details.Tags, ", "))
+	}
+	if details.Description != "" {
+		text.WriteString(details.Description)
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+// creditsText lists the directors and the billed cast of an item
+func creditsText(credits []*models.Credit) string {
+	var directors, cast []string
+	sort.SliceStable(credits, func(i, j int) bool { return credits[i].Order < credits[j].Order })
+	for _, credit := range credits {
+		switch {
+		case credit.Role == models.RoleDirector:
+			directors = append(directors, credit.Name)
+		case credit.IsCast && len(cast) < embeddingMaxCast:
+			cast = append(cast, credit.Name)
+		}
+	}
+
+	var text strings.Builder
+	if len(directors) > 0 {
+		fmt.Fprintf(&text, "Directed by: %s\n", strings.Join(directors, ", "))
+	}
+	if len(cast) > 0 {
+		fmt.Fprintf(&text, "Starring: %s\n", strings.Join(cast, ", "))
+	}
+	return text.String()
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	mediatypes "suasor/clients/media/types"
+	"suasor/repository"
+	"suasor/services/jobs/recommendation"
+	"suasor/types/models"
+)
+
+var (
+	// ErrGroupNotFound is returned when the household group does not exist
+	ErrGroupNotFound = errors.New("household group not found")
+	// ErrGroupAccessDenied is returned when the user is not a member of the household group
+	ErrGroupAccessDenied = errors.New("user is not a member of this household group")
+	// ErrGroupMemberNotFound is returned when a member of a group is not a user
+	ErrGroupMemberNotFound = errors.New("group member not found")
+	// ErrInvalidGroupStrategy is returned for a group strategy that isn't least_misery or average
+	ErrInvalidGroupStrategy = errors.New("invalid group strategy")
+)
+
+// GroupRecommendationService recommends library items for groups of users to watch together
+// and manages the saved household groups
+type GroupRecommendationService interface {
+	// RecommendForMembers recommends items for the user and the other members to watch together
+	RecommendForMembers(ctx context.Context, userID uint64, memberIDs []uint64, mediaType mediatypes.MediaType, strategy models.GroupStrategy, limit int) (*recommendation.GroupRecommendations, error)
+	// RecommendForGroup recommends items for a saved group the user is a member of, with the group's strategy unless one is given
+	RecommendForGroup(ctx context.Context, userID uint64, groupID uint64, mediaType mediatypes.MediaType, strategy models.GroupStrategy, limit int) (*recommendation.GroupRecommendations, error)
+	// CreateGroup saves a group owned by the user, the owner is always a member
+	CreateGroup(ctx context.Context, ownerID uint64, name string, memberIDs []uint64, strategy models.GroupStrategy) (*models.HouseholdGroup, error)
+	// GetGroups returns the groups the user owns or is a member of
+	GetGroups(ctx context.Context, userID uint64) ([]*models.HouseholdGroup, error)
+	// DeleteGroup deletes a group, only its owner can
+	DeleteGroup(ctx context.Context, userID uint64, groupID uint64) error
+}
+
+type groupRecommendationService struct {
+	userRepo    repository.UserRepository
+	groupRepo   repository.HouseholdGroupRepository
+	recommender *recommendation.GroupRecommender
+}
+
+// NewGroupRecommendationService creates a new group recommendation service
+func NewGroupRecommendationService(
+	userRepo repository.UserRepository,
+	groupRepo repository.HouseholdGroupRepository,
+	recommender *recommendation.GroupRecommender,
+) GroupRecommendationService {
+	return &groupRecommendationService{
+		userRepo:    userRepo,
+		groupRepo:   groupRepo,
+		recommender: recommender,
+	}
+}
+
+// RecommendForMembers recommends items for the user and the other members to watch together
+func (s *groupRecommendationService) RecommendForMembers(
+	ctx context.Context,
+	userID uint64,
+	memberIDs []uint64,
+	mediaType mediatypes.MediaType,
+	strategy models.GroupStrategy,
+	limit int,
+) (*recommendation.GroupRecommendations, error) {
+	members, err := s.members(ctx, userID, memberIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.recommend(ctx, members, mediaType, strategy, limit)
+}
+
+// RecommendForGroup recommends items for a saved group the user is a member of
+func (s *groupRecommendationService) RecommendForGroup(
+	ctx context.Context,
+	userID uint64,
+	groupID uint64,
+	mediaType mediatypes.MediaType,
+	strategy models.GroupStrategy,
+	limit int,
+) (*recommendation.GroupRecommendations, error) {
+	group, err := s.group(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.OwnerID != userID && !group.HasMember(userID) {
+		return nil, ErrGroupAccessDenied
+	}
+
+	if strategy == "" {
+		strategy = group.Strategy
+	}
+	return s.recommend(ctx, group.MemberIDs, mediaType, strategy, limit)
+}
+
+// CreateGroup saves a group owned by the user
+func (s *groupRecommendationService) CreateGroup(
+	ctx context.Context,
+	ownerID uint64,
+	name string,
+	memberIDs []uint64,
+	strategy models.GroupStrategy,
+) (*models.HouseholdGroup, error) {
+	if strategy != "" && !strategy.IsValid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidGroupStrategy, strategy)
+	}
+	members, err := s.members(ctx, ownerID, memberIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &models.HouseholdGroup{
+		OwnerID:   ownerID,
+		Name:      name,
+		MemberIDs: members,
+		Strategy:  strategy,
+	}
+	if err := s.groupRepo.Create(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetGroups returns the groups the user owns or is a member of
+func (s *groupRecommendationService) GetGroups(ctx context.Context, userID uint64) ([]*models.HouseholdGroup, error) {
+	return s.groupRepo.GetByUserID(ctx, userID)
+}
+
+// DeleteGroup deletes a group, only its owner can
+func (s *groupRecommendationService) DeleteGroup(ctx context.Context, userID uint64, groupID uint64) error {
+	group, err := s.group(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	if group.OwnerID != userID {
+		return ErrGroupAccessDenied
+	}
+	return s.groupRepo.Delete(ctx, groupID)
+}
+
+func (s *groupRecommendationService) recommend(
+	ctx context.Context,
+	memberIDs []uint64,
+	mediaType mediatypes.MediaType,
+	strategy models.GroupStrategy,
+	limit int,
+) (*recommendation.GroupRecommendations, error) {
+	if strategy != "" && !strategy.IsValid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidGroupStrategy, strategy)
+	}
+	return s.recommender.Recommend(ctx, memberIDs, mediaType, strategy, limit)
+}
+
+func (s *groupRecommendationService) group(ctx context.Context, groupID uint64) (*models.HouseholdGroup, error) {
+	group, err := s.groupRepo.GetByID(ctx, groupID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrGroupNotFound
+	}
+	return group, err
+}
+
+// members returns the user followed by the other members without duplicates, checking every member is a user
+func (s *groupRecommendationService) members(ctx context.Context, userID uint64, memberIDs []uint64) ([]uint64, error) {
+	members := []uint64{userID}
+	seen := map[uint64]bool{userID: true}
+	for _, memberID := range memberIDs {
+		if seen[memberID] {
+			continue
+		}
+		if _, err := s.userRepo.FindByID(ctx, memberID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, fmt.Errorf("%w: %d", ErrGroupMemberNotFound, memberID)
+			}
+			return nil, err
+		}
+		seen[memberID] = true
+		members = append(members, memberID)
+	}
+	return members, nil
+}
@@ -0,0 +1,83 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"suasor/repository"
+	"suasor/services"
+	"suasor/types/models"
+)
+
+// EmbeddingJob embeds the title, description, genres and credits of library items for semantic search
+type EmbeddingJob struct {
+	jobRepo          repository.JobRepository
+	embeddingService services.EmbeddingService
+}
+
+// NewEmbeddingJob creates a new embedding job
+func NewEmbeddingJob(
+	jobRepo repository.JobRepository,
+	embeddingService services.EmbeddingService,
+) *EmbeddingJob {
+	return &EmbeddingJob{
+		jobRepo:          jobRepo,
+		embeddingService: embeddingService,
+	}
+}
+
+// Name returns the unique name of the job
+func (j *EmbeddingJob) Name() string {
+	return "system.embeddings"
+}
+
+// Schedule returns when the job should next run
+func (j *EmbeddingJob) Schedule() time.Duration {
+	// Only changed items are embedded, so running daily is cheap
+	return 24 * time.Hour
+}
+
+// Execute runs the embedding job
+func (j *EmbeddingJob) Execute(ctx context.Context) error {
+	log.Println("Starting embedding job")
+
+	// Create a job run record
+	now := time.Now()
+	jobRun := &models.JobRun{
+		JobName:   j.Name(),
+		JobType:   models.JobTypeSystem,
+		Status:    models.JobStatusRunning,
+		StartTime: &now,
+		Metadata:  fmt.Sprintf(`{"type":"embeddings","startTime":"%s"}`, now.Format(time.RFC3339)),
+	}
+
+	if err := j.jobRepo.CreateJobRun(ctx, jobRun); err != nil {
+		log.Printf("Error creating job run record: %v", err)
+		return err
+	}
+
+	embedded, err := j.embeddingService.IndexMediaItems(ctx)
+	if errors.Is(err, services.ErrNoEmbeddingClient) {
+		// Nothing to do until an OpenAI or Ollama client is configured
+		log.Println("No AI client supports embeddings, skipping embedding job")
+		err = nil
+	}
+
+	status := models.JobStatusCompleted
+	errorMessage := ""
+	if err != nil {
+		log.Printf("Error embedding media items: %v", err)
+		status = models.JobStatusFailed
+		errorMessage = err.Error()
+	}
+
+	if completeErr := j.jobRepo.CompleteJobRun(ctx, jobRun.ID, status, errorMessage); completeErr != nil {
+		log.Printf("Error completing job run: %v", completeErr)
+	}
+
+	log.Printf("Embedding job completed, %d items embedded", embedded)
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	mediatypes "suasor/clients/media/types"
+	clienttypes "suasor/clients/types"
 	"suasor/repository"
 	"suasor/services/jobs/recommendation"
 	"suasor/services/jobs/sync"
@@ -63,7 +64,7 @@ type JobService interface {
 	UpdateRecommendationViewedStatus(ctx context.Context, recommendationID uint64, viewed bool) error
 
 	// SetupMediaSyncJob creates or updates a media sync job
-	SetupMediaSyncJob(ctx context.Context, userID, clientID uint64, clientType string, syncType models.SyncType, frequency string) error
+	SetupMediaSyncJob(ctx context.Context, userID, clientID uint64, clientType string, syncType models.SyncType, frequency string, filters *models.MediaSyncFilters) error
 	// RunMediaSyncJob runs a media sync job manually
 	RunMediaSyncJob(ctx context.Context, userID, clientID uint64, syncType models.SyncType) error
 	// GetMediaSyncJobs retrieves all media sync jobs for a user
@@ -333,7 +334,7 @@ func (s *jobService) UpdateRecommendationViewedStatus(ctx context.Context, recom
 }
 
 // SetupMediaSyncJob creates or updates a media sync job
-func (s *jobService) SetupMediaSyncJob(ctx context.Context, userID, clientID uint64, clientType string, syncType models.SyncType, frequency string) error {
+func (s *jobService) SetupMediaSyncJob(ctx context.Context, userID, clientID uint64, clientType string, syncType models.SyncType, frequency string, filters *models.MediaSyncFilters) error {
 	// Validate inputs
 	if userID == 0 || clientID == 0 || syncType == "" || frequency == "" {
 		return fmt.Errorf("invalid input parameters")
@@ -349,7 +350,34 @@ func (s *jobService) SetupMediaSyncJob(ctx context.Context, userID, clientID uin
 	}
 
 	// Create or update the sync job
-	return s.recommendationJob.SetupMediaSyncJob(ctx, userID, clientID, clientType, syncType, frequency)
+	syncJob, err := s.jobRepo.GetMediaSyncJobByClient(ctx, clientID, syncType)
+	if err != nil {
+		return fmt.Errorf("error finding media sync job: %w", err)
+	}
+	if syncJob == nil {
+		syncJob = &models.MediaSyncJob{
+			UserID:     userID,
+			ClientID:   clientID,
+			ClientType: clienttypes.ClientType(clientType),
+			SyncType:   syncType,
+			Enabled:    true,
+			Filters:    "{}",
+		}
+	} else if syncJob.UserID != userID {
+		return fmt.Errorf("media sync job for client %d belongs to another user", clientID)
+	}
+
+	syncJob.Frequency = frequency
+	if filters != nil {
+		if err := syncJob.SetFilters(filters); err != nil {
+			return fmt.Errorf("error setting media sync filters: %w", err)
+		}
+	}
+
+	if syncJob.ID == 0 {
+		return s.jobRepo.CreateMediaSyncJob(ctx, syncJob)
+	}
+	return s.jobRepo.UpdateMediaSyncJob(ctx, syncJob)
 }
 
 // RunMediaSyncJob runs a media sync job manually in the background
@@ -0,0 +1,408 @@
+package recommendation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	mediatypes "suasor/clients/media/types"
+	"suasor/repository"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"time"
+)
+
+// SimilarUsersRecommender is the RecommendedBy of the recommendations made by collaborative filtering
+const SimilarUsersRecommender = "similar_users"
+
+const (
+	// defaultCollaborativeLimit is the number of recommendations per media type when the user has no maximum set
+	defaultCollaborativeLimit = 20
+	// collaborativeExpiry is how long a collaborative filtering recommendation is shown, the job replaces them daily
+	collaborativeExpiry = 7 * 24 * time.Hour
+	// maxReasonTitles is the number of the user's items named in a recommendation's reasoning
+	maxReasonTitles = 3
+)
+
+// collaborativeTarget is a media type neighbours are computed for and the type its recommendations are stored as
+type collaborativeTarget struct {
+	itemType           mediatypes.MediaType
+	recommendationType mediatypes.MediaType
+}
+
+var collaborativeTargets = []collaborativeTarget{
+	{itemType: mediatypes.MediaTypeMovie, recommendationType: mediatypes.MediaTypeMovie},
+	{itemType: mediatypes.MediaTypeSeries, recommendationType: mediatypes.MediaTypeSeries},
+	{itemType: mediatypes.MediaTypeTrack, recommendationType: "music"},
+}
+
+// itemSummary is what a recommendation shows of a library item
+type itemSummary struct {
+	Title  string
+	Year   int
+	Genres []string
+}
+
+// CollaborativeJob recommends library items from what users with a similar play history watched and rated.
+// It needs no AI client: item-item neighbours are computed from the co-watch and co-rating matrices of all users,
+// then each user gets the neighbours of the items they liked.
+type CollaborativeJob struct {
+	jobRepo            repository.JobRepository
+	userRepo           repository.UserRepository
+	userConfigRepo     repository.UserConfigRepository
+	recommendationRepo repository.RecommendationRepository
+	feedbackRepo       repository.RecommendationFeedbackRepository
+	neighborRepo       repository.NeighborRepository
+	itemRepos          repobundles.CoreMediaItemRepositories
+}
+
+// NewCollaborativeJob creates a new collaborative filtering job
+func NewCollaborativeJob(
+	jobRepo repository.JobRepository,
+	userRepo repository.UserRepository,
+	userConfigRepo repository.UserConfigRepository,
+	recommendationRepo repository.RecommendationRepository,
+	feedbackRepo repository.RecommendationFeedbackRepository,
+	neighborRepo repository.NeighborRepository,
+	itemRepos repobundles.CoreMediaItemRepositories,
+) *CollaborativeJob {
+	return &CollaborativeJob{
+		jobRepo:            jobRepo,
+		userRepo:           userRepo,
+		userConfigRepo:     userConfigRepo,
+		recommendationRepo: recommendationRepo,
+		feedbackRepo:       feedbackRepo,
+		neighborRepo:       neighborRepo,
+		itemRepos:          itemRepos,
+	}
+}
+
+// Name returns the unique name of the job
+func (j *CollaborativeJob) Name() string {
+	return "system.recommendation.similar_users"
+}
+
+// Schedule returns when the job should next run
+func (j *CollaborativeJob) Schedule() time.Duration {
+	return 24 * time.Hour
+}
+
+// Execute precomputes the item neighbours and replaces every active user's collaborative filtering recommendations
+func (j *CollaborativeJob) Execute(ctx context.Context) error {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().Msg("Starting collaborative filtering job")
+
+	now := time.Now()
+	jobRun := &models.JobRun{
+		JobName:   j.Name(),
+		JobType:   models.JobTypeSystem,
+		Status:    models.JobStatusRunning,
+		StartTime: &now,
+		Metadata:  fmt.Sprintf(`{"type":"recommendation","recommender":"%s","startTime":"%s"}`, SimilarUsersRecommender, now.Format(time.RFC3339)),
+	}
+	if err := j.jobRepo.CreateJobRun(ctx, jobRun); err != nil {
+		log.Error().Err(err).Msg("Error creating job run record")
+		return err
+	}
+
+	err := j.run(ctx, jobRun.ID)
+
+	status := models.JobStatusCompleted
+	errorMessage := ""
+	if err != nil {
+		log.Error().Err(err).Msg("Collaborative filtering job failed")
+		status = models.JobStatusFailed
+		errorMessage = err.Error()
+	}
+	if completeErr := j.jobRepo.CompleteJobRun(ctx, jobRun.ID, status, errorMessage); completeErr != nil {
+		log.Error().Err(completeErr).Msg("Error completing job run")
+	}
+
+	return err
+}
+
+// run computes the neighbours of each media type and recommends them to the users
+func (j *CollaborativeJob) run(ctx context.Context, jobRunID uint64) error {
+	log := logger.LoggerFromContext(ctx)
+
+	itemTypes := make([]mediatypes.MediaType, 0, len(collaborativeTargets))
+	for _, target := range collaborativeTargets {
+		itemTypes = append(itemTypes, target.itemType)
+	}
+	interactions, err := j.neighborRepo.GetInteractions(ctx, itemTypes)
+	if err != nil {
+		return err
+	}
+
+	byType := make(map[mediatypes.MediaType][]models.ItemInteraction)
+	byUser := make(map[uint64][]models.ItemInteraction)
+	for _, interaction := range interactions {
+		byType[interaction.Type] = append(byType[interaction.Type], interaction)
+		byUser[interaction.UserID] = append(byUser[interaction.UserID], interaction)
+	}
+
+	for _, target := range collaborativeTargets {
+		neighbors := computeNeighbors(byType[target.itemType])
+		if err := j.neighborRepo.ReplaceNeighbors(ctx, target.itemType, neighbors); err != nil {
+			return err
+		}
+		log.Info().
+			Str("mediaType", string(target.itemType)).
+			Int("interactions", len(byType[target.itemType])).
+			Int("neighbors", len(neighbors)).
+			Msg("Computed item neighbours")
+	}
+
+	users, err := j.userRepo.FindAllActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active users: %w", err)
+	}
+
+	for _, user := range users {
+		if err := j.recommendForUser(ctx, jobRunID, user.ID, byUser[user.ID]); err != nil {
+			log.Error().Err(err).Uint64("userID", user.ID).Msg("Failed to create collaborative filtering recommendations")
+		}
+	}
+
+	log.Info().Int("users", len(users)).Msg("Collaborative filtering job completed")
+	return nil
+}
+
+// recommendForUser replaces the user's recommendations with the neighbours of the items they liked
+func (j *CollaborativeJob) recommendForUser(ctx context.Context, jobRunID uint64, userID uint64, interactions []models.ItemInteraction) error {
+	log := logger.LoggerFromContext(ctx).With().Uint64("userID", userID).Logger()
+
+	config, err := j.userConfigRepo.GetUserConfig(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user config: %w", err)
+	}
+	feedback, err := j.feedbackRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	signals := newFeedbackSignals(feedback)
+
+	var recommendations []*models.Recommendation
+	for _, target := range collaborativeTargets {
+		targetRecommendations, err := j.recommendForTarget(ctx, jobRunID, userID, config, signals, target, interactions)
+		if err != nil {
+			log.Warn().Err(err).Str("mediaType", string(target.itemType)).Msg("Failed to recommend from similar users")
+			continue
+		}
+		recommendations = append(recommendations, targetRecommendations...)
+	}
+
+	if err := j.recommendationRepo.ReplaceByRecommender(ctx, userID, SimilarUsersRecommender, recommendations); err != nil {
+		return err
+	}
+
+	log.Info().Int("count", len(recommendations)).Msg("Stored collaborative filtering recommendations")
+	return nil
+}
+
+// recommendForTarget ranks the neighbours of the user's items of one media type.
+// Items the user rejected as recommendations weigh against their neighbours.
+func (j *CollaborativeJob) recommendForTarget(
+	ctx context.Context,
+	jobRunID uint64,
+	userID uint64,
+	config *models.UserConfig,
+	signals *feedbackSignals,
+	target collaborativeTarget,
+	interactions []models.ItemInteraction,
+) ([]*models.Recommendation, error) {
+	seeds := make(map[uint64]float64)
+	exclude := make(map[uint64]bool)
+	for _, interaction := range dedupeInteractions(interactions) {
+		if interaction.Type != target.itemType {
+			continue
+		}
+		if weight := interactionWeight(interaction); weight > 0 {
+			seeds[interaction.MediaItemID] = weight
+		}
+		// Disliked items are never recommended, watched ones only when the user asked for them
+		if interaction.IsDisliked || (!config.RecommendationIncludeWatched && seenItem(interaction)) {
+			exclude[interaction.MediaItemID] = true
+		}
+	}
+	for id, weight := range signals.weights(target.recommendationType) {
+		seeds[id] += weight
+		if weight < 0 {
+			exclude[id] = true
+		}
+	}
+	if len(seeds) == 0 {
+		return nil, nil
+	}
+
+	seedIDs := make([]uint64, 0, len(seeds))
+	for id := range seeds {
+		seedIDs = append(seedIDs, id)
+	}
+	neighbors, err := j.neighborRepo.GetNeighbors(ctx, seedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := collaborativeLimit(config, target)
+	// Excluded genres are only known once the items are loaded, so more candidates than needed are loaded
+	candidates := firstN(scoreCandidates(seeds, neighbors, exclude), limit*3)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := append([]uint64{}, seedIDs...)
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.MediaItemID)
+	}
+	summaries, err := j.itemSummaries(ctx, target.itemType, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	excludedGenres := dismissedGenresFor(config, target, signals)
+	now := time.Now()
+	expiresAt := now.Add(collaborativeExpiry)
+
+	var recommendations []*models.Recommendation
+	for _, candidate := range candidates {
+		if len(recommendations) >= limit {
+			break
+		}
+		item, ok := summaries[candidate.MediaItemID]
+		if !ok || hasExcludedGenre(item.Genres, excludedGenres) {
+			continue
+		}
+		if signals.excludesItem(target.recommendationType, candidate.MediaItemID, item.Title, item.Year, nil) {
+			continue
+		}
+
+		var because []string
+		for _, seedID := range candidate.Seeds {
+			if seed, ok := summaries[seedID]; ok && seed.Title != "" {
+				because = append(because, seed.Title)
+			}
+		}
+
+		metadata, _ := json.Marshal(map[string]any{
+			"source": SimilarUsersRecommender,
+			"score":  candidate.Score,
+			"seeds":  firstN(candidate.Seeds, maxReasonTitles),
+		})
+
+		recommendations = append(recommendations, &models.Recommendation{
+			UserID:        userID,
+			MediaItemID:   candidate.MediaItemID,
+			MediaType:     target.recommendationType,
+			Title:         item.Title,
+			Year:          item.Year,
+			Genres:        item.Genres,
+			Reasoning:     similarUsersReasoning(firstN(because, maxReasonTitles)),
+			SimilarItems:  firstN(because, maxReasonTitles),
+			RecommendedBy: SimilarUsersRecommender,
+			JobRunID:      jobRunID,
+			CreatedAt:     now,
+			ExpiresAt:     &expiresAt,
+			Source:        models.RecommendationSourceSystem,
+			Confidence:    float32(candidate.Confidence),
+			InLibrary:     true,
+			Active:        true,
+			Metadata:      string(metadata),
+		})
+	}
+
+	return recommendations, nil
+}
+
+// itemSummaries loads the title, year and genres of library items of a media type
+func (j *CollaborativeJob) itemSummaries(ctx context.Context, itemType mediatypes.MediaType, ids []uint64) (map[uint64]itemSummary, error) {
+	switch itemType {
+	case mediatypes.MediaTypeMovie:
+		return summarizeItems(ctx, j.itemRepos.MovieRepo(), ids)
+	case mediatypes.MediaTypeSeries:
+		return summarizeItems(ctx, j.itemRepos.SeriesRepo(), ids)
+	case mediatypes.MediaTypeTrack:
+		return summarizeItems(ctx, j.itemRepos.TrackRepo(), ids)
+	default:
+		return nil, fmt.Errorf("unsupported media type: %s", itemType)
+	}
+}
+
+func summarizeItems[T mediatypes.MediaData](ctx context.Context, repo repository.CoreMediaItemRepository[T], ids []uint64) (map[uint64]itemSummary, error) {
+	items, err := repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[uint64]itemSummary, len(items))
+	for _, item := range items {
+		summary := itemSummary{Title: item.Title, Year: item.ReleaseYear}
+		if details := item.Data.GetDetails(); details != nil {
+			if summary.Title == "" {
+				summary.Title = details.Title
+			}
+			summary.Genres = details.Genres
+		}
+		summaries[item.ID] = summary
+	}
+	return summaries, nil
+}
+
+// collaborativeLimit is the user's maximum number of recommendations for the target's media type
+func collaborativeLimit(config *models.UserConfig, target collaborativeTarget) int {
+	limit := 0
+	if config.MaxRecommendations != nil {
+		switch target.recommendationType {
+		case mediatypes.MediaTypeMovie:
+			limit = config.MaxRecommendations.Movies
+		case mediatypes.MediaTypeSeries:
+			limit = config.MaxRecommendations.Series
+		default:
+			limit = config.MaxRecommendations.Music
+		}
+	}
+	if limit <= 0 {
+		return defaultCollaborativeLimit
+	}
+	return limit
+}
+
+// excludedGenresFor returns the genres the user excluded for the target's media type
+func excludedGenresFor(config *models.UserConfig, target collaborativeTarget) []string {
+	if config.ExcludedGenres == nil {
+		return nil
+	}
+	switch target.recommendationType {
+	case mediatypes.MediaTypeMovie:
+		return config.ExcludedGenres.Movies
+	case mediatypes.MediaTypeSeries:
+		return config.ExcludedGenres.Series
+	default:
+		return config.ExcludedGenres.Music
+	}
+}
+
+// hasExcludedGenre reports whether any of the genres is excluded, comparing them case-insensitively
+func hasExcludedGenre(genres []string, excluded []string) bool {
+	for _, genre := range genres {
+		for _, exclude := range excluded {
+			if strings.EqualFold(strings.TrimSpace(genre), strings.TrimSpace(exclude)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// similarUsersReasoning explains a recommendation with the user's items that led to it
+func similarUsersReasoning(titles []string) string {
+	switch len(titles) {
+	case 0:
+		return "Popular with users who watch the same things as you"
+	case 1:
+		return fmt.Sprintf("Users who liked %s also liked this", titles[0])
+	default:
+		return fmt.Sprintf("Users who liked %s and %s also liked this", strings.Join(titles[:len(titles)-1], ", "), titles[len(titles)-1])
+	}
+}
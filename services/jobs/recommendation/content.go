@@ -0,0 +1,572 @@
+package recommendation
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	mediatypes "suasor/clients/media/types"
+	"suasor/clients/metadata"
+	clienttypes "suasor/clients/types"
+	"suasor/repository"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"time"
+)
+
+// ContentRecommender is the RecommendedBy of the recommendations scored against the user's taste profile
+const ContentRecommender = "content"
+
+const (
+	// contentCastSize is the number of top billed actors that describe an item
+	contentCastSize = 5
+	// minContentScore is the lowest similarity to the user's taste that is recommended
+	minContentScore = 0.1
+	// maxContentMatches is the number of matching genres, actors and directors stored on a recommendation
+	maxContentMatches = 3
+	// contentSeeds is the number of the user's favourite items whose TMDB recommendations are scored
+	contentSeeds = 5
+	// maxTMDBCandidates is the number of TMDB recommendations whose details are loaded and scored
+	maxTMDBCandidates = 30
+	// creditBatchSize is the number of items whose credits are loaded at once
+	creditBatchSize = 1000
+	// contentExpiry is how long a content-based recommendation is shown, the job replaces them on every run
+	contentExpiry = 7 * 24 * time.Hour
+)
+
+// Kinds of the features of an item
+const (
+	featureGenre         = "genre"
+	featureDirector      = "director"
+	featureWriter        = "writer"
+	featureActor         = "actor"
+	featureTag           = "tag"
+	featureStudio        = "studio"
+	featureContentRating = "rating"
+)
+
+// featureWeights is how much a feature of each kind says about an item
+var featureWeights = map[string]float64{
+	featureGenre:         1.0,
+	featureDirector:      1.0,
+	featureWriter:        0.7,
+	featureActor:         0.6,
+	featureTag:           0.5,
+	featureStudio:        0.4,
+	featureContentRating: 0.2,
+}
+
+// featureVector maps the features of an item or of a user's taste to their weight
+type featureVector map[string]float64
+
+// add adds a vector scaled by weight, a negative weight moves the taste away from the item
+func (v featureVector) add(other featureVector, weight float64) {
+	for key, value := range other {
+		v[key] += value * weight
+	}
+}
+
+func (v featureVector) norm() float64 {
+	sum := 0.0
+	for _, value := range v {
+		sum += value * value
+	}
+	return math.Sqrt(sum)
+}
+
+// cosine returns the cosine similarity of two vectors, 0 when either is empty
+func (v featureVector) cosine(other featureVector) float64 {
+	normV, normOther := v.norm(), other.norm()
+	if normV == 0 || normOther == 0 {
+		return 0
+	}
+	dot := 0.0
+	for key, value := range other {
+		dot += v[key] * value
+	}
+	return dot / (normV * normOther)
+}
+
+func featureKey(kind, value string) string {
+	return kind + ":" + strings.ToLower(strings.TrimSpace(value))
+}
+
+// contentItem is what the content-based recommender knows of an item
+type contentItem struct {
+	Genres        []string
+	Tags          []string
+	Studio        string
+	ContentRating string
+	// Directors include the creators of a series, who shape it as a director does a movie
+	Directors []string
+	Writers   []string
+	Actors    []string
+}
+
+// newContentItem describes an item by its details and credits, either may be missing
+func newContentItem(details *mediatypes.MediaDetails, credits []*models.Credit) contentItem {
+	var item contentItem
+	if details != nil {
+		item.Genres = details.Genres
+		item.Tags = details.Tags
+		item.Studio = details.Studio
+		item.ContentRating = details.ContentRating
+	}
+
+	directors := append(GetCrewByRole(credits, models.RoleDirector), GetCreatorsFromCredits(credits)...)
+	item.Directors = uniqueNames(ExtractNamesFromCredits(directors))
+	item.Writers = uniqueNames(ExtractNamesFromCredits(GetCrewByDepartment(credits, models.DepartmentWriting)))
+	item.Actors = uniqueNames(ExtractNamesFromCredits(GetCastFromCredits(credits, contentCastSize)))
+	return item
+}
+
+// vector returns the item's unit length feature vector, so items with long credits don't outweigh the others
+func (c contentItem) vector() featureVector {
+	vector := make(featureVector)
+	addFeatures := func(kind string, values ...string) {
+		for _, value := range values {
+			if strings.TrimSpace(value) != "" {
+				vector[featureKey(kind, value)] = featureWeights[kind]
+			}
+		}
+	}
+	addFeatures(featureGenre, c.Genres...)
+	addFeatures(featureTag, c.Tags...)
+	addFeatures(featureStudio, c.Studio)
+	addFeatures(featureContentRating, c.ContentRating)
+	addFeatures(featureDirector, c.Directors...)
+	addFeatures(featureWriter, c.Writers...)
+	addFeatures(featureActor, c.Actors...)
+
+	if norm := vector.norm(); norm > 0 {
+		for key := range vector {
+			vector[key] /= norm
+		}
+	}
+	return vector
+}
+
+// tasteWeight is how much an item of the history pulls the taste towards it.
+// Disliked items and items rated below the middle of the scale push it away.
+func tasteWeight(weight float32, disliked bool, rating float32) float64 {
+	if disliked || (rating > 0 && rating < neutralRating) {
+		return -math.Abs(float64(weight))
+	}
+	return float64(weight)
+}
+
+// contentMatch is the similarity of an item to the user's taste and the features that explain it
+type contentMatch struct {
+	Score     float64
+	Genres    []string
+	Actors    []string
+	Directors []string
+}
+
+// scoreContent scores an item against the taste, the matches are the item's features the user likes, the strongest first
+func scoreContent(taste featureVector, item contentItem) contentMatch {
+	return contentMatch{
+		Score:     taste.cosine(item.vector()),
+		Genres:    likedFeatures(taste, featureGenre, item.Genres),
+		Actors:    likedFeatures(taste, featureActor, item.Actors),
+		Directors: likedFeatures(taste, featureDirector, item.Directors),
+	}
+}
+
+func likedFeatures(taste featureVector, kind string, values []string) []string {
+	var liked []string
+	for _, value := range values {
+		if taste[featureKey(kind, value)] > 0 {
+			liked = append(liked, value)
+		}
+	}
+	sort.SliceStable(liked, func(i, j int) bool {
+		return taste[featureKey(kind, liked[i])] > taste[featureKey(kind, liked[j])]
+	})
+	return firstN(liked, maxContentMatches)
+}
+
+// contentReasoning explains a recommendation with the people and genres the user likes in it
+func contentReasoning(match contentMatch) string {
+	var liked []string
+	liked = append(liked, match.Directors...)
+	liked = append(liked, match.Actors...)
+	liked = append(liked, match.Genres...)
+	liked = firstN(liked, maxContentMatches)
+
+	switch len(liked) {
+	case 0:
+		return "Similar to what you watch"
+	case 1:
+		return "Because you like " + liked[0]
+	default:
+		return "Because you like " + strings.Join(liked[:len(liked)-1], ", ") + " and " + liked[len(liked)-1]
+	}
+}
+
+// uniqueNames drops empty and repeated names, keeping the order
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var unique []string
+	for _, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, name)
+	}
+	return unique
+}
+
+// contentCandidate is a library item or TMDB title scored against the user's taste
+type contentCandidate struct {
+	MediaItemID uint64
+	Title       string
+	Year        int
+	Genres      []string
+	ExternalIDs models.ExternalIDMap
+	PosterURL   string
+	BackdropURL string
+	Match       contentMatch
+}
+
+// rankContent returns the best scored candidates without the excluded genres
+func rankContent(candidates []contentCandidate, excludedGenres []string, limit int) []contentCandidate {
+	ranked := make([]contentCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.Match.Score >= minContentScore && !hasExcludedGenre(candidate.Genres, excludedGenres) {
+			ranked = append(ranked, candidate)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Match.Score > ranked[j].Match.Score
+	})
+	return firstN(ranked, limit)
+}
+
+// generateContentRecommendations replaces the user's content-based recommendations with the unwatched library
+// items and TMDB titles closest to the taste profile. It needs no AI client. Music has no credits to match and
+// is left to the collaborative filtering job.
+func (j *RecommendationJob) generateContentRecommendations(ctx context.Context, jobRunID uint64, user models.User, profile *UserPreferenceProfile, config *models.UserConfig) error {
+	log := logger.LoggerFromContext(ctx)
+
+	client := j.getMetadataClient(ctx, user.ID)
+
+	var recommendations []*models.Recommendation
+
+	if len(profile.MovieTaste) > 0 {
+		watched := excludedContent(profile.DislikedItemIDs, profile.WatchedMovieIDs, config.RecommendationIncludeWatched)
+		candidates, owned, err := scoreLibrary(ctx, j.itemRepos.MovieRepo(), j.creditRepo, user.ID, profile.MovieTaste, watched)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to score library movies against the taste profile")
+		}
+		if client != nil && client.SupportsMovieMetadata() {
+			seeds := contentSeedIDs(ctx, j.itemRepos.MovieRepo(), movieSummaryIDs(profile.TopRatedMovies, profile.RecentMovies))
+			candidates = append(candidates, tmdbMovieCandidates(ctx, client, seeds, owned, profile.MovieTaste)...)
+		}
+		target := collaborativeTarget{itemType: mediatypes.MediaTypeMovie, recommendationType: mediatypes.MediaTypeMovie}
+		candidates = withoutRejectedContent(candidates, target.recommendationType, profile.Feedback)
+		ranked := rankContent(candidates, dismissedGenresFor(config, target, profile.Feedback), collaborativeLimit(config, target))
+		recommendations = append(recommendations, contentRecommendations(jobRunID, user.ID, mediatypes.MediaTypeMovie, ranked)...)
+	}
+
+	if len(profile.SeriesTaste) > 0 {
+		watched := excludedContent(profile.DislikedItemIDs, profile.WatchedSeriesIDs, config.RecommendationIncludeWatched)
+		candidates, owned, err := scoreLibrary(ctx, j.itemRepos.SeriesRepo(), j.creditRepo, user.ID, profile.SeriesTaste, watched)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to score library series against the taste profile")
+		}
+		if client != nil && client.SupportsTVMetadata() {
+			seeds := contentSeedIDs(ctx, j.itemRepos.SeriesRepo(), seriesSummaryIDs(profile.TopRatedSeries, profile.RecentSeries))
+			candidates = append(candidates, tmdbSeriesCandidates(ctx, client, seeds, owned, profile.SeriesTaste)...)
+		}
+		target := collaborativeTarget{itemType: mediatypes.MediaTypeSeries, recommendationType: mediatypes.MediaTypeSeries}
+		candidates = withoutRejectedContent(candidates, target.recommendationType, profile.Feedback)
+		ranked := rankContent(candidates, dismissedGenresFor(config, target, profile.Feedback), collaborativeLimit(config, target))
+		recommendations = append(recommendations, contentRecommendations(jobRunID, user.ID, mediatypes.MediaTypeSeries, ranked)...)
+	}
+
+	if err := j.recommendationRepo.ReplaceByRecommender(ctx, user.ID, ContentRecommender, recommendations); err != nil {
+		return err
+	}
+
+	log.Info().Int("count", len(recommendations)).Msg("Stored content-based recommendations")
+	return nil
+}
+
+// excludedContent returns the items never to recommend, the disliked ones and the watched ones unless the user wants them
+func excludedContent(disliked map[uint64]bool, watched map[uint64]bool, includeWatched bool) map[uint64]bool {
+	exclude := make(map[uint64]bool, len(disliked)+len(watched))
+	for id := range disliked {
+		exclude[id] = true
+	}
+	if !includeWatched {
+		for id := range watched {
+			exclude[id] = true
+		}
+	}
+	return exclude
+}
+
+// scoreLibrary scores the user's library items of one type against the taste.
+// It also returns the TMDB IDs of the whole library, so TMDB candidates the user already has are skipped.
+func scoreLibrary[T mediatypes.MediaData](
+	ctx context.Context,
+	repo repository.CoreMediaItemRepository[T],
+	creditRepo repository.CreditRepository,
+	userID uint64,
+	taste featureVector,
+	exclude map[uint64]bool,
+) ([]contentCandidate, map[string]bool, error) {
+	items, err := repo.GetByUserID(ctx, userID, 0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owned := make(map[string]bool, len(items))
+	unwatched := make([]*models.MediaItem[T], 0, len(items))
+	for _, item := range items {
+		if id := item.ExternalIDs.GetID(string(clienttypes.ClientTypeTMDB)); id != "" {
+			owned[id] = true
+		}
+		if !exclude[item.ID] {
+			unwatched = append(unwatched, item)
+		}
+	}
+
+	ids := make([]uint64, 0, len(unwatched))
+	for _, item := range unwatched {
+		ids = append(ids, item.ID)
+	}
+	credits, err := loadCredits(ctx, creditRepo, ids)
+	if err != nil {
+		return nil, owned, err
+	}
+
+	candidates := make([]contentCandidate, 0, len(unwatched))
+	for _, item := range unwatched {
+		details := item.Data.GetDetails()
+		candidate := contentCandidate{
+			MediaItemID: item.ID,
+			Title:       item.Title,
+			Year:        item.ReleaseYear,
+			Match:       scoreContent(taste, newContentItem(details, credits[item.ID])),
+		}
+		if details != nil {
+			if candidate.Title == "" {
+				candidate.Title = details.Title
+			}
+			candidate.Genres = details.Genres
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, owned, nil
+}
+
+// loadCredits loads the credits of the items in batches, by item
+func loadCredits(ctx context.Context, creditRepo repository.CreditRepository, ids []uint64) (map[uint64][]*models.Credit, error) {
+	credits := make(map[uint64][]*models.Credit)
+	if creditRepo == nil {
+		return credits, nil
+	}
+	for start := 0; start < len(ids); start += creditBatchSize {
+		batchCredits, err := creditRepo.GetByMediaItemIDs(ctx, ids[start:min(start+creditBatchSize, len(ids))])
+		if err != nil {
+			return nil, err
+		}
+		for _, credit := range batchCredits {
+			credits[credit.MediaItemID] = append(credits[credit.MediaItemID], credit)
+		}
+	}
+	return credits, nil
+}
+
+// contentSeedIDs returns the TMDB IDs of the user's favourite items, whose TMDB recommendations are scored
+func contentSeedIDs[T mediatypes.MediaData](ctx context.Context, repo repository.CoreMediaItemRepository[T], ids []uint64) []string {
+	log := logger.LoggerFromContext(ctx)
+
+	items, err := repo.GetByIDs(ctx, ids)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to get the items TMDB recommendations are based on")
+		return nil
+	}
+
+	byID := make(map[uint64]*models.MediaItem[T], len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	// The favourites come first, an item both rated and recently watched is only used once
+	var seeds []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		item, ok := byID[id]
+		if !ok {
+			continue
+		}
+		if tmdbID := item.ExternalIDs.GetID(string(clienttypes.ClientTypeTMDB)); tmdbID != "" && !seen[tmdbID] {
+			seen[tmdbID] = true
+			seeds = append(seeds, tmdbID)
+		}
+	}
+	return firstN(seeds, contentSeeds)
+}
+
+func movieSummaryIDs(lists ...[]MovieSummary) []uint64 {
+	var ids []uint64
+	for _, list := range lists {
+		for _, movie := range list {
+			ids = append(ids, movie.ID)
+		}
+	}
+	return ids
+}
+
+func seriesSummaryIDs(lists ...[]SeriesSummary) []uint64 {
+	var ids []uint64
+	for _, list := range lists {
+		for _, series := range list {
+			ids = append(ids, series.ID)
+		}
+	}
+	return ids
+}
+
+// tmdbCandidateIDs collects the TMDB recommendations of the seeds the user doesn't have yet
+func tmdbCandidateIDs(ctx context.Context, seeds []string, owned map[string]bool, recommend func(ctx context.Context, id string) ([]string, error)) []string {
+	log := logger.LoggerFromContext(ctx)
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, seed := range seeds {
+		recommended, err := recommend(ctx, seed)
+		if err != nil {
+			log.Warn().Err(err).Str("tmdbID", seed).Msg("Failed to get TMDB recommendations")
+			continue
+		}
+		for _, id := range recommended {
+			if id == "" || owned[id] || seen[id] {
+				continue
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return firstN(ids, maxTMDBCandidates)
+}
+
+// tmdbMovieCandidates scores the TMDB recommendations of the user's favourite movies.
+// TMDB details have no credits, so they are scored on their genres alone.
+func tmdbMovieCandidates(ctx context.Context, client metadata.ClientMetadata, seeds []string, owned map[string]bool, taste featureVector) []contentCandidate {
+	ids := tmdbCandidateIDs(ctx, seeds, owned, func(ctx context.Context, id string) ([]string, error) {
+		movies, err := client.GetMovieRecommendations(ctx, id)
+		var ids []string
+		for _, movie := range movies {
+			ids = append(ids, movie.ID)
+		}
+		return ids, err
+	})
+
+	var candidates []contentCandidate
+	for _, id := range ids {
+		movie, err := client.GetMovie(ctx, id)
+		if err != nil || movie == nil {
+			continue
+		}
+		genres := metadataGenres(movie.Genres)
+		candidates = append(candidates, contentCandidate{
+			Title:       movie.Title,
+			Year:        releaseYear(movie.ReleaseDate),
+			Genres:      genres,
+			ExternalIDs: models.ExternalIDMap{string(clienttypes.ClientTypeTMDB): movie.ID},
+			PosterURL:   tmdbImage(movie.PosterPath),
+			BackdropURL: tmdbImage(movie.BackdropPath),
+			Match:       scoreContent(taste, contentItem{Genres: genres}),
+		})
+	}
+	return candidates
+}
+
+// tmdbSeriesCandidates scores the TMDB recommendations of the user's favourite series on their genres
+func tmdbSeriesCandidates(ctx context.Context, client metadata.ClientMetadata, seeds []string, owned map[string]bool, taste featureVector) []contentCandidate {
+	ids := tmdbCandidateIDs(ctx, seeds, owned, func(ctx context.Context, id string) ([]string, error) {
+		shows, err := client.GetTVShowRecommendations(ctx, id)
+		var ids []string
+		for _, show := range shows {
+			ids = append(ids, show.ID)
+		}
+		return ids, err
+	})
+
+	var candidates []contentCandidate
+	for _, id := range ids {
+		show, err := client.GetTVShow(ctx, id)
+		if err != nil || show == nil {
+			continue
+		}
+		genres := metadataGenres(show.Genres)
+		candidates = append(candidates, contentCandidate{
+			Title:       show.Name,
+			Year:        releaseYear(show.FirstAirDate),
+			Genres:      genres,
+			ExternalIDs: models.ExternalIDMap{string(clienttypes.ClientTypeTMDB): show.ID},
+			PosterURL:   tmdbImage(show.PosterPath),
+			BackdropURL: tmdbImage(show.BackdropPath),
+			Match:       scoreContent(taste, contentItem{Genres: genres}),
+		})
+	}
+	return candidates
+}
+
+func metadataGenres(genres []metadata.Genre) []string {
+	names := make([]string, 0, len(genres))
+	for _, genre := range genres {
+		names = append(names, genre.Name)
+	}
+	return names
+}
+
+// contentRecommendations converts ranked candidates to recommendations
+func contentRecommendations(jobRunID uint64, userID uint64, mediaType mediatypes.MediaType, candidates []contentCandidate) []*models.Recommendation {
+	now := time.Now()
+	expiresAt := now.Add(contentExpiry)
+
+	recommendations := make([]*models.Recommendation, 0, len(candidates))
+	for _, candidate := range candidates {
+		recommendationMetadata, _ := json.Marshal(map[string]any{
+			"source": ContentRecommender,
+			"score":  candidate.Match.Score,
+		})
+
+		recommendation := &models.Recommendation{
+			UserID:           userID,
+			MediaItemID:      candidate.MediaItemID,
+			MediaType:        mediaType,
+			Title:            candidate.Title,
+			Year:             candidate.Year,
+			Genres:           candidate.Genres,
+			Reasoning:        contentReasoning(candidate.Match),
+			MatchesActors:    candidate.Match.Actors,
+			MatchesDirectors: candidate.Match.Directors,
+			MatchesGenres:    candidate.Match.Genres,
+			RecommendedBy:    ContentRecommender,
+			JobRunID:         jobRunID,
+			CreatedAt:        now,
+			ExpiresAt:        &expiresAt,
+			PosterURL:        candidate.PosterURL,
+			BackdropURL:      candidate.BackdropURL,
+			Source:           models.RecommendationSourceSystem,
+			Confidence:       float32(candidate.Match.Score),
+			InLibrary:        candidate.MediaItemID > 0,
+			Active:           true,
+			Metadata:         string(recommendationMetadata),
+		}
+		if candidate.ExternalIDs != nil {
+			recommendation.ExternalIDs = &candidate.ExternalIDs
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations
+}
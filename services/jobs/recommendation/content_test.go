@@ -0,0 +1,81 @@
+package recommendation
+
+import (
+	"testing"
+
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContentItem(t *testing.T) {
+	details := &mediatypes.MediaDetails{Genres: []string{"Drama"}, Tags: []string{"heist"}, Studio: "A24", ContentRating: "R"}
+	credits := []*models.Credit{
+		{Name: "Director One", Role: models.RoleDirector, Department: models.DepartmentDirecting, IsCrew: true},
+		{Name: "Writer One", Role: models.RoleScreenplay, Department: models.DepartmentWriting, IsCrew: true},
+		{Name: "Director One", Role: models.RoleWriter, Department: models.DepartmentWriting, IsCrew: true},
+		{Name: "Lead", IsCast: true, Order: 0},
+		{Name: "Extra", IsCast: true, Order: 9},
+		{Name: "Second", IsCast: true, Order: 1},
+		{Name: "Third", IsCast: true, Order: 2},
+		{Name: "Fourth", IsCast: true, Order: 3},
+		{Name: "Fifth", IsCast: true, Order: 4},
+	}
+
+	item := newContentItem(details, credits)
+
+	assert.Equal(t, []string{"Director One"}, item.Directors)
+	assert.Equal(t, []string{"Writer One", "Director One"}, item.Writers)
+	assert.Equal(t, []string{"Lead", "Second", "Third", "Fourth", "Fifth"}, item.Actors, "only the top billed actors")
+
+	vector := item.vector()
+	assert.InDelta(t, 1, vector.norm(), 1e-9)
+	assert.Greater(t, vector[featureKey(featureDirector, "director one")], vector[featureKey(featureActor, "lead")])
+	assert.Contains(t, vector, featureKey(featureContentRating, "R"))
+}
+
+func TestScoreContent(t *testing.T) {
+	liked := contentItem{Genres: []string{"Thriller", "Drama"}, Directors: []string{"Director One"}, Actors: []string{"Lead"}}
+	disliked := contentItem{Genres: []string{"Comedy"}, Actors: []string{"Clown"}}
+
+	taste := make(featureVector)
+	taste.add(liked.vector(), tasteWeight(2, false, 9))
+	taste.add(disliked.vector(), tasteWeight(1, true, 0))
+
+	same := scoreContent(taste, contentItem{Genres: []string{"drama"}, Directors: []string{"Director One"}, Actors: []string{"Someone"}})
+	assert.Greater(t, same.Score, minContentScore)
+	assert.Equal(t, []string{"Director One"}, same.Directors)
+	assert.Equal(t, []string{"drama"}, same.Genres)
+	assert.Empty(t, same.Actors)
+	assert.Equal(t, "Because you like Director One and drama", contentReasoning(same))
+
+	other := scoreContent(taste, contentItem{Genres: []string{"Comedy"}, Actors: []string{"Clown"}})
+	assert.Less(t, other.Score, 0.0)
+	assert.Empty(t, other.Genres)
+	assert.Equal(t, "Similar to what you watch", contentReasoning(other))
+}
+
+func TestTasteWeight(t *testing.T) {
+	assert.Equal(t, 1.5, tasteWeight(1.5, false, 0))
+	assert.Equal(t, 1.5, tasteWeight(1.5, false, 8))
+	assert.Equal(t, -1.5, tasteWeight(1.5, false, 3))
+	assert.Equal(t, -1.5, tasteWeight(1.5, true, 9))
+}
+
+func TestRankContent(t *testing.T) {
+	candidates := []contentCandidate{
+		{MediaItemID: 1, Genres: []string{"Drama"}, Match: contentMatch{Score: 0.4}},
+		{MediaItemID: 2, Genres: []string{"Horror"}, Match: contentMatch{Score: 0.9}},
+		{MediaItemID: 3, Genres: []string{"Drama"}, Match: contentMatch{Score: 0.05}},
+		{MediaItemID: 4, Genres: []string{"Drama"}, Match: contentMatch{Score: 0.7}},
+		{MediaItemID: 5, Genres: []string{"Drama"}, Match: contentMatch{Score: 0.6}},
+	}
+
+	ranked := rankContent(candidates, []string{"horror"}, 2)
+
+	require.Len(t, ranked, 2)
+	assert.Equal(t, uint64(4), ranked[0].MediaItemID)
+	assert.Equal(t, uint64(5), ranked[1].MediaItemID)
+}
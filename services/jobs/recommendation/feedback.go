@@ -0,0 +1,261 @@
+package recommendation
+
+import (
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+)
+
+const (
+	// neutralFeedbackRating is the middle of the 0 to 5 scale recommendations are rated on
+	neutralFeedbackRating = 2.5
+	// dislikedGenreWeight is the taste weight of a title dismissed for its genres, the genres are excluded as well
+	dislikedGenreWeight = -0.5
+	// rejectedPenalty is how much of its score a ranked candidate loses when it is like a title the user rejected
+	rejectedPenalty = 0.5
+)
+
+// feedbackWeight is how much a feedback signal says the user likes the title, negative when it says they don't.
+// Titles dismissed as already seen say nothing about the user's taste.
+func feedbackWeight(feedback *models.RecommendationFeedback) float64 {
+	switch feedback.Type {
+	case models.FeedbackTypePlayed:
+		return 1
+	case models.FeedbackTypeRated:
+		if feedback.Rating > 0 {
+			return (float64(feedback.Rating) - neutralFeedbackRating) / neutralFeedbackRating
+		}
+	case models.FeedbackTypeDismissed:
+		switch feedback.Reason {
+		case models.DismissReasonSeenIt:
+			return 0
+		case models.DismissReasonDislikeGenre:
+			return dislikedGenreWeight
+		default:
+			return -1
+		}
+	}
+	return 0
+}
+
+// rejectsTitle reports whether the feedback asks for the title not to be recommended again
+func rejectsTitle(feedback *models.RecommendationFeedback) bool {
+	return feedback.Type == models.FeedbackTypeDismissed || feedbackWeight(feedback) < 0
+}
+
+// feedbackKeys are the recommendationKeys of the title the feedback was given on
+func feedbackKeys(feedback *models.RecommendationFeedback) []string {
+	return recommendationKeys(models.Recommendation{
+		MediaItemID: feedback.MediaItemID,
+		MediaType:   feedback.MediaType,
+		Title:       feedback.Title,
+		Year:        feedback.Year,
+		ExternalIDs: feedback.ExternalIDs,
+	})
+}
+
+// feedbackSignals is what a user told us through their recommendations, read by every recommender.
+// A nil feedbackSignals has no signals.
+type feedbackSignals struct {
+	// excluded are the keys of the titles the user rejected, they are never recommended again
+	excluded map[string]bool
+	// rejected are the rejected titles, the latest feedback on each
+	rejected []*models.RecommendationFeedback
+	// disliked are the feedback that count against a title, titles like them are demoted
+	disliked []*models.RecommendationFeedback
+	// itemWeights are the feedbackWeight of the library items by media type, the latest signal on each
+	itemWeights map[mediatypes.MediaType]map[uint64]float64
+	// dislikedGenres are the genres of the titles dismissed for their genres, by media type
+	dislikedGenres map[mediatypes.MediaType][]string
+}
+
+// newFeedbackSignals reads a user's feedback, the oldest first so later signals override earlier ones.
+// A title the user played or liked after rejecting it is no longer excluded.
+func newFeedbackSignals(feedback []*models.RecommendationFeedback) *feedbackSignals {
+	signals := &feedbackSignals{
+		excluded:       make(map[string]bool),
+		itemWeights:    make(map[mediatypes.MediaType]map[uint64]float64),
+		dislikedGenres: make(map[mediatypes.MediaType][]string),
+	}
+
+	rejected := make(map[string]*models.RecommendationFeedback)
+	for _, item := range feedback {
+		weight := feedbackWeight(item)
+		keys := feedbackKeys(item)
+
+		if weight != 0 && item.MediaItemID > 0 {
+			if signals.itemWeights[item.MediaType] == nil {
+				signals.itemWeights[item.MediaType] = make(map[uint64]float64)
+			}
+			signals.itemWeights[item.MediaType][item.MediaItemID] = weight
+		}
+		if weight < 0 {
+			signals.disliked = append(signals.disliked, item)
+		}
+		if item.Type == models.FeedbackTypeDismissed && item.Reason == models.DismissReasonDislikeGenre {
+			signals.dislikedGenres[item.MediaType] = appendUnique(signals.dislikedGenres[item.MediaType], item.Genres...)
+		}
+
+		switch {
+		case rejectsTitle(item):
+			for _, key := range keys {
+				signals.excluded[key] = true
+			}
+			if len(keys) > 0 {
+				rejected[keys[0]] = item
+			}
+		case weight > 0:
+			for _, key := range keys {
+				delete(signals.excluded, key)
+			}
+		}
+	}
+
+	for _, item := range feedback {
+		keys := feedbackKeys(item)
+		if len(keys) > 0 && rejected[keys[0]] == item && anyKey(signals.excluded, keys) {
+			signals.rejected = append(signals.rejected, item)
+		}
+	}
+	return signals
+}
+
+// excludes reports whether the user rejected the recommendation's title
+func (f *feedbackSignals) excludes(recommendation models.Recommendation) bool {
+	if f == nil {
+		return false
+	}
+	return anyKey(f.excluded, recommendationKeys(recommendation))
+}
+
+// excludesItem reports whether the user rejected a title before it is made into a recommendation
+func (f *feedbackSignals) excludesItem(mediaType mediatypes.MediaType, mediaItemID uint64, title string, year int, externalIDs models.ExternalIDMap) bool {
+	recommendation := models.Recommendation{MediaItemID: mediaItemID, MediaType: mediaType, Title: title, Year: year}
+	if len(externalIDs) > 0 {
+		recommendation.ExternalIDs = &externalIDs
+	}
+	return f.excludes(recommendation)
+}
+
+// rejectedTitles returns the labels of the rejected titles of a media type, for AI prompts
+func (f *feedbackSignals) rejectedTitles(mediaType mediatypes.MediaType) []string {
+	if f == nil {
+		return nil
+	}
+	var titles []string
+	for _, item := range f.rejected {
+		if item.MediaType == mediaType && item.Title != "" {
+			titles = append(titles, proposalLabel(item.Title, item.Year))
+		}
+	}
+	return titles
+}
+
+// weights returns the feedbackWeight of the library items of a media type
+func (f *feedbackSignals) weights(mediaType mediatypes.MediaType) map[uint64]float64 {
+	if f == nil {
+		return nil
+	}
+	return f.itemWeights[mediaType]
+}
+
+// genres returns the genres the user dismissed titles of a media type for
+func (f *feedbackSignals) genres(mediaType mediatypes.MediaType) []string {
+	if f == nil {
+		return nil
+	}
+	return f.dislikedGenres[mediaType]
+}
+
+// applyFeedback adds the user's feedback to their taste profile: the genres of the titles they liked or rejected
+// weigh on the taste vectors and the genres they dismissed titles for are excluded
+func applyFeedback(profile *UserPreferenceProfile, feedback []*models.RecommendationFeedback) {
+	profile.Feedback = newFeedbackSignals(feedback)
+
+	for _, item := range feedback {
+		weight := feedbackWeight(item)
+		if weight == 0 || len(item.Genres) == 0 {
+			continue
+		}
+		genres := contentItem{Genres: item.Genres}.vector()
+		switch item.MediaType {
+		case mediatypes.MediaTypeMovie:
+			profile.MovieTaste.add(genres, weight)
+		case mediatypes.MediaTypeSeries:
+			profile.SeriesTaste.add(genres, weight)
+		}
+	}
+
+	profile.ExcludedMovieGenres = appendUnique(profile.ExcludedMovieGenres, profile.Feedback.genres(mediatypes.MediaTypeMovie)...)
+	profile.ExcludedSeriesGenres = appendUnique(profile.ExcludedSeriesGenres, profile.Feedback.genres(mediatypes.MediaTypeSeries)...)
+	profile.ExcludedMusicGenres = appendUnique(profile.ExcludedMusicGenres, profile.Feedback.genres("music")...)
+}
+
+// penalizeRejected lowers the score of the candidates that are like a title the user rejected,
+// in proportion to their similarity to the most similar one
+func penalizeRejected(candidates []*rankedCandidate, disliked []*models.RecommendationFeedback) {
+	if len(disliked) == 0 {
+		return
+	}
+	rejected := make([]*rankedCandidate, 0, len(disliked))
+	for _, item := range disliked {
+		rejected = append(rejected, &rankedCandidate{
+			Best:      models.Recommendation{MediaType: item.MediaType, Title: item.Title},
+			Genres:    item.Genres,
+			Franchise: franchiseStem(item.Title),
+		})
+	}
+
+	for _, candidate := range candidates {
+		similarity := 0.0
+		for _, other := range rejected {
+			if other.Best.MediaType != candidate.Best.MediaType {
+				continue
+			}
+			if s := candidateSimilarity(candidate, other); s > similarity {
+				similarity = s
+			}
+		}
+		candidate.Score -= rejectedPenalty * similarity * candidate.Score
+	}
+}
+
+// withoutRejected leaves out the recommendations of titles the user rejected.
+// Dismissed recommendations are kept, mergeCandidates leaves their items out of every list.
+func withoutRejected(recommendations []models.Recommendation, signals *feedbackSignals) []models.Recommendation {
+	kept := make([]models.Recommendation, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		if !recommendation.Dismissed && signals.excludes(recommendation) {
+			continue
+		}
+		kept = append(kept, recommendation)
+	}
+	return kept
+}
+
+// withoutDislikedGenres leaves out the candidates of the genres the user dismissed titles for
+func withoutDislikedGenres(candidates []*rankedCandidate, signals *feedbackSignals) []*rankedCandidate {
+	kept := make([]*rankedCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !hasExcludedGenre(candidate.Genres, signals.genres(candidate.Best.MediaType)) {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
+
+// withoutRejectedContent leaves out the content candidates of titles the user rejected
+func withoutRejectedContent(candidates []contentCandidate, mediaType mediatypes.MediaType, signals *feedbackSignals) []contentCandidate {
+	kept := make([]contentCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !signals.excludesItem(mediaType, candidate.MediaItemID, candidate.Title, candidate.Year, candidate.ExternalIDs) {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
+
+// dismissedGenresFor returns the genres the user excluded for the target's media type and the ones they
+// dismissed titles for
+func dismissedGenresFor(config *models.UserConfig, target collaborativeTarget, signals *feedbackSignals) []string {
+	return appendUnique(append([]string{}, excludedGenresFor(config, target)...), signals.genres(target.recommendationType)...)
+}
@@ -0,0 +1,86 @@
+package recommendation
+
+import (
+	"testing"
+
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dismissedTitle(itemID uint64, title string, year int, reason models.DismissReason, genres ...string) *models.RecommendationFeedback {
+	return &models.RecommendationFeedback{MediaItemID: itemID, MediaType: mediatypes.MediaTypeMovie, Title: title, Year: year, Genres: genres, Type: models.FeedbackTypeDismissed, Reason: reason}
+}
+
+func TestFeedbackSignals(t *testing.T) {
+	matrix := dismissedTitle(0, "The Matrix", 0, models.DismissReasonSeenIt, "Science Fiction")
+	matrix.ExternalIDs = &models.ExternalIDMap{"tmdb": "603"}
+
+	signals := newFeedbackSignals([]*models.RecommendationFeedback{
+		dismissedTitle(1, "Heat", 1995, models.DismissReasonNotInterested, "Crime"),
+		matrix,
+		dismissedTitle(3, "Scary Movie", 2000, models.DismissReasonDislikeGenre, "Horror", "Comedy"),
+		{MediaItemID: 4, MediaType: mediatypes.MediaTypeMovie, Title: "Alien", Year: 1979, Type: models.FeedbackTypeRated, Rating: 1},
+		{MediaItemID: 5, MediaType: mediatypes.MediaTypeMovie, Title: "Up", Year: 2009, Type: models.FeedbackTypeRated, Rating: 5},
+		dismissedTitle(6, "Cats", 2019, models.DismissReasonNotInterested),
+		// The user changed their mind and played it
+		{MediaItemID: 6, MediaType: mediatypes.MediaTypeMovie, Title: "Cats", Year: 2019, Type: models.FeedbackTypePlayed},
+	})
+
+	assert.True(t, signals.excludes(models.Recommendation{MediaItemID: 1, MediaType: mediatypes.MediaTypeMovie}))
+	assert.True(t, signals.excludesItem(mediatypes.MediaTypeMovie, 0, "heat", 1995, nil), "titles match whatever recommender made them")
+	assert.True(t, signals.excludesItem(mediatypes.MediaTypeMovie, 0, "Matrix", 1999, models.ExternalIDMap{"tmdb": "603"}))
+	assert.False(t, signals.excludesItem(mediatypes.MediaTypeSeries, 0, "Heat", 1995, nil))
+	assert.False(t, signals.excludesItem(mediatypes.MediaTypeMovie, 6, "Cats", 2019, nil))
+
+	weights := signals.weights(mediatypes.MediaTypeMovie)
+	assert.Equal(t, -1.0, weights[1])
+	assert.Equal(t, dislikedGenreWeight, weights[3])
+	assert.InDelta(t, -0.6, weights[4], 1e-9)
+	assert.Equal(t, 1.0, weights[5])
+	assert.Equal(t, 1.0, weights[6])
+	assert.NotContains(t, weights, uint64(0), "seen titles say nothing about the taste")
+
+	assert.Equal(t, []string{"Horror", "Comedy"}, signals.genres(mediatypes.MediaTypeMovie))
+	assert.Equal(t, []string{"Heat (1995)", "The Matrix", "Scary Movie (2000)", "Alien (1979)"}, signals.rejectedTitles(mediatypes.MediaTypeMovie))
+	assert.Len(t, signals.disliked, 4)
+
+	var none *feedbackSignals
+	assert.False(t, none.excludes(models.Recommendation{MediaItemID: 1, MediaType: mediatypes.MediaTypeMovie}))
+	assert.Empty(t, none.rejectedTitles(mediatypes.MediaTypeMovie))
+}
+
+func TestApplyFeedback(t *testing.T) {
+	profile := &UserPreferenceProfile{MovieTaste: make(featureVector), SeriesTaste: make(featureVector)}
+
+	applyFeedback(profile, []*models.RecommendationFeedback{
+		dismissedTitle(1, "Heat", 1995, models.DismissReasonNotInterested, "Crime"),
+		dismissedTitle(2, "Scary Movie", 2000, models.DismissReasonDislikeGenre, "Horror"),
+		{MediaType: mediatypes.MediaTypeMovie, Title: "Up", Genres: []string{"Animation"}, Type: models.FeedbackTypePlayed},
+	})
+
+	require.NotNil(t, profile.Feedback)
+	assert.Less(t, profile.MovieTaste[featureKey(featureGenre, "crime")], 0.0)
+	assert.Greater(t, profile.MovieTaste[featureKey(featureGenre, "animation")], 0.0)
+	assert.Empty(t, profile.SeriesTaste)
+	assert.Equal(t, []string{"Horror"}, profile.ExcludedMovieGenres)
+}
+
+func TestPenalizeRejected(t *testing.T) {
+	candidate := func(mediaType mediatypes.MediaType, title string, genres ...string) *rankedCandidate {
+		return &rankedCandidate{Best: models.Recommendation{MediaType: mediaType, Title: title}, Score: 1, Genres: genres, Franchise: franchiseStem(title)}
+	}
+	sequel := candidate(mediatypes.MediaTypeMovie, "Star Wars: Return of the Jedi", "Science Fiction")
+	crime := candidate(mediatypes.MediaTypeMovie, "Heat", "Crime")
+	series := candidate(mediatypes.MediaTypeSeries, "Star Wars: Andor", "Science Fiction")
+
+	penalizeRejected([]*rankedCandidate{sequel, crime, series}, []*models.RecommendationFeedback{
+		dismissedTitle(1, "Star Wars: A New Hope", 1977, models.DismissReasonNotInterested, "Science Fiction"),
+	})
+
+	assert.InDelta(t, 1-rejectedPenalty, sequel.Score, 1e-9)
+	assert.InDelta(t, 1, crime.Score, 1e-9)
+	assert.InDelta(t, 1, series.Score, 1e-9, "only titles of the same media type are compared")
+}
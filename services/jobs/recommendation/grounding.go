@@ -0,0 +1,457 @@
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"suasor/clients/ai"
+	aitypes "suasor/clients/ai/types"
+	mediatypes "suasor/clients/media/types"
+	"suasor/clients/metadata"
+	"suasor/clients/metadata/musicbrainz"
+	clienttypes "suasor/clients/types"
+	"suasor/repository"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"unicode"
+)
+
+const (
+	// maxGroundingRounds caps how often the AI is asked to replace recommendations that could not be grounded
+	maxGroundingRounds = 3
+	// tmdbImageURL is the base of the poster and backdrop paths TMDB returns
+	tmdbImageURL = "https://image.tmdb.org/t/p/w500"
+	// minMusicBrainzScore is the search score a release group needs to count as a match
+	minMusicBrainzScore = 90
+)
+
+// groundedItem is an AI recommendation matched to a real title on a metadata provider
+type groundedItem struct {
+	aitypes.RecommendationItem
+	// Artist is the artist of a music recommendation
+	Artist string
+	// ExternalIDs are the IDs of the title on the metadata provider, empty when it could not be checked
+	ExternalIDs models.ExternalIDMap
+	// MediaItemID is the item in the user's library with one of the external IDs, 0 if they don't own the title
+	MediaItemID uint64
+}
+
+// InLibrary returns true if the title is in the user's library
+func (g *groundedItem) InLibrary() bool {
+	return g.MediaItemID != 0
+}
+
+// itemResolver matches an AI recommendation to a real title, it returns nil when nothing matches
+type itemResolver func(ctx context.Context, item aitypes.RecommendationItem) (*groundedItem, error)
+
+// recommendationGrounder checks AI recommendations against a metadata provider and the user's library
+type recommendationGrounder struct {
+	resolve itemResolver
+	library libraryIndex
+	// excluded returns true for titles that must not be recommended, such as the ones the user has watched or rejected
+	excluded func(item *groundedItem) bool
+}
+
+// groundRecommendations resolves the AI's recommendations to real titles and drops the ones that don't exist,
+// are excluded or are duplicates. The AI is asked for replacements until the request's count is reached or
+// maxGroundingRounds runs out, so fewer items than requested may be returned.
+func (j *RecommendationJob) groundRecommendations(
+	ctx context.Context,
+	userID uint64,
+	request *aitypes.RecommendationRequest,
+	response *aitypes.RecommendationResponse,
+	grounder *recommendationGrounder) []*groundedItem {
+
+	log := logger.LoggerFromContext(ctx)
+
+	excludedIDs := make(map[string]bool, len(request.ExcludeIDs))
+	for _, id := range request.ExcludeIDs {
+		excludedIDs[id] = true
+	}
+
+	var grounded []*groundedItem
+	// proposed holds every title the AI has suggested so replacements don't repeat them
+	proposed := make(map[string]bool)
+	proposedLabels := append([]string{}, request.ExcludeIDs...)
+	seenIDs := make(map[string]bool)
+	dropped := 0
+
+	var items []aitypes.RecommendationItem
+	if response != nil {
+		items = response.Items
+	}
+
+	for round := 0; ; round++ {
+		for _, item := range items {
+			if len(grounded) >= request.Count {
+				break
+			}
+			if item.Title == "" {
+				continue
+			}
+			key := proposalKey(item.Title, item.Year)
+			if proposed[key] {
+				continue
+			}
+			proposed[key] = true
+			proposedLabels = append(proposedLabels, proposalLabel(item.Title, item.Year))
+
+			match, err := grounder.resolve(ctx, item)
+			if err != nil {
+				// The provider failing says nothing about the title, keep it as the AI made it
+				log.Warn().Err(err).Str("title", item.Title).Msg("Failed to look up AI recommendation, keeping it unverified")
+				match, _ = unverifiedItem(ctx, item)
+			}
+			if match == nil {
+				log.Debug().Str("title", item.Title).Int("year", item.Year).Msg("Dropping AI recommendation that could not be found")
+				dropped++
+				continue
+			}
+
+			if match.ExternalID != "" {
+				if seenIDs[match.ExternalID] {
+					continue
+				}
+				seenIDs[match.ExternalID] = true
+			}
+
+			match.MediaItemID = grounder.library.find(match.ExternalIDs)
+			if excludedIDs[match.ExternalID] || excludedIDs[match.Title] || grounder.excluded(match) {
+				log.Debug().Str("title", match.Title).Msg("Dropping excluded AI recommendation")
+				dropped++
+				continue
+			}
+
+			grounded = append(grounded, match)
+		}
+
+		if len(grounded) >= request.Count || round >= maxGroundingRounds {
+			break
+		}
+
+		// Ask for replacements of the dropped items, excluding everything suggested so far
+		replacementRequest := *request
+		replacementRequest.Count = request.Count - len(grounded)
+		replacementRequest.ExcludeIDs = proposedLabels
+
+		var replacements *aitypes.RecommendationResponse
+		err := j.withAIClient(ctx, userID, func(ctx context.Context, aiClient ai.ClientAI) error {
+			var err error
+			replacements, err = aiClient.GetRecommendations(ctx, &replacementRequest)
+			return err
+		})
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to get replacement AI recommendations")
+			break
+		}
+		if replacements == nil || len(replacements.Items) == 0 {
+			break
+		}
+		items = replacements.Items
+	}
+
+	log.Info().
+		Str("mediaType", request.MediaType).
+		Int("requested", request.Count).
+		Int("grounded", len(grounded)).
+		Int("dropped", dropped).
+		Msg("Grounded AI recommendations")
+
+	return grounded
+}
+
+// getMetadataClient returns the user's first enabled TMDB client, or nil if they have none
+func (j *RecommendationJob) getMetadataClient(ctx context.Context, userID uint64) metadata.ClientMetadata {
+	log := logger.LoggerFromContext(ctx)
+
+	if j.tmdbRepo == nil {
+		return nil
+	}
+	tmdbClients, err := j.tmdbRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Warn().Err(err).Uint64("userID", userID).Msg("Failed to get metadata clients for recommendations")
+		return nil
+	}
+
+	for _, tmdbClient := range tmdbClients {
+		if !tmdbClient.IsEnabled {
+			continue
+		}
+		client, err := j.clientFactories.GetClient(ctx, tmdbClient.ID, tmdbClient.Config)
+		if err != nil {
+			log.Warn().Err(err).Uint64("clientID", tmdbClient.ID).Msg("Failed to get metadata client for recommendations")
+			continue
+		}
+		if metadataClient, ok := client.(metadata.ClientMetadata); ok {
+			return metadataClient
+		}
+	}
+	return nil
+}
+
+// movieResolver resolves movies on the user's TMDB client, without one recommendations are kept unverified
+func (j *RecommendationJob) movieResolver(ctx context.Context, userID uint64) itemResolver {
+	log := logger.LoggerFromContext(ctx)
+
+	client := j.getMetadataClient(ctx, userID)
+	if client == nil || !client.SupportsMovieMetadata() {
+		log.Warn().Uint64("userID", userID).Msg("No metadata client to check AI movie recommendations against")
+		return unverifiedItem
+	}
+
+	return func(ctx context.Context, item aitypes.RecommendationItem) (*groundedItem, error) {
+		movies, err := client.SearchMovies(ctx, item.Title)
+		if err != nil {
+			return nil, err
+		}
+		movie := bestMatch(movies, item, func(movie *metadata.Movie) (string, string, string) {
+			return movie.Title, movie.OriginalTitle, movie.ReleaseDate
+		})
+		if movie == nil {
+			return nil, nil
+		}
+
+		grounded := &groundedItem{
+			RecommendationItem: item,
+			ExternalIDs:        models.ExternalIDMap{string(clienttypes.ClientTypeTMDB): movie.ID},
+		}
+		grounded.Title = movie.Title
+		grounded.Year = releaseYear(movie.ReleaseDate)
+		grounded.ReleaseDate = movie.ReleaseDate
+		grounded.ExternalID = movie.ID
+		grounded.PosterURL = tmdbImage(movie.PosterPath)
+		grounded.BackdropURL = tmdbImage(movie.BackdropPath)
+		if grounded.Description == "" {
+			grounded.Description = movie.Overview
+		}
+		return grounded, nil
+	}
+}
+
+// seriesResolver resolves TV shows on the user's TMDB client, without one recommendations are kept unverified
+func (j *RecommendationJob) seriesResolver(ctx context.Context, userID uint64) itemResolver {
+	log := logger.LoggerFromContext(ctx)
+
+	client := j.getMetadataClient(ctx, userID)
+	if client == nil || !client.SupportsTVMetadata() {
+		log.Warn().Uint64("userID", userID).Msg("No metadata client to check AI series recommendations against")
+		return unverifiedItem
+	}
+
+	return func(ctx context.Context, item aitypes.RecommendationItem) (*groundedItem, error) {
+		shows, err := client.SearchTVShows(ctx, item.Title)
+		if err != nil {
+			return nil, err
+		}
+		show := bestMatch(shows, item, func(show *metadata.TVShow) (string, string, string) {
+			return show.Name, show.OriginalName, show.FirstAirDate
+		})
+		if show == nil {
+			return nil, nil
+		}
+
+		grounded := &groundedItem{
+			RecommendationItem: item,
+			ExternalIDs:        models.ExternalIDMap{string(clienttypes.ClientTypeTMDB): show.ID},
+		}
+		grounded.Title = show.Name
+		grounded.Year = releaseYear(show.FirstAirDate)
+		grounded.ReleaseDate = show.FirstAirDate
+		grounded.ExternalID = show.ID
+		grounded.PosterURL = tmdbImage(show.PosterPath)
+		grounded.BackdropURL = tmdbImage(show.BackdropPath)
+		if grounded.Description == "" {
+			grounded.Description = show.Overview
+		}
+		return grounded, nil
+	}
+}
+
+// musicResolver resolves albums on MusicBrainz, the AI puts the artist of music recommendations in the description
+func (j *RecommendationJob) musicResolver() itemResolver {
+	if j.musicBrainz == nil {
+		return unverifiedItem
+	}
+
+	return func(ctx context.Context, item aitypes.RecommendationItem) (*groundedItem, error) {
+		groups, err := j.musicBrainz.SearchReleaseGroups(ctx, item.Title, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var match *musicbrainz.ReleaseGroup
+		for i := range groups {
+			group := &groups[i]
+			if group.Score < minMusicBrainzScore || normalizeTitle(group.Title) != normalizeTitle(item.Title) {
+				continue
+			}
+			if match == nil {
+				match = group
+			}
+			// Prefer the release group of the artist the AI named
+			if item.Description != "" && strings.Contains(strings.ToLower(item.Description), strings.ToLower(group.Artist())) {
+				match = group
+				break
+			}
+		}
+		if match == nil {
+			return nil, nil
+		}
+
+		grounded := &groundedItem{
+			RecommendationItem: item,
+			Artist:             match.Artist(),
+			ExternalIDs:        models.ExternalIDMap{"musicbrainz": match.ID},
+		}
+		grounded.Title = match.Title
+		grounded.Year = match.Year()
+		grounded.ReleaseDate = match.FirstReleaseDate
+		grounded.ExternalID = match.ID
+		grounded.PosterURL = match.CoverArtURL()
+		return grounded, nil
+	}
+}
+
+// musicArtist returns the MusicBrainz artist of a music recommendation, or the AI's description when it wasn't found
+func musicArtist(item *groundedItem) string {
+	if item.Artist != "" {
+		return item.Artist
+	}
+	return item.Description
+}
+
+// unverifiedItem keeps a recommendation as the AI made it when there is no metadata provider to check it against
+func unverifiedItem(ctx context.Context, item aitypes.RecommendationItem) (*groundedItem, error) {
+	grounded := &groundedItem{RecommendationItem: item}
+	if item.ExternalID != "" {
+		grounded.ExternalIDs = models.ExternalIDMap{string(clienttypes.ClientTypeTMDB): item.ExternalID}
+	}
+	return grounded, nil
+}
+
+// bestMatch returns the first search result whose title matches the recommendation and whose year is at most
+// one off, release dates differ between countries. An exact year is preferred.
+func bestMatch[T any](results []T, item aitypes.RecommendationItem, details func(result T) (title, originalTitle, date string)) T {
+	var match T
+	found := false
+	for _, result := range results {
+		title, originalTitle, date := details(result)
+		wanted := normalizeTitle(item.Title)
+		if normalizeTitle(title) != wanted && normalizeTitle(originalTitle) != wanted {
+			continue
+		}
+
+		year := releaseYear(date)
+		if item.Year == 0 || year == item.Year {
+			return result
+		}
+		if !found && year != 0 && (year == item.Year-1 || year == item.Year+1) {
+			match = result
+			found = true
+		}
+	}
+	return match
+}
+
+// libraryIndex maps the external IDs of the user's library items to the items
+type libraryIndex map[string]uint64
+
+// newLibraryIndex indexes the external IDs of the items of one type synced from the user's media clients
+func newLibraryIndex[T mediatypes.MediaData](
+	ctx context.Context,
+	clientRepos repobundles.ClientRepositories,
+	repo repository.ClientMediaItemRepository[T],
+	userID uint64) libraryIndex {
+
+	log := logger.LoggerFromContext(ctx)
+
+	index := make(libraryIndex)
+	clientList, err := clientRepos.GetAllMediaClientsForUser(ctx, userID)
+	if err != nil {
+		log.Warn().Err(err).Uint64("userID", userID).Msg("Failed to get media clients to check recommendations against")
+		return index
+	}
+
+	mediaType := mediatypes.GetMediaType[T]()
+	for clientID := range clientList.IDs {
+		items, err := repo.GetByClientID(ctx, clientID)
+		if err != nil {
+			log.Warn().Err(err).Uint64("clientID", clientID).Msg("Failed to get library items to check recommendations against")
+			continue
+		}
+
+		for _, item := range items {
+			// All media types share one table
+			if item == nil || item.Type != mediaType {
+				continue
+			}
+			for _, id := range item.ExternalIDs {
+				if id.ID != "" {
+					index[libraryKey(id.Source, id.ID)] = item.ID
+				}
+			}
+		}
+	}
+	return index
+}
+
+// find returns the library item with one of the external IDs, or 0 if there is none
+func (l libraryIndex) find(ids models.ExternalIDMap) uint64 {
+	for source, id := range ids {
+		if itemID, ok := l[libraryKey(source, id)]; ok {
+			return itemID
+		}
+	}
+	return 0
+}
+
+func libraryKey(source, id string) string {
+	return strings.ToLower(source) + ":" + id
+}
+
+// normalizeTitle lowercases a title and strips punctuation and a leading "the" so small differences still match
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else if unicode.IsSpace(r) {
+			b.WriteRune(' ')
+		}
+	}
+	normalized := strings.Join(strings.Fields(b.String()), " ")
+	return strings.TrimPrefix(normalized, "the ")
+}
+
+// releaseYear returns the year of a "2006-01-02" date, or 0 if there is none
+func releaseYear(date string) int {
+	if len(date) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(date[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
+
+// tmdbImage returns the URL of a TMDB image path, or an empty string if there is no image
+func tmdbImage(path string) string {
+	if path == "" {
+		return ""
+	}
+	return tmdbImageURL + path
+}
+
+func proposalKey(title string, year int) string {
+	return fmt.Sprintf("%s-%d", normalizeTitle(title), year)
+}
+
+func proposalLabel(title string, year int) string {
+	if year == 0 {
+		return title
+	}
+	return fmt.Sprintf("%s (%d)", title, year)
+}
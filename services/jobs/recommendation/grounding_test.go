@@ -0,0 +1,217 @@
+package recommendation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"suasor/clients/ai"
+	aitypes "suasor/clients/ai/types"
+	mediatypes "suasor/clients/media/types"
+	clienttypes "suasor/clients/types"
+	"suasor/repository"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRecommendationClient answers every request for recommendations with the next response
+type fakeRecommendationClient struct {
+	ai.ClientAI
+	responses []*aitypes.RecommendationResponse
+	requests  []aitypes.RecommendationRequest
+}
+
+func (c *fakeRecommendationClient) GetRecommendations(ctx context.Context, request *aitypes.RecommendationRequest) (*aitypes.RecommendationResponse, error) {
+	c.requests = append(c.requests, *request)
+	if len(c.responses) == 0 {
+		return nil, nil
+	}
+	response := c.responses[0]
+	if len(c.responses) > 1 {
+		c.responses = c.responses[1:]
+	}
+	return response, nil
+}
+
+type fakeClientRouter struct {
+	client ai.ClientAI
+}
+
+func (r *fakeClientRouter) Clients(ctx context.Context, userID uint64, task models.AIFeature) ([]ai.ClientAI, error) {
+	return []ai.ClientAI{r.client}, nil
+}
+
+func (r *fakeClientRouter) Route(ctx context.Context, userID uint64, task models.AIFeature, fn func(ctx context.Context, client ai.ClientAI) error) error {
+	return fn(ctx, r.client)
+}
+
+// titleResolver resolves the titles in known to their TMDB IDs, the lookup of failed titles returns an error
+func titleResolver(known map[string]string, failed map[string]bool) itemResolver {
+	return func(ctx context.Context, item aitypes.RecommendationItem) (*groundedItem, error) {
+		if failed[item.Title] {
+			return nil, errors.New("tmdb unavailable")
+		}
+		id, ok := known[item.Title]
+		if !ok {
+			return nil, nil
+		}
+		grounded := &groundedItem{RecommendationItem: item, ExternalIDs: models.ExternalIDMap{"tmdb": id}}
+		grounded.ExternalID = id
+		return grounded, nil
+	}
+}
+
+func groundedTitles(items []*groundedItem) []string {
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	return titles
+}
+
+func TestGroundRecommendations(t *testing.T) {
+	client := &fakeRecommendationClient{responses: []*aitypes.RecommendationResponse{
+		{Items: []aitypes.RecommendationItem{{Title: "Up", Year: 2009}}},
+	}}
+	job := &RecommendationJob{router: &fakeClientRouter{client: client}}
+	grounder := &recommendationGrounder{
+		resolve: titleResolver(map[string]string{
+			"Heat":          "949",
+			"Heat (Remake)": "949",
+			"Alien":         "348",
+			"Arrival":       "329865",
+			"Up":            "14160",
+		}, map[string]bool{"Dune": true}),
+		library:  libraryIndex{libraryKey("tmdb", "949"): 12},
+		excluded: func(item *groundedItem) bool { return item.Title == "Alien" },
+	}
+	request := &aitypes.RecommendationRequest{MediaType: "movie", Count: 3, ExcludeIDs: []string{"329865"}}
+
+	items := job.groundRecommendations(context.Background(), 1, request, &aitypes.RecommendationResponse{Items: []aitypes.RecommendationItem{
+		{Title: "Heat", Year: 1995},
+		{Title: "Not A Real Movie", Year: 2001},
+		{Title: "Heat (Remake)", Year: 1995},
+		{Title: "Alien", Year: 1979},
+		{Title: "Arrival", Year: 2016},
+		{Title: "Dune", Year: 2021},
+	}}, grounder)
+
+	assert.Equal(t, []string{"Heat", "Dune", "Up"}, groundedTitles(items))
+	assert.Equal(t, uint64(12), items[0].MediaItemID, "the user owns it")
+	assert.Empty(t, items[1].ExternalIDs, "a failed lookup keeps the item unverified")
+
+	require.Len(t, client.requests, 1, "one replacement round fills the count")
+	assert.Equal(t, 1, client.requests[0].Count)
+	assert.Contains(t, client.requests[0].ExcludeIDs, "Not A Real Movie (2001)", "replacements don't repeat earlier suggestions")
+	assert.Contains(t, client.requests[0].ExcludeIDs, "329865")
+}
+
+type fakeLibraryClientRepositories struct {
+	repobundles.ClientRepositories
+	clients *models.MediaClientList
+}
+
+func (r *fakeLibraryClientRepositories) GetAllMediaClientsForUser(ctx context.Context, userID uint64) (*models.MediaClientList, error) {
+	return r.clients, nil
+}
+
+// fakeLibraryRepository holds the items synced from each client
+type fakeLibraryRepository struct {
+	repository.ClientMediaItemRepository[*mediatypes.Movie]
+	items map[uint64][]*models.MediaItem[*mediatypes.Movie]
+}
+
+func (r *fakeLibraryRepository) GetByClientID(ctx context.Context, clientID uint64) ([]*models.MediaItem[*mediatypes.Movie], error) {
+	return r.items[clientID], nil
+}
+
+func TestNewLibraryIndex(t *testing.T) {
+	clientList := models.NewMediaClientList()
+	clientList.IDs[3] = clienttypes.ClientTypeJellyfin
+
+	owned := &models.MediaItem[*mediatypes.Movie]{
+		Type:        mediatypes.MediaTypeMovie,
+		ExternalIDs: mediatypes.ExternalIDs{{Source: "tmdb", ID: "949"}},
+	}
+	owned.ID = 12
+	// Every media type shares one table, an episode with the same ID must not match
+	episode := &models.MediaItem[*mediatypes.Movie]{
+		Type:        mediatypes.MediaTypeEpisode,
+		ExternalIDs: mediatypes.ExternalIDs{{Source: "tmdb", ID: "348"}},
+	}
+	episode.ID = 13
+	repo := &fakeLibraryRepository{items: map[uint64][]*models.MediaItem[*mediatypes.Movie]{
+		3: {owned, episode},
+		4: {{Type: mediatypes.MediaTypeMovie, ExternalIDs: mediatypes.ExternalIDs{{Source: "tmdb", ID: "329865"}}}},
+	}}
+
+	library := newLibraryIndex(context.Background(), &fakeLibraryClientRepositories{clients: clientList}, repo, 1)
+
+	job := &RecommendationJob{router: &fakeClientRouter{client: &fakeRecommendationClient{}}}
+	grounder := &recommendationGrounder{
+		resolve:  titleResolver(map[string]string{"Heat": "949", "Alien": "348", "Arrival": "329865"}, nil),
+		library:  library,
+		excluded: func(item *groundedItem) bool { return false },
+	}
+	request := &aitypes.RecommendationRequest{MediaType: "movie", Count: 3}
+	items := job.groundRecommendations(context.Background(), 1, request, &aitypes.RecommendationResponse{Items: []aitypes.RecommendationItem{
+		{Title: "Heat", Year: 1995},
+		{Title: "Alien", Year: 1979},
+		{Title: "Arrival", Year: 2016},
+	}}, grounder)
+
+	require.Len(t, items, 3)
+	assert.True(t, items[0].InLibrary())
+	assert.Equal(t, uint64(12), items[0].MediaItemID)
+	assert.False(t, items[1].InLibrary())
+	assert.False(t, items[2].InLibrary(), "the client belongs to another user")
+}
+
+func TestGroundRecommendationsRoundLimit(t *testing.T) {
+	client := &fakeRecommendationClient{responses: []*aitypes.RecommendationResponse{
+		{Items: []aitypes.RecommendationItem{{Title: "Made Up", Year: 2020}}},
+		{Items: []aitypes.RecommendationItem{{Title: "Made Up Again", Year: 2020}}},
+		{Items: []aitypes.RecommendationItem{{Title: "Still Made Up", Year: 2020}}},
+		{Items: []aitypes.RecommendationItem{{Title: "Made Up Forever", Year: 2020}}},
+	}}
+	job := &RecommendationJob{router: &fakeClientRouter{client: client}}
+	grounder := &recommendationGrounder{
+		resolve:  titleResolver(nil, nil),
+		excluded: func(item *groundedItem) bool { return false },
+	}
+	request := &aitypes.RecommendationRequest{MediaType: "movie", Count: 2}
+
+	items := job.groundRecommendations(context.Background(), 1, request, &aitypes.RecommendationResponse{Items: []aitypes.RecommendationItem{
+		{Title: "Imaginary", Year: 2020},
+	}}, grounder)
+
+	assert.Empty(t, items)
+	assert.Len(t, client.requests, maxGroundingRounds, "the AI is asked for replacements at most maxGroundingRounds times")
+}
+
+type searchResult struct {
+	title, originalTitle, date string
+}
+
+func TestBestMatch(t *testing.T) {
+	results := []*searchResult{
+		{title: "Heat Wave", date: "1995-06-01"},
+		{title: "Heat", date: "1996-02-01"},
+		{title: "Fièvre", originalTitle: "Heat", date: "1995-12-15"},
+	}
+	details := func(result *searchResult) (string, string, string) {
+		return result.title, result.originalTitle, result.date
+	}
+
+	match := bestMatch(results, aitypes.RecommendationItem{Title: "Heat", Year: 1995}, details)
+	assert.Equal(t, results[2], match, "an exact year beats an earlier result one year off")
+
+	match = bestMatch(results, aitypes.RecommendationItem{Title: "heat", Year: 1997}, details)
+	assert.Equal(t, results[1], match, "a year off is still a match")
+
+	assert.Nil(t, bestMatch(results, aitypes.RecommendationItem{Title: "Heat", Year: 2005}, details))
+	assert.Equal(t, results[1], bestMatch(results, aitypes.RecommendationItem{Title: "The Heat"}, details), "without a year the first title match wins")
+}
@@ -0,0 +1,380 @@
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	mediatypes "suasor/clients/media/types"
+	"suasor/repository"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+const (
+	// defaultGroupLimit is the number of picks of a group recommendation when no limit is asked for
+	defaultGroupLimit = 20
+	// neutralFit is the fit of an item for a member without a play history, who neither likes nor dislikes it
+	neutralFit = 0.5
+)
+
+// contentRatingLevels orders the US movie and TV content ratings from the mildest,
+// each TV rating on the level of the movie rating it matches
+var contentRatingLevels = map[string]int{
+	"G": 0, "TV-Y": 0, "TV-Y7": 0, "TV-G": 0,
+	"PG": 1, "TV-PG": 1,
+	"PG-13": 2, "TV-14": 2,
+	"R": 3, "TV-MA": 3,
+	"NC-17": 4,
+}
+
+// contentRatingLevel returns the level of a content rating, false for unrated items and ratings it doesn't know
+func contentRatingLevel(rating string) (int, bool) {
+	rating = strings.ToUpper(strings.TrimSpace(rating))
+	rating = strings.TrimPrefix(strings.TrimPrefix(rating, "US-"), "US:")
+	level, ok := contentRatingLevels[rating]
+	return level, ok
+}
+
+// groupRules are what every member of a group allows: the strictest content rating of their configs, none of the
+// genres any of them excluded and nothing any of them already finished or disliked
+type groupRules struct {
+	// MaxRating is the level of the strictest content rating, -1 when no member limits it
+	MaxRating int
+	// MaxContentRating is the strictest content rating as the member who set it wrote it
+	MaxContentRating string
+	// AllowUnrated lets unrated items through the content rating limit, every member who limits it must include them
+	AllowUnrated   bool
+	ExcludedGenres []string
+	// Excluded are the items any member finished or disliked
+	Excluded map[uint64]bool
+}
+
+// newGroupRules combines the members' configs and play history into the rules of the group
+func newGroupRules(configs []*models.UserConfig, mediaType mediatypes.MediaType, interactions []models.ItemInteraction) groupRules {
+	rules := groupRules{MaxRating: -1, AllowUnrated: true, Excluded: make(map[uint64]bool)}
+	target := collaborativeTarget{itemType: mediaType, recommendationType: mediaType}
+	for _, config := range configs {
+		if level, ok := contentRatingLevel(config.MaxContentRating); ok {
+			if rules.MaxRating < 0 || level < rules.MaxRating {
+				rules.MaxRating = level
+				rules.MaxContentRating = config.MaxContentRating
+			}
+			rules.AllowUnrated = rules.AllowUnrated && config.IncludeUnratedContent
+		}
+		rules.ExcludedGenres = appendUnique(rules.ExcludedGenres, excludedGenresFor(config, target)...)
+	}
+	for _, interaction := range interactions {
+		if interaction.Completed || interaction.IsDisliked {
+			rules.Excluded[interaction.MediaItemID] = true
+		}
+	}
+	return rules
+}
+
+// allows reports whether every member of the group allows the item
+func (r groupRules) allows(item groupItem) bool {
+	if r.Excluded[item.MediaItemID] || hasExcludedGenre(item.Genres, r.ExcludedGenres) {
+		return false
+	}
+	if r.MaxRating < 0 {
+		return true
+	}
+	level, ok := contentRatingLevel(item.ContentRating)
+	if !ok {
+		return r.AllowUnrated
+	}
+	return level <= r.MaxRating
+}
+
+// groupItem is a library item as the group recommender ranks it
+type groupItem struct {
+	MediaItemID   uint64
+	Title         string
+	Year          int
+	Genres        []string
+	ContentRating string
+	PosterURL     string
+	Vector        featureVector
+}
+
+// memberTastes builds each member's taste from their play history the way the content-based recommender does:
+// what they watched pulls it towards the item's features, what they disliked or rated low pushes it away
+func memberTastes(memberIDs []uint64, interactions []models.ItemInteraction, vectors map[uint64]featureVector) map[uint64]featureVector {
+	tastes := make(map[uint64]featureVector, len(memberIDs))
+	for _, memberID := range memberIDs {
+		tastes[memberID] = make(featureVector)
+	}
+	for _, interaction := range dedupeInteractions(interactions) {
+		taste, vector := tastes[interaction.UserID], vectors[interaction.MediaItemID]
+		if taste == nil || vector == nil || !seenItem(interaction) {
+			continue
+		}
+		weight := math.Max(interactionWeight(interaction), 1)
+		taste.add(vector, tasteWeight(float32(weight), interaction.IsDisliked, interaction.UserRating))
+	}
+	return tastes
+}
+
+// memberFit is how well an item fits a member's taste, from 0 to 1
+func memberFit(taste featureVector, item featureVector) float64 {
+	if taste.norm() == 0 {
+		return neutralFit
+	}
+	return (1 + taste.cosine(item)) / 2
+}
+
+// groupScore combines the fit of each member with the strategy
+func groupScore(fits []float64, strategy models.GroupStrategy) float64 {
+	if len(fits) == 0 {
+		return 0
+	}
+	if strategy == models.GroupStrategyAverage {
+		return averageFit(fits)
+	}
+	score := fits[0]
+	for _, fit := range fits[1:] {
+		score = math.Min(score, fit)
+	}
+	return score
+}
+
+func averageFit(fits []float64) float64 {
+	if len(fits) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, fit := range fits {
+		sum += fit
+	}
+	return sum / float64(len(fits))
+}
+
+// MemberFit is how well a group pick fits one member
+type MemberFit struct {
+	UserID uint64  `json:"userID"`
+	Fit    float64 `json:"fit"`
+}
+
+// GroupPick is a library item recommended to a group
+type GroupPick struct {
+	MediaItemID   uint64               `json:"mediaItemID"`
+	MediaType     mediatypes.MediaType `json:"mediaType"`
+	Title         string               `json:"title"`
+	Year          int                  `json:"year,omitempty"`
+	Genres        []string             `json:"genres,omitempty"`
+	ContentRating string               `json:"contentRating,omitempty"`
+	PosterURL     string               `json:"posterURL,omitempty"`
+	// Score is the members' fit combined with the group's strategy
+	Score float64 `json:"score"`
+	// Fits are the fit of each member, in the order of the group's members
+	Fits []MemberFit `json:"fits"`
+}
+
+// GroupRecommendations are the picks of a group and the rules they were picked by
+type GroupRecommendations struct {
+	MemberIDs        []uint64             `json:"memberIDs"`
+	MediaType        mediatypes.MediaType `json:"mediaType"`
+	Strategy         models.GroupStrategy `json:"strategy"`
+	MaxContentRating string               `json:"maxContentRating,omitempty"`
+	ExcludedGenres   []string             `json:"excludedGenres,omitempty"`
+	Picks            []GroupPick          `json:"picks"`
+}
+
+// rankGroup scores the items the group allows and returns the best limit of them.
+// Items with the same score rank by the average fit, so the one the group likes more overall comes first.
+func rankGroup(items []groupItem, memberIDs []uint64, tastes map[uint64]featureVector, rules groupRules, strategy models.GroupStrategy, limit int) []GroupPick {
+	type scoredPick struct {
+		pick    GroupPick
+		average float64
+	}
+
+	scored := make([]scoredPick, 0, len(items))
+	for _, item := range items {
+		if !rules.allows(item) {
+			continue
+		}
+		fits := make([]float64, 0, len(memberIDs))
+		memberFits := make([]MemberFit, 0, len(memberIDs))
+		for _, memberID := range memberIDs {
+			fit := memberFit(tastes[memberID], item.Vector)
+			fits = append(fits, fit)
+			memberFits = append(memberFits, MemberFit{UserID: memberID, Fit: fit})
+		}
+		scored = append(scored, scoredPick{
+			pick: GroupPick{
+				MediaItemID:   item.MediaItemID,
+				Title:         item.Title,
+				Year:          item.Year,
+				Genres:        item.Genres,
+				ContentRating: item.ContentRating,
+				PosterURL:     item.PosterURL,
+				Score:         groupScore(fits, strategy),
+				Fits:          memberFits,
+			},
+			average: averageFit(fits),
+		})
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].pick.Score != scored[j].pick.Score {
+			return scored[i].pick.Score > scored[j].pick.Score
+		}
+		if scored[i].average != scored[j].average {
+			return scored[i].average > scored[j].average
+		}
+		return scored[i].pick.MediaItemID < scored[j].pick.MediaItemID
+	})
+
+	if limit <= 0 {
+		limit = defaultGroupLimit
+	}
+	picks := make([]GroupPick, 0, min(limit, len(scored)))
+	for _, s := range scored[:min(limit, len(scored))] {
+		picks = append(picks, s.pick)
+	}
+	return picks
+}
+
+// GroupRecommender recommends library items for a group of users to watch together, such as a household's movie night.
+// Each member's taste is built from their play history, items are kept only when every member's config allows them,
+// then the members' fit is combined with the group's strategy.
+type GroupRecommender struct {
+	userConfigRepo repository.UserConfigRepository
+	neighborRepo   repository.NeighborRepository
+	creditRepo     repository.CreditRepository
+	itemRepos      repobundles.CoreMediaItemRepositories
+}
+
+// NewGroupRecommender creates a new group recommender
+func NewGroupRecommender(
+	userConfigRepo repository.UserConfigRepository,
+	neighborRepo repository.NeighborRepository,
+	creditRepo repository.CreditRepository,
+	itemRepos repobundles.CoreMediaItemRepositories,
+) *GroupRecommender {
+	return &GroupRecommender{
+		userConfigRepo: userConfigRepo,
+		neighborRepo:   neighborRepo,
+		creditRepo:     creditRepo,
+		itemRepos:      itemRepos,
+	}
+}
+
+// Recommend ranks the library's movies or series for the members to watch together
+func (g *GroupRecommender) Recommend(
+	ctx context.Context,
+	memberIDs []uint64,
+	mediaType mediatypes.MediaType,
+	strategy models.GroupStrategy,
+	limit int,
+) (*GroupRecommendations, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	if strategy == "" {
+		strategy = models.GroupStrategyLeastMisery
+	}
+	if !strategy.IsValid() {
+		return nil, fmt.Errorf("unsupported group strategy: %s", strategy)
+	}
+
+	var items []groupItem
+	var err error
+	switch mediaType {
+	case mediatypes.MediaTypeMovie:
+		items, err = loadGroupItems(ctx, g.itemRepos.MovieRepo(), g.creditRepo, mediaType)
+	case mediatypes.MediaTypeSeries:
+		items, err = loadGroupItems(ctx, g.itemRepos.SeriesRepo(), g.creditRepo, mediaType)
+	default:
+		return nil, fmt.Errorf("unsupported media type for group recommendations: %s", mediaType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]*models.UserConfig, 0, len(memberIDs))
+	for _, memberID := range memberIDs {
+		config, err := g.userConfigRepo.GetUserConfig(ctx, memberID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting config of user %d: %w", memberID, err)
+		}
+		configs = append(configs, config)
+	}
+
+	interactions, err := g.neighborRepo.GetUserInteractions(ctx, memberIDs, []mediatypes.MediaType{mediaType})
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := make(map[uint64]featureVector, len(items))
+	for _, item := range items {
+		vectors[item.MediaItemID] = item.Vector
+	}
+	rules := newGroupRules(configs, mediaType, interactions)
+	picks := rankGroup(items, memberIDs, memberTastes(memberIDs, interactions, vectors), rules, strategy, limit)
+	for i := range picks {
+		picks[i].MediaType = mediaType
+	}
+
+	log.Info().
+		Uints64("memberIDs", memberIDs).
+		Str("mediaType", string(mediaType)).
+		Str("strategy", string(strategy)).
+		Int("items", len(items)).
+		Int("picks", len(picks)).
+		Msg("Ranked group recommendations")
+
+	return &GroupRecommendations{
+		MemberIDs:        memberIDs,
+		MediaType:        mediaType,
+		Strategy:         strategy,
+		MaxContentRating: rules.MaxContentRating,
+		ExcludedGenres:   rules.ExcludedGenres,
+		Picks:            picks,
+	}, nil
+}
+
+// loadGroupItems loads the library's items of a type with the feature vectors of their details and credits
+func loadGroupItems[T mediatypes.MediaData](
+	ctx context.Context,
+	repo repository.CoreMediaItemRepository[T],
+	creditRepo repository.CreditRepository,
+	mediaType mediatypes.MediaType,
+) ([]groupItem, error) {
+	libraryItems, err := repo.GetByType(ctx, mediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(libraryItems))
+	for _, item := range libraryItems {
+		ids = append(ids, item.ID)
+	}
+	credits, err := loadCredits(ctx, creditRepo, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]groupItem, 0, len(libraryItems))
+	for _, libraryItem := range libraryItems {
+		details := libraryItem.Data.GetDetails()
+		item := groupItem{
+			MediaItemID: libraryItem.ID,
+			Title:       libraryItem.Title,
+			Year:        libraryItem.ReleaseYear,
+			Vector:      newContentItem(details, credits[libraryItem.ID]).vector(),
+		}
+		if details != nil {
+			if item.Title == "" {
+				item.Title = details.Title
+			}
+			item.Genres = details.Genres
+			item.ContentRating = details.ContentRating
+			item.PosterURL = details.Artwork.Poster
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,101 @@
+package recommendation
+
+import (
+	"testing"
+
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentRatingLevel(t *testing.T) {
+	level, ok := contentRatingLevel(" pg-13 ")
+	assert.True(t, ok)
+	assert.Equal(t, 2, level)
+
+	level, _ = contentRatingLevel("US-PG")
+	assert.Equal(t, 1, level)
+	tvLevel, _ := contentRatingLevel("TV-MA")
+	rLevel, _ := contentRatingLevel("R")
+	assert.Equal(t, rLevel, tvLevel, "TV ratings share the level of the movie rating they match")
+
+	_, ok = contentRatingLevel("NR")
+	assert.False(t, ok)
+}
+
+func TestNewGroupRules(t *testing.T) {
+	rules := newGroupRules([]*models.UserConfig{
+		{MaxContentRating: "R", IncludeUnratedContent: true, ExcludedGenres: &models.Genres{Movies: []string{"Horror"}}},
+		{MaxContentRating: "PG-13", ExcludedGenres: &models.Genres{Movies: []string{"Musical"}, Series: []string{"Reality"}}},
+		{},
+	}, mediatypes.MediaTypeMovie, []models.ItemInteraction{
+		{UserID: 1, MediaItemID: 1, Completed: true},
+		{UserID: 2, MediaItemID: 2, IsDisliked: true},
+		{UserID: 2, MediaItemID: 3, PlayedPercentage: 40},
+	})
+
+	assert.Equal(t, "PG-13", rules.MaxContentRating)
+	assert.False(t, rules.AllowUnrated)
+	assert.Equal(t, []string{"Horror", "Musical"}, rules.ExcludedGenres)
+
+	assert.True(t, rules.allows(groupItem{MediaItemID: 3, ContentRating: "PG"}), "started items can still be finished together")
+	assert.False(t, rules.allows(groupItem{MediaItemID: 1, ContentRating: "PG"}), "a member finished it")
+	assert.False(t, rules.allows(groupItem{MediaItemID: 2, ContentRating: "PG"}), "a member disliked it")
+	assert.False(t, rules.allows(groupItem{MediaItemID: 4, ContentRating: "R"}))
+	assert.False(t, rules.allows(groupItem{MediaItemID: 5}), "a member doesn't include unrated content")
+	assert.False(t, rules.allows(groupItem{MediaItemID: 6, ContentRating: "G", Genres: []string{"horror"}}))
+
+	unlimited := newGroupRules([]*models.UserConfig{{}}, mediatypes.MediaTypeMovie, nil)
+	assert.True(t, unlimited.allows(groupItem{MediaItemID: 5}))
+	assert.True(t, unlimited.allows(groupItem{MediaItemID: 7, ContentRating: "NC-17"}))
+}
+
+func TestGroupScore(t *testing.T) {
+	fits := []float64{0.9, 0.25, 0.5}
+	assert.InDelta(t, 0.25, groupScore(fits, models.GroupStrategyLeastMisery), 1e-9)
+	assert.InDelta(t, 0.55, groupScore(fits, models.GroupStrategyAverage), 1e-9)
+	assert.Zero(t, groupScore(nil, models.GroupStrategyAverage))
+}
+
+func TestMemberTastes(t *testing.T) {
+	action := featureVector{featureKey(featureGenre, "action"): 1}
+	tastes := memberTastes([]uint64{1, 2}, []models.ItemInteraction{
+		{UserID: 1, MediaItemID: 10, Completed: true},
+		{UserID: 2, MediaItemID: 10, IsDisliked: true},
+		{UserID: 3, MediaItemID: 10, Completed: true},
+	}, map[uint64]featureVector{10: action})
+
+	require.Len(t, tastes, 2, "only the members get a taste")
+	assert.InDelta(t, 1, memberFit(tastes[1], action), 1e-9)
+	assert.InDelta(t, 0, memberFit(tastes[2], action), 1e-9)
+	assert.InDelta(t, neutralFit, memberFit(featureVector{}, action), 1e-9, "a member without a history is neutral")
+}
+
+func TestRankGroup(t *testing.T) {
+	action := featureKey(featureGenre, "action")
+	comedy := featureKey(featureGenre, "comedy")
+	drama := featureKey(featureGenre, "drama")
+	items := []groupItem{
+		{MediaItemID: 1, Title: "Heat", Vector: featureVector{action: 1}},
+		{MediaItemID: 2, Title: "Up", Vector: featureVector{drama: 1}},
+		{MediaItemID: 3, Title: "Finished", Vector: featureVector{action: 1}},
+	}
+	tastes := map[uint64]featureVector{
+		1: {action: 1},
+		2: {action: -0.2, comedy: 1},
+	}
+	rules := groupRules{MaxRating: -1, Excluded: map[uint64]bool{3: true}}
+
+	leastMisery := rankGroup(items, []uint64{1, 2}, tastes, rules, models.GroupStrategyLeastMisery, 10)
+	require.Len(t, leastMisery, 2)
+	assert.Equal(t, "Up", leastMisery[0].Title, "nobody minds the drama, the second member dislikes action")
+	assert.Equal(t, []MemberFit{{UserID: 1, Fit: 0.5}, {UserID: 2, Fit: 0.5}}, leastMisery[0].Fits)
+
+	average := rankGroup(items, []uint64{1, 2}, tastes, rules, models.GroupStrategyAverage, 10)
+	assert.Equal(t, "Heat", average[0].Title, "the first member's love of action outweighs the second's dislike")
+	assert.InDelta(t, 1, average[0].Fits[0].Fit, 1e-9)
+
+	assert.Len(t, rankGroup(items, []uint64{1, 2}, tastes, rules, models.GroupStrategyAverage, 1), 1)
+}
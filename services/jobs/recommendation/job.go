@@ -3,18 +3,40 @@ package recommendation
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"strings"
 	"suasor/clients"
 	"suasor/clients/ai"
+	"suasor/clients/metadata/musicbrainz"
+	clienttypes "suasor/clients/types"
 	"suasor/repository"
 	repobundles "suasor/repository/bundles"
 	"suasor/types/models"
 	"suasor/utils/logger"
+	"suasor/utils/prompts"
 	"time"
 )
 
+// UsageTracker enforces AI token budgets and records the tokens spent by the job.
+// It is implemented by services.AIUsageService.
+type UsageTracker interface {
+	CheckBudget(ctx context.Context, userID uint64) error
+	Track(ctx context.Context, userID uint64, client clients.Client, feature models.AIFeature) context.Context
+}
+
+// ClientRouter picks the AI clients of a task and falls back to the next one when a client fails.
+// It is implemented by services.AIRouter.
+type ClientRouter interface {
+	Clients(ctx context.Context, userID uint64, task models.AIFeature) ([]ai.ClientAI, error)
+	Route(ctx context.Context, userID uint64, task models.AIFeature, fn func(ctx context.Context, client ai.ClientAI) error) error
+}
+
+// PromptRenderer renders the active version of the prompt templates sent to AI clients.
+// It is implemented by services.PromptTemplateService.
+type PromptRenderer interface {
+	Render(ctx context.Context, userID uint64, name string, values map[string]any) (*prompts.Rendered, error)
+}
+
 // RecommendationJob creates recommendations for users based on their preferences
 type RecommendationJob struct {
 	ctx                context.Context
@@ -22,6 +44,7 @@ type RecommendationJob struct {
 	userRepo           repository.UserRepository
 	userConfigRepo     repository.UserConfigRepository
 	recommendationRepo repository.RecommendationRepository
+	feedbackRepo       repository.RecommendationFeedbackRepository
 	clientRepos        repobundles.ClientRepositories
 	itemRepos          repobundles.CoreMediaItemRepositories
 	clientItemRepos    repobundles.ClientMediaItemRepositories
@@ -31,6 +54,14 @@ type RecommendationJob struct {
 	clientFactories *clients.ClientProviderFactoryService
 	creditRepo      repository.CreditRepository
 	peopleRepo      repository.PersonRepository
+
+	// Metadata providers AI recommendations are checked against
+	tmdbRepo    repository.ClientRepository[*clienttypes.TMDBConfig]
+	musicBrainz *musicbrainz.Client
+
+	usage   UsageTracker
+	router  ClientRouter
+	prompts PromptRenderer
 }
 
 // NewRecommendationJob creates a new recommendation job
@@ -40,6 +71,7 @@ func NewRecommendationJob(
 	userRepo repository.UserRepository,
 	userConfigRepo repository.UserConfigRepository,
 	recommendationRepo repository.RecommendationRepository,
+	feedbackRepo repository.RecommendationFeedbackRepository,
 	clientRepos repobundles.ClientRepositories,
 	itemRepos repobundles.CoreMediaItemRepositories,
 	clientItemRepos repobundles.ClientMediaItemRepositories,
@@ -50,6 +82,13 @@ func NewRecommendationJob(
 	creditRepo repository.CreditRepository,
 	peopleRepo repository.PersonRepository,
 
+	// Metadata providers AI recommendations are checked against
+	tmdbRepo repository.ClientRepository[*clienttypes.TMDBConfig],
+	musicBrainz *musicbrainz.Client,
+
+	usage UsageTracker,
+	router ClientRouter,
+	prompts PromptRenderer,
 ) *RecommendationJob {
 	return &RecommendationJob{
 		ctx:                ctx,
@@ -57,124 +96,35 @@ func NewRecommendationJob(
 		userRepo:           userRepo,
 		userConfigRepo:     userConfigRepo,
 		recommendationRepo: recommendationRepo,
+		feedbackRepo:       feedbackRepo,
 		clientFactories:    clientFactories,
 		clientRepos:        clientRepos,
 		itemRepos:          itemRepos,
 		clientItemRepos:    clientItemRepos,
 		dataRepos:          dataRepos,
+		tmdbRepo:           tmdbRepo,
+		musicBrainz:        musicBrainz,
+		usage:              usage,
+		router:             router,
+		prompts:            prompts,
 	}
 }
 
-// getAIClient returns an AI client for the given user
-// It tries to get the default AI client from the user config, or falls back to the first active AI client
-func (j *RecommendationJob) getAIClient(ctx context.Context, userID uint64) (ai.ClientAI, error) {
-	logger := log.Logger{} // would ideally use structured logging from context
-
-	// Get user config to check for default AI client
-	config, err := j.userConfigRepo.GetUserConfig(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("error getting user config: %w", err)
-	}
-
-	// First try to get the default AI client if set
-	if config.DefaultClients != nil && config.DefaultClients.AIClientID > 0 {
-		// Try Claude repository first
-		claudeRepo := j.clientRepos.ClaudeRepo()
-		if claudeRepo != nil {
-			claudeClient, err := claudeRepo.GetByID(ctx, config.DefaultClients.AIClientID)
-			if err == nil && claudeClient != nil {
-				// Found the default Claude client
-				client, err := j.clientFactories.GetClient(ctx, claudeClient.ID, claudeClient.Config)
-				if err == nil && client != nil {
-					logger.Printf("Using default Claude AI client ID %d for user %d", claudeClient.ID, userID)
-					return client.(ai.ClientAI), nil
-				}
-			}
-		}
-
-		// Try OpenAI repository next
-		openAIRepo := j.clientRepos.OpenAIRepo()
-		if openAIRepo != nil {
-			openAIClient, err := openAIRepo.GetByID(ctx, config.DefaultClients.AIClientID)
-			if err == nil && openAIClient != nil {
-				// Found the default OpenAI client
-				client, err := j.clientFactories.GetClient(ctx, openAIClient.ID, openAIClient.Config)
-				if err == nil && client != nil {
-					logger.Printf("Using default OpenAI client ID %d for user %d", openAIClient.ID, userID)
-					return client.(ai.ClientAI), nil
-				}
-			}
-		}
-
-		// Try Ollama repository next
-		ollamaRepo := j.clientRepos.OllamaRepo()
-		if ollamaRepo != nil {
-			ollamaClient, err := ollamaRepo.GetByID(ctx, config.DefaultClients.AIClientID)
-			if err == nil && ollamaClient != nil {
-				// Found the default Ollama client
-				client, err := j.clientFactories.GetClient(ctx, ollamaClient.ID, ollamaClient.Config)
-				if err == nil && client != nil {
-					logger.Printf("Using default Ollama client ID %d for user %d", ollamaClient.ID, userID)
-					return client.(ai.ClientAI), nil
-				}
-			}
-		}
-
-		// If we get here, the default client couldn't be found or created
-		logger.Printf("Default AI client ID %d for user %d not found or could not be created",
-			config.DefaultClients.AIClientID, userID)
-	}
-
-	// Try Claude clients first
-	claudeRepo := j.clientRepos.ClaudeRepo()
-	if claudeRepo != nil {
-		claudeClients, err := claudeRepo.GetByUserID(ctx, userID)
-		if err == nil && len(claudeClients) > 0 {
-			// Use the first active Claude client
-			for _, clientConfig := range claudeClients {
-				client, err := j.clientFactories.GetClient(ctx, clientConfig.ID, clientConfig.Config)
-				if err == nil && client != nil {
-					logger.Printf("Using first available Claude client ID %d for user %d", clientConfig.ID, userID)
-					return client.(ai.ClientAI), nil
-				}
-			}
-		}
-	}
-
-	// Try OpenAI clients next
-	openAIRepo := j.clientRepos.OpenAIRepo()
-	if openAIRepo != nil {
-		openAIClients, err := openAIRepo.GetByUserID(ctx, userID)
-		if err == nil && len(openAIClients) > 0 {
-			// Use the first active OpenAI client
-			for _, clientConfig := range openAIClients {
-				client, err := j.clientFactories.GetClient(ctx, clientConfig.ID, clientConfig.Config)
-				if err == nil && client != nil {
-					logger.Printf("Using first available OpenAI client ID %d for user %d", clientConfig.ID, userID)
-					return client.(ai.ClientAI), nil
-				}
-			}
+// withAIClient checks the user's AI token budget and calls fn with their AI clients for recommendations,
+// in the order of the routing policy, until one succeeds. The tokens spent are recorded for the client used.
+func (j *RecommendationJob) withAIClient(ctx context.Context, userID uint64, fn func(ctx context.Context, aiClient ai.ClientAI) error) error {
+	if j.usage != nil {
+		if err := j.usage.CheckBudget(ctx, userID); err != nil {
+			return err
 		}
 	}
 
-	// Try Ollama clients next
-	ollamaRepo := j.clientRepos.OllamaRepo()
-	if ollamaRepo != nil {
-		ollamaClients, err := ollamaRepo.GetByUserID(ctx, userID)
-		if err == nil && len(ollamaClients) > 0 {
-			// Use the first active Ollama client
-			for _, clientConfig := range ollamaClients {
-				client, err := j.clientFactories.GetClient(ctx, clientConfig.ID, clientConfig.Config)
-				if err == nil && client != nil {
-					logger.Printf("Using first available Ollama client ID %d for user %d", clientConfig.ID, userID)
-					return client.(ai.ClientAI), nil
-				}
-			}
+	return j.router.Route(ctx, userID, models.AIFeatureRecommendation, func(ctx context.Context, aiClient ai.ClientAI) error {
+		if j.usage != nil {
+			ctx = j.usage.Track(ctx, userID, aiClient, models.AIFeatureRecommendation)
 		}
-	}
-
-	// No AI client found
-	return nil, fmt.Errorf("no AI client available for user %d", userID)
+		return fn(ctx, aiClient)
+	})
 }
 
 // Name returns the job name
@@ -286,6 +236,13 @@ func (j *RecommendationJob) processUserRecommendations(ctx context.Context, jobR
 	// Calculate some advanced metrics based on the profile
 	j.calculateAdvancedMetrics(profile)
 
+	// Score the library and TMDB against the user's taste, this needs no AI client
+	err = j.generateContentRecommendations(ctx, jobRunID, user, profile, config)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate content-based recommendations")
+		// Continue with the AI recommendations
+	}
+
 	// Generate movie recommendations
 	err = j.generateMovieRecommendations(ctx, jobRunID, user, profile, config)
 	if err != nil {
@@ -300,11 +257,3 @@ func (j *RecommendationJob) processUserRecommendations(ctx context.Context, jobR
 	log.Info().Msg("Finished processing recommendations for user")
 	return nil
 }
-
-// SetupMediaSyncJob creates or updates a media sync job for a user
-func (j *RecommendationJob) SetupMediaSyncJob(ctx context.Context, userID, clientID uint64, clientType string, syncType models.SyncType, frequency string) error {
-	// Implementation would set up a media sync job
-	// This is just a stub to satisfy the interface
-	log.Printf("Setting up media sync job for user %d, client %d, type %s", userID, clientID, syncType)
-	return nil
-}
@@ -5,13 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
-
-	"strings"
+	"suasor/clients/ai"
 	aitypes "suasor/clients/ai/types"
 	mediatypes "suasor/clients/media/types"
 	"suasor/types/models"
 	"suasor/utils"
 	"suasor/utils/logger"
+	"suasor/utils/prompts"
 	"time"
 )
 
@@ -20,25 +20,29 @@ func (j *RecommendationJob) generateMovieRecommendations(ctx context.Context, jo
 	log := logger.LoggerFromContext(ctx)
 	log.Info().Msg("Generating movie recommendations")
 
-	// Get an AI client for the user
-	aiClient, err := j.getAIClient(ctx, user.ID)
+	// Create prompt with user preferences
+	prompt, err := j.buildMovieRecommendationPrompt(ctx, user, preferenceProfile)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get AI client, skipping movie recommendations")
+		log.Error().Err(err).Msg("Failed to render movie recommendation prompt")
 		return err
 	}
+	log.Debug().
+		Str("prompt", prompt.Text).
+		Int("promptVersion", prompt.Version).
+		Msg("Generated AI prompt for movie recommendations")
 
-	// Create prompt with user preferences
-	prompt := j.buildMovieRecommendationPrompt(user, preferenceProfile)
-	log.Debug().Str("prompt", prompt).Msg("Generated AI prompt for movie recommendations")
-
-	// Call AI model for recommendations
-	model := "claude-3-opus-20240229" // Default model
+	// Call AI model for recommendations, the client's default model unless the user prefers another
+	model := ""
 	if config.AIModelPreferences != nil && config.AIModelPreferences.DefaultModelForRecommendations != "" {
 		model = config.AIModelPreferences.DefaultModelForRecommendations
 	}
 
 	// Prepare the system message
-	systemPrompt := "You are a movie recommendation expert. Your goal is to provide personalized movie recommendations based on the user's preferences, watch history, and specified criteria. Provide detailed, thoughtful explanations for why each movie would appeal to this specific user."
+	systemPrompt, err := j.prompts.Render(ctx, user.ID, prompts.RecommendationMovieSystem, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to render movie recommendation system prompt")
+		return err
+	}
 
 	// Set response format as JSON
 	responseFormat := map[string]any{
@@ -53,8 +57,13 @@ func (j *RecommendationJob) generateMovieRecommendations(ctx context.Context, jo
 		"response_format": responseFormat,
 	}
 
-	// Call the AI model
-	resp, err := aiClient.GenerateContent(ctx, systemPrompt, prompt, model, options)
+	// Call the AI model, falling back to the user's next AI client if it fails
+	var resp *aitypes.ContentResponse
+	err = j.withAIClient(ctx, user.ID, func(ctx context.Context, aiClient ai.ClientAI) error {
+		var err error
+		resp, err = aiClient.GenerateContent(ctx, systemPrompt.Text, prompt.Text, ai.SupportedModel(aiClient, model), options)
+		return err
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to generate recommendations from AI")
 		return err
@@ -144,6 +153,10 @@ func (j *RecommendationJob) generateMovieRecommendations(ctx context.Context, jo
 	// Convert recommendations to database model and store them
 	var modelRecommendations []*models.Recommendation
 	for _, rec := range recommendations {
+		if preferenceProfile.Feedback.excludesItem(mediatypes.MediaTypeMovie, 0, rec.Title, rec.Year, nil) {
+			log.Debug().Str("title", rec.Title).Msg("Dropping AI recommendation the user rejected before")
+			continue
+		}
 		modelRec := &models.Recommendation{
 			UserID:           user.ID,
 			MediaType:        "movie",
@@ -158,6 +171,8 @@ func (j *RecommendationJob) generateMovieRecommendations(ctx context.Context, jo
 			MatchesGenres:    rec.MatchesGenres,
 			RecommendedBy:    rec.RecommendedBy,
 			JobRunID:         jobRunID,
+			PromptTemplate:   prompt.Name,
+			PromptVersion:    prompt.Version,
 			CreatedAt:        rec.Timestamp,
 		}
 		modelRecommendations = append(modelRecommendations, modelRec)
@@ -178,277 +193,38 @@ func (j *RecommendationJob) generateMovieRecommendations(ctx context.Context, jo
 	return nil
 }
 
-// buildMovieRecommendationPrompt creates a prompt for the AI model based on user preferences
-func (j *RecommendationJob) buildMovieRecommendationPrompt(user models.User, profile *UserPreferenceProfile) string {
-	var prompt strings.Builder
-
-	// Start with instruction
-	prompt.WriteString("Generate personalized movie recommendations for a user based on their preferences and watch history.\n\n")
-
-	// Add user context
-	prompt.WriteString("# User Information\n")
-	prompt.WriteString(fmt.Sprintf("User ID: %d\n", user.ID))
-	prompt.WriteString(fmt.Sprintf("Username: %s\n\n", user.Username))
-
-	// Add movie preferences
-	prompt.WriteString("# Movie Preferences\n")
-
-	// Provide favorite genres with weights
-	prompt.WriteString("## Favorite Genres\n")
-	if len(profile.FavoriteMovieGenres) > 0 {
-		// Sort genres by weight
-		type genreWeight struct {
-			genre  string
-			weight float32
-		}
-
-		var topGenres []genreWeight
-		for genre, weight := range profile.FavoriteMovieGenres {
-			topGenres = append(topGenres, genreWeight{genre, weight})
+// buildMovieRecommendationPrompt renders the movie recommendation prompt template with the user's preferences
+func (j *RecommendationJob) buildMovieRecommendationPrompt(ctx context.Context, user models.User, profile *UserPreferenceProfile) (*prompts.Rendered, error) {
+	// Sort the watch hours by their number of views
+	var watchTimes []hourCount
+	for hour, counts := range profile.MovieWatchTimes {
+		var totalCount int64
+		for _, count := range counts {
+			totalCount += count
 		}
-
-		// Sort by weight descending
-		sort.Slice(topGenres, func(i, j int) bool {
-			return topGenres[i].weight > topGenres[j].weight
-		})
-
-		// Take top 10 genres at most
-		count := len(topGenres)
-		if count > 10 {
-			count = 10
-		}
-
-		for i := 0; i < count; i++ {
-			prompt.WriteString(fmt.Sprintf("- %s (weight: %.2f)\n", topGenres[i].genre, topGenres[i].weight))
-		}
-	} else {
-		prompt.WriteString("No favorite genres identified yet\n")
+		watchTimes = append(watchTimes, hourCount{Hour: hour, Count: totalCount})
 	}
-	prompt.WriteString("\n")
-
-	// Provide favorite actors with weights
-	prompt.WriteString("## Favorite Actors\n")
-	if len(profile.FavoriteActors) > 0 {
-		// Sort actors by weight
-		type actorWeight struct {
-			actor  string
-			weight float32
-		}
-
-		var topActors []actorWeight
-		for actor, weight := range profile.FavoriteActors {
-			topActors = append(topActors, actorWeight{actor, weight})
-		}
-
-		// Sort by weight descending
-		sort.Slice(topActors, func(i, j int) bool {
-			return topActors[i].weight > topActors[j].weight
-		})
-
-		// Take top 10 actors at most
-		count := len(topActors)
-		if count > 10 {
-			count = 10
-		}
-
-		for i := 0; i < count; i++ {
-			prompt.WriteString(fmt.Sprintf("- %s (weight: %.2f)\n", topActors[i].actor, topActors[i].weight))
-		}
-	} else {
-		prompt.WriteString("No favorite actors identified yet\n")
-	}
-	prompt.WriteString("\n")
-
-	// Provide favorite directors with weights
-	prompt.WriteString("## Favorite Directors\n")
-	if len(profile.FavoriteDirectors) > 0 {
-		// Sort directors by weight
-		type directorWeight struct {
-			director string
-			weight   float32
-		}
-
-		var topDirectors []directorWeight
-		for director, weight := range profile.FavoriteDirectors {
-			topDirectors = append(topDirectors, directorWeight{director, weight})
-		}
-
-		// Sort by weight descending
-		sort.Slice(topDirectors, func(i, j int) bool {
-			return topDirectors[i].weight > topDirectors[j].weight
-		})
-
-		// Take top 5 directors at most
-		count := len(topDirectors)
-		if count > 5 {
-			count = 5
-		}
-
-		for i := 0; i < count; i++ {
-			prompt.WriteString(fmt.Sprintf("- %s (weight: %.2f)\n", topDirectors[i].director, topDirectors[i].weight))
-		}
-	} else {
-		prompt.WriteString("No favorite directors identified yet\n")
-	}
-	prompt.WriteString("\n")
-
-	// Add recently watched movies
-	prompt.WriteString("## Recently Watched Movies\n")
-	if len(profile.RecentMovies) > 0 {
-		// Take up to 10 most recent movies
-		count := len(profile.RecentMovies)
-		if count > 10 {
-			count = 10
-		}
-
-		for i := 0; i < count; i++ {
-			movie := profile.RecentMovies[i]
-			rating := ""
-			if movie.Rating > 0 {
-				rating = fmt.Sprintf(" - Rated: %.1f/5", movie.Rating)
-			}
-
-			genres := ""
-			if len(movie.Genres) > 0 {
-				genres = fmt.Sprintf(" - Genres: %s", strings.Join(movie.Genres, ", "))
-			}
-
-			prompt.WriteString(fmt.Sprintf("- %s (%d)%s%s\n",
-				movie.Title,
-				movie.Year,
-				rating,
-				genres))
-		}
-	} else {
-		prompt.WriteString("No recently watched movies\n")
-	}
-	prompt.WriteString("\n")
-
-	// Add highly rated movies
-	prompt.WriteString("## Highly Rated Movies\n")
-	if len(profile.TopRatedMovies) > 0 {
-		// Take up to 10 highest rated movies
-		count := len(profile.TopRatedMovies)
-		if count > 10 {
-			count = 10
-		}
-
-		for i := 0; i < count; i++ {
-			movie := profile.TopRatedMovies[i]
-			rating := "N/A"
-			if movie.Rating > 0 {
-				rating = fmt.Sprintf("%.1f/5", movie.Rating)
-			}
-
-			genres := ""
-			if len(movie.Genres) > 0 {
-				genres = fmt.Sprintf(" - Genres: %s", strings.Join(movie.Genres, ", "))
-			}
-
-			prompt.WriteString(fmt.Sprintf("- %s (%d) - Rated: %s%s\n",
-				movie.Title,
-				movie.Year,
-				rating,
-				genres))
-		}
-	} else {
-		prompt.WriteString("No highly rated movies\n")
-	}
-	prompt.WriteString("\n")
-
-	// Add watch time patterns
-	prompt.WriteString("## Watch Time Patterns\n")
-	if len(profile.MovieWatchTimes) > 0 {
-		// Convert hour to counts for display
-		type hourCount struct {
-			hour  string
-			count int64
-		}
-
-		var patterns []hourCount
-		for hour, counts := range profile.MovieWatchTimes {
-			var totalCount int64
-			for _, count := range counts {
-				totalCount += count
-			}
-			patterns = append(patterns, hourCount{hour, totalCount})
-		}
-
-		// Sort by count descending
-		sort.Slice(patterns, func(i, j int) bool {
-			return patterns[i].count > patterns[j].count
-		})
-
-		// Take top 5 most active hours
-		count := len(patterns)
-		if count > 5 {
-			count = 5
-		}
-
-		for i := 0; i < count; i++ {
-			prompt.WriteString(fmt.Sprintf("- %s: %d views\n", patterns[i].hour, patterns[i].count))
-		}
-	} else {
-		prompt.WriteString("No watch time patterns identified yet\n")
-	}
-	prompt.WriteString("\n")
-
-	// Add activity level
-	if movieActivity, ok := profile.OverallActivityLevel["movie"]; ok {
-		prompt.WriteString(fmt.Sprintf("## Activity Level: %.2f (0-1 scale, where 1 is very active)\n\n", movieActivity))
-	}
-
-	// Add exclusion criteria
-	prompt.WriteString("# Exclusion Criteria\n")
-
-	// Add already watched movies
-	prompt.WriteString("## Already Watched Movies\n")
-	prompt.WriteString(fmt.Sprintf("User has watched %d unique movies (IDs not shown for brevity)\n\n", len(profile.WatchedMovieIDs)))
-
-	// Add excluded genres if any
-	if len(profile.ExcludedMovieGenres) > 0 {
-		prompt.WriteString("## Excluded Genres\n")
-		for _, genre := range profile.ExcludedMovieGenres {
-			prompt.WriteString(fmt.Sprintf("- %s\n", genre))
-		}
-		prompt.WriteString("\n")
-	}
-
-	// Add request for recommendations in specific format
-	prompt.WriteString(`# Recommendation Request
-Please provide 5-10 movie recommendations based on the user's preferences and watch history. For each recommendation, include:
-
-1. Title
-2. Year of release
-3. Genres (as an array)
-4. A score between 0-1 indicating how well it matches the user's preferences
-5. A detailed explanation of why this movie would appeal to the user based on their specific preferences
-6. Similar movies they've already watched that influenced this recommendation (as an array)
-7. Actors in the movie that match their preferences (as an array)
-8. Directors in the movie that match their preferences (as an array)
-9. Genres in the movie that match their preferences (as an array)
-
-Format your response as a valid JSON object with a "recommendations" array containing these details for each movie.
-
-Example structure:
-{
-  "recommendations": [
-    {
-      "title": "Movie Title",
-      "year": 2023,
-      "genres": ["Action", "Sci-Fi"],
-      "score": 0.92,
-      "reasoning": "This movie would appeal to the user because...",
-      "similarToMovies": ["Similar Movie 1", "Similar Movie 2"],
-      "matchesActors": ["Actor 1", "Actor 2"],
-      "matchesDirectors": ["Director 1"],
-      "matchesGenres": ["Action", "Sci-Fi"]
-    }
-  ]
-}
-`)
+	sort.Slice(watchTimes, func(i, j int) bool {
+		return watchTimes[i].Count > watchTimes[j].Count
+	})
 
-	return prompt.String()
+	movieActivity, hasActivity := profile.OverallActivityLevel["movie"]
+
+	return j.prompts.Render(ctx, user.ID, prompts.RecommendationMovie, map[string]any{
+		"UserID":            user.ID,
+		"Username":          user.Username,
+		"FavoriteGenres":    topWeighted(profile.FavoriteMovieGenres, 10),
+		"FavoriteActors":    topWeighted(profile.FavoriteActors, 10),
+		"FavoriteDirectors": topWeighted(profile.FavoriteDirectors, 5),
+		"RecentMovies":      firstN(profile.RecentMovies, 10),
+		"TopRatedMovies":    firstN(profile.TopRatedMovies, 10),
+		"WatchTimes":        firstN(watchTimes, 5),
+		"HasActivityLevel":  hasActivity,
+		"ActivityLevel":     movieActivity,
+		"WatchedCount":      len(profile.WatchedMovieIDs),
+		"ExcludedGenres":    profile.ExcludedMovieGenres,
+		"ExcludedTitles":    profile.Feedback.rejectedTitles(mediatypes.MediaTypeMovie),
+	})
 }
 
 // generateAIMovieRecommendations uses AI to generate personalized movie recommendations
@@ -461,22 +237,19 @@ func (j *RecommendationJob) generateAIMovieRecommendations(
 
 	log := logger.LoggerFromContext(ctx)
 
-	// Get AI client for the user
-	aiClient, err := j.getAIClient(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get AI client: %v", err)
-	}
-
 	// Prepare AI recommendation request
 	request := &aitypes.RecommendationRequest{
 		MediaType:       "movie",
 		Count:           10,
 		UserPreferences: map[string]interface{}{},
-		ExcludeIDs:      []string{},
+		ExcludeIDs:      append([]string{}, profile.Feedback.rejectedTitles(mediatypes.MediaTypeMovie)...),
 		AdditionalContext: fmt.Sprintf("User profile confidence: %.2f. Exploration score: %.2f. Content completion tendency: %.2f.",
 			profile.ProfileConfidence, profile.ExplorationScore, profile.ContentCompleter),
 	}
 
+	// Ask with the active version of the movie system prompt
+	prompt := j.withSystemPrompt(ctx, userID, prompts.RecommendationMovieSystem, request)
+
 	// Use userPreferences for our filters
 	filters := request.UserPreferences
 
@@ -709,7 +482,12 @@ func (j *RecommendationJob) generateAIMovieRecommendations(
 		Msg("Requesting AI movie recommendations")
 
 	// Request recommendations
-	aiRecommendations, err := aiClient.GetRecommendations(ctx, request)
+	var aiRecommendations *aitypes.RecommendationResponse
+	err := j.withAIClient(ctx, userID, func(ctx context.Context, aiClient ai.ClientAI) error {
+		var err error
+		aiRecommendations, err = aiClient.GetRecommendations(ctx, request)
+		return err
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get AI recommendations")
 		return nil, err
@@ -723,27 +501,21 @@ func (j *RecommendationJob) generateAIMovieRecommendations(
 		return recommendations, nil
 	}
 
-	for i, rec := range aiRecommendations.Items {
-		// Extract details from the recommendation
-		title := rec.Title
-		if title == "" {
-			continue // Skip recommendations without a title
-		}
-
-		// Use the year directly
-		year := rec.Year
-
-		// Create a unique key for this movie to check against watched/library maps
-		key := fmt.Sprintf("%s-%d", title, year)
-
-		// Skip if we've seen this and are excluding watched content
-		if excludeWatched && watchedMap[key] {
-			continue
-		}
-
-		// TODO: Find existing media item or create a placeholder
-		// For now, we'll skip the media item creation
+	// Check the titles against TMDB and the user's library, replacing the ones that don't exist or were watched
+	grounder := &recommendationGrounder{
+		resolve: j.movieResolver(ctx, userID),
+		library: newLibraryIndex(ctx, j.clientRepos, j.clientItemRepos.MovieClientRepo(), userID),
+		excluded: func(item *groundedItem) bool {
+			key := fmt.Sprintf("%s-%d", item.Title, item.Year)
+			if profile.Feedback.excludesItem(mediatypes.MediaTypeMovie, item.MediaItemID, item.Title, item.Year, item.ExternalIDs) {
+				return true
+			}
+			return excludeWatched && (watchedMap[key] || profile.WatchedMovieIDs[item.MediaItemID])
+		},
+	}
+	items := j.groundRecommendations(ctx, userID, request, aiRecommendations, grounder)
 
+	for i, rec := range items {
 		// Set confidence based on profile confidence
 		confidence := float32(0.7) + (profile.ProfileConfidence * 0.3) // Scale from 0.7-1.0 based on profile
 
@@ -769,20 +541,26 @@ func (j *RecommendationJob) generateAIMovieRecommendations(
 			metadata["tmdbId"] = rec.ExternalID
 		}
 
-		// Determine if it's in the user's library
-		isInLibrary := false
-		// Check if we have the external ID as a string
-		isInLibrary = profile.OwnedMovieIDs[rec.ExternalID] || watchedMap[key]
-
 		// Create the recommendation based on the models.Recommendation struct
 		recommendation := &models.Recommendation{
 			UserID:           userID,
+			MediaItemID:      rec.MediaItemID,
 			MediaType:        "movie",
+			Title:            rec.Title,
+			Year:             rec.Year,
+			Genres:           rec.Genres,
+			PosterURL:        rec.PosterURL,
+			BackdropURL:      rec.BackdropURL,
 			Source:           models.RecommendationSourceAI,
 			SourceClientType: "ai",
 			Reasoning:        reason,
 			Confidence:       confidence,
-			InLibrary:        isInLibrary,
+			InLibrary:        rec.InLibrary(),
+			PromptTemplate:   prompt.Name,
+			PromptVersion:    prompt.Version,
+		}
+		if len(rec.ExternalIDs) > 0 {
+			recommendation.ExternalIDs = &rec.ExternalIDs
 		}
 
 		recommendations = append(recommendations, recommendation)
@@ -820,6 +598,9 @@ func (j *RecommendationJob) processMovieHistory(ctx context.Context, profile *Us
 		// Track watched movie ID
 		if history.MediaItemID > 0 {
 			profile.WatchedMovieIDs[history.MediaItemID] = true
+			if history.IsDisliked {
+				profile.DislikedItemIDs[history.MediaItemID] = true
+			}
 		}
 
 		// Track watch time patterns
@@ -920,6 +701,19 @@ func (j *RecommendationJob) processMovieHistory(ctx context.Context, profile *Us
 				}
 			}
 
+			// Add the movie's features to the taste profile the content-based recommender scores against
+			features := newContentItem(movieData.Details, credits)
+			profile.MovieTaste.add(features.vector(), tasteWeight(weight, history.IsDisliked, history.UserRating))
+			for _, writer := range features.Writers {
+				profile.FavoriteWriters[writer] += weight
+			}
+			for _, tag := range features.Tags {
+				profile.MovieTagPreferences[tag] += weight
+			}
+			if features.Studio != "" {
+				profile.FavoriteStudios[features.Studio] += weight
+			}
+
 			// Add to recent movies list
 			recentMovies = append(recentMovies, summary)
 
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"suasor/clients/ai"
 	aitypes "suasor/clients/ai/types"
 	mediatypes "suasor/clients/media/types"
 	"suasor/types/models"
 	"suasor/utils/logger"
+	"suasor/utils/prompts"
 	"time"
 )
 
@@ -267,22 +269,19 @@ func (j *RecommendationJob) generateAIMusicRecommendations(
 
 	log := logger.LoggerFromContext(ctx)
 
-	// Get AI client for the user
-	aiClient, err := j.getAIClient(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get AI client: %v", err)
-	}
-
 	// Prepare AI recommendation request
 	request := &aitypes.RecommendationRequest{
 		MediaType:       "music",
 		Count:           8,
 		UserPreferences: map[string]interface{}{},
-		ExcludeIDs:      []string{},
+		ExcludeIDs:      append([]string{}, profile.Feedback.rejectedTitles("music")...),
 		AdditionalContext: fmt.Sprintf("User profile confidence: %.2f. Activity level: %.2f. Music mood preferences variety: %d.",
 			profile.ProfileConfidence, profile.OverallActivityLevel["music"], len(profile.MusicMoodPreferences)),
 	}
 
+	// Ask with the active version of the music system prompt
+	prompt := j.withSystemPrompt(ctx, userID, prompts.RecommendationMusicSystem, request)
+
 	// Use userPreferences for our filters
 	filters := request.UserPreferences
 
@@ -504,7 +503,12 @@ func (j *RecommendationJob) generateAIMusicRecommendations(
 		Msg("Requesting AI music recommendations")
 
 	// Request recommendations
-	aiRecommendations, err := aiClient.GetRecommendations(ctx, request)
+	var aiRecommendations *aitypes.RecommendationResponse
+	err := j.withAIClient(ctx, userID, func(ctx context.Context, aiClient ai.ClientAI) error {
+		var err error
+		aiRecommendations, err = aiClient.GetRecommendations(ctx, request)
+		return err
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get AI music recommendations")
 		return nil, err
@@ -518,30 +522,25 @@ func (j *RecommendationJob) generateAIMusicRecommendations(
 		return recommendations, nil
 	}
 
-	for i, rec := range aiRecommendations.Items {
-		// Extract details from the recommendation
-		title := rec.Title
-		if title == "" {
-			continue // Skip recommendations without a title
-		}
-
-		// Create a unique key for this track
-		key := title
-		artist := ""
-		if rec.Description != "" {
-			// If description has artist info, use it
-			artist = rec.Description
-			key = fmt.Sprintf("%s-%s", artist, title)
-		}
-
-		// Skip if we've played this and are excluding played content
-		if excludePlayed && playedMap[key] {
-			continue
-		}
+	// Check the albums against MusicBrainz and the user's library, replacing the ones that don't exist or were played
+	grounder := &recommendationGrounder{
+		resolve: j.musicResolver(),
+		library: newLibraryIndex(ctx, j.clientRepos, j.clientItemRepos.AlbumClientRepo(), userID),
+		excluded: func(item *groundedItem) bool {
+			key := item.Title
+			if artist := musicArtist(item); artist != "" {
+				key = fmt.Sprintf("%s-%s", artist, item.Title)
+			}
+			if profile.Feedback.excludesItem("music", item.MediaItemID, item.Title, item.Year, item.ExternalIDs) {
+				return true
+			}
+			return excludePlayed && (playedMap[key] || profile.PlayedMusicIDs[item.MediaItemID])
+		},
+	}
+	items := j.groundRecommendations(ctx, userID, request, aiRecommendations, grounder)
 
-		// Create a new media item for this recommendation
-		// TODO: Find existing media item or create a placeholder
-		// For now, we'll skip the media item creation
+	for i, rec := range items {
+		artist := musicArtist(rec)
 
 		// Set confidence based on profile confidence
 		confidence := float32(0.7) + (profile.ProfileConfidence * 0.3) // Scale from 0.7-1.0 based on profile
@@ -578,20 +577,26 @@ func (j *RecommendationJob) generateAIMusicRecommendations(
 			metadata["externalId"] = rec.ExternalID
 		}
 
-		// Determine if it's in the user's library
-		isInLibrary := false
-		// Check if we have the external ID as a string
-		isInLibrary = profile.OwnedMusicIDs[rec.ExternalID] || playedMap[key]
-
 		// Create the recommendation based on the models.Recommendation struct
 		recommendation := &models.Recommendation{
 			UserID:           userID,
+			MediaItemID:      rec.MediaItemID,
 			MediaType:        "music",
+			Title:            rec.Title,
+			Year:             rec.Year,
+			Genres:           rec.Genres,
+			PosterURL:        rec.PosterURL,
+			BackdropURL:      rec.BackdropURL,
 			Source:           models.RecommendationSourceAI,
 			SourceClientType: "ai",
 			Reasoning:        reason,
 			Confidence:       confidence,
-			InLibrary:        isInLibrary,
+			InLibrary:        rec.InLibrary(),
+			PromptTemplate:   prompt.Name,
+			PromptVersion:    prompt.Version,
+		}
+		if len(rec.ExternalIDs) > 0 {
+			recommendation.ExternalIDs = &rec.ExternalIDs
 		}
 
 		recommendations = append(recommendations, recommendation)
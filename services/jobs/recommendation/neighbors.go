@@ -0,0 +1,265 @@
+package recommendation
+
+import (
+	"math"
+	"sort"
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+)
+
+const (
+	// maxNeighbors is the number of neighbours kept for each item
+	maxNeighbors = 50
+	// minSupport is the number of users who must share two items before they are neighbours
+	minSupport = 2
+	// supportShrinkage pulls the similarity of items few users share towards 0
+	supportShrinkage = 5.0
+	// maxUserItems caps the history of a single user, the pairs of a history grow with its square
+	maxUserItems = 500
+	// neutralRating is the middle of the 0 to 10 rating scale
+	neutralRating = 5.0
+)
+
+// interactionWeight is how strongly an interaction says the user liked the item, 0 when it says nothing or the opposite
+func interactionWeight(interaction models.ItemInteraction) float64 {
+	if interaction.IsDisliked {
+		return 0
+	}
+
+	weight := 0.0
+	if interaction.Completed || interaction.PlayCount > 0 || interaction.PlayedPercentage >= 50 {
+		weight = 1
+	}
+	if interaction.IsFavorite {
+		weight++
+	}
+	if interaction.UserRating > 0 {
+		weight += (float64(interaction.UserRating) - neutralRating) / neutralRating
+	}
+	return math.Max(weight, 0)
+}
+
+// seenItem reports whether the user watched or judged the item
+func seenItem(interaction models.ItemInteraction) bool {
+	return interaction.Completed || interaction.PlayCount > 0 || interaction.PlayedPercentage > 0 ||
+		interaction.IsFavorite || interaction.IsDisliked || interaction.UserRating > 0
+}
+
+// itemPair is two items with the smaller ID first
+type itemPair struct {
+	a, b uint64
+}
+
+// pairStats are the co-watch and co-rating counts of an item pair
+type pairStats struct {
+	support   int
+	coRaters  int
+	dot       float64
+	normA     float64
+	normB     float64
+	hasRating bool
+}
+
+// computeNeighbors computes the item-item similarity of the interactions of one media type.
+// The co-watch similarity is the cosine of the items' watcher sets, the co-rating similarity is the adjusted
+// cosine of the ratings of the users who rated both. Items rated by enough common users average the two.
+func computeNeighbors(interactions []models.ItemInteraction) []*models.MediaItemNeighbor {
+	byUser := make(map[uint64][]models.ItemInteraction)
+	for _, interaction := range dedupeInteractions(interactions) {
+		if interactionWeight(interaction) > 0 {
+			byUser[interaction.UserID] = append(byUser[interaction.UserID], interaction)
+		}
+	}
+
+	watchers := make(map[uint64]int)
+	pairs := make(map[itemPair]*pairStats)
+	for _, history := range byUser {
+		history = strongestInteractions(history, maxUserItems)
+
+		// Ratings are centred on the user's mean, a user who rates everything 8 liked nothing in particular
+		meanRating, rated := 0.0, 0
+		for _, interaction := range history {
+			if interaction.UserRating > 0 {
+				meanRating += float64(interaction.UserRating)
+				rated++
+			}
+		}
+		if rated > 0 {
+			meanRating /= float64(rated)
+		}
+
+		for i, first := range history {
+			watchers[first.MediaItemID]++
+			for _, second := range history[i+1:] {
+				a, b := first, second
+				if a.MediaItemID > b.MediaItemID {
+					a, b = b, a
+				}
+				key := itemPair{a.MediaItemID, b.MediaItemID}
+				stats, ok := pairs[key]
+				if !ok {
+					stats = &pairStats{}
+					pairs[key] = stats
+				}
+				stats.support++
+				if rated > 1 && a.UserRating > 0 && b.UserRating > 0 {
+					ra, rb := float64(a.UserRating)-meanRating, float64(b.UserRating)-meanRating
+					stats.coRaters++
+					stats.dot += ra * rb
+					stats.normA += ra * ra
+					stats.normB += rb * rb
+					stats.hasRating = true
+				}
+			}
+		}
+	}
+
+	byItem := make(map[uint64][]*models.MediaItemNeighbor)
+	itemTypes := make(map[uint64]mediatypes.MediaType)
+	for _, interaction := range interactions {
+		itemTypes[interaction.MediaItemID] = interaction.Type
+	}
+	for key, stats := range pairs {
+		if stats.support < minSupport {
+			continue
+		}
+		score := pairSimilarity(stats, watchers[key.a], watchers[key.b])
+		if score <= 0 {
+			continue
+		}
+		mediaType := itemTypes[key.a]
+		byItem[key.a] = append(byItem[key.a], &models.MediaItemNeighbor{MediaItemID: key.a, NeighborID: key.b, MediaType: mediaType, Score: score, Support: stats.support})
+		byItem[key.b] = append(byItem[key.b], &models.MediaItemNeighbor{MediaItemID: key.b, NeighborID: key.a, MediaType: mediaType, Score: score, Support: stats.support})
+	}
+
+	var neighbors []*models.MediaItemNeighbor
+	for _, itemNeighbors := range byItem {
+		sort.Slice(itemNeighbors, func(i, j int) bool {
+			if itemNeighbors[i].Score != itemNeighbors[j].Score {
+				return itemNeighbors[i].Score > itemNeighbors[j].Score
+			}
+			return itemNeighbors[i].NeighborID < itemNeighbors[j].NeighborID
+		})
+		neighbors = append(neighbors, firstN(itemNeighbors, maxNeighbors)...)
+	}
+	return neighbors
+}
+
+// pairSimilarity blends the co-watch and co-rating similarity of a pair and shrinks it by its support
+func pairSimilarity(stats *pairStats, watchersA, watchersB int) float64 {
+	if watchersA == 0 || watchersB == 0 {
+		return 0
+	}
+	similarity := float64(stats.support) / math.Sqrt(float64(watchersA)*float64(watchersB))
+
+	if stats.hasRating && stats.coRaters >= minSupport && stats.normA > 0 && stats.normB > 0 {
+		rating := stats.dot / math.Sqrt(stats.normA*stats.normB)
+		similarity = (similarity + math.Max(rating, 0)) / 2
+	}
+
+	return similarity * float64(stats.support) / (float64(stats.support) + supportShrinkage)
+}
+
+// dedupeInteractions keeps the strongest interaction of each user and item, an item played on
+// several clients has a row for each
+func dedupeInteractions(interactions []models.ItemInteraction) []models.ItemInteraction {
+	type userItem struct {
+		userID, mediaItemID uint64
+	}
+	index := make(map[userItem]int, len(interactions))
+	deduped := make([]models.ItemInteraction, 0, len(interactions))
+	for _, interaction := range interactions {
+		key := userItem{interaction.UserID, interaction.MediaItemID}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(deduped)
+			deduped = append(deduped, interaction)
+			continue
+		}
+		if interactionWeight(interaction) > interactionWeight(deduped[i]) {
+			deduped[i] = interaction
+		}
+	}
+	return deduped
+}
+
+// strongestInteractions keeps at most limit interactions, the ones that say most about the user's taste
+func strongestInteractions(history []models.ItemInteraction, limit int) []models.ItemInteraction {
+	if len(history) <= limit {
+		return history
+	}
+	sorted := append([]models.ItemInteraction{}, history...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return interactionWeight(sorted[i]) > interactionWeight(sorted[j])
+	})
+	return sorted[:limit]
+}
+
+// candidateScore is an item recommended by the neighbours of the items a user liked
+type candidateScore struct {
+	MediaItemID uint64
+	// Score is the sum of the neighbour similarities weighted by how much the user liked each seed
+	Score float64
+	// Confidence is the weighted mean similarity to the seeds, from 0 to 1
+	Confidence float64
+	// Seeds are the items of the user that led to the candidate, the strongest first
+	Seeds []uint64
+}
+
+// scoreCandidates ranks the neighbours of the seeds, the items the user liked by their weight.
+// Seeds with a negative weight, items the user rejected, subtract from their neighbours, and items that
+// end up with no score are dropped. Items in exclude are never returned.
+func scoreCandidates(seeds map[uint64]float64, neighbors []models.MediaItemNeighbor, exclude map[uint64]bool) []candidateScore {
+	type contribution struct {
+		seed  uint64
+		value float64
+	}
+	scores := make(map[uint64]float64)
+	weights := make(map[uint64]float64)
+	contributions := make(map[uint64][]contribution)
+
+	for _, neighbor := range neighbors {
+		weight, ok := seeds[neighbor.MediaItemID]
+		if !ok || weight == 0 || exclude[neighbor.NeighborID] {
+			continue
+		}
+		scores[neighbor.NeighborID] += weight * neighbor.Score
+		if weight < 0 {
+			continue
+		}
+		weights[neighbor.NeighborID] += weight
+		contributions[neighbor.NeighborID] = append(contributions[neighbor.NeighborID], contribution{neighbor.MediaItemID, weight * neighbor.Score})
+	}
+
+	candidates := make([]candidateScore, 0, len(scores))
+	for id, score := range scores {
+		if score <= 0 {
+			continue
+		}
+		itemContributions := contributions[id]
+		sort.Slice(itemContributions, func(i, j int) bool {
+			if itemContributions[i].value != itemContributions[j].value {
+				return itemContributions[i].value > itemContributions[j].value
+			}
+			return itemContributions[i].seed < itemContributions[j].seed
+		})
+		seedIDs := make([]uint64, 0, len(itemContributions))
+		for _, c := range itemContributions {
+			seedIDs = append(seedIDs, c.seed)
+		}
+		candidates = append(candidates, candidateScore{
+			MediaItemID: id,
+			Score:       score,
+			Confidence:  math.Min(score/weights[id], 1),
+			Seeds:       seedIDs,
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].MediaItemID < candidates[j].MediaItemID
+	})
+	return candidates
+}
@@ -0,0 +1,102 @@
+package recommendation
+
+import (
+	"testing"
+
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func watched(userID, itemID uint64, rating float32) models.ItemInteraction {
+	return models.ItemInteraction{UserID: userID, MediaItemID: itemID, Type: mediatypes.MediaTypeMovie, PlayCount: 1, Completed: true, UserRating: rating}
+}
+
+func TestComputeNeighbors(t *testing.T) {
+	interactions := []models.ItemInteraction{
+		// Items 1 and 2 are watched together by three users, 3 by one of them only
+		watched(1, 1, 0), watched(1, 2, 0), watched(1, 3, 0),
+		watched(2, 1, 0), watched(2, 2, 0),
+		watched(3, 1, 0), watched(3, 2, 0),
+		// A second row of the same play doesn't count twice
+		watched(3, 2, 0),
+		// A disliked item says nothing about the items watched with it
+		watched(4, 1, 0), {UserID: 4, MediaItemID: 4, Type: mediatypes.MediaTypeMovie, PlayCount: 1, IsDisliked: true},
+	}
+
+	neighbors := computeNeighbors(interactions)
+
+	byPair := make(map[[2]uint64]*models.MediaItemNeighbor)
+	for _, neighbor := range neighbors {
+		byPair[[2]uint64{neighbor.MediaItemID, neighbor.NeighborID}] = neighbor
+	}
+	require.Len(t, byPair, 2, "only 1 and 2 are shared by enough users")
+
+	forward, backward := byPair[[2]uint64{1, 2}], byPair[[2]uint64{2, 1}]
+	require.NotNil(t, forward)
+	require.NotNil(t, backward)
+	assert.Equal(t, 3, forward.Support)
+	assert.Equal(t, mediatypes.MediaTypeMovie, forward.MediaType)
+	// 3 shared of 4 and 3 watchers, shrunk by 3 / (3 + 5)
+	assert.InDelta(t, 3/(2*1.7320508)*3/8.0, forward.Score, 1e-6)
+	assert.Equal(t, forward.Score, backward.Score)
+}
+
+func TestPairSimilarityUsesRatings(t *testing.T) {
+	watchOnly := pairSimilarity(&pairStats{support: 4}, 4, 4)
+	agreeing := pairSimilarity(&pairStats{support: 4, coRaters: 4, dot: 4, normA: 4, normB: 4, hasRating: true}, 4, 4)
+	disagreeing := pairSimilarity(&pairStats{support: 4, coRaters: 4, dot: -4, normA: 4, normB: 4, hasRating: true}, 4, 4)
+
+	assert.InDelta(t, 4/9.0, watchOnly, 1e-9)
+	assert.InDelta(t, watchOnly, agreeing, 1e-9)
+	assert.InDelta(t, watchOnly/2, disagreeing, 1e-9)
+}
+
+func TestScoreCandidates(t *testing.T) {
+	seeds := map[uint64]float64{1: 2, 2: 1}
+	neighbors := []models.MediaItemNeighbor{
+		{MediaItemID: 1, NeighborID: 10, Score: 0.5},
+		{MediaItemID: 2, NeighborID: 10, Score: 0.2},
+		{MediaItemID: 2, NeighborID: 11, Score: 0.9},
+		{MediaItemID: 1, NeighborID: 12, Score: 0.9},
+		{MediaItemID: 3, NeighborID: 13, Score: 0.9},
+	}
+
+	candidates := scoreCandidates(seeds, neighbors, map[uint64]bool{12: true})
+
+	require.Len(t, candidates, 2)
+	assert.Equal(t, uint64(10), candidates[0].MediaItemID)
+	assert.InDelta(t, 1.2, candidates[0].Score, 1e-9)
+	assert.InDelta(t, 0.4, candidates[0].Confidence, 1e-9)
+	assert.Equal(t, []uint64{1, 2}, candidates[0].Seeds)
+	assert.Equal(t, uint64(11), candidates[1].MediaItemID)
+}
+
+func TestScoreCandidatesWithRejectedSeeds(t *testing.T) {
+	seeds := map[uint64]float64{1: 1, 2: -1}
+	neighbors := []models.MediaItemNeighbor{
+		{MediaItemID: 1, NeighborID: 10, Score: 0.5},
+		{MediaItemID: 2, NeighborID: 10, Score: 0.2},
+		{MediaItemID: 2, NeighborID: 11, Score: 0.9},
+		{MediaItemID: 1, NeighborID: 12, Score: 0.3},
+		{MediaItemID: 2, NeighborID: 12, Score: 0.4},
+	}
+
+	candidates := scoreCandidates(seeds, neighbors, nil)
+
+	require.Len(t, candidates, 1, "items closer to the rejected seed are dropped")
+	assert.Equal(t, uint64(10), candidates[0].MediaItemID)
+	assert.InDelta(t, 0.3, candidates[0].Score, 1e-9)
+	assert.InDelta(t, 0.3, candidates[0].Confidence, 1e-9)
+	assert.Equal(t, []uint64{1}, candidates[0].Seeds)
+}
+
+func TestInteractionWeight(t *testing.T) {
+	assert.Equal(t, 1.0, interactionWeight(watched(1, 1, 0)))
+	assert.InDelta(t, 2.6, interactionWeight(models.ItemInteraction{PlayCount: 1, IsFavorite: true, UserRating: 8}), 1e-9)
+	assert.Zero(t, interactionWeight(models.ItemInteraction{UserRating: 2}))
+	assert.Zero(t, interactionWeight(models.ItemInteraction{PlayCount: 3, IsDisliked: true}))
+	assert.False(t, seenItem(models.ItemInteraction{}))
+}
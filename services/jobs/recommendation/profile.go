@@ -33,6 +33,9 @@ func (j *RecommendationJob) buildUserPreferenceProfile(ctx context.Context, user
 		MovieWatchTimes:       make(map[string][]int64),
 		MovieWatchDays:        make(map[string]int),
 		MovieTagPreferences:   make(map[string]float32),
+		FavoriteWriters:       make(map[string]float32),
+		FavoriteStudios:       make(map[string]float32),
+		MovieTaste:            make(featureVector),
 		ExcludedMovieGenres:   []string{},
 		PreferredMovieGenres:  []string{},
 		MovieReleaseYearRange: [2]int{1900, time.Now().Year()},
@@ -46,6 +49,7 @@ func (j *RecommendationJob) buildUserPreferenceProfile(ctx context.Context, user
 		SeriesWatchTimes:       make(map[string][]int64),
 		SeriesWatchDays:        make(map[string]int),
 		SeriesTagPreferences:   make(map[string]float32),
+		SeriesTaste:            make(featureVector),
 		ExcludedSeriesGenres:   []string{},
 		PreferredSeriesGenres:  []string{},
 		SeriesReleaseYearRange: [2]int{1900, time.Now().Year()},
@@ -63,6 +67,8 @@ func (j *RecommendationJob) buildUserPreferenceProfile(ctx context.Context, user
 		MusicMoodPreferences: make(map[string]float32),
 		ExcludedMusicGenres:  []string{},
 		PreferredMusicGenres: []string{},
+
+		DislikedItemIDs: make(map[uint64]bool),
 	}
 
 	// Get user's movie watch history
@@ -95,6 +101,14 @@ func (j *RecommendationJob) buildUserPreferenceProfile(ctx context.Context, user
 		j.processMusicHistory(ctx, profile, musicHistory)
 	}
 
+	// Add what the user told us about their recommendations, titles they rejected are never recommended again
+	feedback, err := j.feedbackRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get recommendation feedback")
+		// Don't return - continue with what we have
+	}
+	applyFeedback(profile, feedback)
+
 	// Calculate advanced metrics across all media types
 	j.calculateAdvancedMetrics(profile)
 
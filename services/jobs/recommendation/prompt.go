@@ -0,0 +1,63 @@
+package recommendation
+
+import (
+	"context"
+	"sort"
+	aitypes "suasor/clients/ai/types"
+	"suasor/utils/logger"
+	"suasor/utils/prompts"
+)
+
+// weightedName is a genre or person with its preference weight, as the prompt templates read it
+type weightedName struct {
+	Name   string
+	Weight float32
+}
+
+// hourCount is the number of views in an hour of the day, as the prompt templates read it
+type hourCount struct {
+	Hour  string
+	Count int64
+}
+
+// topWeighted returns at most limit names, the highest weight first
+func topWeighted(weights map[string]float32, limit int) []weightedName {
+	names := make([]weightedName, 0, len(weights))
+	for name, weight := range weights {
+		names = append(names, weightedName{Name: name, Weight: weight})
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Weight != names[j].Weight {
+			return names[i].Weight > names[j].Weight
+		}
+		return names[i].Name < names[j].Name
+	})
+	return firstN(names, limit)
+}
+
+// firstN returns at most the first n items
+func firstN[T any](items []T, n int) []T {
+	if len(items) > n {
+		return items[:n]
+	}
+	return items
+}
+
+// withSystemPrompt renders a system prompt template into the generation options of the request.
+// The template name and version are returned so the recommendations can record them, when the
+// template fails to render the request keeps the client's default instructions.
+func (j *RecommendationJob) withSystemPrompt(ctx context.Context, userID uint64, name string, request *aitypes.RecommendationRequest) *prompts.Rendered {
+	log := logger.LoggerFromContext(ctx)
+
+	rendered, err := j.prompts.Render(ctx, userID, name, nil)
+	if err != nil {
+		log.Warn().Err(err).Str("template", name).Msg("Failed to render system prompt, using the client default")
+		return &prompts.Rendered{}
+	}
+
+	if request.GenerationOptions == nil {
+		request.GenerationOptions = &aitypes.GenerationOptions{}
+	}
+	request.GenerationOptions.SystemInstructions = rendered.Text
+	return rendered
+}
@@ -0,0 +1,560 @@
+package recommendation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	mediatypes "suasor/clients/media/types"
+	clienttypes "suasor/clients/types"
+	"suasor/repository"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"time"
+)
+
+// RankedRecommender is the RecommendedBy of the final list merged from the lists of every recommender
+const RankedRecommender = "ranked"
+
+// Recommendation strategies of a user's config
+const (
+	strategySimilar  = "similar"
+	strategyRecent   = "recent"
+	strategyPopular  = "popular"
+	strategyDiverse  = "diverse"
+	strategyBalanced = "balanced"
+)
+
+const (
+	// maxSourceRelevance is the relevance of the best item of a single recommender, items several recommenders agree on rank above it
+	maxSourceRelevance = 0.9
+	// newnessHalfLife is the age in years at which a release counts half as new
+	newnessHalfLife = 3.0
+	// defaultDiversityLambda is the share of the score in diversity re-ranking, the rest goes to being unlike the items above
+	defaultDiversityLambda = 0.7
+	// minDiversityLambda keeps the score in play however much diversity is asked for
+	minDiversityLambda = 0.3
+	// minFranchiseStem is the shortest title a sequel's title can start with and still be the same franchise
+	minFranchiseStem = 5
+	// rankingExpiry is how long a ranked recommendation without an expiring source is shown
+	rankingExpiry = 7 * 24 * time.Hour
+)
+
+// rankingWeights are the weights of the ranking signals, taken from the user's config
+type rankingWeights struct {
+	// Relevance is the weight of how strongly the recommenders picked an item for the user's history
+	Relevance float64
+	// Popularity is the weight of how many users watched the item
+	Popularity float64
+	// Newness is the weight of how recently the item was released
+	Newness float64
+	// Lambda is the share of the score in diversity re-ranking, 1 ranks by score alone
+	Lambda float64
+}
+
+// rankingWeightsFor reads the ranking weights from the user's config and adjusts them to the strategy.
+// Discovery mode gives its ratio of the ranking to diversity.
+func rankingWeightsFor(config *models.UserConfig) rankingWeights {
+	weights := rankingWeights{
+		Relevance:  float64(config.PersonalHistoryWeight),
+		Popularity: float64(config.PopularityWeight),
+		Newness:    float64(config.NewContentWeight),
+		Lambda:     defaultDiversityLambda,
+	}
+	if weights.Relevance+weights.Popularity+weights.Newness <= 0 {
+		weights.Relevance = 1
+	}
+
+	switch config.RecommendationStrategy {
+	case strategySimilar:
+		weights.Popularity /= 2
+		weights.Newness /= 2
+		weights.Lambda = 0.85
+	case strategyRecent:
+		weights.Newness *= 2
+	case strategyPopular:
+		weights.Popularity *= 2
+	case strategyDiverse:
+		weights.Lambda = 0.5
+	case strategyBalanced:
+		// The configured weights as they are
+	}
+
+	if config.DiscoveryModeEnabled {
+		weights.Lambda = math.Min(weights.Lambda, 1-float64(config.DiscoveryModeRatio))
+	}
+	weights.Lambda = math.Max(weights.Lambda, minDiversityLambda)
+	return weights
+}
+
+// rankedCandidate is an item recommended by one or more recommenders
+type rankedCandidate struct {
+	// Best is the recommendation of the recommender that picked the item most strongly, the ranked one copies it
+	Best    models.Recommendation
+	Sources []string
+
+	Relevance  float64
+	Popularity float64
+	Newness    float64
+	Score      float64
+
+	Genres        []string
+	Creators      []string
+	MatchesActors []string
+	MatchesGenres []string
+	SimilarItems  []string
+	// Franchise is the stem of the title shared with sequels, see franchiseStem
+	Franchise string
+	// BestRelevance is the relevance Best was picked with
+	BestRelevance float64
+
+	// sourceRelevance is the best relevance of the item in each recommender's list
+	sourceRelevance map[string]float64
+}
+
+// recommendationKeys are the identities of a recommendation's item, the same item recommended twice shares one of them
+func recommendationKeys(recommendation models.Recommendation) []string {
+	var keys []string
+	if recommendation.MediaItemID > 0 {
+		keys = append(keys, fmt.Sprintf("item:%d", recommendation.MediaItemID))
+	}
+	if recommendation.ExternalIDs != nil {
+		if id := (*recommendation.ExternalIDs)[string(clienttypes.ClientTypeTMDB)]; id != "" {
+			keys = append(keys, fmt.Sprintf("tmdb:%s:%s", recommendation.MediaType, id))
+		}
+	}
+	if title := normalizeTitle(recommendation.Title); title != "" {
+		keys = append(keys, fmt.Sprintf("title:%s:%s:%d", recommendation.MediaType, title, recommendation.Year))
+	}
+	return keys
+}
+
+// mergeCandidates merges the recommendations of every recommender by item.
+// Each recommender's confidences are scaled to its best one, so recommenders that score differently count alike,
+// and the relevance of an item several recommenders picked combines them. Items the user dismissed anywhere are left out.
+func mergeCandidates(recommendations []models.Recommendation) []*rankedCandidate {
+	dismissed := make(map[string]bool)
+	topConfidence := make(map[string]float32)
+	for _, recommendation := range recommendations {
+		if recommendation.Dismissed {
+			for _, key := range recommendationKeys(recommendation) {
+				dismissed[key] = true
+			}
+			continue
+		}
+		source := string(recommendation.MediaType) + ":" + recommendation.RecommendedBy
+		topConfidence[source] = float32(math.Max(float64(topConfidence[source]), float64(recommendation.Confidence)))
+	}
+
+	byKey := make(map[string]*rankedCandidate)
+	var candidates []*rankedCandidate
+	for _, recommendation := range recommendations {
+		if recommendation.Dismissed || recommendation.RecommendedBy == RankedRecommender {
+			continue
+		}
+		keys := recommendationKeys(recommendation)
+		if len(keys) == 0 || anyKey(dismissed, keys) {
+			continue
+		}
+
+		relevance := maxSourceRelevance / 2
+		if top := topConfidence[string(recommendation.MediaType)+":"+recommendation.RecommendedBy]; top > 0 {
+			relevance = maxSourceRelevance * math.Max(float64(recommendation.Confidence), 0) / float64(top)
+		}
+
+		var candidate *rankedCandidate
+		for _, key := range keys {
+			if found, ok := byKey[key]; ok {
+				candidate = found
+				break
+			}
+		}
+		if candidate == nil {
+			candidate = &rankedCandidate{Best: recommendation, BestRelevance: relevance}
+			candidates = append(candidates, candidate)
+		} else if relevance > candidate.BestRelevance {
+			candidate.Best = recommendation
+			candidate.BestRelevance = relevance
+		}
+		for _, key := range keys {
+			byKey[key] = candidate
+		}
+
+		if candidate.sourceRelevance == nil {
+			candidate.sourceRelevance = make(map[string]float64)
+		}
+		source := recommendation.RecommendedBy
+		candidate.sourceRelevance[source] = math.Max(candidate.sourceRelevance[source], relevance)
+		candidate.Sources = appendUnique(candidate.Sources, source)
+		candidate.Genres = appendUnique(candidate.Genres, recommendation.Genres...)
+		candidate.Creators = appendUnique(candidate.Creators, recommendation.MatchesDirectors...)
+		candidate.MatchesActors = appendUnique(candidate.MatchesActors, recommendation.MatchesActors...)
+		candidate.MatchesGenres = appendUnique(candidate.MatchesGenres, recommendation.MatchesGenres...)
+		candidate.SimilarItems = appendUnique(candidate.SimilarItems, recommendation.SimilarItems...)
+	}
+
+	for _, candidate := range candidates {
+		// Noisy-or of the recommenders, each one that picked the item closes part of the gap to 1
+		missed := 1.0
+		for _, relevance := range candidate.sourceRelevance {
+			missed *= 1 - relevance
+		}
+		candidate.Relevance = 1 - missed
+		candidate.Franchise = franchiseStem(candidate.Best.Title)
+	}
+	return candidates
+}
+
+// scoreRanking scores the candidates by the weighted mean of their relevance, popularity and newness.
+// Popularity is the log of the item's watchers relative to the most watched candidate.
+func scoreRanking(candidates []*rankedCandidate, watchers map[uint64]int, weights rankingWeights, year int) {
+	maxWatchers := 0
+	for _, candidate := range candidates {
+		maxWatchers = max(maxWatchers, watchers[candidate.Best.MediaItemID])
+	}
+
+	total := weights.Relevance + weights.Popularity + weights.Newness
+	for _, candidate := range candidates {
+		candidate.Popularity = 0
+		if count := watchers[candidate.Best.MediaItemID]; candidate.Best.MediaItemID > 0 && maxWatchers > 0 {
+			candidate.Popularity = math.Log1p(float64(count)) / math.Log1p(float64(maxWatchers))
+		}
+		candidate.Newness = newness(candidate.Best.Year, year)
+		candidate.Score = (weights.Relevance*candidate.Relevance + weights.Popularity*candidate.Popularity + weights.Newness*candidate.Newness) / total
+	}
+}
+
+// newness is 1 for this year's releases and halves every newnessHalfLife years, 0 when the year is unknown
+func newness(releaseYear, year int) float64 {
+	if releaseYear <= 0 {
+		return 0
+	}
+	return math.Pow(0.5, float64(max(year-releaseYear, 0))/newnessHalfLife)
+}
+
+// diversify orders the candidates by maximal marginal relevance: each pick is the candidate with the best
+// score less its similarity to the picks above it, so one franchise, genre or creator doesn't fill the list
+func diversify(candidates []*rankedCandidate, lambda float64, limit int) []*rankedCandidate {
+	remaining := append([]*rankedCandidate{}, candidates...)
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return remaining[i].Score > remaining[j].Score
+	})
+
+	var picked []*rankedCandidate
+	for len(remaining) > 0 && len(picked) < limit {
+		bestIndex, bestValue := 0, math.Inf(-1)
+		for i, candidate := range remaining {
+			similarity := 0.0
+			for _, other := range picked {
+				similarity = math.Max(similarity, candidateSimilarity(candidate, other))
+			}
+			if value := lambda*candidate.Score - (1-lambda)*similarity; value > bestValue {
+				bestIndex, bestValue = i, value
+			}
+		}
+		picked = append(picked, remaining[bestIndex])
+		remaining = append(remaining[:bestIndex], remaining[bestIndex+1:]...)
+	}
+	return picked
+}
+
+// candidateSimilarity is 1 for the same franchise, otherwise it blends the overlap of the genres and a shared creator
+func candidateSimilarity(a, b *rankedCandidate) float64 {
+	if sameFranchise(a.Franchise, b.Franchise) {
+		return 1
+	}
+	similarity := 0.6 * jaccard(a.Genres, b.Genres)
+	if jaccard(a.Creators, b.Creators) > 0 {
+		similarity += 0.4
+	}
+	return similarity
+}
+
+// franchiseStem is the part of a title its sequels share: the title before a subtitle, without a trailing number
+func franchiseStem(title string) string {
+	for _, separator := range []string{":", " - "} {
+		if i := strings.Index(title, separator); i > 0 {
+			title = title[:i]
+		}
+	}
+	words := strings.Fields(normalizeTitle(title))
+	for len(words) > 1 && isSequelNumber(words[len(words)-1]) {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+func isSequelNumber(word string) bool {
+	switch word {
+	case "ii", "iii", "iv", "v", "vi", "vii", "viii", "ix", "x", "part":
+		return true
+	}
+	for _, r := range word {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// sameFranchise reports whether two stems are equal or one starts with the other at a word boundary
+func sameFranchise(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if a == b {
+		return true
+	}
+	return len(a) >= minFranchiseStem && strings.HasPrefix(b, a+" ")
+}
+
+// jaccard is the share of the values two lists have in common, comparing them case-insensitively
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, value := range a {
+		set[strings.ToLower(value)] = true
+	}
+	union := len(set)
+	shared := 0
+	seen := make(map[string]bool, len(b))
+	for _, value := range b {
+		value = strings.ToLower(value)
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		if set[value] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared) / float64(union)
+}
+
+func appendUnique(values []string, more ...string) []string {
+	for _, value := range more {
+		found := false
+		for _, existing := range values {
+			if strings.EqualFold(existing, value) {
+				found = true
+				break
+			}
+		}
+		if !found && value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func anyKey(set map[string]bool, keys []string) bool {
+	for _, key := range keys {
+		if set[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// RankingJob merges the recommendations of every recommender into one ranked list per user and media type.
+// Items are scored with the weights and strategy of the user's config, then re-ranked for diversity.
+type RankingJob struct {
+	jobRepo            repository.JobRepository
+	userRepo           repository.UserRepository
+	userConfigRepo     repository.UserConfigRepository
+	recommendationRepo repository.RecommendationRepository
+	feedbackRepo       repository.RecommendationFeedbackRepository
+	neighborRepo       repository.NeighborRepository
+}
+
+// NewRankingJob creates a new recommendation ranking job
+func NewRankingJob(
+	jobRepo repository.JobRepository,
+	userRepo repository.UserRepository,
+	userConfigRepo repository.UserConfigRepository,
+	recommendationRepo repository.RecommendationRepository,
+	feedbackRepo repository.RecommendationFeedbackRepository,
+	neighborRepo repository.NeighborRepository,
+) *RankingJob {
+	return &RankingJob{
+		jobRepo:            jobRepo,
+		userRepo:           userRepo,
+		userConfigRepo:     userConfigRepo,
+		recommendationRepo: recommendationRepo,
+		feedbackRepo:       feedbackRepo,
+		neighborRepo:       neighborRepo,
+	}
+}
+
+// Name returns the unique name of the job
+func (j *RankingJob) Name() string {
+	return "system.recommendation.ranking"
+}
+
+// Schedule returns when the job should next run
+func (j *RankingJob) Schedule() time.Duration {
+	return 6 * time.Hour
+}
+
+// Execute replaces every active user's ranked recommendations
+func (j *RankingJob) Execute(ctx context.Context) error {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().Msg("Starting recommendation ranking job")
+
+	now := time.Now()
+	jobRun := &models.JobRun{
+		JobName:   j.Name(),
+		JobType:   models.JobTypeSystem,
+		Status:    models.JobStatusRunning,
+		StartTime: &now,
+		Metadata:  fmt.Sprintf(`{"type":"recommendation","recommender":"%s","startTime":"%s"}`, RankedRecommender, now.Format(time.RFC3339)),
+	}
+	if err := j.jobRepo.CreateJobRun(ctx, jobRun); err != nil {
+		log.Error().Err(err).Msg("Error creating job run record")
+		return err
+	}
+
+	err := j.run(ctx, jobRun.ID)
+
+	status := models.JobStatusCompleted
+	errorMessage := ""
+	if err != nil {
+		log.Error().Err(err).Msg("Recommendation ranking job failed")
+		status = models.JobStatusFailed
+		errorMessage = err.Error()
+	}
+	if completeErr := j.jobRepo.CompleteJobRun(ctx, jobRun.ID, status, errorMessage); completeErr != nil {
+		log.Error().Err(completeErr).Msg("Error completing job run")
+	}
+
+	return err
+}
+
+func (j *RankingJob) run(ctx context.Context, jobRunID uint64) error {
+	log := logger.LoggerFromContext(ctx)
+
+	users, err := j.userRepo.FindAllActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active users: %w", err)
+	}
+
+	for _, user := range users {
+		if err := j.RankForUser(ctx, jobRunID, user.ID); err != nil {
+			log.Error().Err(err).Uint64("userID", user.ID).Msg("Failed to rank recommendations")
+		}
+	}
+
+	log.Info().Int("users", len(users)).Msg("Recommendation ranking job completed")
+	return nil
+}
+
+// RankForUser replaces the user's ranked list with their current recommendations, merged, scored and diversified
+func (j *RankingJob) RankForUser(ctx context.Context, jobRunID uint64, userID uint64) error {
+	log := logger.LoggerFromContext(ctx).With().Uint64("userID", userID).Logger()
+
+	config, err := j.userConfigRepo.GetUserConfig(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user config: %w", err)
+	}
+
+	recommendations, err := j.recommendationRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	feedback, err := j.feedbackRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	signals := newFeedbackSignals(feedback)
+	candidates := withoutDislikedGenres(mergeCandidates(withoutRejected(recommendations, signals)), signals)
+
+	var ids []uint64
+	for _, candidate := range candidates {
+		if candidate.Best.MediaItemID > 0 {
+			ids = append(ids, candidate.Best.MediaItemID)
+		}
+	}
+	watchers, err := j.neighborRepo.GetWatcherCounts(ctx, ids)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to get item popularity, ranking without it")
+		watchers = nil
+	}
+
+	weights := rankingWeightsFor(config)
+	scoreRanking(candidates, watchers, weights, time.Now().Year())
+	penalizeRejected(candidates, signals.disliked)
+
+	byType := make(map[mediatypes.MediaType][]*rankedCandidate)
+	var mediaTypes []mediatypes.MediaType
+	for _, candidate := range candidates {
+		mediaType := candidate.Best.MediaType
+		if _, ok := byType[mediaType]; !ok {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+		byType[mediaType] = append(byType[mediaType], candidate)
+	}
+
+	var ranked []*models.Recommendation
+	for _, mediaType := range mediaTypes {
+		limit := collaborativeLimit(config, collaborativeTarget{recommendationType: mediaType})
+		for i, candidate := range diversify(byType[mediaType], weights.Lambda, limit) {
+			ranked = append(ranked, rankedRecommendation(jobRunID, candidate, i+1))
+		}
+	}
+
+	if err := j.recommendationRepo.ReplaceByRecommender(ctx, userID, RankedRecommender, ranked); err != nil {
+		return err
+	}
+
+	log.Info().
+		Int("candidates", len(candidates)).
+		Int("ranked", len(ranked)).
+		Str("strategy", config.RecommendationStrategy).
+		Msg("Stored ranked recommendations")
+	return nil
+}
+
+// rankedRecommendation copies the best recommendation of a candidate into the ranked list
+func rankedRecommendation(jobRunID uint64, candidate *rankedCandidate, rank int) *models.Recommendation {
+	now := time.Now()
+
+	recommendation := candidate.Best
+	recommendation.BaseModel = models.BaseModel{}
+	recommendation.RecommendedBy = RankedRecommender
+	recommendation.Rank = rank
+	recommendation.Confidence = float32(candidate.Score)
+	recommendation.JobRunID = jobRunID
+	recommendation.CreatedAt = now
+	recommendation.Genres = candidate.Genres
+	recommendation.MatchesActors = candidate.MatchesActors
+	recommendation.MatchesDirectors = candidate.Creators
+	recommendation.MatchesGenres = candidate.MatchesGenres
+	recommendation.SimilarItems = candidate.SimilarItems
+	recommendation.IsViewed = false
+	recommendation.UserRating = 0
+	recommendation.Active = true
+	if recommendation.ExpiresAt == nil {
+		expiresAt := now.Add(rankingExpiry)
+		recommendation.ExpiresAt = &expiresAt
+	}
+
+	rankingMetadata, _ := json.Marshal(map[string]any{
+		"source":     RankedRecommender,
+		"sources":    candidate.Sources,
+		"relevance":  candidate.Relevance,
+		"popularity": candidate.Popularity,
+		"newness":    candidate.Newness,
+		"score":      candidate.Score,
+	})
+	recommendation.Metadata = string(rankingMetadata)
+	return &recommendation
+}
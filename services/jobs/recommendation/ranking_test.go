@@ -0,0 +1,108 @@
+package recommendation
+
+import (
+	"testing"
+
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recommended(by string, itemID uint64, title string, confidence float32, genres ...string) models.Recommendation {
+	return models.Recommendation{RecommendedBy: by, MediaItemID: itemID, MediaType: mediatypes.MediaTypeMovie, Title: title, Confidence: confidence, Genres: genres}
+}
+
+func TestMergeCandidates(t *testing.T) {
+	tmdb := models.ExternalIDMap{"tmdb": "603"}
+	aiPick := recommended("AI", 0, "The Matrix", 8, "Action")
+	aiPick.ExternalIDs = &tmdb
+	dismissed := recommended(ContentRecommender, 3, "Dismissed", 1)
+	dismissed.Dismissed = true
+
+	candidates := mergeCandidates([]models.Recommendation{
+		aiPick,
+		recommended("AI", 0, "Heat", 4, "Crime"),
+		recommended(ContentRecommender, 1, "Matrix", 0.5, "Science Fiction"),
+		recommended(SimilarUsersRecommender, 1, "The Matrix", 0.2),
+		recommended(SimilarUsersRecommender, 2, "Alien", 0.4),
+		recommended(SimilarUsersRecommender, 3, "Dismissed", 0.4),
+		recommended(RankedRecommender, 4, "Old ranking", 1),
+		dismissed,
+	})
+
+	require.Len(t, candidates, 3, "the matrix is merged by title, the dismissed and ranked items are left out")
+	matrix := candidates[0]
+	assert.ElementsMatch(t, []string{"AI", ContentRecommender, SimilarUsersRecommender}, matrix.Sources)
+	assert.Equal(t, "AI", matrix.Best.RecommendedBy, "the strongest pick is kept")
+	assert.ElementsMatch(t, []string{"Action", "Science Fiction"}, matrix.Genres)
+	// 0.9 from the AI and the content lists, 0.45 from the similar users list
+	assert.InDelta(t, 1-0.1*0.1*0.55, matrix.Relevance, 1e-6)
+	assert.InDelta(t, 0.45, candidates[1].Relevance, 1e-6)
+	assert.Equal(t, "matrix", matrix.Franchise)
+}
+
+func TestRankingWeightsFor(t *testing.T) {
+	balanced := rankingWeightsFor(&models.UserConfig{RecommendationStrategy: strategyBalanced, PersonalHistoryWeight: 0.75, PopularityWeight: 0.5, NewContentWeight: 0.5})
+	assert.Equal(t, rankingWeights{Relevance: 0.75, Popularity: 0.5, Newness: 0.5, Lambda: defaultDiversityLambda}, balanced)
+
+	popular := rankingWeightsFor(&models.UserConfig{RecommendationStrategy: strategyPopular, PersonalHistoryWeight: 0.8, PopularityWeight: 0.5})
+	assert.InDelta(t, 1, popular.Popularity, 1e-9)
+
+	discovery := rankingWeightsFor(&models.UserConfig{RecommendationStrategy: strategySimilar, DiscoveryModeEnabled: true, DiscoveryModeRatio: 0.25})
+	assert.InDelta(t, 1, discovery.Relevance, 1e-9, "no weights ranks by relevance")
+	assert.InDelta(t, 0.75, discovery.Lambda, 1e-9)
+
+	allDiscovery := rankingWeightsFor(&models.UserConfig{DiscoveryModeEnabled: true, DiscoveryModeRatio: 1})
+	assert.InDelta(t, minDiversityLambda, allDiscovery.Lambda, 1e-9)
+}
+
+func TestScoreRanking(t *testing.T) {
+	candidates := []*rankedCandidate{
+		{Best: models.Recommendation{MediaItemID: 1, Year: 2026}, Relevance: 0.5},
+		{Best: models.Recommendation{MediaItemID: 2, Year: 2020}, Relevance: 1},
+		{Best: models.Recommendation{Title: "Not in the library"}, Relevance: 1},
+	}
+
+	scoreRanking(candidates, map[uint64]int{1: 9, 2: 0}, rankingWeights{Relevance: 1, Popularity: 1, Newness: 2}, 2026)
+
+	assert.InDelta(t, 1, candidates[0].Popularity, 1e-9)
+	assert.InDelta(t, 1, candidates[0].Newness, 1e-9)
+	assert.InDelta(t, 0.25, candidates[1].Newness, 1e-9)
+	assert.InDelta(t, (0.5+1+2)/4, candidates[0].Score, 1e-9)
+	assert.InDelta(t, (1+0+0.5)/4, candidates[1].Score, 1e-9)
+	assert.Zero(t, candidates[2].Popularity)
+	assert.Zero(t, candidates[2].Newness)
+}
+
+func TestDiversify(t *testing.T) {
+	candidate := func(title string, score float64, genres ...string) *rankedCandidate {
+		return &rankedCandidate{Best: models.Recommendation{Title: title}, Score: score, Genres: genres, Franchise: franchiseStem(title)}
+	}
+	candidates := []*rankedCandidate{
+		candidate("Star Wars: A New Hope", 0.9, "Science Fiction"),
+		candidate("Star Wars: The Empire Strikes Back", 0.88, "Science Fiction"),
+		candidate("Star Wars: Return of the Jedi", 0.86, "Science Fiction"),
+		candidate("Heat", 0.6, "Crime"),
+	}
+
+	byScore := diversify(candidates, 1, 3)
+	assert.Equal(t, "Star Wars: Return of the Jedi", byScore[2].Best.Title)
+
+	diverse := diversify(candidates, defaultDiversityLambda, 3)
+	require.Len(t, diverse, 3)
+	assert.Equal(t, "Star Wars: A New Hope", diverse[0].Best.Title)
+	assert.Equal(t, "Heat", diverse[1].Best.Title, "the sequels are pushed below another franchise")
+}
+
+func TestFranchiseStem(t *testing.T) {
+	assert.Equal(t, "star wars", franchiseStem("Star Wars: Episode IV - A New Hope"))
+	assert.Equal(t, "toy story", franchiseStem("Toy Story 3"))
+	assert.Equal(t, "godfather", franchiseStem("The Godfather Part II"))
+	assert.Equal(t, "9", franchiseStem("9"))
+
+	assert.True(t, sameFranchise("matrix", "matrix reloaded"))
+	assert.False(t, sameFranchise("it", "it follows"), "short titles don't make a franchise")
+	assert.False(t, sameFranchise("", ""))
+}
@@ -6,10 +6,12 @@ import (
 	"sort"
 	"time"
 
+	"suasor/clients/ai"
 	aitypes "suasor/clients/ai/types"
 	mediatypes "suasor/clients/media/types"
 	"suasor/types/models"
 	"suasor/utils/logger"
+	"suasor/utils/prompts"
 )
 
 // generateSeriesRecommendations creates TV series recommendations for a user
@@ -48,11 +50,11 @@ func (j *RecommendationJob) generateSeriesRecommendations(ctx context.Context, j
 	// Generate recommendation strategies based on user profile
 	var recommendations []*models.Recommendation
 
-	// Get AI client for recommendations if needed
-	aiClient, aiErr := j.getAIClient(ctx, user.ID)
+	// Get AI clients for recommendations if needed
+	aiClients, aiErr := j.router.Clients(ctx, user.ID, models.AIFeatureRecommendation)
 
 	// Decide if we should use AI recommendations
-	useAI := config.RecommendationSyncEnabled && aiErr == nil && aiClient != nil
+	useAI := config.RecommendationSyncEnabled && aiErr == nil && len(aiClients) > 0
 
 	if useAI {
 		// Generate AI recommendations if enabled and available
@@ -98,22 +100,19 @@ func (j *RecommendationJob) generateAISeriesRecommendations(
 
 	log := logger.LoggerFromContext(ctx)
 
-	// Get AI client for the user
-	aiClient, err := j.getAIClient(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get AI client: %v", err)
-	}
-
 	// Prepare AI recommendation request
 	request := &aitypes.RecommendationRequest{
 		MediaType:       "series",
 		Count:           8,
 		UserPreferences: map[string]interface{}{},
-		ExcludeIDs:      []string{},
+		ExcludeIDs:      append([]string{}, profile.Feedback.rejectedTitles(mediatypes.MediaTypeSeries)...),
 		AdditionalContext: fmt.Sprintf("User profile confidence: %.2f. Binge-watching score: %.2f. Content rotation frequency: %.2f.",
 			profile.ProfileConfidence, profile.BingeWatchingScore, profile.ContentRotationFreq),
 	}
 
+	// Ask with the active version of the series system prompt
+	prompt := j.withSystemPrompt(ctx, userID, prompts.RecommendationSeriesSystem, request)
+
 	// Use userPreferences for our filters
 	filters := request.UserPreferences
 
@@ -323,7 +322,12 @@ func (j *RecommendationJob) generateAISeriesRecommendations(
 		Msg("Requesting AI series recommendations")
 
 	// Request recommendations
-	aiRecommendations, err := aiClient.GetRecommendations(ctx, request)
+	var aiRecommendations *aitypes.RecommendationResponse
+	err := j.withAIClient(ctx, userID, func(ctx context.Context, aiClient ai.ClientAI) error {
+		var err error
+		aiRecommendations, err = aiClient.GetRecommendations(ctx, request)
+		return err
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get AI series recommendations")
 		return nil, err
@@ -337,27 +341,20 @@ func (j *RecommendationJob) generateAISeriesRecommendations(
 		return recommendations, nil
 	}
 
-	for i, rec := range aiRecommendations.Items {
-		// Extract details from the recommendation
-		title := rec.Title
-		if title == "" {
-			continue // Skip recommendations without a title
-		}
-
-		// We don't need to store year in a variable since we don't use it later
-
-		// Create a unique key for this series to check against watched/library maps
-		key := fmt.Sprintf("%s", title)
-
-		// Skip if we've seen this and are excluding watched content
-		if excludeWatched && watchedMap[key] {
-			continue
-		}
-
-		// Create a new media item for this recommendation
-		// TODO: Find existing media item or create a placeholder
-		// For now, we'll skip the media item creation
+	// Check the titles against TMDB and the user's library, replacing the ones that don't exist or were watched
+	grounder := &recommendationGrounder{
+		resolve: j.seriesResolver(ctx, userID),
+		library: newLibraryIndex(ctx, j.clientRepos, j.clientItemRepos.SeriesClientRepo(), userID),
+		excluded: func(item *groundedItem) bool {
+			if profile.Feedback.excludesItem(mediatypes.MediaTypeSeries, item.MediaItemID, item.Title, item.Year, item.ExternalIDs) {
+				return true
+			}
+			return excludeWatched && (watchedMap[item.Title] || profile.WatchedSeriesIDs[item.MediaItemID])
+		},
+	}
+	items := j.groundRecommendations(ctx, userID, request, aiRecommendations, grounder)
 
+	for i, rec := range items {
 		// Set confidence based on profile confidence
 		confidence := float32(0.7) + (profile.ProfileConfidence * 0.3) // Scale from 0.7-1.0 based on profile
 
@@ -386,22 +383,26 @@ func (j *RecommendationJob) generateAISeriesRecommendations(
 		// Add status if available (like "continuing" or "ended")
 		// rec.Status doesn't exist in RecommendationItem, we'll skip this for now
 
-		// Determine if it's in the user's library
-		isInLibrary := false
-		// Check if we have the external ID as a string
-		// TODO: Need to look at the series IDs we are using and how we want to
-		// handle this better
-		isInLibrary = profile.OwnedSeriesIDs[rec.ExternalID] || watchedMap[key]
-
 		// Create the recommendation based on the models.Recommendation struct
 		recommendation := &models.Recommendation{
 			UserID:           userID,
+			MediaItemID:      rec.MediaItemID,
 			MediaType:        "series",
+			Title:            rec.Title,
+			Year:             rec.Year,
+			Genres:           rec.Genres,
+			PosterURL:        rec.PosterURL,
+			BackdropURL:      rec.BackdropURL,
 			Source:           models.RecommendationSourceAI,
 			SourceClientType: "ai",
 			Reasoning:        reason,
 			Confidence:       confidence,
-			InLibrary:        isInLibrary,
+			InLibrary:        rec.InLibrary(),
+			PromptTemplate:   prompt.Name,
+			PromptVersion:    prompt.Version,
+		}
+		if len(rec.ExternalIDs) > 0 {
+			recommendation.ExternalIDs = &rec.ExternalIDs
 		}
 
 		recommendations = append(recommendations, recommendation)
@@ -438,6 +439,9 @@ func (j *RecommendationJob) processSeriesHistory(ctx context.Context, profile *U
 		// Track watched series ID
 		if history.MediaItemID > 0 {
 			profile.WatchedSeriesIDs[history.MediaItemID] = true
+			if history.IsDisliked {
+				profile.DislikedItemIDs[history.MediaItemID] = true
+			}
 		}
 
 		// Track watch time patterns
@@ -513,6 +517,25 @@ func (j *RecommendationJob) processSeriesHistory(ctx context.Context, profile *U
 				}
 			}
 
+			// Get credits if available
+			var credits []*models.Credit
+			if j.creditRepo != nil {
+				credits, _ = j.getCreditsForMediaItem(ctx, series.ID)
+			}
+
+			// Add the series' features to the taste profile the content-based recommender scores against
+			features := newContentItem(seriesData.Details, credits)
+			if len(features.Genres) == 0 {
+				features.Genres = seriesData.Genres
+			}
+			profile.SeriesTaste.add(features.vector(), tasteWeight(weight, history.IsDisliked, history.UserRating))
+			for _, creator := range ExtractNamesFromCredits(GetCreatorsFromCredits(credits)) {
+				profile.FavoriteShowrunners[creator] += weight
+			}
+			for _, tag := range features.Tags {
+				profile.SeriesTagPreferences[tag] += weight
+			}
+
 			// Add to recent series list
 			recentSeries = append(recentSeries, summary)
 
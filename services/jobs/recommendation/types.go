@@ -26,6 +26,9 @@ type UserPreferenceProfile struct {
 	MovieWatchTimes         map[string][]int64 // Hour -> counts
 	MovieWatchDays          map[string]int     // Day of week -> count
 	MovieTagPreferences     map[string]float32 // Tag -> weight
+	FavoriteWriters         map[string]float32 // Writer name -> weight
+	FavoriteStudios         map[string]float32 // Studio -> weight
+	MovieTaste              featureVector      // Weighted features of the watched movies, see contentItem
 	ExcludedMovieGenres     []string           // Genres the user dislikes
 	PreferredMovieGenres    []string           // Genres the user particularly likes
 	MovieReleaseYearRange   [2]int             // [min, max] year range specifically for movies
@@ -40,6 +43,7 @@ type UserPreferenceProfile struct {
 	SeriesWatchTimes         map[string][]int64 // Hour -> counts
 	SeriesWatchDays          map[string]int     // Day of week -> count
 	SeriesTagPreferences     map[string]float32 // Tag -> weight
+	SeriesTaste              featureVector      // Weighted features of the watched series, see contentItem
 	ExcludedSeriesGenres     []string           // Genres the user dislikes
 	PreferredSeriesGenres    []string           // Genres the user particularly likes
 	SeriesReleaseYearRange   [2]int             // [min, max] year range specifically for series
@@ -62,6 +66,9 @@ type UserPreferenceProfile struct {
 	MusicDurationRange   [2]int             // [min, max] duration range specifically for music
 	OwnedMusicIDs        map[string]bool    // Owned music IDs
 
+	DislikedItemIDs map[uint64]bool  // Disliked movie and series IDs, never recommended
+	Feedback        *feedbackSignals // What the user told us through their recommendations
+
 	ExcludePlayed      bool    // Exclude previously played content from recommendations
 	ProfileConfidence  float32 // User profile confidence
 	BingeWatchingScore float32 // Binge-watching score
@@ -352,6 +352,16 @@ func (j *MediaSyncJob) RunFullSync(ctx context.Context, userID uint64) error {
 			fmt.Sprintf("Completed %s sync", syncType))
 	}
 
+	// Resume positions are reconciled across all clients at once
+	if clientList.GetTotal() >= 2 {
+		if err := j.syncResumePositions(ctx, userID, jobRun.ID); err != nil {
+			log.Error().
+				Err(err).
+				Uint64("userID", userID).
+				Msg("Error reconciling resume positions")
+		}
+	}
+
 	// Complete the job
 	j.completeJobRun(ctx, jobRun.ID, models.JobStatusCompleted, "Full sync completed")
 	return nil
@@ -430,6 +440,38 @@ func (j *MediaSyncJob) isDue(job models.MediaSyncJob) bool {
 	return time.Since(*job.LastSyncTime) >= duration
 }
 
+// getSelectedLibraries returns the libraries a sync job is limited to, or nil for all of them.
+// Manual and full syncs run a temporary job, so the stored job for the client is used instead.
+func (j *MediaSyncJob) getSelectedLibraries(ctx context.Context, syncJob models.MediaSyncJob) []string {
+	log := logger.LoggerFromContext(ctx)
+
+	if syncJob.ID == 0 {
+		storedJob, err := j.jobRepo.GetMediaSyncJobByClient(ctx, syncJob.ClientID, syncJob.SyncType)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Uint64("clientID", syncJob.ClientID).
+				Str("syncType", string(syncJob.SyncType)).
+				Msg("Failed to load stored sync job, syncing all libraries")
+			return nil
+		}
+		if storedJob == nil {
+			return nil
+		}
+		syncJob = *storedJob
+	}
+
+	filters, err := syncJob.GetFilters()
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Uint64("syncJobID", syncJob.ID).
+			Msg("Invalid sync job filters, syncing all libraries")
+		return nil
+	}
+	return filters.LibraryIDs
+}
+
 // runSyncJob executes a media sync job
 func (j *MediaSyncJob) runSyncJob(ctx context.Context, syncJob models.MediaSyncJob) error {
 	// Create a job run record
@@ -463,17 +505,20 @@ func (j *MediaSyncJob) runSyncJob(ctx context.Context, syncJob models.MediaSyncJ
 
 	// Process different media types
 	var syncError error
+	libraryIDs := j.getSelectedLibraries(ctx, syncJob)
 
 	// Normalize media type to handle both singular and plural forms
 	switch syncJob.SyncType {
 	case models.SyncTypeMovies:
-		syncError = j.syncMovies(ctx, clientMedia, jobRun.ID, syncJob.ClientID)
+		syncError = j.syncMovies(ctx, clientMedia, jobRun.ID, syncJob.ClientID, libraryIDs)
 	case models.SyncTypeSeries:
-		syncError = j.syncSeries(ctx, clientMedia, jobRun.ID, syncJob.ClientID)
+		syncError = j.syncSeries(ctx, clientMedia, jobRun.ID, syncJob.ClientID, libraryIDs)
 	case models.SyncTypeMusic:
-		syncError = j.syncMusic(ctx, clientMedia, jobRun.ID, syncJob.ClientID)
+		syncError = j.syncMusic(ctx, clientMedia, jobRun.ID, syncJob.ClientID, libraryIDs)
 	case models.SyncTypeHistory:
 		syncError = j.syncHistory(ctx, clientMedia, ownerID, jobRun.ID, syncJob.ClientID)
+	case models.SyncTypeResume:
+		syncError = j.syncResumePositions(ctx, ownerID, jobRun.ID)
 	case models.SyncTypeFavorites:
 		// syncError = j.syncFavorites(ctx, clientMedia, jobRun.ID, syncJob.ClientID)
 	case models.SyncTypeCollections:
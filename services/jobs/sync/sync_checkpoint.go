@@ -0,0 +1,275 @@
+package sync
+
+import (
+	"context"
+	"sort"
+	"strings"
+	mediatypes "suasor/clients/media/types"
+	"suasor/repository"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"time"
+)
+
+const (
+	// fullSyncInterval is how often the whole library is pulled even when a checkpoint exists
+	fullSyncInterval = 7 * 24 * time.Hour
+	// reconcileInterval is how often a delta sync also looks for items deleted on the client
+	reconcileInterval = 24 * time.Hour
+	// checkpointOverlap re-requests a short window before the high-water mark to cover clock skew
+	checkpointOverlap = 5 * time.Minute
+)
+
+// syncWindow describes which part of a client's library a single sync run covers
+type syncWindow struct {
+	clientID   uint64
+	syncType   models.SyncType
+	checkpoint *models.SyncCheckpoint
+	// since is nil for a full sync
+	since     *time.Time
+	startedAt time.Time
+	// libraryIDs limits the sync to the selected libraries, empty means all of them
+	libraryIDs []string
+}
+
+// loadSyncWindow reads the client's checkpoint and decides between a full and a delta sync
+func (j *MediaSyncJob) loadSyncWindow(ctx context.Context, clientID uint64, syncType models.SyncType, libraryIDs []string) *syncWindow {
+	log := logger.LoggerFromContext(ctx)
+
+	window := &syncWindow{
+		clientID:   clientID,
+		syncType:   syncType,
+		startedAt:  time.Now(),
+		libraryIDs: libraryIDs,
+	}
+
+	checkpoint, err := j.jobRepo.GetSyncCheckpoint(ctx, clientID, syncType)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Uint64("clientID", clientID).
+			Str("syncType", string(syncType)).
+			Msg("Failed to load sync checkpoint, falling back to a full sync")
+	}
+	if checkpoint == nil {
+		checkpoint = &models.SyncCheckpoint{
+			ClientID: clientID,
+			SyncType: syncType,
+		}
+	}
+	window.checkpoint = checkpoint
+
+	// Libraries that were just selected have never been synced
+	if !checkpoint.NeedsFullSync(fullSyncInterval) && checkpoint.Libraries == libraryKey(libraryIDs) {
+		since := checkpoint.HighWaterMark.Add(-checkpointOverlap)
+		window.since = &since
+	}
+
+	log.Info().
+		Uint64("clientID", clientID).
+		Str("syncType", string(syncType)).
+		Bool("fullSync", window.isFull()).
+		Msg("Determined sync window")
+
+	return window
+}
+
+// isFull returns true when the whole library is being synced
+func (w *syncWindow) isFull() bool {
+	return w.since == nil
+}
+
+// needsReconcile returns true when a delta sync should also look for deleted items.
+// Full syncs reconcile with the listing they already have.
+func (w *syncWindow) needsReconcile() bool {
+	return !w.isFull() && w.checkpoint.NeedsReconcile(reconcileInterval)
+}
+
+// queryOptions returns the options to list the library with, limited to changes for a delta sync.
+// Clients that can't filter on updates, like Subsonic, fall back to what was added.
+func (w *syncWindow) queryOptions() *mediatypes.QueryOptions {
+	return &mediatypes.QueryOptions{UpdatedAfter: w.since, DateAddedAfter: w.since, LibraryIDs: w.libraryIDs}
+}
+
+// listAllOptions returns the options to list every library for the deletion check.
+// Items in libraries that are no longer selected stay linked, the selection only narrows future syncs.
+func (w *syncWindow) listAllOptions() *mediatypes.QueryOptions {
+	return &mediatypes.QueryOptions{}
+}
+
+// libraryKey normalizes a library selection so it can be compared between runs
+func libraryKey(libraryIDs []string) string {
+	sorted := append([]string(nil), libraryIDs...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// saveSyncWindow moves the checkpoint forward after a successful run
+func (j *MediaSyncJob) saveSyncWindow(ctx context.Context, w *syncWindow, reconciled bool, itemCount int) {
+	log := logger.LoggerFromContext(ctx)
+
+	checkpoint := w.checkpoint
+	// The start time is used so that anything changed while the sync was running is picked up next time
+	checkpoint.HighWaterMark = &w.startedAt
+	checkpoint.Libraries = libraryKey(w.libraryIDs)
+	now := time.Now()
+	checkpoint.LastSyncTime = &now
+	if w.isFull() {
+		checkpoint.LastFullSyncTime = &now
+	}
+	if reconciled {
+		checkpoint.LastReconcileTime = &now
+		checkpoint.ItemCount = itemCount
+	}
+
+	if err := j.jobRepo.SaveSyncCheckpoint(ctx, checkpoint); err != nil {
+		log.Error().
+			Err(err).
+			Uint64("clientID", w.clientID).
+			Str("syncType", string(w.syncType)).
+			Msg("Failed to save sync checkpoint")
+	}
+}
+
+// filterChangedItems drops items that have not changed since the window started.
+// Not every client can filter by date itself, so the delta is also enforced here.
+// Items without timestamps are always kept.
+func filterChangedItems[T mediatypes.MediaData](w *syncWindow, items []*models.MediaItem[T]) []*models.MediaItem[T] {
+	if w.isFull() {
+		return items
+	}
+
+	changed := make([]*models.MediaItem[T], 0, len(items))
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		details := item.GetData().GetDetails()
+		if details == nil || (details.AddedAt.IsZero() && details.UpdatedAt.IsZero()) {
+			changed = append(changed, item)
+			continue
+		}
+		if details.AddedAt.After(*w.since) || details.UpdatedAt.After(*w.since) {
+			changed = append(changed, item)
+		}
+	}
+	return changed
+}
+
+// reconcileSyncWindow checks for deleted items when the window calls for it.
+// listed is what the run already fetched, listAll pulls the whole library for a delta run
+// or when only some libraries were synced.
+// It returns whether the check ran and how many items the client listed.
+func reconcileSyncWindow[T mediatypes.MediaData](
+	ctx context.Context,
+	w *syncWindow,
+	repo repository.ClientMediaItemRepository[T],
+	listed clientListing[T],
+	listAll func() (clientListing[T], error),
+) (bool, int) {
+	log := logger.LoggerFromContext(ctx)
+
+	if !w.isFull() && !w.needsReconcile() {
+		return false, 0
+	}
+
+	all := listed
+	if !w.isFull() || len(w.libraryIDs) > 0 {
+		var err error
+		all, err = listAll()
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Uint64("clientID", w.clientID).
+				Str("syncType", string(w.syncType)).
+				Msg("Failed to list library for deletion check")
+			return false, 0
+		}
+	}
+
+	// Anything missing from a partial listing would look deleted
+	if !all.complete {
+		log.Warn().
+			Uint64("clientID", w.clientID).
+			Str("syncType", string(w.syncType)).
+			Int("listedItems", len(all.items)).
+			Msg("Client listing could not be paged to the end, skipping deletion check")
+		return false, 0
+	}
+
+	reconcileDeletedItems(ctx, repo, w.clientID, all.items)
+	return true, len(all.items)
+}
+
+// reconcileDeletedItems unlinks the client from every item it no longer lists.
+// It returns the number of items that were unlinked.
+func reconcileDeletedItems[T mediatypes.MediaData](
+	ctx context.Context,
+	repo repository.ClientMediaItemRepository[T],
+	clientID uint64,
+	remote []*models.MediaItem[T],
+) int {
+	log := logger.LoggerFromContext(ctx)
+
+	// An empty listing is far more likely to be an outage than an empty library
+	if len(remote) == 0 {
+		log.Warn().
+			Uint64("clientID", clientID).
+			Msg("Client returned no items, skipping deletion check")
+		return 0
+	}
+
+	remoteIDs := make(map[string]struct{}, len(remote))
+	for _, item := range remote {
+		if item == nil {
+			continue
+		}
+		if clientItemID, ok := item.GetClientItemID(clientID); ok && clientItemID != "" {
+			remoteIDs[clientItemID] = struct{}{}
+		}
+	}
+
+	local, err := repo.GetByClientID(ctx, clientID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Uint64("clientID", clientID).
+			Msg("Failed to load local items for deletion check")
+		return 0
+	}
+
+	mediaType := mediatypes.GetMediaType[T]()
+	removed := 0
+	for _, item := range local {
+		// All media types share one table, only look at the type being synced
+		if item == nil || item.Type != mediaType {
+			continue
+		}
+		clientItemID, ok := item.GetClientItemID(clientID)
+		if !ok || clientItemID == "" {
+			continue
+		}
+		if _, exists := remoteIDs[clientItemID]; exists {
+			continue
+		}
+
+		if err := repo.DeleteClientItem(ctx, clientID, clientItemID); err != nil {
+			log.Warn().
+				Err(err).
+				Uint64("clientID", clientID).
+				Str("clientItemID", clientItemID).
+				Msg("Failed to unlink deleted item")
+			continue
+		}
+		removed++
+	}
+
+	log.Info().
+		Uint64("clientID", clientID).
+		Str("mediaType", string(mediaType)).
+		Int("remoteItems", len(remoteIDs)).
+		Int("removed", removed).
+		Msg("Reconciled deleted items")
+
+	return removed
+}
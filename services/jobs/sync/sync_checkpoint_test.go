@@ -0,0 +1,136 @@
+package sync
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	mediatypes "suasor/clients/media/types"
+	clienttypes "suasor/clients/types"
+	"suasor/types/models"
+)
+
+func TestFilterChangedItems(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+	movie := func(title string, addedAt, updatedAt time.Time) *models.MediaItem[*mediatypes.Movie] {
+		return &models.MediaItem[*mediatypes.Movie]{
+			Data: &mediatypes.Movie{
+				Details: &mediatypes.MediaDetails{Title: title, AddedAt: addedAt, UpdatedAt: updatedAt},
+			},
+		}
+	}
+
+	items := []*models.MediaItem[*mediatypes.Movie]{
+		movie("old", since.Add(-48*time.Hour), since.Add(-24*time.Hour)),
+		movie("added", since.Add(time.Minute), time.Time{}),
+		movie("updated", since.Add(-48*time.Hour), since.Add(time.Minute)),
+		movie("unknown", time.Time{}, time.Time{}),
+	}
+
+	full := filterChangedItems(&syncWindow{}, items)
+	assert.Len(t, full, 4, "full syncs keep everything")
+
+	delta := filterChangedItems(&syncWindow{since: &since}, items)
+	titles := make([]string, 0, len(delta))
+	for _, item := range delta {
+		titles = append(titles, item.GetData().GetTitle())
+	}
+	assert.Equal(t, []string{"added", "updated", "unknown"}, titles)
+}
+
+func TestSyncCheckpointIntervals(t *testing.T) {
+	checkpoint := &models.SyncCheckpoint{}
+	assert.True(t, checkpoint.NeedsFullSync(fullSyncInterval), "no checkpoint means a full sync")
+
+	now := time.Now()
+	recent := now.Add(-time.Hour)
+	checkpoint.HighWaterMark = &now
+	checkpoint.LastFullSyncTime = &recent
+	checkpoint.LastReconcileTime = &recent
+	assert.False(t, checkpoint.NeedsFullSync(fullSyncInterval))
+	assert.False(t, checkpoint.NeedsReconcile(reconcileInterval))
+
+	stale := now.Add(-8 * 24 * time.Hour)
+	checkpoint.LastFullSyncTime = &stale
+	checkpoint.LastReconcileTime = &stale
+	assert.True(t, checkpoint.NeedsFullSync(fullSyncInterval))
+	assert.True(t, checkpoint.NeedsReconcile(reconcileInterval))
+}
+
+func TestReconcileSyncWindowListsAllLibraries(t *testing.T) {
+	ctx := context.Background()
+	var listAllCalls int
+	listAll := func() (clientListing[*mediatypes.Movie], error) {
+		listAllCalls++
+		return clientListing[*mediatypes.Movie]{complete: true}, nil
+	}
+	listed := clientListing[*mediatypes.Movie]{complete: true}
+
+	full := &syncWindow{checkpoint: &models.SyncCheckpoint{}}
+	reconciled, _ := reconcileSyncWindow(ctx, full, nil, listed, listAll)
+	assert.True(t, reconciled)
+	assert.Zero(t, listAllCalls, "a full sync of every library reconciles with what it listed")
+
+	selected := &syncWindow{checkpoint: &models.SyncCheckpoint{}, libraryIDs: []string{"1"}}
+	reconciled, _ = reconcileSyncWindow(ctx, selected, nil, listed, listAll)
+	assert.True(t, reconciled)
+	assert.Equal(t, 1, listAllCalls, "items in unselected libraries are only unlinked when the client no longer lists them")
+	assert.Empty(t, selected.listAllOptions().LibraryIDs)
+}
+
+func TestReconcileSyncWindowSkipsIncompleteListing(t *testing.T) {
+	ctx := context.Background()
+	full := &syncWindow{checkpoint: &models.SyncCheckpoint{}}
+	partial := clientListing[*mediatypes.Movie]{items: listingMovies(1, 0, 10)}
+
+	// The repository is nil, reconciling would panic
+	reconciled, itemCount := reconcileSyncWindow(ctx, full, nil, partial,
+		func() (clientListing[*mediatypes.Movie], error) { return partial, nil })
+	assert.False(t, reconciled)
+	assert.Zero(t, itemCount)
+}
+
+func TestListAllPages(t *testing.T) {
+	ctx := context.Background()
+	const clientID = 1
+	library := listingMovies(clientID, 0, 2*listPageSize+7)
+
+	// Pages like a real client would, falling back to a small limit when none is given
+	paged := func(_ context.Context, options *mediatypes.QueryOptions) ([]*models.MediaItem[*mediatypes.Movie], error) {
+		limit := options.Limit
+		if limit == 0 {
+			limit = 100
+		}
+		start := min(options.Offset, len(library))
+		end := min(start+limit, len(library))
+		return library[start:end], nil
+	}
+
+	listing, err := listAllPages(ctx, clientID, &mediatypes.QueryOptions{}, paged)
+	assert.NoError(t, err)
+	assert.True(t, listing.complete)
+	assert.Len(t, listing.items, len(library))
+
+	// A client that ignores the offset keeps returning the first page
+	firstPageOnly := func(_ context.Context, options *mediatypes.QueryOptions) ([]*models.MediaItem[*mediatypes.Movie], error) {
+		return library[:options.Limit], nil
+	}
+
+	listing, err = listAllPages(ctx, clientID, &mediatypes.QueryOptions{}, firstPageOnly)
+	assert.NoError(t, err)
+	assert.False(t, listing.complete)
+	assert.Len(t, listing.items, listPageSize)
+}
+
+func listingMovies(clientID uint64, from, count int) []*models.MediaItem[*mediatypes.Movie] {
+	movies := make([]*models.MediaItem[*mediatypes.Movie], 0, count)
+	for i := from; i < from+count; i++ {
+		movie := &models.MediaItem[*mediatypes.Movie]{Data: &mediatypes.Movie{Details: &mediatypes.MediaDetails{}}}
+		movie.SetClientInfo(clientID, clienttypes.ClientTypeJellyfin, strconv.Itoa(i))
+		movies = append(movies, movie)
+	}
+	return movies
+}
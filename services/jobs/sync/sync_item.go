@@ -0,0 +1,80 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"suasor/clients"
+	"suasor/clients/media/providers"
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+// SyncItem fetches a single item from a client and saves it to the database.
+// It is used by webhooks so new additions show up without waiting for a full library sync.
+func (j *MediaSyncJob) SyncItem(ctx context.Context, clientID uint64, mediaType mediatypes.MediaType, clientItemID string) error {
+	log := logger.LoggerFromContext(ctx)
+
+	clientMedia, _, err := j.getClientMedia(ctx, clientID)
+	if err != nil {
+		return fmt.Errorf("failed to get client media: %w", err)
+	}
+	clientType := clientMedia.(clients.Client).GetClientType()
+
+	log.Info().
+		Uint64("clientID", clientID).
+		Str("mediaType", string(mediaType)).
+		Str("clientItemID", clientItemID).
+		Msg("Syncing single item from client")
+
+	switch mediaType {
+	case mediatypes.MediaTypeMovie:
+		movieProvider, ok := clientMedia.(providers.MovieProvider)
+		if !ok || !movieProvider.SupportsMovies() {
+			return fmt.Errorf("client doesn't support movies")
+		}
+		movie, err := movieProvider.GetMovieByID(ctx, clientItemID)
+		if err != nil {
+			return fmt.Errorf("failed to get movie: %w", err)
+		}
+		return j.processMovieBatch(ctx, []*models.MediaItem[*mediatypes.Movie]{movie}, clientID, clientType.AsClientMediaType())
+
+	case mediatypes.MediaTypeSeries:
+		seriesProvider, ok := clientMedia.(providers.SeriesProvider)
+		if !ok || !seriesProvider.SupportsSeries() {
+			return fmt.Errorf("client doesn't support series")
+		}
+		series, err := seriesProvider.GetSeriesByID(ctx, clientItemID)
+		if err != nil {
+			return fmt.Errorf("failed to get series: %w", err)
+		}
+		return j.processSeriesBatch(ctx, []*models.MediaItem[*mediatypes.Series]{series}, clientID, clientType)
+
+	case mediatypes.MediaTypeEpisode:
+		seriesProvider, ok := clientMedia.(providers.SeriesProvider)
+		if !ok || !seriesProvider.SupportsSeries() {
+			return fmt.Errorf("client doesn't support series")
+		}
+		episode, err := seriesProvider.GetEpisodeByID(ctx, clientItemID)
+		if err != nil {
+			return fmt.Errorf("failed to get episode: %w", err)
+		}
+		_, err = j.processEpisodeBatch(ctx, []*models.MediaItem[*mediatypes.Episode]{episode}, clientID, clientType)
+		return err
+
+	case mediatypes.MediaTypeTrack:
+		musicProvider, ok := clientMedia.(providers.MusicProvider)
+		if !ok || !musicProvider.SupportsMusic() {
+			return fmt.Errorf("client doesn't support music")
+		}
+		track, err := musicProvider.GetMusicTrackByID(ctx, clientItemID)
+		if err != nil {
+			return fmt.Errorf("failed to get track: %w", err)
+		}
+		_, err = j.processIndependentTrackBatch(ctx, []*models.MediaItem[*mediatypes.Track]{track}, clientID, clientType)
+		return err
+
+	default:
+		return fmt.Errorf("unsupported media type for single item sync: %s", mediaType)
+	}
+}
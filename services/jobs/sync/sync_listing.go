@@ -0,0 +1,83 @@
+package sync
+
+import (
+	"context"
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+)
+
+const (
+	// listPageSize is how many items are requested at a time when listing a library.
+	// Clients fall back to a small default limit of their own when none is given.
+	listPageSize = 500
+	// maxListPages stops a listing that never ends, the listing is then treated as incomplete
+	maxListPages = 1000
+)
+
+// clientListing is what a client returned for a query.
+// complete is false when the listing could not be paged to the end, it can't be used to find deleted items.
+type clientListing[T mediatypes.MediaData] struct {
+	items    []*models.MediaItem[T]
+	complete bool
+}
+
+// listAllPages pages through every selected library until the client returns a short page
+func listAllPages[T mediatypes.MediaData](
+	ctx context.Context,
+	clientID uint64,
+	options *mediatypes.QueryOptions,
+	fetch func(context.Context, *mediatypes.QueryOptions) ([]*models.MediaItem[T], error),
+) (clientListing[T], error) {
+	listing := clientListing[T]{complete: true}
+
+	for _, libraryOptions := range options.ByLibrary() {
+		page := mediatypes.QueryOptions{}
+		if libraryOptions != nil {
+			page = *libraryOptions
+		}
+		page.Limit = listPageSize
+		page.Offset = 0
+
+		var firstID string
+		for pages := 0; ; pages++ {
+			if pages == maxListPages {
+				listing.complete = false
+				break
+			}
+
+			batch, err := fetch(ctx, &page)
+			if err != nil {
+				return clientListing[T]{}, err
+			}
+			if len(batch) == 0 {
+				break
+			}
+
+			// A client that ignores the offset returns the first page again
+			batchFirstID := listingItemID(batch[0], clientID)
+			if pages > 0 && batchFirstID != "" && batchFirstID == firstID {
+				listing.complete = false
+				break
+			}
+			if pages == 0 {
+				firstID = batchFirstID
+			}
+
+			listing.items = append(listing.items, batch...)
+			if len(batch) < listPageSize {
+				break
+			}
+			page.Offset += len(batch)
+		}
+	}
+
+	return listing, nil
+}
+
+func listingItemID[T mediatypes.MediaData](item *models.MediaItem[T], clientID uint64) string {
+	if item == nil {
+		return ""
+	}
+	itemID, _ := item.GetClientItemID(clientID)
+	return itemID
+}
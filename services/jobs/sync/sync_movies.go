@@ -14,7 +14,7 @@ import (
 )
 
 // syncMovies syncs movies from the client to the database
-func (j *MediaSyncJob) syncMovies(ctx context.Context, clientMedia media.ClientMedia, jobRunID uint64, clientID uint64) error {
+func (j *MediaSyncJob) syncMovies(ctx context.Context, clientMedia media.ClientMedia, jobRunID uint64, clientID uint64, libraryIDs []string) error {
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 10, "Fetching movies from client")
 
@@ -26,10 +26,12 @@ func (j *MediaSyncJob) syncMovies(ctx context.Context, clientMedia media.ClientM
 
 	// Get all movies from the client
 	clientType := clientMedia.(clients.Client).GetClientType().AsClientMediaType()
-	movies, err := movieProvider.GetMovies(ctx, &mediatypes.QueryOptions{})
+	window := j.loadSyncWindow(ctx, clientID, models.SyncTypeMovies, libraryIDs)
+	listed, err := listAllPages(ctx, clientID, window.queryOptions(), movieProvider.GetMovies)
 	if err != nil {
 		return fmt.Errorf("failed to get movies: %w", err)
 	}
+	movies := filterChangedItems(window, listed.items)
 
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 50, fmt.Sprintf("Processing %d movies", len(movies)))
@@ -56,6 +58,14 @@ func (j *MediaSyncJob) syncMovies(ctx context.Context, clientMedia media.ClientM
 		j.jobRepo.UpdateJobProgress(ctx, jobRunID, progress, fmt.Sprintf("Processed %d/%d movies", processedMovies, totalMovies))
 	}
 
+	// Look for movies that were removed from the client
+	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 95, "Checking for deleted movies")
+	reconciled, itemCount := reconcileSyncWindow(ctx, window, j.clientItemRepos.MovieClientRepo(), listed,
+		func() (clientListing[*mediatypes.Movie], error) {
+			return listAllPages(ctx, clientID, window.listAllOptions(), movieProvider.GetMovies)
+		})
+	j.saveSyncWindow(ctx, window, reconciled, itemCount)
+
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 100, fmt.Sprintf("Synced %d movies", totalMovies))
 
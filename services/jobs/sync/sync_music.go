@@ -7,12 +7,11 @@ import (
 	"suasor/clients/media"
 	"suasor/clients/media/providers"
 	mediatypes "suasor/clients/media/types"
-	"suasor/utils/logger"
+	"suasor/types/models"
 )
 
 // syncMusic syncs music tracks from the client to the database
-func (j *MediaSyncJob) syncMusic(ctx context.Context, clientMedia media.ClientMedia, jobRunID uint64, clientID uint64) error {
-	log := logger.LoggerFromContext(ctx)
+func (j *MediaSyncJob) syncMusic(ctx context.Context, clientMedia media.ClientMedia, jobRunID uint64, clientID uint64, libraryIDs []string) error {
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 10, "Fetching music from client")
 
@@ -24,29 +23,12 @@ func (j *MediaSyncJob) syncMusic(ctx context.Context, clientMedia media.ClientMe
 
 	// Get all tracks from the client in batches
 	clientType := clientMedia.(clients.Client).GetClientType()
-	// Fetch tracks in pages to avoid overwhelming the provider
-	pageSize := 1000
-	offset := 0
-	tracks, err := musicProvider.GetMusicTracks(ctx, &mediatypes.QueryOptions{Limit: pageSize, Offset: offset})
+	window := j.loadSyncWindow(ctx, clientID, models.SyncTypeMusic, libraryIDs)
+	listed, err := listAllPages(ctx, clientID, window.queryOptions(), musicProvider.GetMusicTracks)
 	if err != nil {
 		return fmt.Errorf("failed to get tracks: %w", err)
 	}
-	offset += len(tracks)
-	for {
-		batch, err := musicProvider.GetMusicTracks(ctx, &mediatypes.QueryOptions{Limit: pageSize, Offset: offset})
-		if err != nil {
-			return fmt.Errorf("failed to get tracks: %w", err)
-		}
-		if len(batch) == 0 {
-			break
-		}
-		tracks = append(tracks, batch...)
-		offset += len(batch)
-		log.Debug().
-			Int("offset", offset).
-			Int("totalTracks", len(tracks)).
-			Msg("Fetched more tracks from the provider")
-	}
+	tracks := filterChangedItems(window, listed.items)
 
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 50, fmt.Sprintf("Processing %d tracks", len(tracks)))
@@ -73,8 +55,15 @@ func (j *MediaSyncJob) syncMusic(ctx context.Context, clientMedia media.ClientMe
 		j.jobRepo.UpdateJobProgress(ctx, jobRunID, progress, fmt.Sprintf("Processed %d/%d tracks", processedTracks, totalTracks))
 	}
 
-	j.syncAlbums(ctx, clientMedia, jobRunID, clientID)
-	j.syncArtists(ctx, clientMedia, jobRunID, clientID)
+	albumsReconciled, _ := j.syncAlbums(ctx, clientMedia, window, jobRunID, clientID)
+	artistsReconciled, _ := j.syncArtists(ctx, clientMedia, window, jobRunID, clientID)
+
+	// Look for tracks that were removed from the client
+	tracksReconciled, itemCount := reconcileSyncWindow(ctx, window, j.clientItemRepos.TrackClientRepo(), listed,
+		func() (clientListing[*mediatypes.Track], error) {
+			return listAllPages(ctx, clientID, window.listAllOptions(), musicProvider.GetMusicTracks)
+		})
+	j.saveSyncWindow(ctx, window, tracksReconciled && albumsReconciled && artistsReconciled, itemCount)
 
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 100, fmt.Sprintf("Synced %d tracks", totalTracks))
@@ -82,23 +71,25 @@ func (j *MediaSyncJob) syncMusic(ctx context.Context, clientMedia media.ClientMe
 	return nil
 }
 
-// syncAlbums syncs music albums from the client to the database
-func (j *MediaSyncJob) syncAlbums(ctx context.Context, clientMedia media.ClientMedia, jobRunID uint64, clientID uint64) error {
+// syncAlbums syncs music albums from the client to the database.
+// It returns whether deleted albums were checked for.
+func (j *MediaSyncJob) syncAlbums(ctx context.Context, clientMedia media.ClientMedia, window *syncWindow, jobRunID uint64, clientID uint64) (bool, error) {
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 10, "Fetching albums from client")
 
 	// Check if client supports albums
 	musicProvider, ok := clientMedia.(providers.MusicProvider)
 	if !ok {
-		return fmt.Errorf("client doesn't support albums")
+		return false, fmt.Errorf("client doesn't support albums")
 	}
 
 	// Get all albums from the client
 	clientType := clientMedia.(clients.Client).GetClientType()
-	albums, err := musicProvider.GetMusicAlbums(ctx, &mediatypes.QueryOptions{})
+	listed, err := listAllPages(ctx, clientID, window.queryOptions(), musicProvider.GetMusicAlbums)
 	if err != nil {
-		return fmt.Errorf("failed to get albums: %w", err)
+		return false, fmt.Errorf("failed to get albums: %w", err)
 	}
+	albums := filterChangedItems(window, listed.items)
 
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 50, fmt.Sprintf("Processing %d albums", len(albums)))
@@ -117,7 +108,7 @@ func (j *MediaSyncJob) syncAlbums(ctx context.Context, clientMedia media.ClientM
 		albumBatch := albums[i:end]
 		albumsWithTracks, err := j.processIndependentAlbumBatch(ctx, albumBatch, clientID, clientType)
 		if err != nil {
-			return fmt.Errorf("failed to process album batch: %w", err)
+			return false, fmt.Errorf("failed to process album batch: %w", err)
 		}
 
 		processedAlbums += len(albumsWithTracks)
@@ -125,29 +116,37 @@ func (j *MediaSyncJob) syncAlbums(ctx context.Context, clientMedia media.ClientM
 		j.jobRepo.UpdateJobProgress(ctx, jobRunID, progress, fmt.Sprintf("Processed %d/%d albums", processedAlbums, totalAlbums))
 	}
 
+	// Look for albums that were removed from the client
+	reconciled, _ := reconcileSyncWindow(ctx, window, j.clientItemRepos.AlbumClientRepo(), listed,
+		func() (clientListing[*mediatypes.Album], error) {
+			return listAllPages(ctx, clientID, window.listAllOptions(), musicProvider.GetMusicAlbums)
+		})
+
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 100, fmt.Sprintf("Synced %d albums", totalAlbums))
 
-	return nil
+	return reconciled, nil
 }
 
-// syncArtists syncs music artists from the client to the database
-func (j *MediaSyncJob) syncArtists(ctx context.Context, clientMedia media.ClientMedia, jobRunID uint64, clientID uint64) error {
+// syncArtists syncs music artists from the client to the database.
+// It returns whether deleted artists were checked for.
+func (j *MediaSyncJob) syncArtists(ctx context.Context, clientMedia media.ClientMedia, window *syncWindow, jobRunID uint64, clientID uint64) (bool, error) {
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 10, "Fetching artists from client")
 
 	// Check if client supports artists
 	musicProvider, ok := clientMedia.(providers.MusicProvider)
 	if !ok {
-		return fmt.Errorf("client doesn't support artists")
+		return false, fmt.Errorf("client doesn't support artists")
 	}
 
 	// Get all artists from the client
 	clientType := clientMedia.(clients.Client).GetClientType()
-	artists, err := musicProvider.GetMusicArtists(ctx, &mediatypes.QueryOptions{})
+	listed, err := listAllPages(ctx, clientID, window.queryOptions(), musicProvider.GetMusicArtists)
 	if err != nil {
-		return fmt.Errorf("failed to get artists: %w", err)
+		return false, fmt.Errorf("failed to get artists: %w", err)
 	}
+	artists := filterChangedItems(window, listed.items)
 
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 50, fmt.Sprintf("Processing %d artists", len(artists)))
@@ -166,7 +165,7 @@ func (j *MediaSyncJob) syncArtists(ctx context.Context, clientMedia media.Client
 		artistBatch := artists[i:end]
 		err := j.processArtistBatch(ctx, artistBatch, clientID, clientType)
 		if err != nil {
-			return fmt.Errorf("failed to process artist batch: %w", err)
+			return false, fmt.Errorf("failed to process artist batch: %w", err)
 		}
 
 		processedArtists += len(artistBatch)
@@ -174,8 +173,14 @@ func (j *MediaSyncJob) syncArtists(ctx context.Context, clientMedia media.Client
 		j.jobRepo.UpdateJobProgress(ctx, jobRunID, progress, fmt.Sprintf("Processed %d/%d artists", processedArtists, totalArtists))
 	}
 
+	// Look for artists that were removed from the client
+	reconciled, _ := reconcileSyncWindow(ctx, window, j.clientItemRepos.ArtistClientRepo(), listed,
+		func() (clientListing[*mediatypes.Artist], error) {
+			return listAllPages(ctx, clientID, window.listAllOptions(), musicProvider.GetMusicArtists)
+		})
+
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 100, fmt.Sprintf("Synced %d artists", totalArtists))
 
-	return nil
+	return reconciled, nil
 }
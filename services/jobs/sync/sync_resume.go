@@ -0,0 +1,312 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"suasor/clients/media"
+	"suasor/clients/media/providers"
+	mediatypes "suasor/clients/media/types"
+	"suasor/repository"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"time"
+)
+
+// resumePositionTolerance is how far apart two positions can be and still be treated as equal
+const resumePositionTolerance = 5
+
+// resumeCandidate is a resume point reported by a single client (or by Suasor when clientID is 0)
+type resumeCandidate struct {
+	clientID         uint64
+	positionSeconds  int
+	durationSeconds  int
+	playedPercentage float64
+	lastPlayedAt     time.Time
+}
+
+func (c resumeCandidate) inProgress() bool {
+	return c.positionSeconds > 0 && c.playedPercentage < 90
+}
+
+// newerThan reports whether the candidate was played after the other one.
+// Clients that don't report when lose to the ones that do, the furthest position wins a tie.
+func (c resumeCandidate) newerThan(other resumeCandidate) bool {
+	if !c.lastPlayedAt.Equal(other.lastPlayedAt) {
+		return c.lastPlayedAt.After(other.lastPlayedAt)
+	}
+	return c.positionSeconds > other.positionSeconds
+}
+
+// resumeState collects every client's resume point for a single media item
+type resumeState[T mediatypes.MediaData] struct {
+	item       *models.MediaItem[T]
+	winner     resumeCandidate
+	candidates map[uint64]resumeCandidate
+}
+
+func newResumeState[T mediatypes.MediaData](item *models.MediaItem[T]) *resumeState[T] {
+	return &resumeState[T]{
+		item:       item,
+		candidates: make(map[uint64]resumeCandidate),
+	}
+}
+
+// add records a client's resume point, the last write wins
+func (s *resumeState[T]) add(candidate resumeCandidate) {
+	if len(s.candidates) == 0 || candidate.newerThan(s.winner) {
+		s.winner = candidate
+	}
+	s.candidates[candidate.clientID] = candidate
+}
+
+// pushTargets returns the linked clients that need the winning position.
+// Resume lists only hold items in progress, so a client without the item gets it too. Only clients that
+// report the item finished or that are already at or past the winning position are left alone.
+func (s *resumeState[T]) pushTargets(winner resumeCandidate) []*models.SyncClient {
+	var targets []*models.SyncClient
+	for _, syncClient := range s.item.SyncClients {
+		if syncClient == nil || syncClient.ItemID == "" || syncClient.ID == winner.clientID {
+			continue
+		}
+		if current, ok := s.candidates[syncClient.ID]; ok {
+			if current.positionSeconds > 0 && !current.inProgress() {
+				continue
+			}
+			if current.positionSeconds+resumePositionTolerance >= winner.positionSeconds {
+				continue
+			}
+		}
+		targets = append(targets, syncClient)
+	}
+	return targets
+}
+
+// syncResumePositions reconciles in-progress positions across all of a user's clients.
+// The most recently played position wins and is written to every other linked client.
+func (j *MediaSyncJob) syncResumePositions(ctx context.Context, userID, jobRunID uint64) error {
+	log := logger.LoggerFromContext(ctx)
+
+	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 10, "Fetching resume positions from clients")
+
+	clientList, err := j.clientRepos.GetAllMediaClientsForUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get media clients: %w", err)
+	}
+
+	// Connect to every client the user owns, the ones without play state support are skipped below
+	clientMedias := make(map[uint64]media.ClientMedia)
+	for clientID := range clientList.IDs {
+		clientMedia, _, err := j.getClientMedia(ctx, clientID)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Uint64("clientID", clientID).
+				Msg("Failed to connect to client for resume sync, skipping")
+			continue
+		}
+		clientMedias[clientID] = clientMedia
+	}
+
+	if len(clientMedias) < 2 {
+		log.Info().
+			Uint64("userID", userID).
+			Int("clientCount", len(clientMedias)).
+			Msg("Resume sync needs at least two clients, nothing to do")
+		j.jobRepo.UpdateJobProgress(ctx, jobRunID, 100, "Nothing to reconcile")
+		return nil
+	}
+
+	movies := make(map[uint64]*resumeState[*mediatypes.Movie])
+	episodes := make(map[uint64]*resumeState[*mediatypes.Episode])
+	tracks := make(map[uint64]*resumeState[*mediatypes.Track])
+
+	// Resume lists only hold the items the user stopped partway through, unlike the play history
+	for clientID, clientMedia := range clientMedias {
+		playStateProvider, ok := clientMedia.(providers.PlayStateProvider)
+		if !ok || !playStateProvider.SupportsPlayState() {
+			continue
+		}
+
+		points, err := playStateProvider.GetResumePoints(ctx)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Uint64("clientID", clientID).
+				Msg("Failed to get resume points for resume sync, skipping")
+			continue
+		}
+
+		for _, point := range points {
+			switch point.MediaType {
+			case mediatypes.MediaTypeMovie:
+				collectResumeCandidate(ctx, j.itemRepos.MovieUserRepo(), clientID, point, movies)
+			case mediatypes.MediaTypeEpisode:
+				collectResumeCandidate(ctx, j.itemRepos.EpisodeUserRepo(), clientID, point, episodes)
+			case mediatypes.MediaTypeTrack:
+				collectResumeCandidate(ctx, j.itemRepos.TrackUserRepo(), clientID, point, tracks)
+			}
+		}
+	}
+
+	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 50,
+		fmt.Sprintf("Reconciling %d resume positions", len(movies)+len(episodes)+len(tracks)))
+
+	movieUpdates := reconcileResumePositions(ctx, userID, j.dataRepos.MovieDataRepo(), movies, clientMedias)
+	episodeUpdates := reconcileResumePositions(ctx, userID, j.dataRepos.EpisodeDataRepo(), episodes, clientMedias)
+	trackUpdates := reconcileResumePositions(ctx, userID, j.dataRepos.TrackDataRepo(), tracks, clientMedias)
+
+	log.Info().
+		Uint64("userID", userID).
+		Int("movieUpdates", movieUpdates).
+		Int("episodeUpdates", episodeUpdates).
+		Int("trackUpdates", trackUpdates).
+		Msg("Resume positions reconciled")
+
+	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 100,
+		fmt.Sprintf("Pushed %d resume positions", movieUpdates+episodeUpdates+trackUpdates))
+
+	return nil
+}
+
+// collectResumeCandidate maps a client's resume point onto a local media item
+func collectResumeCandidate[T mediatypes.MediaData](
+	ctx context.Context,
+	itemRepo repository.UserMediaItemRepository[T],
+	clientID uint64,
+	point mediatypes.ResumePoint,
+	states map[uint64]*resumeState[T],
+) {
+	log := logger.LoggerFromContext(ctx)
+
+	if point.ItemID == "" {
+		return
+	}
+
+	// Only items already synced to the database can be matched across clients
+	item, err := itemRepo.GetByClientItemID(ctx, clientID, point.ItemID)
+	if err != nil || item == nil {
+		log.Debug().
+			Uint64("clientID", clientID).
+			Str("clientItemID", point.ItemID).
+			Msg("Item not found in database, skipping resume position")
+		return
+	}
+
+	state, ok := states[item.ID]
+	if !ok {
+		state = newResumeState(item)
+		states[item.ID] = state
+	}
+	state.add(resumeCandidate{
+		clientID:         clientID,
+		positionSeconds:  point.PositionSeconds,
+		durationSeconds:  point.DurationSeconds,
+		playedPercentage: point.PlayedPercentage(),
+		lastPlayedAt:     point.LastPlayedAt,
+	})
+}
+
+// reconcileResumePositions stores the winning position locally and pushes it to the other clients.
+// It returns the number of clients that were updated.
+func reconcileResumePositions[T mediatypes.MediaData](
+	ctx context.Context,
+	userID uint64,
+	dataRepo repository.UserMediaItemDataRepository[T],
+	states map[uint64]*resumeState[T],
+	clientMedias map[uint64]media.ClientMedia,
+) int {
+	log := logger.LoggerFromContext(ctx)
+	pushed := 0
+
+	for itemID, state := range states {
+		winner := state.winner
+
+		// Positions recorded through Suasor take part in the comparison too
+		local, err := dataRepo.GetByUserIDAndMediaItemID(ctx, userID, itemID)
+		if err != nil {
+			local = nil
+		}
+		if local != nil {
+			if suasor := localResumeCandidate(local); suasor.lastPlayedAt.After(winner.lastPlayedAt) {
+				winner = suasor
+			}
+		}
+
+		// Finished items are handled by the history sync, only resume points move here
+		if !winner.inProgress() {
+			continue
+		}
+
+		if winner.clientID != 0 {
+			if err := saveResumePosition(ctx, userID, dataRepo, state.item, local, winner); err != nil {
+				log.Warn().
+					Err(err).
+					Uint64("mediaItemID", itemID).
+					Msg("Failed to save resume position")
+			}
+		}
+
+		for _, syncClient := range state.pushTargets(winner) {
+			clientMedia, ok := clientMedias[syncClient.ID]
+			if !ok {
+				continue
+			}
+
+			playStateProvider, ok := clientMedia.(providers.PlayStateProvider)
+			if !ok || !playStateProvider.SupportsPlayState() {
+				continue
+			}
+
+			if err := playStateProvider.SetPlaybackPosition(ctx, syncClient.ItemID, winner.positionSeconds); err != nil {
+				log.Warn().
+					Err(err).
+					Uint64("clientID", syncClient.ID).
+					Str("clientItemID", syncClient.ItemID).
+					Msg("Failed to push resume position to client")
+				continue
+			}
+			pushed++
+		}
+	}
+
+	return pushed
+}
+
+// localResumeCandidate is the resume point recorded through Suasor
+func localResumeCandidate[T mediatypes.MediaData](local *models.UserMediaItemData[T]) resumeCandidate {
+	return resumeCandidate{
+		positionSeconds:  local.PositionSeconds,
+		durationSeconds:  local.DurationSeconds,
+		playedPercentage: local.PlayedPercentage,
+		lastPlayedAt:     local.LastPlayedAt,
+	}
+}
+
+// saveResumePosition writes the winning resume position into the user's media item data
+func saveResumePosition[T mediatypes.MediaData](
+	ctx context.Context,
+	userID uint64,
+	dataRepo repository.UserMediaItemDataRepository[T],
+	item *models.MediaItem[T],
+	local *models.UserMediaItemData[T],
+	winner resumeCandidate,
+) error {
+	if local == nil {
+		local = models.NewUserMediaItemData(item, userID)
+		local.Associate(item)
+		local.PlayedAt = winner.lastPlayedAt
+		local.PositionSeconds = winner.positionSeconds
+		local.DurationSeconds = winner.durationSeconds
+		local.PlayedPercentage = winner.playedPercentage
+		local.LastPlayedAt = winner.lastPlayedAt
+		_, err := dataRepo.Create(ctx, local)
+		return err
+	}
+
+	local.PositionSeconds = winner.positionSeconds
+	local.DurationSeconds = winner.durationSeconds
+	local.PlayedPercentage = winner.playedPercentage
+	local.LastPlayedAt = winner.lastPlayedAt
+	_, err := dataRepo.Update(ctx, local)
+	return err
+}
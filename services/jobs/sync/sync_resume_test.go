@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	mediatypes "suasor/clients/media/types"
+	"suasor/types/models"
+)
+
+func TestResumeStateLastWriteWins(t *testing.T) {
+	now := time.Now()
+	item := &models.MediaItem[*mediatypes.Movie]{
+		SyncClients: models.SyncClients{
+			{ID: 1, ItemID: "jf-1"},
+			{ID: 2, ItemID: "plex-1"},
+			{ID: 3, ItemID: "emby-1"},
+			{ID: 4, ItemID: "subsonic-1"},
+			{ID: 5, ItemID: "not-started-1"},
+			{ID: 6, ItemID: "finished-1"},
+		},
+	}
+
+	state := newResumeState(item)
+	state.add(resumeCandidate{clientID: 1, positionSeconds: 600, durationSeconds: 6000, lastPlayedAt: now.Add(-time.Hour)})
+	state.add(resumeCandidate{clientID: 2, positionSeconds: 1200, durationSeconds: 6000, lastPlayedAt: now})
+	state.add(resumeCandidate{clientID: 3, positionSeconds: 1203, durationSeconds: 6000, lastPlayedAt: now.Add(-2 * time.Hour)})
+	state.add(resumeCandidate{clientID: 4, positionSeconds: 3000, durationSeconds: 6000})
+	state.add(resumeCandidate{clientID: 6, positionSeconds: 1100, durationSeconds: 1200, playedPercentage: 92})
+
+	assert.Equal(t, uint64(2), state.winner.clientID, "the most recently played position wins")
+
+	var targets []string
+	for _, syncClient := range state.pushTargets(state.winner) {
+		targets = append(targets, syncClient.ItemID)
+	}
+	assert.Equal(t, []string{"jf-1", "not-started-1"}, targets,
+		"the winner, clients within the tolerance or ahead and clients that finished the item are skipped")
+}
+
+func TestResumeCandidateNewerThan(t *testing.T) {
+	now := time.Now()
+	older := resumeCandidate{positionSeconds: 900, lastPlayedAt: now.Add(-time.Minute)}
+	newer := resumeCandidate{positionSeconds: 100, lastPlayedAt: now}
+	unknown := resumeCandidate{positionSeconds: 2000}
+
+	assert.True(t, newer.newerThan(older))
+	assert.False(t, older.newerThan(newer))
+	assert.False(t, unknown.newerThan(older), "a client that doesn't say when loses to one that does")
+	assert.True(t, unknown.newerThan(resumeCandidate{positionSeconds: 10}), "the furthest position wins a tie")
+}
+
+func TestResumeCandidateInProgress(t *testing.T) {
+	point := mediatypes.ResumePoint{PositionSeconds: 5700, DurationSeconds: 6000}
+	assert.InDelta(t, 95, point.PlayedPercentage(), 1e-9)
+
+	assert.True(t, resumeCandidate{positionSeconds: 60, playedPercentage: 1}.inProgress())
+	assert.False(t, resumeCandidate{positionSeconds: 5700, playedPercentage: point.PlayedPercentage()}.inProgress(), "nearly finished items are left to the history sync")
+	assert.False(t, resumeCandidate{}.inProgress())
+}
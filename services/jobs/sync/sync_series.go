@@ -14,7 +14,7 @@ import (
 )
 
 // syncSeries syncs TV series from the client to the database
-func (j *MediaSyncJob) syncSeries(ctx context.Context, clientMedia media.ClientMedia, jobRunID uint64, clientID uint64) error {
+func (j *MediaSyncJob) syncSeries(ctx context.Context, clientMedia media.ClientMedia, jobRunID uint64, clientID uint64, libraryIDs []string) error {
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 10, "Fetching series from client")
 
@@ -26,10 +26,12 @@ func (j *MediaSyncJob) syncSeries(ctx context.Context, clientMedia media.ClientM
 
 	// Get all series from the client
 	clientType := clientMedia.(clients.Client).GetClientType()
-	series, err := seriesProvider.GetSeries(ctx, &mediatypes.QueryOptions{})
+	window := j.loadSyncWindow(ctx, clientID, models.SyncTypeSeries, libraryIDs)
+	listed, err := listAllPages(ctx, clientID, window.queryOptions(), seriesProvider.GetSeries)
 	if err != nil {
 		return fmt.Errorf("failed to get series: %w", err)
 	}
+	series := filterChangedItems(window, listed.items)
 
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 50, fmt.Sprintf("Processing %d series", len(series)))
@@ -56,6 +58,14 @@ func (j *MediaSyncJob) syncSeries(ctx context.Context, clientMedia media.ClientM
 		j.jobRepo.UpdateJobProgress(ctx, jobRunID, progress, fmt.Sprintf("Processed %d/%d series", processedSeries, totalSeries))
 	}
 
+	// Look for series that were removed from the client
+	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 95, "Checking for deleted series")
+	reconciled, itemCount := reconcileSyncWindow(ctx, window, j.clientItemRepos.SeriesClientRepo(), listed,
+		func() (clientListing[*mediatypes.Series], error) {
+			return listAllPages(ctx, clientID, window.listAllOptions(), seriesProvider.GetSeries)
+		})
+	j.saveSyncWindow(ctx, window, reconciled, itemCount)
+
 	// Update job progress
 	j.jobRepo.UpdateJobProgress(ctx, jobRunID, 100, fmt.Sprintf("Synced %d series", totalSeries))
 
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"suasor/clients"
+	"suasor/clients/media/providers"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+var (
+	// ErrLibraryClientNotFound is returned when the client does not exist or belongs to another user
+	ErrLibraryClientNotFound = errors.New("media client not found")
+	// ErrLibrariesNotSupported is returned when the client cannot list its libraries
+	ErrLibrariesNotSupported = errors.New("client does not support libraries")
+)
+
+// LibraryService lists the libraries on a user's media clients so they can be selected for syncing
+type LibraryService interface {
+	// GetLibraries returns the libraries, sections or music folders on one of the user's clients
+	GetLibraries(ctx context.Context, userID uint64, clientID uint64) ([]*models.MediaLibrary, error)
+}
+
+type libraryService struct {
+	clientRepos   repobundles.ClientRepositories
+	clientFactory *clients.ClientProviderFactoryService
+}
+
+// NewLibraryService creates a new library service
+func NewLibraryService(
+	clientRepos repobundles.ClientRepositories,
+	clientFactory *clients.ClientProviderFactoryService,
+) LibraryService {
+	return &libraryService{
+		clientRepos:   clientRepos,
+		clientFactory: clientFactory,
+	}
+}
+
+// GetLibraries returns the libraries, sections or music folders on one of the user's clients
+func (s *libraryService) GetLibraries(ctx context.Context, userID uint64, clientID uint64) ([]*models.MediaLibrary, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	clientList, err := s.clientRepos.GetAllMediaClientsForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media clients: %w", err)
+	}
+
+	config := clientList.GetClientConfig(clientID)
+	if config == nil {
+		return nil, ErrLibraryClientNotFound
+	}
+
+	client, err := s.clientFactory.GetClient(ctx, clientID, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	provider, ok := client.(providers.LibraryProvider)
+	if !ok || !provider.SupportsLibraries() {
+		return nil, ErrLibrariesNotSupported
+	}
+
+	libraries, err := provider.GetLibraries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug().
+		Uint64("userID", userID).
+		Uint64("clientID", clientID).
+		Int("libraryCount", len(libraries)).
+		Msg("Retrieved client libraries")
+
+	return libraries, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"suasor/clients"
+	mediaclient "suasor/clients/media"
+	"suasor/clients/media/providers"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+	"suasor/utils/logger"
+)
+
+// MediaWriteBackService pushes user changes made in Suasor back to the linked media clients.
+// Clients that don't support a change are left out of the results.
+type MediaWriteBackService interface {
+	// PushPlayed marks the item as played on every linked client
+	PushPlayed(ctx context.Context, userID uint64, syncClients models.SyncClients) []models.ClientSyncResult
+
+	// PushUnplayed marks the item as unplayed on every linked client
+	PushUnplayed(ctx context.Context, userID uint64, syncClients models.SyncClients) []models.ClientSyncResult
+
+	// PushPlaybackPosition updates the resume position on every linked client
+	PushPlaybackPosition(ctx context.Context, userID uint64, syncClients models.SyncClients, positionSeconds int) []models.ClientSyncResult
+
+	// PushFavorite marks or unmarks the item as a favorite on every linked client
+	PushFavorite(ctx context.Context, userID uint64, syncClients models.SyncClients, favorite bool) []models.ClientSyncResult
+
+	// PushRating sets the user's rating on every linked client
+	PushRating(ctx context.Context, userID uint64, syncClients models.SyncClients, rating float32) []models.ClientSyncResult
+}
+
+type mediaWriteBackService struct {
+	clientRepos   repobundles.ClientRepositories
+	clientFactory *clients.ClientProviderFactoryService
+}
+
+// NewMediaWriteBackService creates a new media write-back service
+func NewMediaWriteBackService(
+	clientRepos repobundles.ClientRepositories,
+	clientFactory *clients.ClientProviderFactoryService,
+) MediaWriteBackService {
+	return &mediaWriteBackService{
+		clientRepos:   clientRepos,
+		clientFactory: clientFactory,
+	}
+}
+
+// PushPlayed marks the item as played on every linked client
+func (s *mediaWriteBackService) PushPlayed(ctx context.Context, userID uint64, syncClients models.SyncClients) []models.ClientSyncResult {
+	return s.forEachPlayStateProvider(ctx, userID, syncClients, func(provider providers.PlayStateProvider, itemID string) error {
+		return provider.MarkPlayed(ctx, itemID)
+	})
+}
+
+// PushUnplayed marks the item as unplayed on every linked client
+func (s *mediaWriteBackService) PushUnplayed(ctx context.Context, userID uint64, syncClients models.SyncClients) []models.ClientSyncResult {
+	return s.forEachPlayStateProvider(ctx, userID, syncClients, func(provider providers.PlayStateProvider, itemID string) error {
+		return provider.MarkUnplayed(ctx, itemID)
+	})
+}
+
+// PushPlaybackPosition updates the resume position on every linked client
+func (s *mediaWriteBackService) PushPlaybackPosition(ctx context.Context, userID uint64, syncClients models.SyncClients, positionSeconds int) []models.ClientSyncResult {
+	return s.forEachPlayStateProvider(ctx, userID, syncClients, func(provider providers.PlayStateProvider, itemID string) error {
+		return provider.SetPlaybackPosition(ctx, itemID, positionSeconds)
+	})
+}
+
+// PushFavorite marks or unmarks the item as a favorite on every linked client
+func (s *mediaWriteBackService) PushFavorite(ctx context.Context, userID uint64, syncClients models.SyncClients, favorite bool) []models.ClientSyncResult {
+	return s.forEachUserDataProvider(ctx, userID, syncClients, func(provider providers.UserDataProvider, itemID string) error {
+		return provider.SetFavorite(ctx, itemID, favorite)
+	})
+}
+
+// PushRating sets the user's rating on every linked client
+func (s *mediaWriteBackService) PushRating(ctx context.Context, userID uint64, syncClients models.SyncClients, rating float32) []models.ClientSyncResult {
+	return s.forEachUserDataProvider(ctx, userID, syncClients, func(provider providers.UserDataProvider, itemID string) error {
+		return provider.SetRating(ctx, itemID, rating)
+	})
+}
+
+func (s *mediaWriteBackService) forEachPlayStateProvider(
+	ctx context.Context,
+	userID uint64,
+	syncClients models.SyncClients,
+	fn func(provider providers.PlayStateProvider, itemID string) error,
+) []models.ClientSyncResult {
+	return s.forEachClient(ctx, userID, syncClients, func(client clients.Client, itemID string) error {
+		provider, ok := client.(providers.PlayStateProvider)
+		if !ok || !provider.SupportsPlayState() {
+			return fmt.Errorf("client does not support play state updates: %w", mediaclient.ErrFeatureNotSupported)
+		}
+		return fn(provider, itemID)
+	})
+}
+
+func (s *mediaWriteBackService) forEachUserDataProvider(
+	ctx context.Context,
+	userID uint64,
+	syncClients models.SyncClients,
+	fn func(provider providers.UserDataProvider, itemID string) error,
+) []models.ClientSyncResult {
+	return s.forEachClient(ctx, userID, syncClients, func(client clients.Client, itemID string) error {
+		provider, ok := client.(providers.UserDataProvider)
+		if !ok || !provider.SupportsUserData() {
+			return fmt.Errorf("client does not support favorite or rating updates: %w", mediaclient.ErrFeatureNotSupported)
+		}
+		return fn(provider, itemID)
+	})
+}
+
+// forEachClient runs fn against every linked client owned by the user and collects the results
+func (s *mediaWriteBackService) forEachClient(
+	ctx context.Context,
+	userID uint64,
+	syncClients models.SyncClients,
+	fn func(client clients.Client, itemID string) error,
+) []models.ClientSyncResult {
+	log := logger.LoggerFromContext(ctx)
+	results := make([]models.ClientSyncResult, 0, len(syncClients))
+
+	if len(syncClients) == 0 {
+		return results
+	}
+
+	userClients, err := s.clientRepos.GetAllMediaClientsForUser(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).
+			Uint64("userID", userID).
+			Msg("Failed to get media clients for write-back")
+		for _, syncClient := range syncClients {
+			if syncClient == nil {
+				continue
+			}
+			results = append(results, newClientSyncResult(syncClient, err))
+		}
+		return results
+	}
+
+	for _, syncClient := range syncClients {
+		if syncClient == nil || syncClient.ItemID == "" {
+			continue
+		}
+
+		// Only push to clients that belong to this user
+		config := userClients.GetClientConfig(syncClient.ID)
+		if config == nil {
+			continue
+		}
+
+		client, err := s.clientFactory.GetClient(ctx, syncClient.ID, config)
+		if err != nil {
+			results = append(results, newClientSyncResult(syncClient, err))
+			continue
+		}
+
+		err = fn(client, syncClient.ItemID)
+		if errors.Is(err, mediaclient.ErrFeatureNotSupported) {
+			// Nothing was written, the client can't take the change so it didn't fail to either
+			log.Debug().
+				Uint64("clientID", syncClient.ID).
+				Str("clientType", string(syncClient.Type)).
+				Msg("Client does not support the change, skipping write-back")
+			continue
+		}
+		if err != nil {
+			log.Warn().Err(err).
+				Uint64("clientID", syncClient.ID).
+				Str("clientType", string(syncClient.Type)).
+				Str("itemID", syncClient.ItemID).
+				Msg("Failed to write back change to client")
+		}
+		results = append(results, newClientSyncResult(syncClient, err))
+	}
+
+	return results
+}
+
+func newClientSyncResult(syncClient *models.SyncClient, err error) models.ClientSyncResult {
+	result := models.ClientSyncResult{
+		ClientID:   syncClient.ID,
+		ClientType: syncClient.Type,
+		ItemID:     syncClient.ItemID,
+		Status:     models.SyncStatusSuccess,
+	}
+	if err != nil {
+		result.Status = models.SyncStatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
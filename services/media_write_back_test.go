@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"suasor/clients"
+	mediaclient "suasor/clients/media"
+	clienttypes "suasor/clients/types"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+)
+
+type mockWriteBackClientRepositories struct {
+	repobundles.ClientRepositories
+	clients *models.MediaClientList
+}
+
+func (m *mockWriteBackClientRepositories) GetAllMediaClientsForUser(ctx context.Context, userID uint64) (*models.MediaClientList, error) {
+	return m.clients, nil
+}
+
+// fakeUserDataClient records the favorites and ratings written to it
+type fakeUserDataClient struct {
+	clients.Client
+	favoriteErr error
+	favorites   map[string]bool
+	ratings     map[string]float32
+}
+
+func (c *fakeUserDataClient) SupportsUserData() bool { return true }
+
+func (c *fakeUserDataClient) SetFavorite(ctx context.Context, itemID string, favorite bool) error {
+	if c.favoriteErr != nil {
+		return c.favoriteErr
+	}
+	c.favorites[itemID] = favorite
+	return nil
+}
+
+func (c *fakeUserDataClient) SetRating(ctx context.Context, itemID string, rating float32) error {
+	c.ratings[itemID] = rating
+	return nil
+}
+
+func newFakeUserDataClient(favoriteErr error) *fakeUserDataClient {
+	return &fakeUserDataClient{favoriteErr: favoriteErr, favorites: map[string]bool{}, ratings: map[string]float32{}}
+}
+
+func TestMediaWriteBackUserData(t *testing.T) {
+	ctx := context.Background()
+	// Plex has no favorites, Jellyfin is down, Emby takes everything
+	fakeClients := map[uint64]*fakeUserDataClient{
+		901: newFakeUserDataClient(mediaclient.ErrFeatureNotSupported),
+		902: newFakeUserDataClient(errors.New("server unavailable")),
+		903: newFakeUserDataClient(nil),
+	}
+	factory := clients.NewClientProviderFactoryService()
+	for _, clientType := range []clienttypes.ClientType{clienttypes.ClientTypePlex, clienttypes.ClientTypeJellyfin, clienttypes.ClientTypeEmby} {
+		factory.RegisterClientProviderFactory(clientType, func(ctx context.Context, clientID uint64, config clienttypes.ClientConfig) (clients.Client, error) {
+			return fakeClients[clientID], nil
+		})
+	}
+
+	plexConfig := clienttypes.NewPlexConfig("http://plex", "token", "tester", true, false)
+	jellyfinConfig := clienttypes.NewJellyfinConfig("tester", "user-1", "http://jellyfin", "key", true, false)
+	embyConfig := clienttypes.NewEmbyConfig("tester", "user-1", "http://emby", "key", true, false)
+	userClients := models.NewMediaClientList()
+	userClients.AddPlex(&models.Client[*clienttypes.PlexConfig]{BaseModel: models.BaseModel{ID: 901}, Type: clienttypes.ClientTypePlex, Config: &plexConfig})
+	userClients.AddJellyfin(&models.Client[*clienttypes.JellyfinConfig]{BaseModel: models.BaseModel{ID: 902}, Type: clienttypes.ClientTypeJellyfin, Config: &jellyfinConfig})
+	userClients.AddEmby(&models.Client[*clienttypes.EmbyConfig]{BaseModel: models.BaseModel{ID: 903}, Type: clienttypes.ClientTypeEmby, Config: &embyConfig})
+
+	service := NewMediaWriteBackService(&mockWriteBackClientRepositories{clients: userClients}, factory)
+	syncClients := models.SyncClients{
+		{ID: 901, Type: clienttypes.ClientTypePlex, ItemID: "p1"},
+		{ID: 902, Type: clienttypes.ClientTypeJellyfin, ItemID: "j1"},
+		{ID: 903, Type: clienttypes.ClientTypeEmby, ItemID: "e1"},
+		{ID: 904, Type: clienttypes.ClientTypeJellyfin, ItemID: "other-user"},
+	}
+
+	results := service.PushFavorite(ctx, 1, syncClients, true)
+	assert.Equal(t, []models.ClientSyncResult{
+		{ClientID: 902, ClientType: clienttypes.ClientTypeJellyfin, ItemID: "j1", Status: models.SyncStatusFailed, Error: "server unavailable"},
+		{ClientID: 903, ClientType: clienttypes.ClientTypeEmby, ItemID: "e1", Status: models.SyncStatusSuccess},
+	}, results, "Plex doesn't support favorites and is skipped instead of failing")
+	assert.True(t, fakeClients[903].favorites["e1"])
+
+	results = service.PushRating(ctx, 1, syncClients, 7.5)
+	assert.Len(t, results, 3)
+	for _, result := range results {
+		assert.Equal(t, models.SyncStatusSuccess, result.Status)
+	}
+	assert.Equal(t, float32(7.5), fakeClients[901].ratings["p1"])
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"suasor/repository"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"suasor/utils/prompts"
+)
+
+var (
+	// ErrPromptTemplateNotFound is returned for a template name without a built-in default
+	ErrPromptTemplateNotFound = errors.New("prompt template not found")
+	// ErrPromptVersionNotFound is returned when activating a version that does not exist
+	ErrPromptVersionNotFound = errors.New("prompt template version not found")
+	// ErrInvalidPromptTemplate is returned when a template body does not parse or uses undeclared variables
+	ErrInvalidPromptTemplate = errors.New("invalid prompt template")
+)
+
+// defaultPersonality is used when the user's AiChatPersonality has no template
+const defaultPersonality = "friendly"
+
+// PromptTemplateService renders the prompts sent to AI clients from versioned templates.
+// A template without a saved version uses the built-in default embedded in the binary, version 0.
+type PromptTemplateService interface {
+	// Render renders the active version of a template with typed values, only a custom personality is per user
+	Render(ctx context.Context, userID uint64, name string, values map[string]any) (*prompts.Rendered, error)
+	// ConversationInstructions renders the conversation system prompt with the user's chat personality
+	ConversationInstructions(ctx context.Context, userID uint64, contentType string) (*prompts.Rendered, error)
+
+	// GetTemplates returns every shared template with its variables and active version
+	GetTemplates(ctx context.Context) ([]*models.PromptTemplateInfo, error)
+	// GetVersions returns the saved versions of a shared template, the newest first
+	GetVersions(ctx context.Context, name string) ([]*models.PromptTemplate, error)
+	// SaveTemplate validates the body and saves it as the new active version of a shared template
+	SaveTemplate(ctx context.Context, name string, body string, authorID uint64) (*models.PromptTemplate, error)
+	// ActivateVersion makes a saved version of a shared template active again, version 0 restores the default
+	ActivateVersion(ctx context.Context, name string, version int) error
+
+	// GetPersonalities returns the personality templates a user can pick, with their own custom personality
+	GetPersonalities(ctx context.Context, userID uint64) ([]*models.PromptTemplateInfo, error)
+	// SaveCustomPersonality saves a new version of the user's custom personality
+	SaveCustomPersonality(ctx context.Context, userID uint64, body string) (*models.PromptTemplate, error)
+}
+
+type promptTemplateService struct {
+	templateRepo   repository.PromptTemplateRepository
+	userConfigRepo repository.UserConfigRepository
+}
+
+// NewPromptTemplateService creates a new prompt template service
+func NewPromptTemplateService(
+	templateRepo repository.PromptTemplateRepository,
+	userConfigRepo repository.UserConfigRepository,
+) PromptTemplateService {
+	return &promptTemplateService{
+		templateRepo:   templateRepo,
+		userConfigRepo: userConfigRepo,
+	}
+}
+
+// Render renders the active version of a template with typed values
+func (s *promptTemplateService) Render(ctx context.Context, userID uint64, name string, values map[string]any) (*prompts.Rendered, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	definition, err := prompts.Default(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPromptTemplateNotFound, name)
+	}
+
+	owner := uint64(0)
+	if name == prompts.PersonalityCustom {
+		owner = userID
+	}
+
+	active, err := s.templateRepo.GetActive(ctx, name, owner)
+	if err != nil {
+		log.Warn().Err(err).Str("template", name).Msg("Failed to get prompt template, using the built-in default")
+	} else if active != nil {
+		text, err := prompts.Render(name, active.Body, definition.Variables, values)
+		if err == nil {
+			return &prompts.Rendered{Name: name, Version: active.Version, Text: text}, nil
+		}
+		if errors.Is(err, prompts.ErrInvalidVariable) {
+			return nil, err
+		}
+		log.Error().Err(err).
+			Str("template", name).
+			Int("version", active.Version).
+			Msg("Failed to render prompt template, using the built-in default")
+	}
+
+	text, err := prompts.Render(name, definition.Body, definition.Variables, values)
+	if err != nil {
+		return nil, err
+	}
+	return &prompts.Rendered{Name: name, Version: 0, Text: text}, nil
+}
+
+// ConversationInstructions renders the conversation system prompt with the user's chat personality
+func (s *promptTemplateService) ConversationInstructions(ctx context.Context, userID uint64, contentType string) (*prompts.Rendered, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	personality := defaultPersonality
+	userConfig, err := s.userConfigRepo.GetUserConfig(ctx, userID)
+	if err != nil {
+		log.Warn().Err(err).Uint64("userID", userID).Msg("Failed to get user config, using the default chat personality")
+	} else if userConfig != nil && userConfig.AiChatPersonality != "" {
+		personality = userConfig.AiChatPersonality
+	}
+
+	name := prompts.PersonalityPrefix + personality
+	if _, err := prompts.Default(name); err != nil {
+		name = prompts.PersonalityPrefix + defaultPersonality
+	}
+
+	personalityPrompt, err := s.Render(ctx, userID, name, map[string]any{"ContentType": contentType})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Render(ctx, userID, prompts.ConversationSystem, map[string]any{
+		"ContentType": contentType,
+		"Personality": personalityPrompt.Text,
+	})
+}
+
+// GetTemplates returns every shared template with its variables and active version
+func (s *promptTemplateService) GetTemplates(ctx context.Context) ([]*models.PromptTemplateInfo, error) {
+	active, err := s.templateRepo.GetAllActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	activeByName := make(map[string]*models.PromptTemplate, len(active))
+	for _, template := range active {
+		activeByName[template.Name] = template
+	}
+
+	var templates []*models.PromptTemplateInfo
+	for _, definition := range prompts.Defaults() {
+		templates = append(templates, templateInfo(definition, activeByName[definition.Name]))
+	}
+	return templates, nil
+}
+
+// GetVersions returns the saved versions of a shared template, the newest first
+func (s *promptTemplateService) GetVersions(ctx context.Context, name string) ([]*models.PromptTemplate, error) {
+	if _, err := prompts.Default(name); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPromptTemplateNotFound, name)
+	}
+	return s.templateRepo.GetVersions(ctx, name, 0)
+}
+
+// SaveTemplate validates the body and saves it as the new active version of a shared template
+func (s *promptTemplateService) SaveTemplate(ctx context.Context, name string, body string, authorID uint64) (*models.PromptTemplate, error) {
+	return s.saveVersion(ctx, name, 0, body, authorID)
+}
+
+// ActivateVersion makes a saved version of a shared template active again, version 0 restores the default
+func (s *promptTemplateService) ActivateVersion(ctx context.Context, name string, version int) error {
+	if _, err := prompts.Default(name); err != nil {
+		return fmt.Errorf("%w: %s", ErrPromptTemplateNotFound, name)
+	}
+
+	if err := s.templateRepo.Activate(ctx, name, 0, version); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: %s version %d", ErrPromptVersionNotFound, name, version)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetPersonalities returns the personality templates a user can pick, with their own custom personality
+func (s *promptTemplateService) GetPersonalities(ctx context.Context, userID uint64) ([]*models.PromptTemplateInfo, error) {
+	var personalities []*models.PromptTemplateInfo
+	for _, definition := range prompts.Defaults() {
+		if !prompts.IsPersonality(definition.Name) {
+			continue
+		}
+
+		owner := uint64(0)
+		if definition.Name == prompts.PersonalityCustom {
+			owner = userID
+		}
+		active, err := s.templateRepo.GetActive(ctx, definition.Name, owner)
+		if err != nil {
+			return nil, err
+		}
+		personalities = append(personalities, templateInfo(definition, active))
+	}
+	return personalities, nil
+}
+
+// SaveCustomPersonality saves a new version of the user's custom personality
+func (s *promptTemplateService) SaveCustomPersonality(ctx context.Context, userID uint64, body string) (*models.PromptTemplate, error) {
+	return s.saveVersion(ctx, prompts.PersonalityCustom, userID, body, userID)
+}
+
+func (s *promptTemplateService) saveVersion(ctx context.Context, name string, owner uint64, body string, authorID uint64) (*models.PromptTemplate, error) {
+	definition, err := prompts.Default(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPromptTemplateNotFound, name)
+	}
+	if err := prompts.Validate(name, body, definition.Variables); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPromptTemplate, err)
+	}
+
+	template := &models.PromptTemplate{
+		Name:      name,
+		UserID:    owner,
+		Body:      body,
+		CreatedBy: authorID,
+	}
+	if err := s.templateRepo.CreateVersion(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// templateInfo describes a built-in template with its active saved version, if it has one
+func templateInfo(definition prompts.Definition, active *models.PromptTemplate) *models.PromptTemplateInfo {
+	info := &models.PromptTemplateInfo{
+		Name:        definition.Name,
+		Description: definition.Description,
+		Variables:   definition.Variables,
+		Body:        definition.Body,
+		DefaultBody: definition.Body,
+	}
+	if info.Variables == nil {
+		info.Variables = []prompts.Variable{}
+	}
+	if active != nil {
+		info.ActiveVersion = active.Version
+		info.Body = active.Body
+	}
+	return info
+}
@@ -19,6 +19,10 @@ type RecommendationService interface {
 	MarkRecommendationAsViewed(ctx context.Context, id uint64, userID uint64) error
 	// RateRecommendation sets a user rating for a recommendation
 	RateRecommendation(ctx context.Context, id uint64, userID uint64, rating float32) error
+	// DismissRecommendation dismisses a recommendation, the reason tells the recommenders what to avoid
+	DismissRecommendation(ctx context.Context, id uint64, userID uint64, reason models.DismissReason) error
+	// RecordPlay records that the user clicked through from a recommendation to play it
+	RecordPlay(ctx context.Context, id uint64, userID uint64) error
 	// StoreRecommendations stores recommendations for a user
 	StoreRecommendations(ctx context.Context, recommendations []*models.Recommendation) error
 	// GetRecentRecommendations retrieves recently created recommendations for a user
@@ -34,17 +38,38 @@ type RecommendationService interface {
 // recommendationService implements the RecommendationService interface
 type recommendationService struct {
 	recommendationRepo repository.RecommendationRepository
+	feedbackRepo       repository.RecommendationFeedbackRepository
 }
 
 // NewRecommendationService creates a new recommendation service
-func NewRecommendationService(recommendationRepo repository.RecommendationRepository) RecommendationService {
+func NewRecommendationService(recommendationRepo repository.RecommendationRepository, feedbackRepo repository.RecommendationFeedbackRepository) RecommendationService {
 	return &recommendationService{
 		recommendationRepo: recommendationRepo,
+		feedbackRepo:       feedbackRepo,
 	}
 }
 
-// GetRecommendations retrieves recommendations for a user with optional filtering
+// GetRecommendations retrieves recommendations for a user with optional filtering.
+// The ranked list is returned when the ranking job has made one, the newest recommendations otherwise.
 func (s *recommendationService) GetRecommendations(ctx context.Context, userID uint64, mediaType string, limit, offset int) ([]models.Recommendation, error) {
+	ranked, err := s.recommendationRepo.GetRanked(ctx, userID, mediaType, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if len(ranked) > 0 {
+		return ranked, nil
+	}
+	if offset > 0 {
+		// Past the end of a ranked list, or there is none to page through
+		first, err := s.recommendationRepo.GetRanked(ctx, userID, mediaType, 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(first) > 0 {
+			return ranked, nil
+		}
+	}
+
 	if mediaType != "" {
 		return s.recommendationRepo.GetByMediaType(ctx, userID, mediaType, limit, offset)
 	}
@@ -68,7 +93,13 @@ func (s *recommendationService) MarkRecommendationAsViewed(ctx context.Context,
 		return fmt.Errorf("recommendation does not belong to the user")
 	}
 	
-	return s.recommendationRepo.MarkAsViewed(ctx, id)
+	if err := s.recommendationRepo.MarkAsViewed(ctx, id); err != nil {
+		return err
+	}
+	if rec.IsViewed {
+		return nil
+	}
+	return s.feedbackRepo.Create(ctx, models.NewRecommendationFeedback(rec, models.FeedbackTypeViewed))
 }
 
 // RateRecommendation sets a user rating for a recommendation
@@ -88,7 +119,52 @@ func (s *recommendationService) RateRecommendation(ctx context.Context, id uint6
 		return fmt.Errorf("recommendation does not belong to the user")
 	}
 	
-	return s.recommendationRepo.RateRecommendation(ctx, id, rating)
+	if err := s.recommendationRepo.RateRecommendation(ctx, id, rating); err != nil {
+		return err
+	}
+	feedback := models.NewRecommendationFeedback(rec, models.FeedbackTypeRated)
+	feedback.Rating = rating
+	return s.feedbackRepo.Create(ctx, feedback)
+}
+
+// DismissRecommendation dismisses a recommendation and records why, so the recommenders stop suggesting it
+func (s *recommendationService) DismissRecommendation(ctx context.Context, id uint64, userID uint64, reason models.DismissReason) error {
+	if reason == "" {
+		reason = models.DismissReasonNotInterested
+	}
+	if !reason.IsValid() {
+		return fmt.Errorf("invalid dismiss reason: %s", reason)
+	}
+
+	rec, err := s.recommendationRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if rec.UserID != userID {
+		return fmt.Errorf("recommendation does not belong to the user")
+	}
+
+	if err := s.recommendationRepo.Dismiss(ctx, id); err != nil {
+		return err
+	}
+	feedback := models.NewRecommendationFeedback(rec, models.FeedbackTypeDismissed)
+	feedback.Reason = reason
+	return s.feedbackRepo.Create(ctx, feedback)
+}
+
+// RecordPlay records that the user clicked through from a recommendation to play it
+func (s *recommendationService) RecordPlay(ctx context.Context, id uint64, userID uint64) error {
+	rec, err := s.recommendationRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if rec.UserID != userID {
+		return fmt.Errorf("recommendation does not belong to the user")
+	}
+
+	return s.feedbackRepo.Create(ctx, models.NewRecommendationFeedback(rec, models.FeedbackTypePlayed))
 }
 
 // StoreRecommendations stores recommendations for a user
@@ -21,6 +21,13 @@ type SearchService interface {
 	// SearchMedia searches local database media items
 	SearchMedia(ctx context.Context, userID uint64, options types.QueryOptions) (responses.SearchResults, error)
 
+	// SemanticSearch searches local database media items by the meaning of the query instead of their title
+	SemanticSearch(ctx context.Context, userID uint64, options types.QueryOptions) (responses.SearchResults, error)
+
+	// NaturalSearch parses a free text query into filters with the user's AI clients and searches the local
+	// library with them, the filters are returned so they can be shown and edited
+	NaturalSearch(ctx context.Context, userID uint64, options types.QueryOptions) (responses.SearchResults, *responses.SearchFilters, error)
+
 	// SearchClientMedias searches all media clients for a user
 	SearchClientMedias(ctx context.Context, userID uint64, options types.QueryOptions) (responses.SearchResults, error)
 
@@ -44,6 +51,9 @@ type searchService struct {
 	itemRepos            repobundles.CoreMediaItemRepositories
 	personRepo           repository.PersonRepository
 	clientFactoryService *clients.ClientProviderFactoryService
+	embeddingService     EmbeddingService
+	aiRouter             AIRouter
+	usageService         AIUsageService
 }
 
 // NewSearchService creates a new search service instance
@@ -53,6 +63,9 @@ func NewSearchService(
 	itemRepos repobundles.CoreMediaItemRepositories,
 	personRepo repository.PersonRepository,
 	clientFactoryService *clients.ClientProviderFactoryService,
+	embeddingService EmbeddingService,
+	aiRouter AIRouter,
+	usageService AIUsageService,
 ) SearchService {
 	return &searchService{
 		searchRepo:           searchRepo,
@@ -60,6 +73,9 @@ func NewSearchService(
 		itemRepos:            itemRepos,
 		personRepo:           personRepo,
 		clientFactoryService: clientFactoryService,
+		embeddingService:     embeddingService,
+		aiRouter:             aiRouter,
+		usageService:         usageService,
 	}
 }
 
@@ -102,6 +118,50 @@ func (s *searchService) SearchAll(ctx context.Context, userID uint64, options ty
 	return results, nil
 }
 
+// SemanticSearch searches local database media items by the meaning of the query, best matches first
+func (s *searchService) SemanticSearch(ctx context.Context, userID uint64, options types.QueryOptions) (responses.SearchResults, error) {
+	log := logger.LoggerFromContext(ctx)
+	log.Info().Str("query", options.Query).Msg("Performing semantic search")
+
+	results := responses.SearchResults{}
+	matches, err := s.embeddingService.SemanticSearch(ctx, options.Query, options.MediaType, options.Limit)
+	if err != nil {
+		return results, err
+	}
+
+	for _, match := range matches {
+		switch item := match.Item.(type) {
+		case *models.MediaItem[*types.Movie]:
+			results.Movies = append(results.Movies, item)
+		case *models.MediaItem[*types.Series]:
+			results.Series = append(results.Series, item)
+		case *models.MediaItem[*types.Season]:
+			results.Seasons = append(results.Seasons, item)
+		case *models.MediaItem[*types.Episode]:
+			results.Episodes = append(results.Episodes, item)
+		case *models.MediaItem[*types.Track]:
+			results.Tracks = append(results.Tracks, item)
+		case *models.MediaItem[*types.Album]:
+			results.Albums = append(results.Albums, item)
+		case *models.MediaItem[*types.Artist]:
+			results.Artists = append(results.Artists, item)
+		case *models.MediaItem[*types.Collection]:
+			results.Collections = append(results.Collections, item)
+		case *models.MediaItem[*types.Playlist]:
+			results.Playlists = append(results.Playlists, item)
+		default:
+			continue
+		}
+		results.TotalCount++
+	}
+
+	if _, err := s.searchRepo.SaveSearchHistory(ctx, userID, options.Query, results.TotalCount); err != nil {
+		log.Error().Err(err).Msg("Failed to save search history")
+	}
+
+	return results, nil
+}
+
 // SearchMedia searches local database media items
 func (s *searchService) SearchMedia(ctx context.Context, userID uint64, options types.QueryOptions) (responses.SearchResults, error) {
 	log := logger.LoggerFromContext(ctx)
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"suasor/clients/ai"
+	aitypes "suasor/clients/ai/types"
+	"suasor/clients/media/types"
+	clienttypes "suasor/clients/types"
+	"suasor/types/models"
+	"suasor/types/responses"
+	"suasor/utils/logger"
+	"time"
+)
+
+// naturalMediaTypes are the media types a natural language query can be limited to
+var naturalMediaTypes = []types.MediaType{
+	types.MediaTypeMovie,
+	types.MediaTypeSeries,
+	types.MediaTypeEpisode,
+	types.MediaTypeArtist,
+	types.MediaTypeAlbum,
+	types.MediaTypeTrack,
+}
+
+// naturalQuery is the fixed shape the AI client parses a natural language query into
+type naturalQuery struct {
+	Text             string   `json:"text"`
+	MediaType        string   `json:"mediaType"`
+	Genres           []string `json:"genres"`
+	ReleasedFromYear int      `json:"releasedFromYear"`
+	ReleasedToYear   int      `json:"releasedToYear"`
+	Watched          string   `json:"watched"`
+	Client           string   `json:"client"`
+	MinimumMinutes   int      `json:"minimumMinutes"`
+	MaximumMinutes   int      `json:"maximumMinutes"`
+}
+
+// searchClient is a media client a natural language query can name
+type searchClient struct {
+	ID   uint64
+	Name string
+	Type string
+}
+
+// NaturalSearch parses a free text query like "unwatched 80s horror under 100 minutes on Plex" into filters
+// and searches the local library with them. Without an AI client the query is searched as plain text.
+func (s *searchService) NaturalSearch(ctx context.Context, userID uint64, options types.QueryOptions) (responses.SearchResults, *responses.SearchFilters, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	filters, err := s.parseNaturalQuery(ctx, userID, options)
+	if err != nil {
+		if errors.Is(err, ErrNoAIClient) || errors.Is(err, ErrAIBudgetExceeded) {
+			log.Info().Err(err).Msg("Natural search unavailable, searching as plain text")
+		} else {
+			log.Warn().Err(err).Msg("Failed to parse natural search query, searching as plain text")
+		}
+		results, err := s.SearchAll(ctx, userID, options)
+		return results, &responses.SearchFilters{Options: options}, err
+	}
+
+	log.Info().
+		Str("query", options.Query).
+		Str("text", filters.Options.Query).
+		Strs("genres", filters.Options.Genres).
+		Str("mediaType", string(filters.Options.MediaType)).
+		Uint64("clientID", filters.Options.ClientID).
+		Msg("Performing natural search")
+
+	// Only the local library can answer the typed filters
+	results, err := s.SearchMedia(ctx, userID, filters.Options)
+	if err != nil {
+		return results, filters, err
+	}
+
+	if _, err := s.searchRepo.SaveSearchHistory(ctx, userID, options.Query, results.TotalCount); err != nil {
+		log.Error().Err(err).Msg("Failed to save search history")
+	}
+
+	return results, filters, nil
+}
+
+// parseNaturalQuery asks the user's AI clients for search to parse the query
+func (s *searchService) parseNaturalQuery(ctx context.Context, userID uint64, options types.QueryOptions) (*responses.SearchFilters, error) {
+	if err := s.usageService.CheckBudget(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	mediaClients, err := s.clientRepos.GetAllMediaClientsForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media clients: %w", err)
+	}
+	clientList := searchClients(mediaClients)
+	prompt := naturalQueryPrompt(options.Query, clientList, time.Now())
+
+	var parsed naturalQuery
+	err = s.aiRouter.Route(ctx, userID, models.AIFeatureSearch, func(ctx context.Context, aiClient ai.ClientAI) error {
+		ctx = s.usageService.Track(ctx, userID, aiClient, models.AIFeatureSearch)
+		parsed = naturalQuery{}
+		return aiClient.GenerateStructured(ctx, prompt, &parsed, &aitypes.GenerationOptions{
+			Temperature:        0.1,
+			MaxTokens:          300,
+			SystemInstructions: "You turn media library search queries into search filters. Respond only with valid JSON.",
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	filters := naturalQueryFilters(parsed, options, clientList)
+	filters.Options.UserID = userID
+	return filters, nil
+}
+
+// naturalQueryPrompt describes the filters the query can be parsed into
+func naturalQueryPrompt(query string, clientList []searchClient, now time.Time) string {
+	mediaTypes := make([]string, 0, len(naturalMediaTypes))
+	for _, mediaType := range naturalMediaTypes {
+		mediaTypes = append(mediaTypes, string(mediaType))
+	}
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Parse this search of a media library into filters: %q\n\n", query)
+	fmt.Fprintf(&prompt, "The current year is %d.\n", now.Year())
+	if len(clientList) > 0 {
+		prompt.WriteString("The library is synced from these media servers:\n")
+		for _, client := range clientList {
+			fmt.Fprintf(&prompt, "- %s (%s)\n", client.Name, client.Type)
+		}
+	}
+	prompt.WriteString("\nFill in only what the search asks for and leave the rest empty or 0:\n")
+	prompt.WriteString("- text: words left over that should match titles, empty when the search only describes filters\n")
+	fmt.Fprintf(&prompt, "- mediaType: one of %s\n", strings.Join(mediaTypes, ", "))
+	prompt.WriteString("- genres: genre names in English, e.g. Horror, Science Fiction, Comedy\n")
+	prompt.WriteString("- releasedFromYear and releasedToYear: the release years, e.g. 1980 and 1989 for the 80s\n")
+	prompt.WriteString("- watched: \"watched\", \"unwatched\" or empty\n")
+	prompt.WriteString("- client: the name or type of the media server the search names\n")
+	prompt.WriteString("- minimumMinutes and maximumMinutes: the runtime in minutes\n")
+	return prompt.String()
+}
+
+// naturalQueryFilters turns the parsed query into query options, keeping the paging and media type of the request.
+// Values outside of what the library can filter on are dropped.
+func naturalQueryFilters(parsed naturalQuery, options types.QueryOptions, clientList []searchClient) *responses.SearchFilters {
+	filtered := types.QueryOptions{
+		Query:     strings.TrimSpace(parsed.Text),
+		Limit:     options.Limit,
+		Offset:    options.Offset,
+		MediaType: options.MediaType,
+	}
+
+	if filtered.MediaType == "" {
+		for _, mediaType := range naturalMediaTypes {
+			if strings.EqualFold(parsed.MediaType, string(mediaType)) {
+				filtered.MediaType = mediaType
+			}
+		}
+	}
+
+	for _, genre := range parsed.Genres {
+		if genre = strings.TrimSpace(genre); genre != "" {
+			filtered.Genres = append(filtered.Genres, genre)
+		}
+	}
+
+	if validYear(parsed.ReleasedFromYear) {
+		releasedAfter := time.Date(parsed.ReleasedFromYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+		filtered.ReleasedAfter = &releasedAfter
+	}
+	if validYear(parsed.ReleasedToYear) {
+		releasedBefore := time.Date(parsed.ReleasedToYear+1, time.January, 1, 0, 0, 0, 0, time.UTC).Add(-time.Second)
+		filtered.ReleasedBefore = &releasedBefore
+	}
+
+	switch strings.ToLower(strings.TrimSpace(parsed.Watched)) {
+	case "watched":
+		filtered.Watched = true
+	case "unwatched":
+		filtered.Unwatched = true
+	}
+
+	if parsed.MinimumMinutes > 0 {
+		filtered.MinimumDuration = parsed.MinimumMinutes
+	}
+	if parsed.MaximumMinutes > 0 {
+		filtered.MaximumDuration = parsed.MaximumMinutes
+	}
+
+	filters := &responses.SearchFilters{Parsed: true}
+	if client, ok := matchSearchClient(clientList, parsed.Client); ok {
+		filtered.ClientID = client.ID
+		filters.ClientName = client.Name
+	}
+	filters.Options = filtered
+	return filters
+}
+
+// searchClients returns the user's media clients, the lowest ID first
+func searchClients(list *models.MediaClientList) []searchClient {
+	var clientList []searchClient
+	if list == nil {
+		return clientList
+	}
+	clientList = appendSearchClients(clientList, list.Emby)
+	clientList = appendSearchClients(clientList, list.Jellyfin)
+	clientList = appendSearchClients(clientList, list.Plex)
+	clientList = appendSearchClients(clientList, list.Subsonic)
+	sort.Slice(clientList, func(i, j int) bool { return clientList[i].ID < clientList[j].ID })
+	return clientList
+}
+
+func appendSearchClients[T clienttypes.ClientConfig](clientList []searchClient, clients map[uint64]*models.Client[T]) []searchClient {
+	for id, client := range clients {
+		if client != nil {
+			clientList = append(clientList, searchClient{ID: id, Name: client.Name, Type: string(client.Type)})
+		}
+	}
+	return clientList
+}
+
+// matchSearchClient finds the client a query names, by its name first and then by its type
+func matchSearchClient(clientList []searchClient, name string) (searchClient, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return searchClient{}, false
+	}
+	for _, client := range clientList {
+		if strings.EqualFold(client.Name, name) {
+			return client, true
+		}
+	}
+	for _, client := range clientList {
+		if strings.EqualFold(client.Type, name) {
+			return client, true
+		}
+	}
+	return searchClient{}, false
+}
+
+// validYear reports whether a parsed year can be a release year
+func validYear(year int) bool {
+	return year >= 1850 && year <= 2200
+}
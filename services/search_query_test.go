@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"suasor/clients/media/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaturalQueryFilters(t *testing.T) {
+	clientList := []searchClient{
+		{ID: 1, Name: "Living Room", Type: "plex"},
+		{ID: 2, Name: "Plex", Type: "jellyfin"},
+	}
+	parsed := naturalQuery{
+		MediaType:        "Movie",
+		Genres:           []string{"Horror", " "},
+		ReleasedFromYear: 1980,
+		ReleasedToYear:   1989,
+		Watched:          "unwatched",
+		Client:           "plex",
+		MaximumMinutes:   100,
+	}
+
+	filters := naturalQueryFilters(parsed, types.QueryOptions{Query: "unwatched 80s horror under 100 minutes on Plex", Limit: 20}, clientList)
+
+	assert.True(t, filters.Parsed)
+	assert.Empty(t, filters.Options.Query)
+	assert.Equal(t, 20, filters.Options.Limit)
+	assert.Equal(t, types.MediaTypeMovie, filters.Options.MediaType)
+	assert.Equal(t, []string{"Horror"}, filters.Options.Genres)
+	assert.Equal(t, time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC), *filters.Options.ReleasedAfter)
+	assert.Equal(t, time.Date(1989, time.December, 31, 23, 59, 59, 0, time.UTC), *filters.Options.ReleasedBefore)
+	assert.True(t, filters.Options.Unwatched)
+	assert.False(t, filters.Options.Watched)
+	assert.Equal(t, 100, filters.Options.MaximumDuration)
+	assert.Zero(t, filters.Options.MinimumDuration)
+	// A client's name wins over another client's type
+	assert.Equal(t, uint64(2), filters.Options.ClientID)
+	assert.Equal(t, "Plex", filters.ClientName)
+}
+
+func TestNaturalQueryFiltersDropsInvalidValues(t *testing.T) {
+	parsed := naturalQuery{
+		Text:             " alien ",
+		MediaType:        "podcast",
+		ReleasedFromYear: 80,
+		Watched:          "maybe",
+		Client:           "emby",
+	}
+
+	filters := naturalQueryFilters(parsed, types.QueryOptions{MediaType: types.MediaTypeSeries}, nil)
+
+	assert.Equal(t, "alien", filters.Options.Query)
+	// The media type of the request is kept
+	assert.Equal(t, types.MediaTypeSeries, filters.Options.MediaType)
+	assert.Nil(t, filters.Options.ReleasedAfter)
+	assert.False(t, filters.Options.Watched || filters.Options.Unwatched)
+	assert.Zero(t, filters.Options.ClientID)
+	assert.Empty(t, filters.ClientName)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"suasor/clients"
+	"suasor/clients/media/providers"
+	mediatypes "suasor/clients/media/types"
+	"suasor/repository"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"sync"
+)
+
+// SessionService provides a merged view of what is currently playing on a user's media clients
+type SessionService interface {
+	// GetActiveSessions returns the active sessions from all of the user's media clients
+	GetActiveSessions(ctx context.Context, userID uint64) ([]*models.PlaybackSession, error)
+}
+
+type sessionService struct {
+	clientRepos   repobundles.ClientRepositories
+	itemRepos     repobundles.CoreMediaItemRepositories
+	clientFactory *clients.ClientProviderFactoryService
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(
+	clientRepos repobundles.ClientRepositories,
+	itemRepos repobundles.CoreMediaItemRepositories,
+	clientFactory *clients.ClientProviderFactoryService,
+) SessionService {
+	return &sessionService{
+		clientRepos:   clientRepos,
+		itemRepos:     itemRepos,
+		clientFactory: clientFactory,
+	}
+}
+
+// GetActiveSessions returns the active sessions from all of the user's media clients
+func (s *sessionService) GetActiveSessions(ctx context.Context, userID uint64) ([]*models.PlaybackSession, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	clientList, err := s.clientRepos.GetAllMediaClientsForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media clients: %w", err)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		sessions = make([]*models.PlaybackSession, 0)
+	)
+
+	// Query every client in parallel so one slow server does not hold up the rest
+	for clientID := range clientList.IDs {
+		config := clientList.GetClientConfig(clientID)
+		if config == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(clientID uint64) {
+			defer wg.Done()
+
+			client, err := s.clientFactory.GetClient(ctx, clientID, config)
+			if err != nil {
+				log.Warn().Err(err).Uint64("clientID", clientID).Msg("Failed to get client for sessions")
+				return
+			}
+
+			provider, ok := client.(providers.SessionProvider)
+			if !ok || !provider.SupportsSessions() {
+				return
+			}
+
+			clientSessions, err := provider.GetSessions(ctx)
+			if err != nil {
+				log.Warn().Err(err).Uint64("clientID", clientID).Msg("Failed to get sessions from client")
+				return
+			}
+
+			mu.Lock()
+			sessions = append(sessions, clientSessions...)
+			mu.Unlock()
+		}(clientID)
+	}
+	wg.Wait()
+
+	for _, session := range sessions {
+		s.resolveMediaItem(ctx, session)
+	}
+
+	// Stable ordering for the UI
+	sort.SliceStable(sessions, func(i, j int) bool {
+		if sessions[i].ClientID != sessions[j].ClientID {
+			return sessions[i].ClientID < sessions[j].ClientID
+		}
+		return sessions[i].SessionID < sessions[j].SessionID
+	})
+
+	log.Debug().
+		Uint64("userID", userID).
+		Int("sessionCount", len(sessions)).
+		Msg("Retrieved active sessions")
+
+	return sessions, nil
+}
+
+// resolveMediaItem links a session to our media item using the client item ID
+func (s *sessionService) resolveMediaItem(ctx context.Context, session *models.PlaybackSession) {
+	if session.ClientItemID == "" {
+		return
+	}
+
+	switch session.MediaType {
+	case mediatypes.MediaTypeMovie:
+		resolveSessionItem(ctx, s.itemRepos.MovieRepo(), session)
+	case mediatypes.MediaTypeEpisode:
+		resolveSessionItem(ctx, s.itemRepos.EpisodeRepo(), session)
+	case mediatypes.MediaTypeTrack:
+		resolveSessionItem(ctx, s.itemRepos.TrackRepo(), session)
+	}
+}
+
+func resolveSessionItem[T mediatypes.MediaData](ctx context.Context, repo repository.CoreMediaItemRepository[T], session *models.PlaybackSession) {
+	item, err := repo.GetByClientItemID(ctx, session.ClientID, session.ClientItemID)
+	if err != nil || item == nil {
+		return
+	}
+	session.MediaItemID = item.ID
+	session.Item = item
+}
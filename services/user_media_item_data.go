@@ -30,11 +30,11 @@ type UserMediaItemDataService[T types.MediaData] interface {
 	// RecordPlay records a new play event
 	RecordPlay(ctx context.Context, data *models.UserMediaItemData[T]) (*models.UserMediaItemData[T], error)
 
-	// ToggleFavorite marks or unmarks a media item as a favorite
-	ToggleFavorite(ctx context.Context, itemID uint64, userID uint64, favorite bool) error
+	// ToggleFavorite marks or unmarks a media item as a favorite and pushes the change to linked clients
+	ToggleFavorite(ctx context.Context, itemID uint64, userID uint64, favorite bool) ([]models.ClientSyncResult, error)
 
-	// UpdateRating sets a user's rating for a media item
-	UpdateRating(ctx context.Context, itemID, userID uint64, rating float32) error
+	// UpdateRating sets a user's rating for a media item and pushes the change to linked clients
+	UpdateRating(ctx context.Context, itemID, userID uint64, rating float32) ([]models.ClientSyncResult, error)
 
 	// GetFavorites retrieves favorite media items for a user
 	GetFavorites(ctx context.Context, userID uint64, limit, offset int) ([]*models.UserMediaItemData[T], error)
@@ -46,17 +46,23 @@ type UserMediaItemDataService[T types.MediaData] interface {
 // userMediaItemDataService implements UserMediaItemDataService
 type userMediaItemDataService[T types.MediaData] struct {
 	CoreUserMediaItemDataService[T]
-	repo repository.UserMediaItemDataRepository[T]
+	repo             repository.UserMediaItemDataRepository[T]
+	itemService      CoreMediaItemService[T]
+	writeBackService MediaWriteBackService
 }
 
 // NewUserMediaItemDataService creates a new user media item data service
 func NewUserMediaItemDataService[T types.MediaData](
 	coreService CoreUserMediaItemDataService[T],
 	repo repository.UserMediaItemDataRepository[T],
+	itemService CoreMediaItemService[T],
+	writeBackService MediaWriteBackService,
 ) UserMediaItemDataService[T] {
 	return &userMediaItemDataService[T]{
 		CoreUserMediaItemDataService: coreService,
 		repo:                         repo,
+		itemService:                  itemService,
+		writeBackService:             writeBackService,
 	}
 }
 
@@ -171,6 +177,12 @@ func (s *userMediaItemDataService[T]) RecordPlay(ctx context.Context, data *mode
 		Uint64("mediaItemID", data.MediaItemID).
 		Msg("Recording play event")
 
+	// Remember the previous completed state so we can detect it being toggled off
+	wasCompleted := false
+	if previous, err := s.repo.GetByUserIDAndMediaItemID(ctx, data.UserID, data.MediaItemID); err == nil && previous != nil {
+		wasCompleted = previous.Completed
+	}
+
 	// Delegate to repository
 	result, err := s.repo.RecordPlay(ctx, data)
 	if err != nil {
@@ -187,11 +199,81 @@ func (s *userMediaItemDataService[T]) RecordPlay(ctx context.Context, data *mode
 		Uint64("mediaItemID", result.MediaItemID).
 		Msg("Play event recorded successfully")
 
+	// Writing back waits on every linked client, the play event is not held up by a slow one
+	go s.pushPlayState(context.WithoutCancel(ctx), result, wasCompleted)
+
 	return result, nil
 }
 
+// pushPlayState writes the recorded play state back to the clients linked to the item.
+// It runs after the play event has been returned, so failures are only logged.
+func (s *userMediaItemDataService[T]) pushPlayState(ctx context.Context, data *models.UserMediaItemData[T], wasCompleted bool) {
+	if s.writeBackService == nil {
+		return
+	}
+	log := logger.LoggerFromContext(ctx)
+
+	syncClients := s.getSyncClients(ctx, data.Item, data.MediaItemID)
+	if len(syncClients) == 0 {
+		return
+	}
+
+	var results []models.ClientSyncResult
+	switch {
+	case data.Completed:
+		results = s.writeBackService.PushPlayed(ctx, data.UserID, syncClients)
+	case wasCompleted:
+		results = s.writeBackService.PushUnplayed(ctx, data.UserID, syncClients)
+	case data.PositionSeconds > 0:
+		results = s.writeBackService.PushPlaybackPosition(ctx, data.UserID, syncClients, data.PositionSeconds)
+	default:
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Status == models.SyncStatusFailed {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		log.Warn().
+			Uint64("userID", data.UserID).
+			Uint64("mediaItemID", data.MediaItemID).
+			Int("clients", len(results)).
+			Int("failed", failed).
+			Msg("Failed to write play state back to some clients")
+		return
+	}
+
+	log.Debug().
+		Uint64("mediaItemID", data.MediaItemID).
+		Int("clients", len(results)).
+		Msg("Play state written back to clients")
+}
+
+// getSyncClients returns the clients linked to a media item, loading the item if needed
+func (s *userMediaItemDataService[T]) getSyncClients(ctx context.Context, item *models.MediaItem[T], mediaItemID uint64) models.SyncClients {
+	if item == nil && s.itemService != nil {
+		var err error
+		item, err = s.itemService.GetByID(ctx, mediaItemID)
+		if err != nil {
+			log := logger.LoggerFromContext(ctx)
+			log.Warn().Err(err).
+				Uint64("mediaItemID", mediaItemID).
+				Msg("Failed to load media item for client write-back")
+			return nil
+		}
+	}
+	if item == nil {
+		return nil
+	}
+	return item.SyncClients
+}
+
 // ToggleFavorite marks or unmarks a media item as a favorite
-func (s *userMediaItemDataService[T]) ToggleFavorite(ctx context.Context, mediaItemID, userID uint64, favorite bool) error {
+func (s *userMediaItemDataService[T]) ToggleFavorite(ctx context.Context, mediaItemID, userID uint64, favorite bool) ([]models.ClientSyncResult, error) {
 	log := logger.LoggerFromContext(ctx)
 	log.Debug().
 		Uint64("userID", userID).
@@ -206,18 +288,23 @@ func (s *userMediaItemDataService[T]) ToggleFavorite(ctx context.Context, mediaI
 			Uint64("userID", userID).
 			Uint64("mediaItemID", mediaItemID).
 			Msg("Failed to toggle favorite status")
-		return err
+		return nil, err
 	}
 
 	log.Info().
 		Bool("favorite", favorite).
 		Msg("Favorite status toggled successfully")
 
-	return nil
+	results := []models.ClientSyncResult{}
+	if syncClients := s.getSyncClients(ctx, nil, mediaItemID); len(syncClients) > 0 && s.writeBackService != nil {
+		results = s.writeBackService.PushFavorite(ctx, userID, syncClients, favorite)
+	}
+
+	return results, nil
 }
 
 // UpdateRating sets a user's rating for a media item
-func (s *userMediaItemDataService[T]) UpdateRating(ctx context.Context, mediaItemID, userID uint64, rating float32) error {
+func (s *userMediaItemDataService[T]) UpdateRating(ctx context.Context, mediaItemID, userID uint64, rating float32) ([]models.ClientSyncResult, error) {
 	log := logger.LoggerFromContext(ctx)
 	log.Debug().
 		Uint64("userID", userID).
@@ -232,14 +319,19 @@ func (s *userMediaItemDataService[T]) UpdateRating(ctx context.Context, mediaIte
 			Uint64("userID", userID).
 			Uint64("mediaItemID", mediaItemID).
 			Msg("Failed to update rating")
-		return err
+		return nil, err
 	}
 
 	log.Info().
 		Float32("rating", rating).
 		Msg("Rating updated successfully")
 
-	return nil
+	results := []models.ClientSyncResult{}
+	if syncClients := s.getSyncClients(ctx, nil, mediaItemID); len(syncClients) > 0 && s.writeBackService != nil {
+		results = s.writeBackService.PushRating(ctx, userID, syncClients, rating)
+	}
+
+	return results, nil
 }
 
 // GetFavorites retrieves favorite media items for a user
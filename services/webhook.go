@@ -0,0 +1,662 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	mediatypes "suasor/clients/media/types"
+	clienttypes "suasor/clients/types"
+	"suasor/repository"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+	"suasor/utils/logger"
+	"time"
+)
+
+var (
+	// ErrInvalidWebhookSignature is returned when a webhook is not signed with the client's secret
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	// ErrWebhookClientNotFound is returned when a webhook targets a client that does not exist
+	ErrWebhookClientNotFound = errors.New("webhook client not found")
+	// ErrUnsupportedWebhookClient is returned for client types that do not send webhooks
+	ErrUnsupportedWebhookClient = errors.New("client type does not support webhooks")
+)
+
+// MediaItemSyncer syncs a single item from a client into the database
+type MediaItemSyncer interface {
+	SyncItem(ctx context.Context, clientID uint64, mediaType mediatypes.MediaType, clientItemID string) error
+}
+
+// WebhookService turns webhooks sent by media servers into targeted updates
+type WebhookService interface {
+	// GetWebhookURL returns the signed URL a user configures on their media server, creating a token the first time
+	GetWebhookURL(ctx context.Context, userID, clientID uint64) (string, error)
+
+	// RotateWebhookToken replaces the client's token, the webhook configured with the old URL stops working
+	RotateWebhookToken(ctx context.Context, userID, clientID uint64) (string, error)
+
+	// VerifyWebhook checks the token query parameter or the body signature header
+	VerifyWebhook(ctx context.Context, clientID uint64, token string, signature string, body []byte) (bool, error)
+
+	// HandleWebhook parses a webhook payload and applies it to the client owner's data
+	HandleWebhook(ctx context.Context, clientID uint64, contentType string, body []byte) (*models.WebhookEvent, error)
+}
+
+type webhookService struct {
+	baseURL     string
+	tokenRepo   repository.WebhookTokenRepository
+	clientRepos repobundles.ClientRepositories
+	itemRepos   repobundles.CoreMediaItemRepositories
+	dataRepos   repobundles.UserMediaDataRepositories
+	itemSyncer  MediaItemSyncer
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(
+	baseURL string,
+	tokenRepo repository.WebhookTokenRepository,
+	clientRepos repobundles.ClientRepositories,
+	itemRepos repobundles.CoreMediaItemRepositories,
+	dataRepos repobundles.UserMediaDataRepositories,
+	itemSyncer MediaItemSyncer,
+) WebhookService {
+	return &webhookService{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		tokenRepo:   tokenRepo,
+		clientRepos: clientRepos,
+		itemRepos:   itemRepos,
+		dataRepos:   dataRepos,
+		itemSyncer:  itemSyncer,
+	}
+}
+
+// GetWebhookURL returns the signed URL a user configures on their media server, creating a token the first time
+func (s *webhookService) GetWebhookURL(ctx context.Context, userID, clientID uint64) (string, error) {
+	if err := s.checkWebhookClient(ctx, userID, clientID); err != nil {
+		return "", err
+	}
+
+	webhookToken, err := s.tokenRepo.GetByClientID(ctx, clientID)
+	if err != nil {
+		return "", err
+	}
+	if webhookToken == nil {
+		return s.rotateWebhookToken(ctx, clientID)
+	}
+	return s.webhookURL(clientID, webhookToken.Token), nil
+}
+
+// RotateWebhookToken replaces the client's token, the webhook configured with the old URL stops working
+func (s *webhookService) RotateWebhookToken(ctx context.Context, userID, clientID uint64) (string, error) {
+	if err := s.checkWebhookClient(ctx, userID, clientID); err != nil {
+		return "", err
+	}
+	return s.rotateWebhookToken(ctx, clientID)
+}
+
+func (s *webhookService) rotateWebhookToken(ctx context.Context, clientID uint64) (string, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	webhookToken, err := s.tokenRepo.GetByClientID(ctx, clientID)
+	if err != nil {
+		return "", err
+	}
+	if webhookToken == nil {
+		webhookToken = &models.WebhookToken{ClientID: clientID}
+	}
+
+	token, err := newWebhookToken()
+	if err != nil {
+		return "", err
+	}
+	webhookToken.Token = token
+
+	if err := s.tokenRepo.Save(ctx, webhookToken); err != nil {
+		return "", err
+	}
+
+	log.Info().Uint64("clientID", clientID).Msg("Issued webhook token")
+
+	return s.webhookURL(clientID, token), nil
+}
+
+// checkWebhookClient checks the client belongs to the user and can send webhooks
+func (s *webhookService) checkWebhookClient(ctx context.Context, userID, clientID uint64) error {
+	clientList, err := s.clientRepos.GetAllMediaClientsForUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get media clients: %w", err)
+	}
+
+	clientType, ok := clientList.GetClientType(clientID)
+	if !ok {
+		return ErrWebhookClientNotFound
+	}
+	if !supportsWebhooks(clientType) {
+		return ErrUnsupportedWebhookClient
+	}
+	return nil
+}
+
+func (s *webhookService) webhookURL(clientID uint64, token string) string {
+	return fmt.Sprintf("%s/api/v1/webhooks/%d?token=%s", s.baseURL, clientID, token)
+}
+
+// VerifyWebhook checks the token query parameter or the body signature header.
+// Plex cannot send custom headers so the token is accepted on its own; the
+// signature is a hex HMAC-SHA256 of the body keyed with the same token.
+func (s *webhookService) VerifyWebhook(ctx context.Context, clientID uint64, token string, signature string, body []byte) (bool, error) {
+	webhookToken, err := s.tokenRepo.GetByClientID(ctx, clientID)
+	if err != nil {
+		return false, err
+	}
+	if webhookToken == nil {
+		// No URL was ever issued for the client
+		return false, nil
+	}
+	expected := webhookToken.Token
+
+	if token != "" {
+		return hmac.Equal([]byte(token), []byte(expected)), nil
+	}
+
+	if signature != "" {
+		signature = strings.TrimPrefix(signature, "sha256=")
+		mac := hmac.New(sha256.New, []byte(expected))
+		mac.Write(body)
+		return hmac.Equal([]byte(signature), []byte(hex.EncodeToString(mac.Sum(nil)))), nil
+	}
+
+	return false, nil
+}
+
+// newWebhookToken generates a random 256 bit token
+func newWebhookToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HandleWebhook parses a webhook payload and applies it to the client owner's data
+func (s *webhookService) HandleWebhook(ctx context.Context, clientID uint64, contentType string, body []byte) (*models.WebhookEvent, error) {
+	log := logger.LoggerFromContext(ctx)
+
+	clientList, err := s.clientRepos.GetAllMediaClients(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media clients: %w", err)
+	}
+
+	clientType, ok := clientList.GetClientType(clientID)
+	if !ok {
+		return nil, ErrWebhookClientNotFound
+	}
+	ownerID := clientList.GetClientOwnerID(clientID)
+
+	var event *models.WebhookEvent
+	switch clientType {
+	case clienttypes.ClientTypePlex:
+		event, err = parsePlexWebhook(contentType, body)
+	case clienttypes.ClientTypeJellyfin:
+		event, err = parseJellyfinWebhook(body)
+	case clienttypes.ClientTypeEmby:
+		event, err = parseEmbyWebhook(contentType, body)
+	default:
+		return nil, ErrUnsupportedWebhookClient
+	}
+	if err != nil {
+		return nil, err
+	}
+	event.ClientID = clientID
+	event.ClientType = clientType
+
+	log.Info().
+		Uint64("clientID", clientID).
+		Str("clientType", string(clientType)).
+		Str("event", event.RawEvent).
+		Str("itemID", event.ItemID).
+		Msg("Received webhook")
+
+	if event.Event == models.WebhookEventUnknown || event.ItemID == "" {
+		return event, nil
+	}
+
+	if event.Event == models.WebhookEventLibraryNew {
+		// Seasons, albums and artists come in with the next library sync, the server shouldn't retry them
+		if !syncableWebhookMediaType(event.MediaType) {
+			log.Debug().
+				Str("mediaType", string(event.MediaType)).
+				Msg("Ignoring new item webhook for a media type that can't be synced on its own")
+			return event, nil
+		}
+		if err := s.itemSyncer.SyncItem(ctx, clientID, event.MediaType, event.ItemID); err != nil {
+			return event, fmt.Errorf("failed to sync new item: %w", err)
+		}
+		return event, nil
+	}
+
+	// Servers send webhooks for every user, only the client's own user's events are the owner's
+	userID, username := webhookClientUser(clientList.GetClientConfig(clientID))
+	if !webhookForUser(event, userID, username) {
+		log.Debug().
+			Str("webhookUserID", event.UserID).
+			Str("webhookUserName", event.UserName).
+			Msg("Ignoring webhook for another media server user")
+		return event, nil
+	}
+
+	switch event.MediaType {
+	case mediatypes.MediaTypeMovie:
+		err = applyWebhookEvent(ctx, s.itemSyncer, s.itemRepos.MovieRepo(), s.dataRepos.MovieDataRepo(), ownerID, event)
+	case mediatypes.MediaTypeEpisode:
+		err = applyWebhookEvent(ctx, s.itemSyncer, s.itemRepos.EpisodeRepo(), s.dataRepos.EpisodeDataRepo(), ownerID, event)
+	case mediatypes.MediaTypeTrack:
+		err = applyWebhookEvent(ctx, s.itemSyncer, s.itemRepos.TrackRepo(), s.dataRepos.TrackDataRepo(), ownerID, event)
+	default:
+		log.Debug().
+			Str("mediaType", string(event.MediaType)).
+			Msg("Ignoring webhook for unsupported media type")
+		return event, nil
+	}
+
+	return event, err
+}
+
+// applyWebhookEvent updates the user's data for the item the event refers to
+func applyWebhookEvent[T mediatypes.MediaData](
+	ctx context.Context,
+	itemSyncer MediaItemSyncer,
+	itemRepo repository.CoreMediaItemRepository[T],
+	dataRepo repository.UserMediaItemDataRepository[T],
+	userID uint64,
+	event *models.WebhookEvent,
+) error {
+	item, err := itemRepo.GetByClientItemID(ctx, event.ClientID, event.ItemID)
+	if err != nil || item == nil {
+		// Played before a library sync picked it up, pull it in first
+		if err := itemSyncer.SyncItem(ctx, event.ClientID, event.MediaType, event.ItemID); err != nil {
+			return fmt.Errorf("failed to sync item %s: %w", event.ItemID, err)
+		}
+		item, err = itemRepo.GetByClientItemID(ctx, event.ClientID, event.ItemID)
+		if err != nil || item == nil {
+			return fmt.Errorf("media item not found for client item %s", event.ItemID)
+		}
+	}
+	event.MediaItemID = item.ID
+
+	switch {
+	case event.Event == models.WebhookEventRate:
+		return dataRepo.UpdateRating(ctx, item.ID, userID, event.Rating)
+
+	case event.Event == models.WebhookEventFavorite:
+		return dataRepo.ToggleFavorite(ctx, item.ID, userID, event.Favorite)
+
+	case event.Event == models.WebhookEventScrobble:
+		data := models.NewUserMediaItemData(item, userID)
+		data.Associate(item)
+		data.DurationSeconds = event.DurationSeconds
+		data.PlayedPercentage = 100
+		data.Completed = true
+		_, err := dataRepo.RecordPlay(ctx, data)
+		return err
+
+	case event.Event.IsPlayback():
+		// Position updates must not bump the play count, so bypass RecordPlay
+		now := time.Now()
+		data, err := dataRepo.GetByUserIDAndMediaItemID(ctx, userID, item.ID)
+		if err != nil || data == nil {
+			data = models.NewUserMediaItemData(item, userID)
+			data.Associate(item)
+			data.PlayedAt = now
+			data.PositionSeconds = event.PositionSeconds
+			data.DurationSeconds = event.DurationSeconds
+			data.PlayedPercentage = event.PlayedPercentage()
+			data.LastPlayedAt = now
+			_, err = dataRepo.Create(ctx, data)
+			return err
+		}
+		data.PositionSeconds = event.PositionSeconds
+		if event.DurationSeconds > 0 {
+			data.DurationSeconds = event.DurationSeconds
+		}
+		data.PlayedPercentage = event.PlayedPercentage()
+		data.LastPlayedAt = now
+		_, err = dataRepo.Update(ctx, data)
+		return err
+	}
+
+	return nil
+}
+
+// webhookClientUser returns the media server user ID and name a client is configured with
+func webhookClientUser(config clienttypes.ClientConfig) (userID string, username string) {
+	switch cfg := config.(type) {
+	case *clienttypes.PlexConfig:
+		return "", cfg.GetUsername()
+	case *clienttypes.JellyfinConfig:
+		return cfg.GetUserID(), cfg.GetUsername()
+	case *clienttypes.EmbyConfig:
+		return cfg.GetUserID(), cfg.GetUsername()
+	default:
+		return "", ""
+	}
+}
+
+// webhookForUser reports whether a user event is for the given media server user.
+// IDs are compared when both sides have one, Jellyfin sends them without dashes.
+// Events that don't say whose they are are dropped unless the client has no user configured.
+func webhookForUser(event *models.WebhookEvent, userID string, username string) bool {
+	if userID == "" && username == "" {
+		return true
+	}
+	if event.UserID != "" && userID != "" {
+		return normalizeWebhookUserID(event.UserID) == normalizeWebhookUserID(userID)
+	}
+	if event.UserName != "" && username != "" {
+		return strings.EqualFold(event.UserName, username)
+	}
+	return false
+}
+
+func normalizeWebhookUserID(userID string) string {
+	return strings.ToLower(strings.ReplaceAll(userID, "-", ""))
+}
+
+func supportsWebhooks(clientType clienttypes.ClientType) bool {
+	switch clientType {
+	case clienttypes.ClientTypePlex, clienttypes.ClientTypeJellyfin, clienttypes.ClientTypeEmby:
+		return true
+	default:
+		return false
+	}
+}
+
+// plexWebhookPayload is the JSON sent in the "payload" field of Plex webhooks
+type plexWebhookPayload struct {
+	Event   string  `json:"event"`
+	Rating  float32 `json:"rating"`
+	Account struct {
+		ID    int64  `json:"id"`
+		Title string `json:"title"`
+	} `json:"Account"`
+	Metadata struct {
+		RatingKey  string `json:"ratingKey"`
+		Type       string `json:"type"`
+		Title      string `json:"title"`
+		ViewOffset int64  `json:"viewOffset"`
+		Duration   int64  `json:"duration"`
+	} `json:"Metadata"`
+}
+
+// parsePlexWebhook parses the multipart form Plex posts to webhooks
+func parsePlexWebhook(contentType string, body []byte) (*models.WebhookEvent, error) {
+	payload, err := readMultipartField(contentType, body, "payload")
+	if err != nil {
+		return nil, err
+	}
+
+	var plex plexWebhookPayload
+	if err := json.Unmarshal(payload, &plex); err != nil {
+		return nil, fmt.Errorf("failed to decode plex webhook payload: %w", err)
+	}
+
+	event := &models.WebhookEvent{
+		RawEvent:        plex.Event,
+		MediaType:       webhookMediaType(plex.Metadata.Type),
+		ItemID:          plex.Metadata.RatingKey,
+		Title:           plex.Metadata.Title,
+		UserName:        plex.Account.Title,
+		PositionSeconds: int(plex.Metadata.ViewOffset / 1000),
+		DurationSeconds: int(plex.Metadata.Duration / 1000),
+	}
+
+	if plex.Account.ID != 0 {
+		// Plex account IDs aren't in the client config, the account title is matched instead
+		event.UserID = strconv.FormatInt(plex.Account.ID, 10)
+	}
+
+	switch plex.Event {
+	case "media.play":
+		event.Event = models.WebhookEventPlay
+	case "media.pause":
+		event.Event = models.WebhookEventPause
+	case "media.resume":
+		event.Event = models.WebhookEventResume
+	case "media.stop":
+		event.Event = models.WebhookEventStop
+	case "media.scrobble":
+		event.Event = models.WebhookEventScrobble
+		event.Completed = true
+	case "media.rate":
+		// Plex uses the same 0-10 scale, -1 clears the rating
+		event.Event = models.WebhookEventRate
+		event.Rating = max(plex.Rating, 0)
+	case "library.new":
+		event.Event = models.WebhookEventLibraryNew
+	default:
+		event.Event = models.WebhookEventUnknown
+	}
+
+	return event, nil
+}
+
+// jellyfinWebhookPayload is the JSON sent by the Jellyfin webhook plugin
+type jellyfinWebhookPayload struct {
+	NotificationType      string `json:"NotificationType"`
+	ItemID                string `json:"ItemId"`
+	ItemType              string `json:"ItemType"`
+	Name                  string `json:"Name"`
+	PlaybackPositionTicks int64  `json:"PlaybackPositionTicks"`
+	RunTimeTicks          int64  `json:"RunTimeTicks"`
+	PlayedToCompletion    bool   `json:"PlayedToCompletion"`
+	IsPaused              bool   `json:"IsPaused"`
+	SaveReason            string `json:"SaveReason"`
+	Played                bool   `json:"Played"`
+	Favorite              bool   `json:"Favorite"`
+	UserID                string `json:"UserId"`
+	NotificationUsername  string `json:"NotificationUsername"`
+}
+
+// parseJellyfinWebhook parses the JSON sent by the Jellyfin webhook plugin
+func parseJellyfinWebhook(body []byte) (*models.WebhookEvent, error) {
+	var jellyfin jellyfinWebhookPayload
+	if err := json.Unmarshal(body, &jellyfin); err != nil {
+		return nil, fmt.Errorf("failed to decode jellyfin webhook payload: %w", err)
+	}
+
+	event := &models.WebhookEvent{
+		RawEvent:        jellyfin.NotificationType,
+		MediaType:       webhookMediaType(jellyfin.ItemType),
+		ItemID:          jellyfin.ItemID,
+		Title:           jellyfin.Name,
+		UserID:          jellyfin.UserID,
+		UserName:        jellyfin.NotificationUsername,
+		PositionSeconds: int(jellyfin.PlaybackPositionTicks / 10000000),
+		DurationSeconds: int(jellyfin.RunTimeTicks / 10000000),
+	}
+
+	switch jellyfin.NotificationType {
+	case "PlaybackStart":
+		event.Event = models.WebhookEventPlay
+	case "PlaybackProgress":
+		event.Event = models.WebhookEventProgress
+		if jellyfin.IsPaused {
+			event.Event = models.WebhookEventPause
+		}
+	case "PlaybackStop":
+		event.Event = models.WebhookEventStop
+		if jellyfin.PlayedToCompletion {
+			event.Event = models.WebhookEventScrobble
+			event.Completed = true
+		}
+	case "UserDataSaved":
+		switch jellyfin.SaveReason {
+		case "TogglePlayed":
+			event.Event = models.WebhookEventUnknown
+			if jellyfin.Played {
+				event.Event = models.WebhookEventScrobble
+				event.Completed = true
+			}
+		case "UpdateUserRating":
+			event.Event = models.WebhookEventFavorite
+			event.Favorite = jellyfin.Favorite
+		default:
+			event.Event = models.WebhookEventUnknown
+		}
+	case "ItemAdded":
+		event.Event = models.WebhookEventLibraryNew
+	default:
+		event.Event = models.WebhookEventUnknown
+	}
+
+	return event, nil
+}
+
+// embyWebhookPayload is the JSON sent by Emby webhooks
+type embyWebhookPayload struct {
+	Event string `json:"Event"`
+	User  struct {
+		ID   string `json:"Id"`
+		Name string `json:"Name"`
+	} `json:"User"`
+	Item struct {
+		ID           string `json:"Id"`
+		Name         string `json:"Name"`
+		Type         string `json:"Type"`
+		RunTimeTicks int64  `json:"RunTimeTicks"`
+		UserData     struct {
+			Rating     float32 `json:"Rating"`
+			IsFavorite bool    `json:"IsFavorite"`
+		} `json:"UserData"`
+	} `json:"Item"`
+	PlaybackInfo struct {
+		PositionTicks      int64 `json:"PositionTicks"`
+		PlayedToCompletion bool  `json:"PlayedToCompletion"`
+	} `json:"PlaybackInfo"`
+}
+
+// parseEmbyWebhook parses Emby webhooks, which are sent either as JSON or as
+// a multipart form with the JSON in the "data" field
+func parseEmbyWebhook(contentType string, body []byte) (*models.WebhookEvent, error) {
+	if strings.HasPrefix(contentType, "multipart/") {
+		data, err := readMultipartField(contentType, body, "data")
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	var emby embyWebhookPayload
+	if err := json.Unmarshal(body, &emby); err != nil {
+		return nil, fmt.Errorf("failed to decode emby webhook payload: %w", err)
+	}
+
+	event := &models.WebhookEvent{
+		RawEvent:        emby.Event,
+		MediaType:       webhookMediaType(emby.Item.Type),
+		ItemID:          emby.Item.ID,
+		Title:           emby.Item.Name,
+		UserID:          emby.User.ID,
+		UserName:        emby.User.Name,
+		PositionSeconds: int(emby.PlaybackInfo.PositionTicks / 10000000),
+		DurationSeconds: int(emby.Item.RunTimeTicks / 10000000),
+	}
+
+	switch emby.Event {
+	case "playback.start":
+		event.Event = models.WebhookEventPlay
+	case "playback.pause":
+		event.Event = models.WebhookEventPause
+	case "playback.unpause":
+		event.Event = models.WebhookEventResume
+	case "playback.stop":
+		event.Event = models.WebhookEventStop
+		if emby.PlaybackInfo.PlayedToCompletion {
+			event.Event = models.WebhookEventScrobble
+			event.Completed = true
+		}
+	case "item.markplayed":
+		event.Event = models.WebhookEventScrobble
+		event.Completed = true
+	case "item.rate":
+		// Emby raises item.rate for favorites as well as ratings
+		if emby.Item.UserData.Rating > 0 {
+			event.Event = models.WebhookEventRate
+			event.Rating = emby.Item.UserData.Rating
+		} else {
+			event.Event = models.WebhookEventFavorite
+			event.Favorite = emby.Item.UserData.IsFavorite
+		}
+	case "library.new":
+		event.Event = models.WebhookEventLibraryNew
+	default:
+		event.Event = models.WebhookEventUnknown
+	}
+
+	return event, nil
+}
+
+// readMultipartField returns the value of a single field of a multipart form body
+func readMultipartField(contentType string, body []byte, field string) ([]byte, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("expected multipart webhook payload, got %q", contentType)
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, fmt.Errorf("webhook payload has no %q field", field)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read multipart webhook payload: %w", err)
+		}
+		if part.FormName() == field {
+			return io.ReadAll(part)
+		}
+	}
+}
+
+// syncableWebhookMediaType returns true for the media types a single new item can be synced for
+func syncableWebhookMediaType(mediaType mediatypes.MediaType) bool {
+	switch mediaType {
+	case mediatypes.MediaTypeMovie, mediatypes.MediaTypeSeries, mediatypes.MediaTypeEpisode, mediatypes.MediaTypeTrack:
+		return true
+	default:
+		return false
+	}
+}
+
+// webhookMediaType maps the item types used by Plex, Jellyfin and Emby to ours
+func webhookMediaType(itemType string) mediatypes.MediaType {
+	switch strings.ToLower(itemType) {
+	case "movie":
+		return mediatypes.MediaTypeMovie
+	case "show", "series":
+		return mediatypes.MediaTypeSeries
+	case "season":
+		return mediatypes.MediaTypeSeason
+	case "episode":
+		return mediatypes.MediaTypeEpisode
+	case "track", "audio":
+		return mediatypes.MediaTypeTrack
+	case "album", "musicalbum":
+		return mediatypes.MediaTypeAlbum
+	case "artist", "musicartist":
+		return mediatypes.MediaTypeArtist
+	default:
+		return mediatypes.MediaTypeUnknown
+	}
+}
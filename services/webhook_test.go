@@ -0,0 +1,156 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime/multipart"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mediatypes "suasor/clients/media/types"
+	clienttypes "suasor/clients/types"
+	repobundles "suasor/repository/bundles"
+	"suasor/types/models"
+)
+
+type mockWebhookTokenRepository struct {
+	tokens map[uint64]*models.WebhookToken
+}
+
+func (m *mockWebhookTokenRepository) GetByClientID(ctx context.Context, clientID uint64) (*models.WebhookToken, error) {
+	return m.tokens[clientID], nil
+}
+
+func (m *mockWebhookTokenRepository) Save(ctx context.Context, token *models.WebhookToken) error {
+	m.tokens[token.ClientID] = token
+	return nil
+}
+
+func TestWebhookVerify(t *testing.T) {
+	ctx := context.Background()
+	tokenRepo := &mockWebhookTokenRepository{tokens: map[uint64]*models.WebhookToken{}}
+	service := NewWebhookService("http://localhost:8080", tokenRepo, nil, nil, nil, nil).(*webhookService)
+
+	url, err := service.rotateWebhookToken(ctx, 7)
+	require.NoError(t, err)
+	token := tokenRepo.tokens[7].Token
+	assert.Len(t, token, 64)
+	assert.Equal(t, "http://localhost:8080/api/v1/webhooks/7?token="+token, url)
+	body := []byte(`{"NotificationType":"PlaybackStart"}`)
+
+	verify := func(clientID uint64, token string, signature string, body []byte) bool {
+		verified, err := service.VerifyWebhook(ctx, clientID, token, signature, body)
+		require.NoError(t, err)
+		return verified
+	}
+
+	assert.True(t, verify(7, token, "", body))
+	assert.False(t, verify(8, token, "", body), "tokens are per client")
+	assert.False(t, verify(7, "", "", body), "unsigned requests are rejected")
+
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+	assert.True(t, verify(7, "", "sha256="+signature, body))
+	assert.False(t, verify(7, "", signature, []byte(`{}`)))
+
+	_, err = service.rotateWebhookToken(ctx, 7)
+	require.NoError(t, err)
+	assert.False(t, verify(7, token, "", body), "rotating replaces the token")
+	assert.True(t, verify(7, tokenRepo.tokens[7].Token, "", body))
+}
+
+func TestWebhookForUser(t *testing.T) {
+	event := &models.WebhookEvent{UserID: "4f1c2a9e8d7b4c3a9e8d7b4c3a9e8d7b", UserName: "alice"}
+	assert.True(t, webhookForUser(event, "4F1C2A9E-8D7B-4C3A-9E8D-7B4C3A9E8D7B", "alice"))
+	assert.False(t, webhookForUser(event, "0a1b2c3d-8d7b-4c3a-9e8d-7b4c3a9e8d7b", "alice"), "the ID decides when both sides have one")
+	assert.True(t, webhookForUser(event, "", "Alice"))
+	assert.False(t, webhookForUser(event, "", "bob"))
+
+	assert.False(t, webhookForUser(&models.WebhookEvent{}, "", "alice"), "events without a user can't be attributed")
+	assert.True(t, webhookForUser(&models.WebhookEvent{UserName: "bob"}, "", ""), "clients without a user take every event")
+}
+
+func TestParsePlexWebhook(t *testing.T) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	require.NoError(t, writer.WriteField("payload", `{
+		"event": "media.pause",
+		"Account": {"id": 1, "title": "alice"},
+		"Metadata": {"ratingKey": "42", "type": "episode", "title": "Pilot", "viewOffset": 90000, "duration": 360000}
+	}`))
+	require.NoError(t, writer.Close())
+
+	event, err := parsePlexWebhook(writer.FormDataContentType(), buf.Bytes())
+	require.NoError(t, err)
+
+	assert.Equal(t, models.WebhookEventPause, event.Event)
+	assert.Equal(t, mediatypes.MediaTypeEpisode, event.MediaType)
+	assert.Equal(t, "42", event.ItemID)
+	assert.Equal(t, "alice", event.UserName)
+	assert.Equal(t, 90, event.PositionSeconds)
+	assert.Equal(t, 360, event.DurationSeconds)
+	assert.InDelta(t, 25.0, event.PlayedPercentage(), 0.01)
+}
+
+func TestParseJellyfinWebhook(t *testing.T) {
+	event, err := parseJellyfinWebhook([]byte(`{
+		"NotificationType": "PlaybackStop",
+		"ItemId": "abc123",
+		"ItemType": "Movie",
+		"PlaybackPositionTicks": 72000000000,
+		"RunTimeTicks": 72000000000,
+		"PlayedToCompletion": true,
+		"UserId": "4f1c2a9e8d7b4c3a9e8d7b4c3a9e8d7b",
+		"NotificationUsername": "alice"
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, models.WebhookEventScrobble, event.Event)
+	assert.Equal(t, mediatypes.MediaTypeMovie, event.MediaType)
+	assert.True(t, event.Completed)
+	assert.Equal(t, 7200, event.DurationSeconds)
+	assert.Equal(t, "4f1c2a9e8d7b4c3a9e8d7b4c3a9e8d7b", event.UserID)
+	assert.Equal(t, "alice", event.UserName)
+}
+
+type mockWebhookClientRepositories struct {
+	repobundles.ClientRepositories
+	clients *models.MediaClientList
+}
+
+func (m *mockWebhookClientRepositories) GetAllMediaClients(ctx context.Context) (*models.MediaClientList, error) {
+	return m.clients, nil
+}
+
+// mockItemSyncer records the items it was asked to sync
+type mockItemSyncer struct {
+	synced []mediatypes.MediaType
+}
+
+func (m *mockItemSyncer) SyncItem(ctx context.Context, clientID uint64, mediaType mediatypes.MediaType, clientItemID string) error {
+	m.synced = append(m.synced, mediaType)
+	return nil
+}
+
+func TestHandleWebhookLibraryNew(t *testing.T) {
+	clientList := models.NewMediaClientList()
+	jellyfin := clienttypes.NewJellyfinConfig("", "", "http://jellyfin", "key", true, false)
+	clientList.AddJellyfin(&models.Client[*clienttypes.JellyfinConfig]{BaseModel: models.BaseModel{ID: 7}, UserID: 1, Type: clienttypes.ClientTypeJellyfin, Config: &jellyfin})
+	syncer := &mockItemSyncer{}
+	service := NewWebhookService("http://localhost:8080", nil, &mockWebhookClientRepositories{clients: clientList}, nil, nil, syncer)
+
+	for _, itemType := range []string{"Movie", "Season", "MusicAlbum"} {
+		event, err := service.HandleWebhook(context.Background(), 7, "application/json",
+			[]byte(`{"NotificationType": "ItemAdded", "ItemId": "abc123", "ItemType": "`+itemType+`"}`))
+		require.NoError(t, err, "new %s items are acknowledged", itemType)
+		assert.Equal(t, models.WebhookEventLibraryNew, event.Event)
+	}
+
+	assert.Equal(t, []mediatypes.MediaType{mediatypes.MediaTypeMovie}, syncer.synced, "seasons and albums are left to the library sync")
+}
@@ -65,4 +65,12 @@ type Configuration struct {
 		TokenIssuer         string `json:"tokenIssuer" mapstructure:"tokenIssuer" example:"suasor-api" binding:"required"`
 		TokenAudience       string `json:"tokenAudience" mapstructure:"tokenAudience" example:"suasor-client" binding:"required"`
 	} `json:"auth"`
+
+	// AI contains the routing policy that picks an AI client per task
+	AI struct {
+		// Routing lists the AI client types tried for each task in order, tasks without a route use "default"
+		Routing map[string][]string `json:"routing" mapstructure:"routing"`
+		// AttemptTimeout is the number of seconds a client has to answer before the next one is tried, 0 disables it
+		AttemptTimeout int `json:"attemptTimeout" mapstructure:"attemptTimeout" example:"120" binding:"min=0"`
+	} `json:"ai"`
 }
@@ -43,4 +43,11 @@ var DefaultConfig = map[string]interface{}{
 	"auth.refreshExpiryDays":   7,
 	"auth.tokenIssuer":         "suasor-api",
 	"auth.tokenAudience":       "suasor-client",
+
+	// AI routing defaults, quick classification and search parsing run on the local model first
+	"ai.routing.default":        []string{"claude", "openai", "ollama"},
+	"ai.routing.recommendation": []string{"claude", "openai", "ollama"},
+	"ai.routing.classification": []string{"ollama", "openai", "claude"},
+	"ai.routing.search":         []string{"ollama", "openai", "claude"},
+	"ai.attemptTimeout":         120,
 }
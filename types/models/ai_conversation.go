@@ -22,6 +22,8 @@ type AIConversation struct {
 	ExpiresAt        sql.NullTime   `json:"expiresAt,omitempty" gorm:"column:expires_at"`
 	MessageCount     int            `json:"messageCount" gorm:"column:message_count;default:0"`
 	LastMessageTime  sql.NullTime   `json:"lastMessageTime,omitempty" gorm:"column:last_message_time"`
+	// Memory summarises the turns compacted out of a long conversation, nil until the first compaction
+	Memory           *ConversationMemory `json:"memory,omitempty" gorm:"column:memory;type:json;serializer:json"`
 	
 	// Relationship fields
 	Messages         []AIMessage         `json:"messages,omitempty" gorm:"foreignKey:ConversationID"`
@@ -29,6 +31,16 @@ type AIConversation struct {
 	Analytics        *AIConversationAnalytics `json:"analytics,omitempty" gorm:"foreignKey:ConversationID"`
 }
 
+// ConversationMemory is what a conversation remembers of the turns compacted out of the history sent to the model
+type ConversationMemory struct {
+	Summary        string   `json:"summary"`
+	LikedTitles    []string `json:"likedTitles"`
+	DislikedTitles []string `json:"dislikedTitles"`
+	// CompactedMessages is the number of messages, oldest first, the summary stands in for
+	CompactedMessages int       `json:"compactedMessages"`
+	CompactedAt       time.Time `json:"compactedAt"`
+}
+
 // TableName specifies the table name for GORM
 func (AIConversation) TableName() string {
 	return "ai_conversations"
@@ -89,7 +101,7 @@ func NewAIConversation(id string, userID, clientID uint64, contentType, systemPr
 type AIMessage struct {
 	ID              string         `json:"id" gorm:"primaryKey;type:varchar(36)"`
 	ConversationID  string         `json:"conversationId" gorm:"column:conversation_id;not null;index;type:varchar(36)"`
-	Role            string         `json:"role" gorm:"column:role;not null;type:varchar(20)"` // "user" or "assistant"
+	Role            string         `json:"role" gorm:"column:role;not null;type:varchar(20)"` // "user", "assistant" or "tool"
 	Content         string         `json:"content" gorm:"column:content;not null;type:text"`
 	Timestamp       time.Time      `json:"timestamp" gorm:"column:timestamp;not null;index"`
 	Metadata        sql.NullString `json:"metadata,omitempty" gorm:"column:metadata;type:json"` // JSON storage for optional metadata
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"suasor/clients/types"
+	"time"
+)
+
+// AIFeature is the part of Suasor that made an AI request, it is also the task the AI router picks clients for
+type AIFeature string
+
+const (
+	AIFeatureConversation   AIFeature = "conversation"
+	AIFeatureRecommendation AIFeature = "recommendation"
+	AIFeatureAnalysis       AIFeature = "analysis"
+	AIFeatureClassification AIFeature = "classification"
+	AIFeatureSearch         AIFeature = "search"
+)
+
+// AIUsageRecord is the token usage of a single AI request
+type AIUsageRecord struct {
+	BaseModel
+	UserID           uint64           `json:"userId" gorm:"not null;index"`
+	ClientID         uint64           `json:"clientId" gorm:"index"`
+	ClientType       types.ClientType `json:"clientType" gorm:"type:varchar(50)"`
+	Model            string           `json:"model" gorm:"type:varchar(100)"`
+	Feature          AIFeature        `json:"feature" gorm:"type:varchar(50);index"`
+	PromptTokens     int64            `json:"promptTokens"`
+	CompletionTokens int64            `json:"completionTokens"`
+	TotalTokens      int64            `json:"totalTokens"`
+}
+
+// AIBudget limits the tokens a user may spend, a budget with UserID 0 limits the whole household.
+// A limit of 0 is unlimited.
+type AIBudget struct {
+	BaseModel
+	UserID        uint64 `json:"userId" gorm:"uniqueIndex"`
+	DailyTokens   int64  `json:"dailyTokens"`
+	MonthlyTokens int64  `json:"monthlyTokens"`
+}
+
+// AIUsageSummary is the token usage of one user, client, model and feature in a report
+type AIUsageSummary struct {
+	UserID           uint64           `json:"userId"`
+	ClientID         uint64           `json:"clientId"`
+	ClientType       types.ClientType `json:"clientType"`
+	Model            string           `json:"model"`
+	Feature          AIFeature        `json:"feature"`
+	Requests         int64            `json:"requests"`
+	PromptTokens     int64            `json:"promptTokens"`
+	CompletionTokens int64            `json:"completionTokens"`
+	TotalTokens      int64            `json:"totalTokens"`
+}
+
+// AIUserSpend is a user's token usage in a report with the breakdown per client, model and feature
+type AIUserSpend struct {
+	UserID      uint64            `json:"userId"`
+	Username    string            `json:"username"`
+	Requests    int64             `json:"requests"`
+	TotalTokens int64             `json:"totalTokens"`
+	Budget      *AIBudget         `json:"budget,omitempty"`
+	Breakdown   []*AIUsageSummary `json:"breakdown"`
+}
+
+// AIUsageReport is the token usage of the household between two dates
+type AIUsageReport struct {
+	Start        time.Time      `json:"start"`
+	End          time.Time      `json:"end"`
+	TotalTokens  int64          `json:"totalTokens"`
+	GlobalBudget *AIBudget      `json:"globalBudget,omitempty"`
+	Users        []*AIUserSpend `json:"users"`
+}
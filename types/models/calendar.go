@@ -0,0 +1,115 @@
+package models
+
+import (
+	mediatypes "suasor/clients/media/types"
+	client "suasor/clients/types"
+	"time"
+)
+
+// CalendarSource is where a calendar entry came from
+type CalendarSource string
+
+const (
+	// CalendarSourceAutomation entries come from Radarr, Sonarr or Lidarr
+	CalendarSourceAutomation CalendarSource = "automation"
+	// CalendarSourceMetadata entries come from upcoming and recent releases on the metadata client
+	CalendarSourceMetadata CalendarSource = "metadata"
+	// CalendarSourceRecommendation entries are releases of items recommended to the user
+	CalendarSourceRecommendation CalendarSource = "recommendation"
+	// CalendarSourceWatchlist entries are releases of items on the user's watchlist
+	CalendarSourceWatchlist CalendarSource = "watchlist"
+)
+
+// ParseCalendarSource maps a source name from a query string, "tmdb" is accepted for metadata
+func ParseCalendarSource(value string) (CalendarSource, bool) {
+	switch CalendarSource(value) {
+	case CalendarSourceAutomation, CalendarSourceMetadata, CalendarSourceRecommendation, CalendarSourceWatchlist:
+		return CalendarSource(value), true
+	}
+	switch value {
+	case "tmdb":
+		return CalendarSourceMetadata, true
+	case "recommendations":
+		return CalendarSourceRecommendation, true
+	}
+	return "", false
+}
+
+// CalendarEntry is a single release in a user's calendar
+type CalendarEntry struct {
+	Title       string                 `json:"title"`
+	Overview    string                 `json:"overview,omitempty"`
+	MediaType   mediatypes.MediaType   `json:"mediaType"`
+	ReleaseDate time.Time              `json:"releaseDate"`
+	ExternalIDs mediatypes.ExternalIDs `json:"externalIDs,omitempty"`
+	// Sources lists every feed the release was found in
+	Sources []CalendarSource `json:"sources"`
+	// Automation client tracking the release, only set for automation entries
+	ClientID   uint64            `json:"clientID,omitempty"`
+	ClientType client.ClientType `json:"clientType,omitempty"`
+	Monitored  bool              `json:"monitored,omitempty"`
+	// MediaItemID is the local media item, only set for watchlist and recommendation entries
+	MediaItemID uint64 `json:"mediaItemID,omitempty"`
+}
+
+// HasSource returns true if the entry was found in the given feed
+func (e *CalendarEntry) HasSource(source CalendarSource) bool {
+	for _, s := range e.Sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// CalendarFilter limits a calendar to some media types and sources, an empty list allows everything
+type CalendarFilter struct {
+	MediaTypes []mediatypes.MediaType `json:"mediaTypes,omitempty"`
+	Sources    []CalendarSource       `json:"sources,omitempty"`
+}
+
+// IncludesSource returns true if entries from the source should be fetched
+func (f *CalendarFilter) IncludesSource(source CalendarSource) bool {
+	if f == nil || len(f.Sources) == 0 {
+		return true
+	}
+	for _, s := range f.Sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches returns true if the entry has an allowed media type and was found in an allowed source
+func (f *CalendarFilter) Matches(entry *CalendarEntry) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.MediaTypes) > 0 {
+		allowed := false
+		for _, mediaType := range f.MediaTypes {
+			if mediaType == entry.MediaType {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	for _, source := range entry.Sources {
+		if f.IncludesSource(source) {
+			return true
+		}
+	}
+	return len(entry.Sources) == 0
+}
+
+// CalendarFeedToken is the secret in a user's ICS feed URL.
+// Rotating it breaks every existing subscription.
+type CalendarFeedToken struct {
+	BaseModel
+	UserID uint64 `json:"userID" gorm:"uniqueIndex;not null"`
+	Token  string `json:"-" gorm:"uniqueIndex;size:64;not null"`
+}
@@ -346,6 +346,7 @@ func NewAutomationClientList() *AutomationClientList {
 		Sonarr: map[uint64]*Client[*client.SonarrConfig]{},
 		Radarr: map[uint64]*Client[*client.RadarrConfig]{},
 		Lidarr: map[uint64]*Client[*client.LidarrConfig]{},
+		IDs:    map[uint64]client.ClientType{},
 		Total:  0,
 	}
 }
@@ -0,0 +1,41 @@
+package models
+
+// GroupStrategy is how the fit of each member of a group is combined into the group's score of an item
+type GroupStrategy string
+
+const (
+	// GroupStrategyLeastMisery scores an item by the member it fits least, so nobody is left out
+	GroupStrategyLeastMisery GroupStrategy = "least_misery"
+	// GroupStrategyAverage scores an item by the average fit of the members
+	GroupStrategyAverage GroupStrategy = "average"
+)
+
+// IsValid reports whether the strategy is one of the known group strategies
+func (s GroupStrategy) IsValid() bool {
+	switch s {
+	case GroupStrategyLeastMisery, GroupStrategyAverage:
+		return true
+	}
+	return false
+}
+
+// HouseholdGroup is a saved group of users who watch together, such as a household's movie night
+type HouseholdGroup struct {
+	BaseModel
+	OwnerID uint64 `json:"ownerID" gorm:"index;not null"`
+	Name    string `json:"name" gorm:"not null"`
+	// MemberIDs are the users of the group, the owner included
+	MemberIDs []uint64 `json:"memberIDs" gorm:"type:jsonb;serializer:json"`
+	// Strategy is the group's default strategy, least misery when empty
+	Strategy GroupStrategy `json:"strategy,omitempty" gorm:"type:varchar(20)"`
+}
+
+// HasMember reports whether the user is a member of the group
+func (g *HouseholdGroup) HasMember(userID uint64) bool {
+	for _, memberID := range g.MemberIDs {
+		if memberID == userID {
+			return true
+		}
+	}
+	return false
+}
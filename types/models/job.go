@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	clienttypes "suasor/clients/types"
 	"time"
 )
@@ -18,6 +19,7 @@ const (
 	SyncTypeFavorites   SyncType = "favorites"
 	SyncTypeCollections SyncType = "collections"
 	SyncTypePlaylists   SyncType = "playlists"
+	SyncTypeResume      SyncType = "resume" // Reconciles in-progress positions across all user clients
 	
 	// Special types
 	SyncTypeFull        SyncType = "full"    // Syncs everything from all clients
@@ -31,7 +33,7 @@ func (s SyncType) IsValid() bool {
 	switch s {
 	case SyncTypeMovies, SyncTypeSeries, SyncTypeMusic,
 		SyncTypeHistory, SyncTypeFavorites, SyncTypeCollections, SyncTypePlaylists,
-		SyncTypeFull:
+		SyncTypeResume, SyncTypeFull:
 		return true
 	default:
 		return false
@@ -136,3 +138,73 @@ type MediaSyncJob struct {
 	// Sync filter criteria (stored as JSON)
 	Filters string `json:"filters" gorm:"type:jsonb;default:'{}'"`
 }
+
+// MediaSyncFilters is the filter criteria stored in MediaSyncJob.Filters
+type MediaSyncFilters struct {
+	// Libraries to sync, empty means every library on the client.
+	// Only the sync job applies it, other calls to the client pass their own QueryOptions.LibraryIDs.
+	LibraryIDs []string `json:"libraryIDs,omitempty"`
+}
+
+// GetFilters parses the job's filter criteria
+func (j *MediaSyncJob) GetFilters() (*MediaSyncFilters, error) {
+	filters := &MediaSyncFilters{}
+	if j.Filters == "" {
+		return filters, nil
+	}
+	if err := json.Unmarshal([]byte(j.Filters), filters); err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+// SetFilters stores the job's filter criteria
+func (j *MediaSyncJob) SetFilters(filters *MediaSyncFilters) error {
+	if filters == nil {
+		filters = &MediaSyncFilters{}
+	}
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+	j.Filters = string(data)
+	return nil
+}
+
+// SyncCheckpoint records how far a client has been synced for a single sync type,
+// so that later runs only need to ask for items added or updated since then
+type SyncCheckpoint struct {
+	BaseModel
+	// ID of the client the checkpoint belongs to
+	ClientID uint64 `json:"clientID" gorm:"uniqueIndex:idx_sync_checkpoint_client_type;not null"`
+	// Type of media the checkpoint covers
+	SyncType SyncType `json:"syncType" gorm:"uniqueIndex:idx_sync_checkpoint_client_type;not null"`
+	// Newest added or updated time seen on the client, used as DateAddedAfter on the next run
+	HighWaterMark *time.Time `json:"highWaterMark"`
+	// Last time any sync ran for this client and type
+	LastSyncTime *time.Time `json:"lastSyncTime"`
+	// Last time the full library was pulled
+	LastFullSyncTime *time.Time `json:"lastFullSyncTime"`
+	// Last time deleted items were reconciled
+	LastReconcileTime *time.Time `json:"lastReconcileTime"`
+	// Number of items seen on the client during the last full sync or reconciliation
+	ItemCount int `json:"itemCount"`
+	// Libraries the checkpoint covers, changing the selection forces a full sync
+	Libraries string `json:"libraries"`
+}
+
+// NeedsFullSync returns true when there is no usable high-water mark or the last full sync is older than maxAge
+func (c *SyncCheckpoint) NeedsFullSync(maxAge time.Duration) bool {
+	if c == nil || c.HighWaterMark == nil || c.LastFullSyncTime == nil {
+		return true
+	}
+	return time.Since(*c.LastFullSyncTime) >= maxAge
+}
+
+// NeedsReconcile returns true when deleted items have not been checked for within interval
+func (c *SyncCheckpoint) NeedsReconcile(interval time.Duration) bool {
+	if c == nil || c.LastReconcileTime == nil {
+		return true
+	}
+	return time.Since(*c.LastReconcileTime) >= interval
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	client "suasor/clients/types"
+)
+
+// MediaLibraryType is the kind of content a library holds
+type MediaLibraryType string
+
+const (
+	MediaLibraryTypeMovies MediaLibraryType = "movies"
+	MediaLibraryTypeSeries MediaLibraryType = "series"
+	MediaLibraryTypeMusic  MediaLibraryType = "music"
+	MediaLibraryTypeMixed  MediaLibraryType = "mixed"
+	MediaLibraryTypeOther  MediaLibraryType = "other"
+)
+
+// MediaLibrary is a library, section or music folder on a media client
+type MediaLibrary struct {
+	ClientID   uint64            `json:"clientID"`
+	ClientType client.ClientType `json:"clientType"`
+	// LibraryID is the client's own ID for the library, used in MediaSyncFilters
+	LibraryID string           `json:"libraryID"`
+	Name      string           `json:"name"`
+	Type      MediaLibraryType `json:"type"`
+	// ItemCount is only set when the client reports it
+	ItemCount int `json:"itemCount,omitempty"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"suasor/clients/media/types"
+)
+
+// MediaItemEmbedding is the embedding of a media item's title, description, genres and credits.
+// Vectors of different models cannot be compared, so an item has one embedding per model.
+type MediaItemEmbedding struct {
+	BaseModel
+	MediaItemID uint64          `json:"mediaItemID" gorm:"not null;uniqueIndex:idx_media_item_embedding_model"`
+	Model       string          `json:"model" gorm:"type:varchar(100);not null;uniqueIndex:idx_media_item_embedding_model"`
+	MediaType   types.MediaType `json:"mediaType" gorm:"type:varchar(50);index"`
+	Dimensions  int             `json:"dimensions"`
+	// ContentHash is the SHA-256 of the embedded text, items whose text did not change are not embedded again
+	ContentHash string `json:"contentHash" gorm:"type:varchar(64)"`
+	Vector      Vector `json:"-" gorm:"not null"`
+}
+
+// EmbeddingMatch is a media item found by a similarity query
+type EmbeddingMatch struct {
+	MediaItemID uint64          `json:"mediaItemID"`
+	MediaType   types.MediaType `json:"mediaType"`
+	Score       float64         `json:"score"` // Cosine similarity, 1 is the most similar
+	// Item is the matched *MediaItem, set by the service
+	Item any `json:"item,omitempty"`
+}
+
+// Vector is an embedding stored as little-endian float32 bytes, which works on both Postgres and SQLite
+type Vector []float32
+
+// GormDataType maps the vector to bytea on Postgres and blob on SQLite
+func (Vector) GormDataType() string {
+	return "bytes"
+}
+
+// Value implements the driver.Valuer interface for Vector
+func (v Vector) Value() (driver.Value, error) {
+	b := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(f))
+	}
+	return b, nil
+}
+
+// Scan implements the sql.Scanner interface for Vector
+func (v *Vector) Scan(value any) error {
+	var b []byte
+	switch data := value.(type) {
+	case []byte:
+		b = data
+	case string:
+		b = []byte(data)
+	case nil:
+		*v = nil
+		return nil
+	default:
+		return errors.New("type assertion to []byte failed")
+	}
+	if len(b)%4 != 0 {
+		return fmt.Errorf("invalid vector length: %d bytes", len(b))
+	}
+	vector := make(Vector, len(b)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	*v = vector
+	return nil
+}
+
+// CosineSimilarity returns the cosine of the angle between two vectors, or 0 if their sizes differ or one is zero
+func (v Vector) CosineSimilarity(other Vector) float64 {
+	if len(v) != len(other) || len(v) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range v {
+		a, b := float64(v[i]), float64(other[i])
+		dot += a * b
+		normA += a * a
+		normB += b * b
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"suasor/clients/media/types"
+)
+
+// MediaItemNeighbor is an item that the users who watched or rated a media item also watched or rated alike.
+// Neighbours are precomputed across all users by the collaborative filtering job.
+type MediaItemNeighbor struct {
+	BaseModel
+	MediaItemID uint64          `json:"mediaItemID" gorm:"not null;uniqueIndex:idx_media_item_neighbor"`
+	NeighborID  uint64          `json:"neighborID" gorm:"not null;uniqueIndex:idx_media_item_neighbor"`
+	MediaType   types.MediaType `json:"mediaType" gorm:"type:varchar(50);index"`
+	// Score is the similarity of the items from 0 to 1, shrunk towards 0 when few users share them
+	Score float64 `json:"score"`
+	// Support is the number of users who watched or rated both items
+	Support int `json:"support"`
+}
+
+// ItemInteraction is what a user's play history says about a media item, as collaborative filtering reads it
+type ItemInteraction struct {
+	UserID           uint64          `json:"userID"`
+	MediaItemID      uint64          `json:"mediaItemID"`
+	Type             types.MediaType `json:"type"`
+	PlayCount        int32           `json:"playCount"`
+	PlayedPercentage float64         `json:"playedPercentage"`
+	Completed        bool            `json:"completed"`
+	IsFavorite       bool            `json:"isFavorite"`
+	IsDisliked       bool            `json:"isDisliked"`
+	UserRating       float32         `json:"userRating"`
+}
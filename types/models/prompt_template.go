@@ -0,0 +1,28 @@
+package models
+
+import "suasor/utils/prompts"
+
+// PromptTemplate is one version of a prompt template. Versions are never edited, saving a template adds
+// a version and makes it the active one. Templates without a version use the built-in default, version 0.
+type PromptTemplate struct {
+	BaseModel
+	Name string `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_prompt_template_version"`
+	// UserID is the owner of a custom personality, 0 for the templates shared by everyone
+	UserID  uint64 `json:"userId" gorm:"not null;default:0;uniqueIndex:idx_prompt_template_version"`
+	Version int    `json:"version" gorm:"not null;uniqueIndex:idx_prompt_template_version"`
+	Body    string `json:"body" gorm:"type:text;not null"`
+	Active  bool   `json:"active" gorm:"index;default:false"`
+	// CreatedBy is the user who saved the version
+	CreatedBy uint64 `json:"createdBy"`
+}
+
+// PromptTemplateInfo is a template with its typed variables and the version in use
+type PromptTemplateInfo struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Variables   []prompts.Variable `json:"variables"`
+	// ActiveVersion is 0 while the built-in default is in use
+	ActiveVersion int    `json:"activeVersion"`
+	Body          string `json:"body"`
+	DefaultBody   string `json:"defaultBody"`
+}
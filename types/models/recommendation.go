@@ -37,11 +37,15 @@ type Recommendation struct {
 	RecommendedBy    string          `json:"recommendedBy" gorm:"not null"` // "AI", "popular", "similar_users"
 	AIModel          string          `json:"aiModel,omitempty"`             // AI model used if recommendedBy="AI"
 	JobRunID         uint64          `json:"jobRunID,omitempty"`            // Job run that created this recommendation
+	PromptTemplate   string          `json:"promptTemplate,omitempty"`      // Prompt template the AI was asked with
+	PromptVersion    int             `json:"promptVersion"`                 // Version of the prompt template, 0 for the built-in default
 	CreatedAt        time.Time       `json:"createdAt" gorm:"not null"`
 	ExpiresAt        *time.Time      `json:"expiresAt,omitempty"`           // When this recommendation expires
 	IsViewed         bool            `json:"isViewed" gorm:"default:false"` // Whether user has viewed this item
 	UserRating       float32         `json:"userRating" gorm:"default:0"`   // If user has rated this recommendation
 	ExternalIDs      *ExternalIDMap  `json:"externalIDs" gorm:"type:jsonb;serializer:json"`
+	PosterURL        string          `json:"posterURL,omitempty"`
+	BackdropURL      string          `json:"backdropURL,omitempty"`
 	// Source of the recommendation (AI, system, manual)
 	Source RecommendationSource `json:"source" gorm:"index;not null"`
 	// ID of the client that generated this recommendation, if applicable
@@ -58,6 +62,8 @@ type Recommendation struct {
 	Active bool `json:"active" gorm:"index;default:true"`
 	// Additional metadata (stored as JSON)
 	Metadata string `json:"metadata" gorm:"type:jsonb"`
+	// Position in the user's ranked list of the media type from 1, 0 for the lists of single recommenders
+	Rank int `json:"rank,omitempty" gorm:"index;default:0"`
 }
 
 // StringArray is a string array for JSONB storage
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"suasor/clients/media/types"
+)
+
+// FeedbackType is what a user did with a recommendation
+type FeedbackType string
+
+const (
+	// FeedbackTypeDismissed the user dismissed the recommendation
+	FeedbackTypeDismissed FeedbackType = "dismissed"
+	// FeedbackTypeRated the user rated the recommendation
+	FeedbackTypeRated FeedbackType = "rated"
+	// FeedbackTypePlayed the user clicked through to play the recommendation
+	FeedbackTypePlayed FeedbackType = "played"
+	// FeedbackTypeViewed the user opened the recommendation
+	FeedbackTypeViewed FeedbackType = "viewed"
+)
+
+// DismissReason is why a user dismissed a recommendation
+type DismissReason string
+
+const (
+	// DismissReasonSeenIt the user has already seen the title, it says nothing about their taste
+	DismissReasonSeenIt DismissReason = "seen_it"
+	// DismissReasonNotInterested the user doesn't want the title
+	DismissReasonNotInterested DismissReason = "not_interested"
+	// DismissReasonDislikeGenre the user doesn't want the title's genres
+	DismissReasonDislikeGenre DismissReason = "dislike_genre"
+)
+
+// IsValid reports whether the reason is one of the known dismiss reasons
+func (r DismissReason) IsValid() bool {
+	switch r {
+	case DismissReasonSeenIt, DismissReasonNotInterested, DismissReasonDislikeGenre:
+		return true
+	}
+	return false
+}
+
+// RecommendationFeedback is a signal a user gave on one of their recommendations.
+// The recommendation's item is copied so the feedback outlives the recommendation, which the jobs replace.
+type RecommendationFeedback struct {
+	BaseModel
+	UserID           uint64          `json:"userID" gorm:"index;not null"`
+	RecommendationID uint64          `json:"recommendationID" gorm:"index"`
+	MediaItemID      uint64          `json:"mediaItemID" gorm:"index"`
+	MediaType        types.MediaType `json:"mediaType" gorm:"index;not null"`
+	Title            string          `json:"title"`
+	Year             int             `json:"year,omitempty"`
+	Genres           StringArray     `json:"genres" gorm:"type:jsonb;serializer:json"`
+	ExternalIDs      *ExternalIDMap  `json:"externalIDs" gorm:"type:jsonb;serializer:json"`
+	// RecommendedBy is the recommender that made the recommendation
+	RecommendedBy string       `json:"recommendedBy"`
+	Type          FeedbackType `json:"type" gorm:"type:varchar(20);index;not null"`
+	// Reason is why the recommendation was dismissed, empty for other feedback
+	Reason DismissReason `json:"reason,omitempty" gorm:"type:varchar(20)"`
+	// Rating is the user's rating from 0 to 5 for rated feedback
+	Rating float32 `json:"rating,omitempty"`
+}
+
+// NewRecommendationFeedback creates feedback of a type on a recommendation
+func NewRecommendationFeedback(recommendation *Recommendation, feedbackType FeedbackType) *RecommendationFeedback {
+	return &RecommendationFeedback{
+		UserID:           recommendation.UserID,
+		RecommendationID: recommendation.ID,
+		MediaItemID:      recommendation.MediaItemID,
+		MediaType:        recommendation.MediaType,
+		Title:            recommendation.Title,
+		Year:             recommendation.Year,
+		Genres:           recommendation.Genres,
+		ExternalIDs:      recommendation.ExternalIDs,
+		RecommendedBy:    recommendation.RecommendedBy,
+		Type:             feedbackType,
+	}
+}
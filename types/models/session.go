@@ -0,0 +1,57 @@
+package models
+
+import (
+	"suasor/clients/media/types"
+	client "suasor/clients/types"
+)
+
+// PlayMethod describes how a session is being delivered to the player
+type PlayMethod string
+
+const (
+	PlayMethodDirectPlay   PlayMethod = "directplay"
+	PlayMethodDirectStream PlayMethod = "directstream"
+	PlayMethodTranscode    PlayMethod = "transcode"
+	PlayMethodUnknown      PlayMethod = "unknown"
+)
+
+// PlaybackSession represents something currently playing on one of a user's media clients
+type PlaybackSession struct {
+	ClientID   uint64            `json:"clientID"`
+	ClientType client.ClientType `json:"clientType"`
+	SessionID  string            `json:"sessionID"`
+
+	// Who is watching and where
+	UserName   string `json:"userName"`
+	DeviceName string `json:"deviceName"`
+	DeviceID   string `json:"deviceID,omitempty"`
+	Player     string `json:"player,omitempty"`
+
+	// What is playing. MediaItemID is only set when the item exists in the local library
+	MediaType    types.MediaType `json:"mediaType"`
+	ClientItemID string          `json:"clientItemID"`
+	Title        string          `json:"title"`
+	MediaItemID  uint64          `json:"mediaItemID,omitempty"`
+	Item         any             `json:"item,omitempty"`
+
+	// Progress
+	PositionSeconds int        `json:"positionSeconds"`
+	DurationSeconds int        `json:"durationSeconds"`
+	Progress        float64    `json:"progress"`
+	IsPaused        bool       `json:"isPaused"`
+	PlayMethod      PlayMethod `json:"playMethod"`
+}
+
+// UpdateProgress calculates the progress percentage from the position and duration
+func (s *PlaybackSession) UpdateProgress() {
+	if s.DurationSeconds <= 0 {
+		s.Progress = 0
+		return
+	}
+	s.Progress = float64(s.PositionSeconds) / float64(s.DurationSeconds) * 100
+}
+
+// IsTranscoding returns true when the server is transcoding the stream
+func (s *PlaybackSession) IsTranscoding() bool {
+	return s.PlayMethod == PlayMethodTranscode
+}
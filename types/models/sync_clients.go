@@ -32,6 +32,15 @@ type SyncClient struct {
 
 type SyncClients []*SyncClient
 
+// ClientSyncResult reports the outcome of pushing a change to a single client
+type ClientSyncResult struct {
+	ClientID   uint64           `json:"clientID"`
+	ClientType types.ClientType `json:"clientType"`
+	ItemID     string           `json:"itemID"`
+	Status     SyncStatus       `json:"status"`
+	Error      string           `json:"error,omitempty"`
+}
+
 func (s *SyncClients) AddClient(clientID uint64, clientType types.ClientType, itemID string) {
 	*s = append(*s, &SyncClient{
 		ID:         clientID,
@@ -135,6 +144,20 @@ func (s *SyncClients) Scan(value any) error {
 	return json.Unmarshal(data, s)
 }
 
+// RemoveClient removes the entry for a client, returning true if one was removed
+func (s *SyncClients) RemoveClient(clientID uint64) bool {
+	if s == nil {
+		return false
+	}
+	for i, client := range *s {
+		if client != nil && client.ID == clientID {
+			*s = append((*s)[:i], (*s)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 func (s *SyncClients) IsClientPresent(clientID uint64) bool {
 	if s == nil {
 		return false
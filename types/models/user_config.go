@@ -190,6 +190,8 @@ type AIModelPreferences struct {
 	DefaultModelForRecommendations string `json:"defaultModelForRecommendations" gorm:"default:''" example:"claude-3-opus-20240229"`
 	DefaultTemperature            float32 `json:"defaultTemperature" gorm:"default:0.7" example:"0.7" binding:"omitempty,min=0,max=1"`
 	DefaultMaxTokens              int `json:"defaultMaxTokens" gorm:"default:4000" example:"4000" binding:"omitempty,min=100,max=100000"`
+	// Routing overrides the server's AI routing policy, it lists the AI client types tried for a task in order
+	Routing map[string][]string `json:"routing,omitempty" example:"recommendation:openai,ollama"`
 }
 
 func (a *AIModelPreferences) Scan(value any) error {
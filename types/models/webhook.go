@@ -0,0 +1,71 @@
+package models
+
+import (
+	mediatypes "suasor/clients/media/types"
+	client "suasor/clients/types"
+)
+
+// WebhookEventType is the normalized type of an event pushed by a media server
+type WebhookEventType string
+
+const (
+	WebhookEventPlay       WebhookEventType = "play"
+	WebhookEventPause      WebhookEventType = "pause"
+	WebhookEventResume     WebhookEventType = "resume"
+	WebhookEventProgress   WebhookEventType = "progress"
+	WebhookEventStop       WebhookEventType = "stop"
+	WebhookEventScrobble   WebhookEventType = "scrobble"
+	WebhookEventRate       WebhookEventType = "rate"
+	WebhookEventFavorite   WebhookEventType = "favorite"
+	WebhookEventLibraryNew WebhookEventType = "library.new"
+	WebhookEventUnknown    WebhookEventType = "unknown"
+)
+
+// IsPlayback returns true for events that carry a playback position
+func (t WebhookEventType) IsPlayback() bool {
+	switch t {
+	case WebhookEventPlay, WebhookEventPause, WebhookEventResume, WebhookEventProgress, WebhookEventStop:
+		return true
+	default:
+		return false
+	}
+}
+
+// WebhookEvent is a Plex, Jellyfin or Emby webhook payload normalized to what Suasor needs
+type WebhookEvent struct {
+	ClientID   uint64               `json:"clientId"`
+	ClientType client.ClientType    `json:"clientType"`
+	Event      WebhookEventType     `json:"event"`
+	RawEvent   string               `json:"rawEvent"`
+	MediaType  mediatypes.MediaType `json:"mediaType"`
+	ItemID     string               `json:"itemId"`
+	Title      string               `json:"title,omitempty"`
+	// The media server user the event is for, empty for server wide events
+	UserID   string `json:"userId,omitempty"`
+	UserName string `json:"userName,omitempty"`
+	// Playback position, only set for playback events
+	PositionSeconds int  `json:"positionSeconds,omitempty"`
+	DurationSeconds int  `json:"durationSeconds,omitempty"`
+	Completed       bool `json:"completed,omitempty"`
+	// User data, only set for rate and favorite events
+	Rating   float32 `json:"rating,omitempty"`
+	Favorite bool    `json:"favorite,omitempty"`
+	// MediaItemID is the local media item the event was applied to
+	MediaItemID uint64 `json:"mediaItemId,omitempty"`
+}
+
+// PlayedPercentage returns how far into the item the position is
+func (e *WebhookEvent) PlayedPercentage() float64 {
+	if e.DurationSeconds <= 0 {
+		return 0
+	}
+	return float64(e.PositionSeconds) / float64(e.DurationSeconds) * 100
+}
+
+// WebhookToken is the secret in a client's webhook URL.
+// Rotating it breaks the webhook configured on the media server.
+type WebhookToken struct {
+	BaseModel
+	ClientID uint64 `json:"clientID" gorm:"uniqueIndex;not null"`
+	Token    string `json:"-" gorm:"uniqueIndex;size:64;not null"`
+}
@@ -196,3 +196,30 @@ type GetUserRecommendationsRequest struct {
 	// example: desc
 	SortDir string `form:"sortDir,default=desc"`
 }
+
+// AIBudgetRequest sets the token budget of a user or of the household
+// @Description Daily and monthly AI token limits, 0 is unlimited
+type AIBudgetRequest struct {
+	// Tokens that may be spent per day
+	// example: 50000
+	DailyTokens int64 `json:"dailyTokens" binding:"min=0"`
+
+	// Tokens that may be spent per calendar month
+	// example: 1000000
+	MonthlyTokens int64 `json:"monthlyTokens" binding:"min=0"`
+}
+
+// PromptTemplateRequest saves a new version of a prompt template
+// @Description Body of a prompt template in Go text/template syntax
+type PromptTemplateRequest struct {
+	// Template body, it may only use the template's variables
+	// example: You are an expert {{.ContentType}} recommendation assistant.
+	Body string `json:"body" binding:"required"`
+}
+
+// PromptVersionRequest picks the active version of a prompt template
+// @Description Version to make active, 0 restores the built-in default
+type PromptVersionRequest struct {
+	// example: 2
+	Version int `json:"version" binding:"min=0"`
+}
@@ -15,6 +15,8 @@ type SetupMediaSyncJobRequest struct {
 	ClientType string          `json:"clientType" binding:"required"`
 	SyncType   models.SyncType `json:"syncType" binding:"required"`
 	Frequency  string          `json:"frequency" binding:"required"`
+	// Filters limits the sync, for example to selected libraries. Omit to keep the current filters.
+	Filters *models.MediaSyncFilters `json:"filters,omitempty"`
 }
 
 // RunMediaSyncJobRequest represents a request to run a media sync job
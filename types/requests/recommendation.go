@@ -30,4 +30,34 @@ type MarkRecommendationAsViewedRequest struct {
 type RateRecommendationRequest struct {
 	RecommendationID uint64  `json:"recommendationId" binding:"required" example:"123"`
 	Rating           float32 `json:"rating" binding:"required,min=0,max=5" example:"4.5"`
-}
\ No newline at end of file
+}
+
+// DismissRecommendationRequest represents a request to dismiss a recommendation
+type DismissRecommendationRequest struct {
+	RecommendationID uint64 `json:"recommendationId" binding:"required" example:"123"`
+	// Reason is why the recommendation is dismissed, not_interested when empty
+	Reason string `json:"reason" binding:"omitempty,oneof=seen_it not_interested dislike_genre" example:"not_interested"`
+}
+
+// RecordRecommendationPlayRequest represents a request to record that a recommendation was played
+type RecordRecommendationPlayRequest struct {
+	RecommendationID uint64 `json:"recommendationId" binding:"required" example:"123"`
+}
+
+// GroupRecommendationsRequest represents a request for recommendations for a group to watch together.
+// The group is either a saved group or the requesting user and the given users, one of them is required.
+type GroupRecommendationsRequest struct {
+	UserIDs   []uint64 `json:"userIds" binding:"max=20" example:"2,3"`
+	GroupID   uint64   `json:"groupId" example:"1"`
+	MediaType string   `json:"mediaType" binding:"omitempty,oneof=movie series" example:"movie"`
+	// Strategy is how the members' fit is combined, the saved group's strategy or least_misery when empty
+	Strategy string `json:"strategy" binding:"omitempty,oneof=least_misery average" example:"least_misery"`
+	Limit    int    `json:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+}
+
+// CreateHouseholdGroupRequest represents a request to save a group of users who watch together
+type CreateHouseholdGroupRequest struct {
+	Name      string   `json:"name" binding:"required,max=100" example:"Movie night"`
+	MemberIDs []uint64 `json:"memberIds" binding:"required,min=1,max=20" example:"2,3"`
+	Strategy  string   `json:"strategy" binding:"omitempty,oneof=least_misery average" example:"average"`
+}
@@ -30,6 +30,27 @@ type MediaDataList[T types.MediaData] struct {
 	Total int                            `json:"total"`
 }
 
+// ClientWriteBackResponse reports the per-client outcome of pushing a change to linked clients
+type ClientWriteBackResponse struct {
+	Results   []models.ClientSyncResult `json:"results"`
+	Succeeded int                       `json:"succeeded"`
+	Failed    int                       `json:"failed"`
+}
+
+func NewClientWriteBackResponse(results []models.ClientSyncResult) ClientWriteBackResponse {
+	response := ClientWriteBackResponse{
+		Results: results,
+	}
+	for _, result := range results {
+		if result.Status == models.SyncStatusFailed {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+	return response
+}
+
 // Convenience functions for success responses
 func RespondMediaItemListOK[T types.MediaData](c *gin.Context, data []*models.MediaItem[T], message ...string) {
 	msg := "Success"
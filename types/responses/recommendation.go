@@ -25,6 +25,7 @@ type RecommendationResponse struct {
 	ExternalIDs      map[string]string `json:"externalIds,omitempty"`
 	IsViewed         bool              `json:"isViewed"`
 	UserRating       float32           `json:"userRating,omitempty"`
+	Rank             int               `json:"rank,omitempty"`
 }
 
 // RecommendationsListResponse represents a paginated list of recommendations
@@ -55,6 +56,7 @@ func ConvertToRecommendationResponse(recommendation models.Recommendation) Recom
 		CreatedAt:        recommendation.CreatedAt,
 		IsViewed:         recommendation.IsViewed,
 		UserRating:       recommendation.UserRating,
+		Rank:             recommendation.Rank,
 	}
 
 	// Convert ExternalIDs if present
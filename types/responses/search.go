@@ -25,6 +25,17 @@ type SearchResults struct {
 type SearchResponse struct {
 	Success bool `json:"success"`
 	Results SearchResults
+	// Filters are the filters a natural language query was parsed into, only set for natural searches
+	Filters *SearchFilters `json:"filters,omitempty"`
+}
+
+// SearchFilters are the filters of a natural language search, the UI shows them as editable chips
+type SearchFilters struct {
+	// Parsed is false when no AI client could parse the query and it was searched as plain text
+	Parsed  bool               `json:"parsed"`
+	Options types.QueryOptions `json:"options"`
+	// ClientName is the name of the media client the results are limited to
+	ClientName string `json:"clientName,omitempty"`
 }
 
 // RecentSearchHistoryItem represents a single search history item in responses
@@ -67,6 +78,13 @@ func ConvertToSearchResponse(results SearchResults) SearchResponse {
 	}
 }
 
+// ConvertToNaturalSearchResponse converts natural search results and their filters to API response format
+func ConvertToNaturalSearchResponse(results SearchResults, filters *SearchFilters) SearchResponse {
+	response := ConvertToSearchResponse(results)
+	response.Filters = filters
+	return response
+}
+
 // ConvertToRecentSearchesResponse converts search history to API response format
 func ConvertToRecentSearchesResponse(searches []models.SearchHistory) RecentSearchesResponse {
 	response := RecentSearchesResponse{
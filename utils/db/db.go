@@ -144,6 +144,16 @@ func Initialize(ctx context.Context, dbConfig types.DatabaseConfig) (*gorm.DB, e
 		&models.JobRun{},
 		&models.Recommendation{},
 		&models.MediaSyncJob{},
+		&models.SyncCheckpoint{},
+		&models.CalendarFeedToken{},
+		&models.WebhookToken{},
+		&models.MediaItemEmbedding{},
+		&models.MediaItemNeighbor{},
+		&models.RecommendationFeedback{},
+		&models.HouseholdGroup{},
+		&models.AIUsageRecord{},
+		&models.AIBudget{},
+		&models.PromptTemplate{},
 		
 		// AI Conversation models
 		&models.AIConversation{},
@@ -60,6 +60,16 @@ func InitializeInMemoryDB(ctx context.Context) (*gorm.DB, error) {
 		&models.JobRun{},
 		&models.Recommendation{},
 		&models.MediaSyncJob{},
+		&models.SyncCheckpoint{},
+		&models.CalendarFeedToken{},
+		&models.WebhookToken{},
+		&models.MediaItemEmbedding{},
+		&models.MediaItemNeighbor{},
+		&models.RecommendationFeedback{},
+		&models.HouseholdGroup{},
+		&models.AIUsageRecord{},
+		&models.AIBudget{},
+		&models.PromptTemplate{},
 	); err != nil {
 		return nil, fmt.Errorf("failed to migrate database schema: %w", err)
 	}
@@ -0,0 +1,162 @@
+// Package ical writes RFC 5545 iCalendar feeds
+package ical
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxLineOctets is the longest a content line may be before it has to be folded
+	maxLineOctets = 75
+	dateLayout    = "20060102"
+	stampLayout   = "20060102T150405Z"
+)
+
+// Event is an all-day VEVENT
+type Event struct {
+	// UID must stay the same across refreshes so clients update the event instead of duplicating it
+	UID         string
+	Summary     string
+	Description string
+	Categories  []string
+	Date        time.Time
+}
+
+// Calendar is a VCALENDAR with its events
+type Calendar struct {
+	ProdID string
+	// Name is shown by clients that support X-WR-CALNAME
+	Name string
+	// RefreshInterval is how often subscribers should poll, zero leaves it to the client
+	RefreshInterval time.Duration
+	// Stamp is the DTSTAMP of every event, it defaults to now
+	Stamp  time.Time
+	Events []Event
+}
+
+// Write writes the calendar with CRLF line endings and folded lines
+func (c *Calendar) Write(w io.Writer) error {
+	stamp := c.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + c.ProdID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		interval := formatDuration(c.RefreshInterval)
+		lw.line("REFRESH-INTERVAL;VALUE=DURATION:" + interval)
+		lw.line("X-PUBLISHED-TTL:" + interval)
+	}
+
+	for _, event := range c.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + event.UID)
+		lw.line("DTSTAMP:" + stamp.UTC().Format(stampLayout))
+		// All-day events end on the following day, DTEND is exclusive
+		date := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, time.UTC)
+		lw.line("DTSTART;VALUE=DATE:" + date.Format(dateLayout))
+		lw.line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format(dateLayout))
+		lw.line("SUMMARY:" + escapeText(event.Summary))
+		if event.Description != "" {
+			lw.line("DESCRIPTION:" + escapeText(event.Description))
+		}
+		if len(event.Categories) > 0 {
+			categories := make([]string, len(event.Categories))
+			for i, category := range event.Categories {
+				categories[i] = escapeText(category)
+			}
+			lw.line("CATEGORIES:" + strings.Join(categories, ","))
+		}
+		lw.line("TRANSP:TRANSPARENT")
+		lw.line("END:VEVENT")
+	}
+
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+// Bytes returns the calendar as an ICS document
+func (c *Calendar) Bytes() []byte {
+	var buf bytes.Buffer
+	// Writing to a buffer cannot fail
+	_ = c.Write(&buf)
+	return buf.Bytes()
+}
+
+// lineWriter folds content lines and keeps the first write error
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(content string) {
+	if lw.err != nil {
+		return
+	}
+	_, lw.err = io.WriteString(lw.w, foldLine(content))
+}
+
+// foldLine splits a content line into 75 octet chunks joined by CRLF and a space,
+// never breaking inside a UTF-8 sequence
+func foldLine(content string) string {
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// The leading space of a continuation line counts towards its length
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// escapeText escapes a TEXT value as described in RFC 5545 section 3.3.11
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", "",
+)
+
+// formatDuration formats a duration as an RFC 5545 DURATION in whole minutes
+func formatDuration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	var b strings.Builder
+	b.WriteString("PT")
+	if hours := minutes / 60; hours > 0 {
+		b.WriteString(strconv.Itoa(hours) + "H")
+	}
+	if rest := minutes % 60; rest > 0 {
+		b.WriteString(strconv.Itoa(rest) + "M")
+	}
+	return b.String()
+}
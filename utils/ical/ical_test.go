@@ -0,0 +1,50 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarWrite(t *testing.T) {
+	calendar := &Calendar{
+		ProdID:          "-//Suasor//Calendar//EN",
+		Name:            "Releases",
+		RefreshInterval: 6 * time.Hour,
+		Stamp:           time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Events: []Event{{
+			UID:         "movie-438631@suasor",
+			Summary:     "Dune; Part Two",
+			Description: "Paul unites with Chani, and the Fremen\nto seek revenge",
+			Categories:  []string{"movie"},
+			Date:        time.Date(2025, 6, 30, 20, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	ics := string(calendar.Bytes())
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "REFRESH-INTERVAL;VALUE=DURATION:PT6H\r\n")
+	assert.Contains(t, ics, "DTSTAMP:20250601T120000Z\r\n")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20250630\r\n")
+	assert.Contains(t, ics, "DTEND;VALUE=DATE:20250701\r\n")
+	assert.Contains(t, ics, `SUMMARY:Dune\; Part Two`+"\r\n")
+	assert.Contains(t, ics, `DESCRIPTION:Paul unites with Chani\, and the Fremen\nto seek revenge`+"\r\n")
+}
+
+func TestFoldLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("é", 60)
+	folded := foldLine(line)
+
+	assert.True(t, strings.HasSuffix(folded, "\r\n"))
+	for _, part := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(part), maxLineOctets)
+		assert.True(t, strings.ToValidUTF8(part, "?") == part, "folding must not split a character")
+	}
+
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", "")
+	assert.Equal(t, line, unfolded)
+}
@@ -0,0 +1,114 @@
+package prompts
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Names of the templates the code renders
+const (
+	RecommendationMovie        = "recommendation.movie"
+	RecommendationMovieSystem  = "recommendation.movie.system"
+	RecommendationSeriesSystem = "recommendation.series.system"
+	RecommendationMusicSystem  = "recommendation.music.system"
+	ConversationSystem         = "conversation.system"
+
+	// PersonalityPrefix starts the names of the personality templates, the rest is the user's AiChatPersonality
+	PersonalityPrefix = "personality."
+	// PersonalityCustom is the personality template users write themselves
+	PersonalityCustom = PersonalityPrefix + "custom"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// weighted is the description of list variables of names with weights
+const weighted = "Items with a Name and a Weight, the highest weight first"
+
+// personalityVariables are the variables of every personality template
+var personalityVariables = []Variable{
+	{Name: "ContentType", Type: VariableTypeString, Required: true, Description: "What the conversation recommends, e.g. movies"},
+}
+
+// definitions are the built-in templates without their bodies, which are read from templates/<name>.tmpl
+var definitions = []Definition{
+	{
+		Name:        RecommendationMovie,
+		Description: "Prompt of the movie recommendation job",
+		Variables: []Variable{
+			{Name: "UserID", Type: VariableTypeInt, Required: true},
+			{Name: "Username", Type: VariableTypeString, Required: true},
+			{Name: "FavoriteGenres", Type: VariableTypeList, Description: weighted},
+			{Name: "FavoriteActors", Type: VariableTypeList, Description: weighted},
+			{Name: "FavoriteDirectors", Type: VariableTypeList, Description: weighted},
+			{Name: "RecentMovies", Type: VariableTypeList, Description: "Movies with a Title, Year, Rating and Genres, the most recent first"},
+			{Name: "TopRatedMovies", Type: VariableTypeList, Description: "Movies with a Title, Year, Rating and Genres, the highest rated first"},
+			{Name: "WatchTimes", Type: VariableTypeList, Description: "Hours with a Count of views, the busiest first"},
+			{Name: "HasActivityLevel", Type: VariableTypeBool},
+			{Name: "ActivityLevel", Type: VariableTypeFloat, Description: "Movie activity between 0 and 1"},
+			{Name: "WatchedCount", Type: VariableTypeInt},
+			{Name: "ExcludedGenres", Type: VariableTypeList, Description: "Genre names"},
+			{Name: "ExcludedTitles", Type: VariableTypeList, Description: "Titles with their year the user rejected as recommendations"},
+		},
+	},
+	{Name: RecommendationMovieSystem, Description: "System prompt of the movie recommendation job"},
+	{Name: RecommendationSeriesSystem, Description: "System prompt of the AI series recommendations"},
+	{Name: RecommendationMusicSystem, Description: "System prompt of the AI music recommendations"},
+	{
+		Name:        ConversationSystem,
+		Description: "System prompt of recommendation conversations that don't bring their own",
+		Variables: []Variable{
+			{Name: "ContentType", Type: VariableTypeString, Required: true, Description: "What the conversation recommends, e.g. movies"},
+			{Name: "Personality", Type: VariableTypeString, Description: "The rendered personality template of the user"},
+		},
+	},
+	{Name: PersonalityPrefix + "friendly", Description: "Friendly conversation personality", Variables: personalityVariables},
+	{Name: PersonalityPrefix + "serious", Description: "Serious conversation personality", Variables: personalityVariables},
+	{Name: PersonalityPrefix + "enthusiastic", Description: "Enthusiastic conversation personality", Variables: personalityVariables},
+	{Name: PersonalityPrefix + "analytical", Description: "Analytical conversation personality", Variables: personalityVariables},
+	{Name: PersonalityCustom, Description: "Conversation personality written by the user", Variables: personalityVariables},
+}
+
+// defaults are the built-in templates by name
+var defaults = loadDefaults()
+
+func loadDefaults() map[string]Definition {
+	loaded := make(map[string]Definition, len(definitions))
+	for _, definition := range definitions {
+		body, err := templateFiles.ReadFile("templates/" + definition.Name + ".tmpl")
+		if err != nil {
+			panic(fmt.Sprintf("missing built-in prompt template %s: %v", definition.Name, err))
+		}
+		definition.Body = string(body)
+		loaded[definition.Name] = definition
+	}
+	return loaded
+}
+
+// Default returns the built-in template with the name
+func Default(name string) (Definition, error) {
+	definition, ok := defaults[name]
+	if !ok {
+		return Definition{}, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	return definition, nil
+}
+
+// Defaults returns every built-in template sorted by name
+func Defaults() []Definition {
+	list := make([]Definition, 0, len(defaults))
+	for _, definition := range defaults {
+		list = append(list, definition)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// IsPersonality returns true if the name is a personality template
+func IsPersonality(name string) bool {
+	return strings.HasPrefix(name, PersonalityPrefix)
+}
@@ -0,0 +1,171 @@
+// Package prompts renders the text/template prompts sent to AI clients. Every template declares typed
+// variables that are checked before it is rendered, the defaults are embedded in the binary.
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+var (
+	// ErrUnknownTemplate is returned for a template name without a built-in default
+	ErrUnknownTemplate = errors.New("unknown prompt template")
+	// ErrInvalidTemplate is returned when a template does not parse or uses undeclared variables
+	ErrInvalidTemplate = errors.New("invalid prompt template")
+	// ErrInvalidVariable is returned when a value is missing or has the wrong type
+	ErrInvalidVariable = errors.New("invalid prompt variable")
+)
+
+// VariableType is the type of a template variable
+type VariableType string
+
+const (
+	VariableTypeString VariableType = "string"
+	VariableTypeInt    VariableType = "int"
+	VariableTypeFloat  VariableType = "float"
+	VariableTypeBool   VariableType = "bool"
+	// VariableTypeList is a slice, its elements may be structs the template reads fields of
+	VariableTypeList VariableType = "list"
+)
+
+// Variable is a value a template can use as {{.Name}}
+type Variable struct {
+	Name        string       `json:"name"`
+	Type        VariableType `json:"type"`
+	Required    bool         `json:"required"`
+	Description string       `json:"description,omitempty"`
+}
+
+// Definition is a template the code renders, the variables are the values the code passes to it
+type Definition struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Body        string     `json:"body"`
+	Variables   []Variable `json:"variables"`
+}
+
+// Rendered is the text of a template and the template version it came from, version 0 is the built-in default
+type Rendered struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	Text    string `json:"text"`
+}
+
+// funcs are the functions templates can call besides the text/template builtins
+var funcs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"title": func(s string) string {
+		if s == "" {
+			return s
+		}
+		return strings.ToUpper(s[:1]) + s[1:]
+	},
+}
+
+// Parse parses a template body
+func Parse(name, body string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// Validate checks that a body parses and renders with zero values for its variables, which catches
+// references to variables the template doesn't declare
+func Validate(name, body string, variables []Variable) error {
+	tmpl, err := Parse(name, body)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]any, len(variables))
+	for _, variable := range variables {
+		values[variable.Name] = zeroValue(variable.Type)
+	}
+	if err := tmpl.Execute(&strings.Builder{}, values); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return nil
+}
+
+// Render checks the values against the variables and renders the body
+func Render(name, body string, variables []Variable, values map[string]any) (string, error) {
+	if err := CheckValues(variables, values); err != nil {
+		return "", err
+	}
+
+	tmpl, err := Parse(name, body)
+	if err != nil {
+		return "", err
+	}
+
+	// Optional variables the caller left out render as their zero value
+	data := make(map[string]any, len(variables))
+	for _, variable := range variables {
+		data[variable.Name] = zeroValue(variable.Type)
+	}
+	for key, value := range values {
+		data[key] = value
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("error rendering prompt template %s: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// CheckValues returns ErrInvalidVariable when a required value is missing or a value has the wrong type
+func CheckValues(variables []Variable, values map[string]any) error {
+	for _, variable := range variables {
+		value, ok := values[variable.Name]
+		if !ok || value == nil {
+			if variable.Required {
+				return fmt.Errorf("%w: %s is required", ErrInvalidVariable, variable.Name)
+			}
+			continue
+		}
+		if !hasType(value, variable.Type) {
+			return fmt.Errorf("%w: %s must be a %s, got %T", ErrInvalidVariable, variable.Name, variable.Type, value)
+		}
+	}
+	return nil
+}
+
+func hasType(value any, variableType VariableType) bool {
+	kind := reflect.TypeOf(value).Kind()
+	switch variableType {
+	case VariableTypeString:
+		return kind == reflect.String
+	case VariableTypeInt:
+		return kind >= reflect.Int && kind <= reflect.Uint64
+	case VariableTypeFloat:
+		return (kind >= reflect.Int && kind <= reflect.Uint64) || kind == reflect.Float32 || kind == reflect.Float64
+	case VariableTypeBool:
+		return kind == reflect.Bool
+	case VariableTypeList:
+		return kind == reflect.Slice || kind == reflect.Array
+	default:
+		return false
+	}
+}
+
+func zeroValue(variableType VariableType) any {
+	switch variableType {
+	case VariableTypeString:
+		return ""
+	case VariableTypeInt:
+		return 0
+	case VariableTypeFloat:
+		return 0.0
+	case VariableTypeBool:
+		return false
+	default:
+		return []any{}
+	}
+}
@@ -0,0 +1,95 @@
+package prompts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type weight struct {
+	Name   string
+	Weight float32
+}
+
+type movie struct {
+	Title  string
+	Year   int
+	Rating float32
+	Genres []string
+}
+
+func TestDefaultsAreValid(t *testing.T) {
+	for _, definition := range Defaults() {
+		t.Run(definition.Name, func(t *testing.T) {
+			assert.NotEmpty(t, definition.Body)
+			assert.NoError(t, Validate(definition.Name, definition.Body, definition.Variables))
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	definition, err := Default(RecommendationMovie)
+	require.NoError(t, err)
+
+	t.Run("renders values", func(t *testing.T) {
+		text, err := Render(definition.Name, definition.Body, definition.Variables, map[string]any{
+			"UserID":         uint64(7),
+			"Username":       "alice",
+			"FavoriteGenres": []weight{{Name: "Drama", Weight: 0.75}},
+			"RecentMovies":   []movie{{Title: "Heat", Year: 1995, Rating: 4.5, Genres: []string{"Crime", "Thriller"}}},
+			"WatchedCount":   12,
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, text, "Username: alice")
+		assert.Contains(t, text, "- Drama (weight: 0.75)")
+		assert.Contains(t, text, "- Heat (1995) - Rated: 4.5/5 - Genres: Crime, Thriller")
+		assert.Contains(t, text, "No favorite actors identified yet")
+		assert.Contains(t, text, "User has watched 12 unique movies")
+		assert.NotContains(t, text, "## Excluded Genres")
+		assert.NotContains(t, text, "## Rejected Movies")
+	})
+
+	t.Run("renders rejected titles", func(t *testing.T) {
+		text, err := Render(definition.Name, definition.Body, definition.Variables, map[string]any{
+			"UserID":         uint64(7),
+			"Username":       "alice",
+			"ExcludedTitles": []string{"Heat (1995)"},
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, text, "## Rejected Movies")
+		assert.Contains(t, text, "- Heat (1995)")
+	})
+
+	t.Run("missing required variable", func(t *testing.T) {
+		_, err := Render(definition.Name, definition.Body, definition.Variables, map[string]any{"UserID": 7})
+		assert.ErrorIs(t, err, ErrInvalidVariable)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		_, err := Render(definition.Name, definition.Body, definition.Variables, map[string]any{
+			"UserID":       7,
+			"Username":     "alice",
+			"WatchedCount": "many",
+		})
+		assert.ErrorIs(t, err, ErrInvalidVariable)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	variables := []Variable{{Name: "ContentType", Type: VariableTypeString, Required: true}}
+
+	assert.NoError(t, Validate("test", "Recommend {{.ContentType}}", variables))
+	assert.ErrorIs(t, Validate("test", "Recommend {{.ContentType", variables), ErrInvalidTemplate)
+	assert.ErrorIs(t, Validate("test", "Recommend {{.MediaType}}", variables), ErrInvalidTemplate)
+}
+
+func TestDefault(t *testing.T) {
+	_, err := Default("recommendation.unknown")
+	assert.ErrorIs(t, err, ErrUnknownTemplate)
+
+	assert.True(t, IsPersonality(PersonalityCustom))
+	assert.False(t, IsPersonality(ConversationSystem))
+}
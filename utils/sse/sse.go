@@ -0,0 +1,76 @@
+// Package sse reads Server-Sent Events streams as described in the HTML living standard
+package sse
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// maxLineSize bounds a single line, streamed JSON chunks can be large
+const maxLineSize = 1 << 20
+
+// Event is a dispatched event. Event is empty for unnamed events, Data joins multiple data lines with "\n".
+type Event struct {
+	Event string
+	Data  string
+	ID    string
+}
+
+// Reader reads events from a stream
+type Reader struct {
+	scanner *bufio.Scanner
+}
+
+// NewReader creates a reader for the stream
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &Reader{scanner: scanner}
+}
+
+// Next returns the next event. It returns io.EOF once the stream ends,
+// an event that is not terminated by a blank line is still dispatched.
+func (r *Reader) Next() (Event, error) {
+	var event Event
+	var data []string
+	hasData := false
+
+	for r.scanner.Scan() {
+		line := strings.TrimSuffix(r.scanner.Text(), "\r")
+		if line == "" {
+			if hasData {
+				event.Data = strings.Join(data, "\n")
+				return event, nil
+			}
+			// Events without data are not dispatched
+			event = Event{}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// Comment, often used as a keep-alive
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+			hasData = true
+		case "id":
+			event.ID = value
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	if hasData {
+		event.Data = strings.Join(data, "\n")
+		return event, nil
+	}
+	return Event{}, io.EOF
+}
@@ -0,0 +1,42 @@
+package sse
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader(t *testing.T) {
+	stream := ": keep-alive\r\n" +
+		"event: message_start\r\n" +
+		"data: {\"type\":\"message_start\"}\r\n" +
+		"\r\n" +
+		"event: ping\n" +
+		"\n" +
+		"data: first line\n" +
+		"data: second line\n" +
+		"\n" +
+		"data: [DONE]"
+
+	reader := NewReader(strings.NewReader(stream))
+
+	event, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, Event{Event: "message_start", Data: `{"type":"message_start"}`}, event)
+
+	// The ping event has no data and is skipped
+	event, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, Event{Data: "first line\nsecond line"}, event)
+
+	// The last event is dispatched even without a trailing blank line
+	event, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "[DONE]", event.Data)
+
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}